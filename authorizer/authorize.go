@@ -25,3 +25,14 @@ func IsAllowed(ctx context.Context, p influxdb.Permission) error {
 
 	return nil
 }
+
+// IsAllowedAll checks to see if all of the actions are authorized by retrieving the
+// authorizer off of context and authorizing each action appropriately.
+func IsAllowedAll(ctx context.Context, permissions []influxdb.Permission) error {
+	for _, p := range permissions {
+		if err := IsAllowed(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
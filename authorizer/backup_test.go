@@ -0,0 +1,64 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBackupService_CreateBackup(t *testing.T) {
+	type wants struct {
+		err   error
+		files []string
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wants       wants
+	}{
+		{
+			name:        "operator is authorized to create a backup",
+			permissions: influxdb.OperPermissions(),
+			wants: wants{
+				files: []string{"data/000000001-000000001.tsm"},
+			},
+		},
+		{
+			name:        "organization owner is not authorized to create a backup",
+			permissions: influxdb.OwnerPermissions(10),
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EUnauthorized,
+					Msg:  "read:authorizations is unauthorized",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewBackupService()
+			svc.CreateBackupFn = func(ctx context.Context) (int, []string, error) {
+				return 1, []string{"data/000000001-000000001.tsm"}, nil
+			}
+			s := authorizer.NewBackupService(svc)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			_, files, err := s.CreateBackup(ctx)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(files, tt.wants.files); diff != "" {
+				t.Errorf("backup files are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
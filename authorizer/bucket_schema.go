@@ -0,0 +1,53 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService wraps a influxdb.BucketSchemaService and authorizes actions
+// against it appropriately.
+type BucketSchemaService struct {
+	s  influxdb.BucketSchemaService
+	bs influxdb.BucketService
+}
+
+// NewBucketSchemaService constructs an instance of an authorizing bucket schema
+// service. The buckets of bs are used to find the organization of a bucket.
+func NewBucketSchemaService(s influxdb.BucketSchemaService, bs influxdb.BucketService) *BucketSchemaService {
+	return &BucketSchemaService{
+		s:  s,
+		bs: bs,
+	}
+}
+
+// FindBucketSchema checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	b, err := s.bs.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, b.OrgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindBucketSchema(ctx, bucketID)
+}
+
+// UpdateBucketSchema checks to see if the authorizer on context has write access to the bucket.
+func (s *BucketSchemaService) UpdateBucketSchema(ctx context.Context, bucketID influxdb.ID, schema influxdb.BucketSchema) (*influxdb.BucketSchema, error) {
+	b, err := s.bs.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, b.OrgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateBucketSchema(ctx, bucketID, schema)
+}
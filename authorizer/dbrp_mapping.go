@@ -0,0 +1,103 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// DBRPMappingService wraps a influxdb.DBRPMappingService and authorizes actions
+// against it appropriately. A mapping is authorized through the bucket it maps to.
+type DBRPMappingService struct {
+	s influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingService constructs an instance of an authorizing dbrp mapping service.
+func NewDBRPMappingService(s influxdb.DBRPMappingService) *DBRPMappingService {
+	return &DBRPMappingService{
+		s: s,
+	}
+}
+
+// FindBy checks to see if the authorizer on context has read access to the bucket of the mapping.
+func (s *DBRPMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	m, err := s.s.FindBy(ctx, cluster, db, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Find checks to see if the authorizer on context has read access to the bucket of the mapping.
+func (s *DBRPMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	m, err := s.s.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindMany retrieves all mappings that match the provided filter and then filters the list down to only the mappings whose buckets are authorized.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	ms, _, err := s.s.FindMany(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	mappings := ms[:0]
+	for _, m := range ms {
+		err := authorizeReadBucket(ctx, m.OrganizationID, m.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		mappings = append(mappings, m)
+	}
+
+	return mappings, len(mappings), nil
+}
+
+// Create checks to see if the authorizer on context has write access to the bucket of the mapping.
+func (s *DBRPMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	if err := authorizeWriteBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.Create(ctx, m)
+}
+
+// Delete checks to see if the authorizer on context has write access to the bucket of the mapping.
+func (s *DBRPMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	m, err := s.s.FindBy(ctx, cluster, db, rp)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		// Deleting a mapping that does not exist is not an error.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrganizationID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.Delete(ctx, cluster, db, rp)
+}
@@ -0,0 +1,279 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDBRPMappingService_FindBy(t *testing.T) {
+	type fields struct {
+		DBRPMappingService influxdb.DBRPMappingService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to read bucket",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					FindByFn: func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+						return &influxdb.DBRPMapping{
+							Cluster:         cluster,
+							Database:        db,
+							RetentionPolicy: rp,
+							OrganizationID:  10,
+							BucketID:        1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to read bucket",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					FindByFn: func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+						return &influxdb.DBRPMapping{
+							Cluster:         cluster,
+							Database:        db,
+							RetentionPolicy: rp,
+							OrganizationID:  10,
+							BucketID:        1,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(2),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(tt.fields.DBRPMappingService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			_, err := s.FindBy(ctx, "cluster", "db", "rp")
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestDBRPMappingService_FindMany(t *testing.T) {
+	type fields struct {
+		DBRPMappingService influxdb.DBRPMappingService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err      error
+		mappings []*influxdb.DBRPMapping
+	}
+
+	mappings := func() []*influxdb.DBRPMapping {
+		return []*influxdb.DBRPMapping{
+			{Cluster: "c", Database: "db", RetentionPolicy: "rp1", OrganizationID: 10, BucketID: 1},
+			{Cluster: "c", Database: "db", RetentionPolicy: "rp2", OrganizationID: 10, BucketID: 2},
+			{Cluster: "c", Database: "db", RetentionPolicy: "rp3", OrganizationID: 11, BucketID: 3},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all mappings",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					FindManyFn: func(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+						ms := mappings()
+						return ms, len(ms), nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				mappings: mappings(),
+			},
+		},
+		{
+			name: "authorized to see mappings of one org",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					FindManyFn: func(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+						ms := mappings()
+						return ms, len(ms), nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				mappings: mappings()[:2],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(tt.fields.DBRPMappingService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			ms, _, err := s.FindMany(ctx, influxdb.DBRPMappingFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(ms, tt.wants.mappings); diff != "" {
+				t.Errorf("mappings are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestDBRPMappingService_Create(t *testing.T) {
+	type fields struct {
+		DBRPMappingService influxdb.DBRPMappingService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to write bucket",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					CreateFn: func(ctx context.Context, m *influxdb.DBRPMapping) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			fields: fields{
+				DBRPMappingService: &mock.DBRPMappingService{
+					CreateFn: func(ctx context.Context, m *influxdb.DBRPMapping) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(tt.fields.DBRPMappingService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			err := s.Create(ctx, &influxdb.DBRPMapping{
+				Cluster:         "c",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				OrganizationID:  10,
+				BucketID:        1,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
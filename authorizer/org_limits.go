@@ -0,0 +1,79 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.OrgLimitsService = (*OrgLimitsService)(nil)
+
+// OrgLimitsService wraps a influxdb.OrgLimitsService and authorizes actions
+// against it appropriately.
+type OrgLimitsService struct {
+	s influxdb.OrgLimitsService
+}
+
+// NewOrgLimitsService constructs an instance of an authorizing org limits service.
+func NewOrgLimitsService(s influxdb.OrgLimitsService) *OrgLimitsService {
+	return &OrgLimitsService{
+		s: s,
+	}
+}
+
+// FindOrgLimits checks to see if the authorizer on context has read access to the organization.
+func (s *OrgLimitsService) FindOrgLimits(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+	if err := authorizeReadOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindOrgLimits(ctx, orgID)
+}
+
+// UpdateOrgLimits checks to see if the authorizer on context has write access to all
+// organizations. Write access to the organization itself is not sufficient, as it
+// would allow organizations to raise their own limits.
+func (s *OrgLimitsService) UpdateOrgLimits(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error) {
+	p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.OrgsResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateOrgLimits(ctx, orgID, l)
+}
+
+var _ influxdb.UsageService = (*UsageService)(nil)
+
+// UsageService wraps a influxdb.UsageService and authorizes actions
+// against it appropriately.
+type UsageService struct {
+	s influxdb.UsageService
+}
+
+// NewUsageService constructs an instance of an authorizing usage service.
+func NewUsageService(s influxdb.UsageService) *UsageService {
+	return &UsageService{
+		s: s,
+	}
+}
+
+// GetUsage checks to see if the authorizer on context has read access to the
+// organization of the filter.
+func (s *UsageService) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.OrgID == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "organization id is required",
+		}
+	}
+
+	if err := authorizeReadOrg(ctx, *filter.OrgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.GetUsage(ctx, filter)
+}
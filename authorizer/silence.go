@@ -0,0 +1,130 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService wraps a influxdb.SilenceService and authorizes actions
+// against it appropriately. Silences apply to the notification rules of an
+// organization, so they require the matching notification rule permissions.
+type SilenceService struct {
+	s influxdb.SilenceService
+}
+
+// NewSilenceService constructs an instance of an authorizing silence service.
+func NewSilenceService(s influxdb.SilenceService) *SilenceService {
+	return &SilenceService{
+		s: s,
+	}
+}
+
+func newSilencePermission(a influxdb.Action, orgID influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermission(a, influxdb.NotificationRuleResourceType, orgID)
+}
+
+func authorizeReadSilence(ctx context.Context, orgID influxdb.ID) error {
+	p, err := newSilencePermission(influxdb.ReadAction, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteSilence(ctx context.Context, orgID influxdb.ID) error {
+	p, err := newSilencePermission(influxdb.WriteAction, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindSilenceByID checks to see if the authorizer on context has read access to the silence provided.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadSilence(ctx, sl.OrgID); err != nil {
+		return nil, err
+	}
+
+	return sl, nil
+}
+
+// FindSilences retrieves all silences that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	ss, _, err := s.s.FindSilences(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	silences := ss[:0]
+	for _, sl := range ss {
+		err := authorizeReadSilence(ctx, sl.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		silences = append(silences, sl)
+	}
+
+	return silences, len(silences), nil
+}
+
+// CreateSilence checks to see if the authorizer on context has write access to the notification rules of the organization.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := authorizeWriteSilence(ctx, sl.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.CreateSilence(ctx, sl)
+}
+
+// UpdateSilence checks to see if the authorizer on context has write access to the silence provided.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	sl, err := s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteSilence(ctx, sl.OrgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateSilence(ctx, id, upd)
+}
+
+// DeleteSilence checks to see if the authorizer on context has write access to the silence provided.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	sl, err := s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteSilence(ctx, sl.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteSilence(ctx, id)
+}
@@ -0,0 +1,64 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskBackfillService)(nil)
+
+// TaskBackfillService wraps a influxdb.TaskBackfillService and authorizes actions
+// against it appropriately.
+type TaskBackfillService struct {
+	s  influxdb.TaskBackfillService
+	ts influxdb.TaskService
+}
+
+// NewTaskBackfillService constructs an instance of an authorizing task backfill
+// service. The tasks of ts are used to find the organization of a task.
+func NewTaskBackfillService(s influxdb.TaskBackfillService, ts influxdb.TaskService) *TaskBackfillService {
+	return &TaskBackfillService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+func (s *TaskBackfillService) authorizeTask(ctx context.Context, a influxdb.Action, taskID influxdb.ID) error {
+	// Unauthenticated task lookup, to identify the task's organization.
+	t, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	p, err := influxdb.NewPermissionAtID(taskID, a, influxdb.TasksResourceType, t.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// BackfillTask checks to see if the authorizer on context has write access to the task.
+func (s *TaskBackfillService) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	if err := s.authorizeTask(ctx, influxdb.WriteAction, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.BackfillTask(ctx, taskID, start, stop)
+}
+
+// FindTaskBackfill checks to see if the authorizer on context has read access to the task.
+func (s *TaskBackfillService) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	if err := s.authorizeTask(ctx, influxdb.ReadAction, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskBackfill(ctx, taskID)
+}
+
+// CancelTaskBackfill checks to see if the authorizer on context has write access to the task.
+func (s *TaskBackfillService) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	if err := s.authorizeTask(ctx, influxdb.WriteAction, taskID); err != nil {
+		return err
+	}
+	return s.s.CancelTaskBackfill(ctx, taskID)
+}
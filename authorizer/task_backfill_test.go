@@ -0,0 +1,148 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newTaskBackfillTaskService() *mock.TaskService {
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+	}
+	return ts
+}
+
+func TestTaskBackfillService_BackfillTask(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		taskID     influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to backfill task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				taskID: 1,
+			},
+		},
+		{
+			name: "unauthorized to backfill task with read access",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				taskID: 1,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTaskBackfillService(mock.NewTaskBackfillService(), newTaskBackfillTaskService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+			_, err := s.BackfillTask(ctx, tt.args.taskID, start, start.Add(time.Hour))
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestTaskBackfillService_FindTaskBackfill(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+		taskID     influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to read backfill of task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				taskID: 1,
+			},
+		},
+		{
+			name: "unauthorized to read backfill of other task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(1),
+					},
+				},
+				taskID: 2,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a/tasks/0000000000000002 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewTaskBackfillService()
+			bs.FindTaskBackfillFn = func(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+				return &influxdb.TaskBackfill{TaskID: taskID}, nil
+			}
+			s := authorizer.NewTaskBackfillService(bs, newTaskBackfillTaskService())
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			_, err := s.FindTaskBackfill(ctx, tt.args.taskID)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
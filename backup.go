@@ -0,0 +1,29 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+)
+
+// BackupService represents the data backup functions of InfluxDB.
+type BackupService interface {
+	// CreateBackup creates a consistent local copy of the time series data, series file
+	// and index. The returned file names are relative to the backup and are used by
+	// the client to request copies of the backup files.
+	CreateBackup(ctx context.Context) (backupID int, backupFiles []string, err error)
+
+	// FetchBackupFile writes one backup file to w.
+	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+
+	// DeleteBackup removes the local copy of a backup.
+	DeleteBackup(ctx context.Context, backupID int) error
+
+	// InternalBackupPath is a utility to determine the on-disk location of a backup fileset.
+	InternalBackupPath(backupID int) string
+}
+
+// KVBackupService represents the metadata backup functions of InfluxDB.
+type KVBackupService interface {
+	// Backup writes a consistent copy of the metadata database to w.
+	Backup(ctx context.Context, w io.Writer) error
+}
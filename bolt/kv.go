@@ -3,11 +3,13 @@ package bolt
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
 	"go.uber.org/zap"
@@ -16,6 +18,12 @@ import (
 // check that *KVStore implement kv.Store interface.
 var _ kv.Store = (*KVStore)(nil)
 
+// check that *KVStore implement influxdb.KVBackupService interface.
+var _ influxdb.KVBackupService = (*KVStore)(nil)
+
+// DefaultFilename is the default name of the boltdb file.
+const DefaultFilename = "influxd.bolt"
+
 // KVStore is a kv.Store backed by boltdb.
 type KVStore struct {
 	path string
@@ -123,6 +131,18 @@ func (s *KVStore) Update(ctx context.Context, fn func(tx kv.Tx) error) error {
 	})
 }
 
+// Backup copies all K:Vs to a writer, in boltdb format. The copy is taken from
+// a read transaction, so writes to the store continue while it is running.
+func (s *KVStore) Backup(ctx context.Context, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
 // Tx is a light wrapper around a boltdb transaction. It implements kv.Tx.
 type Tx struct {
 	tx  *bolt.Tx
@@ -2,10 +2,14 @@ package bolt_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
+	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/kv"
 	platformtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
 )
 
 func initKVStore(f platformtesting.KVStoreFields, t *testing.T) (kv.Store, func()) {
@@ -39,3 +43,60 @@ func initKVStore(f platformtesting.KVStoreFields, t *testing.T) (kv.Store, func(
 func TestKVStore(t *testing.T) {
 	platformtesting.KVStore(initKVStore, t)
 }
+
+func TestKVStore_Backup(t *testing.T) {
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("failed to put key: %v", err)
+	}
+
+	f, err := ioutil.TempFile("", "influxdata-platform-bolt-backup-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.Backup(ctx, f); err != nil {
+		t.Fatalf("failed to backup kv store: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := bolt.NewKVStore(zaptest.NewLogger(t), f.Name())
+	if err := restored.Open(ctx); err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer restored.Close()
+
+	err = restored.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		v, err := b.Get([]byte("key"))
+		if err != nil {
+			return err
+		}
+		if got, want := string(v), "value"; got != want {
+			t.Errorf("unexpected value: got %q want %q", got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read backup: %v", err)
+	}
+}
@@ -33,13 +33,15 @@ const InfiniteRetention = 0
 
 // Bucket is a bucket. 🎉
 type Bucket struct {
-	ID                  ID            `json:"id,omitempty"`
-	OrgID               ID            `json:"orgID,omitempty"`
-	Type                BucketType    `json:"type"`
-	Name                string        `json:"name"`
-	Description         string        `json:"description"`
-	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
-	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	ID                   ID               `json:"id,omitempty"`
+	OrgID                ID               `json:"orgID,omitempty"`
+	Type                 BucketType       `json:"type"`
+	Name                 string           `json:"name"`
+	Description          string           `json:"description"`
+	RetentionPolicyName  string           `json:"rp,omitempty"` // This to support v1 sources
+	RetentionPeriod      time.Duration    `json:"retentionPeriod"`
+	MaxSeriesCardinality int64            `json:"maxSeriesCardinality,omitempty"` // Zero means the number of series is unlimited
+	DownsampleTiers      []DownsampleTier `json:"downsampleTiers,omitempty"`
 	CRUDLog
 }
 
@@ -99,9 +101,11 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name                 *string           `json:"name,omitempty"`
+	Description          *string           `json:"description,omitempty"`
+	RetentionPeriod      *time.Duration    `json:"retentionPeriod,omitempty"`
+	MaxSeriesCardinality *int64            `json:"maxSeriesCardinality,omitempty"`
+	DownsampleTiers      *[]DownsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -0,0 +1,157 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// SchemaFieldType is the type of the values of a field in a bucket schema.
+type SchemaFieldType string
+
+// Field types of bucket schemas.
+const (
+	SchemaFieldTypeFloat    SchemaFieldType = "float"
+	SchemaFieldTypeInteger  SchemaFieldType = "integer"
+	SchemaFieldTypeUnsigned SchemaFieldType = "unsigned"
+	SchemaFieldTypeString   SchemaFieldType = "string"
+	SchemaFieldTypeBoolean  SchemaFieldType = "boolean"
+)
+
+// Valid returns an error if the field type is not known.
+func (t SchemaFieldType) Valid() error {
+	switch t {
+	case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned, SchemaFieldTypeString, SchemaFieldTypeBoolean:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid field type %q", t),
+	}
+}
+
+// BucketSchema is an explicit schema of the data written to a bucket. When a
+// bucket has a schema, only points of the declared measurements, with the
+// allowed tag keys and declared fields of the declared types, are written to
+// it. A bucket without measurements in its schema accepts any point.
+type BucketSchema struct {
+	BucketID     ID                  `json:"bucketID"`
+	Measurements []MeasurementSchema `json:"measurements"`
+}
+
+// MeasurementSchema declares a measurement of a bucket schema.
+type MeasurementSchema struct {
+	Name   string        `json:"name"`
+	Tags   []string      `json:"tags"` // Allowed tag keys.
+	Fields []FieldSchema `json:"fields"`
+}
+
+// FieldSchema declares a field of a measurement schema.
+type FieldSchema struct {
+	Name string          `json:"name"`
+	Type SchemaFieldType `json:"type"`
+}
+
+// Valid returns an error if the schema is not valid.
+func (s BucketSchema) Valid() error {
+	names := make(map[string]bool, len(s.Measurements))
+	for _, m := range s.Measurements {
+		if m.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "measurement name is required",
+			}
+		}
+		if names[m.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement %q is declared more than once", m.Name),
+			}
+		}
+		names[m.Name] = true
+
+		if err := m.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Valid returns an error if the measurement schema is not valid.
+func (m MeasurementSchema) Valid() error {
+	if len(m.Fields) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("measurement %q must declare at least one field", m.Name),
+		}
+	}
+
+	tags := make(map[string]bool, len(m.Tags))
+	for _, k := range m.Tags {
+		if k == "" || tags[k] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement %q has an empty or duplicate tag key %q", m.Name, k),
+			}
+		}
+		tags[k] = true
+	}
+
+	fields := make(map[string]bool, len(m.Fields))
+	for _, f := range m.Fields {
+		if f.Name == "" || fields[f.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement %q has an empty or duplicate field name %q", m.Name, f.Name),
+			}
+		}
+		fields[f.Name] = true
+
+		if err := f.Type.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Measurement returns the schema of the measurement with the name, or nil if
+// the measurement is not declared.
+func (s *BucketSchema) Measurement(name string) *MeasurementSchema {
+	for i := range s.Measurements {
+		if s.Measurements[i].Name == name {
+			return &s.Measurements[i]
+		}
+	}
+	return nil
+}
+
+// HasTag returns true if the tag key is allowed by the measurement schema.
+func (m *MeasurementSchema) HasTag(key string) bool {
+	for _, k := range m.Tags {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the schema of the field with the name, or nil if the field is
+// not declared.
+func (m *MeasurementSchema) Field(name string) *FieldSchema {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// BucketSchemaService manages the schemas of buckets.
+type BucketSchemaService interface {
+	// FindBucketSchema returns the schema of a bucket. A bucket without a
+	// schema has a schema without measurements.
+	FindBucketSchema(ctx context.Context, bucketID ID) (*BucketSchema, error)
+
+	// UpdateBucketSchema replaces the schema of a bucket. A schema without
+	// measurements removes the schema of the bucket.
+	UpdateBucketSchema(ctx context.Context, bucketID ID, s BucketSchema) (*BucketSchema, error)
+}
@@ -0,0 +1,90 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestBucketSchema_Valid(t *testing.T) {
+	cpu := func(fields ...influxdb.FieldSchema) influxdb.MeasurementSchema {
+		return influxdb.MeasurementSchema{Name: "cpu", Tags: []string{"host"}, Fields: fields}
+	}
+
+	tests := []struct {
+		name   string
+		schema influxdb.BucketSchema
+		err    string
+	}{
+		{
+			name: "valid schema",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				cpu(influxdb.FieldSchema{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}),
+			}},
+		},
+		{
+			name:   "no measurements",
+			schema: influxdb.BucketSchema{},
+		},
+		{
+			name: "measurement without name",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				{Fields: []influxdb.FieldSchema{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}}},
+			}},
+			err: "measurement name is required",
+		},
+		{
+			name: "duplicate measurement",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				cpu(influxdb.FieldSchema{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}),
+				cpu(influxdb.FieldSchema{Name: "idle", Type: influxdb.SchemaFieldTypeFloat}),
+			}},
+			err: `measurement "cpu" is declared more than once`,
+		},
+		{
+			name: "measurement without fields",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				cpu(),
+			}},
+			err: `measurement "cpu" must declare at least one field`,
+		},
+		{
+			name: "duplicate field",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				cpu(
+					influxdb.FieldSchema{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+					influxdb.FieldSchema{Name: "usage", Type: influxdb.SchemaFieldTypeInteger},
+				),
+			}},
+			err: `measurement "cpu" has an empty or duplicate field name "usage"`,
+		},
+		{
+			name: "unknown field type",
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				cpu(influxdb.FieldSchema{Name: "usage", Type: "double"}),
+			}},
+			err: `invalid field type "double"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Valid()
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q", tt.err)
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.err {
+				t.Errorf("got error %q, want %q", got, tt.err)
+			}
+			if got := influxdb.ErrorCode(err); got != influxdb.EInvalid {
+				t.Errorf("got error code %q, want %q", got, influxdb.EInvalid)
+			}
+		})
+	}
+}
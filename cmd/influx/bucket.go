@@ -26,6 +26,7 @@ type BucketCreateFlags struct {
 	name string
 	organization
 	retention time.Duration
+	maxSeries int64
 }
 
 var bucketCreateFlags BucketCreateFlags
@@ -39,6 +40,7 @@ func init() {
 
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.name, "name", "n", "", "Name of bucket that will be created")
 	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
+	bucketCreateCmd.Flags().Int64Var(&bucketCreateFlags.maxSeries, "max-series", 0, "Maximum number of series in bucket, 0 for unlimited")
 	bucketCreateCmd.MarkFlagRequired("name")
 	bucketCreateFlags.organization.register(bucketCreateCmd)
 
@@ -71,8 +73,9 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 	}
 
 	b := &platform.Bucket{
-		Name:            bucketCreateFlags.name,
-		RetentionPeriod: bucketCreateFlags.retention,
+		Name:                 bucketCreateFlags.name,
+		RetentionPeriod:      bucketCreateFlags.retention,
+		MaxSeriesCardinality: bucketCreateFlags.maxSeries,
 	}
 
 	orgSvc, err := newOrganizationService()
@@ -202,6 +205,7 @@ type BucketUpdateFlags struct {
 	id        string
 	name      string
 	retention time.Duration
+	maxSeries int64
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -221,6 +225,7 @@ func init() {
 	}
 
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "New duration data will live in bucket")
+	bucketUpdateCmd.Flags().Int64Var(&bucketUpdateFlags.maxSeries, "max-series", 0, "New maximum number of series in bucket, 0 for unlimited")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -244,6 +249,9 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 	if bucketUpdateFlags.retention != 0 {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
+	if cmd.Flags().Changed("max-series") {
+		update.MaxSeriesCardinality = &bucketUpdateFlags.maxSeries
+	}
 
 	b, err := s.UpdateBucket(context.Background(), id, update)
 	if err != nil {
@@ -577,3 +577,148 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var taskBackfillFlags struct {
+	id    string
+	start string
+	stop  string
+	wait  bool
+}
+
+func init() {
+	taskBackfillCmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Run a task at every time it was scheduled for in a time range",
+		RunE:  wrapCheckSetup(taskBackfillF),
+	}
+
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.id, "id", "i", "", "task id (required)")
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.start, "start", "", "", "earliest scheduled time to run, in RFC3339 format (required)")
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.stop, "stop", "", "", "scheduled time to stop before, in RFC3339 format (required)")
+	taskBackfillCmd.Flags().BoolVarP(&taskBackfillFlags.wait, "wait", "w", false, "wait for the backfill to finish, reporting its progress")
+	taskBackfillCmd.MarkFlagRequired("id")
+	taskBackfillCmd.MarkFlagRequired("start")
+	taskBackfillCmd.MarkFlagRequired("stop")
+
+	taskBackfillStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the progress of the latest backfill of a task",
+		RunE:  wrapCheckSetup(taskBackfillStatusF),
+	}
+	taskBackfillStatusCmd.Flags().StringVarP(&taskBackfillFlags.id, "id", "i", "", "task id (required)")
+	taskBackfillStatusCmd.MarkFlagRequired("id")
+
+	taskBackfillCancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel the running backfill of a task",
+		RunE:  wrapCheckSetup(taskBackfillCancelF),
+	}
+	taskBackfillCancelCmd.Flags().StringVarP(&taskBackfillFlags.id, "id", "i", "", "task id (required)")
+	taskBackfillCancelCmd.MarkFlagRequired("id")
+
+	taskBackfillCmd.AddCommand(taskBackfillStatusCmd, taskBackfillCancelCmd)
+	taskCmd.AddCommand(taskBackfillCmd)
+}
+
+func taskBackfillF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	id, err := platform.IDFromString(taskBackfillFlags.id)
+	if err != nil {
+		return err
+	}
+	start, err := time.Parse(time.RFC3339, taskBackfillFlags.start)
+	if err != nil {
+		return err
+	}
+	stop, err := time.Parse(time.RFC3339, taskBackfillFlags.stop)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	b, err := s.BackfillTask(ctx, *id, start, stop)
+	if err != nil {
+		return err
+	}
+
+	for taskBackfillFlags.wait && b.Status == platform.TaskBackfillRunning {
+		fmt.Printf("%d of %d runs done, %d failed\n", b.Succeeded+b.Failed, b.Total, b.Failed)
+		time.Sleep(time.Second)
+
+		if b, err = s.FindTaskBackfill(ctx, *id); err != nil {
+			return err
+		}
+	}
+
+	writeTaskBackfill(b)
+	return nil
+}
+
+func taskBackfillStatusF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	id, err := platform.IDFromString(taskBackfillFlags.id)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.FindTaskBackfill(context.Background(), *id)
+	if err != nil {
+		return err
+	}
+
+	writeTaskBackfill(b)
+	return nil
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	id, err := platform.IDFromString(taskBackfillFlags.id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CancelTaskBackfill(context.Background(), *id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backfill of task %s canceled.\n", id)
+	return nil
+}
+
+func writeTaskBackfill(b *platform.TaskBackfill) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"TaskID",
+		"Status",
+		"Start",
+		"Stop",
+		"Total",
+		"Succeeded",
+		"Failed",
+	)
+	w.Write(map[string]interface{}{
+		"TaskID":    b.TaskID,
+		"Status":    b.Status,
+		"Start":     b.Start.Format(time.RFC3339),
+		"Stop":      b.Stop.Format(time.RFC3339),
+		"Total":     b.Total,
+		"Succeeded": b.Succeeded,
+		"Failed":    b.Failed,
+	})
+	w.Flush()
+}
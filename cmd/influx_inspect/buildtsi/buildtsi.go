@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/toml"
@@ -18,7 +19,11 @@ import (
 	"go.uber.org/zap"
 )
 
-func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, log *zap.Logger, verboseLogging bool) error {
+// IndexShard builds the TSI index at indexPath from the TSM files in dataDir and
+// the WAL segments in walDir. Encrypted TSM files are decrypted, and the index
+// files encrypted, with keyring; encrypted WAL segments are decrypted with
+// walKeyring.
+func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLogFileSize int64, maxCacheSize uint64, batchSize int, keyring, walKeyring *encryption.Keyring, log *zap.Logger, verboseLogging bool) error {
 	log.Info("Rebuilding shard")
 
 	// Check if shard already has a TSI index.
@@ -50,6 +55,7 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 		tsi1.DisableMetrics(), // Disable metrics when rebuilding an index
 	)
 	tsiIndex.WithLogger(log)
+	tsiIndex.WithKeyring(keyring)
 
 	log.Info("Opening tsi index in temporary location", zap.String("path", tmpPath))
 	if err := tsiIndex.Open(context.Background()); err != nil {
@@ -67,7 +73,7 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 	log.Info("Iterating over tsm files")
 	for _, path := range tsmPaths {
 		log.Info("Processing tsm file", zap.String("path", path))
-		if err := IndexTSMFile(tsiIndex, path, batchSize, log, verboseLogging); err != nil {
+		if err := IndexTSMFile(tsiIndex, path, batchSize, keyring, log, verboseLogging); err != nil {
 			return err
 		}
 	}
@@ -84,6 +90,7 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 		cache := tsm1.NewCache(uint64(tsm1.DefaultCacheMaxMemorySize))
 		loader := tsm1.NewCacheLoader(walPaths)
 		loader.WithLogger(log)
+		loader.WithKeyring(walKeyring)
 		if err := loader.Load(cache); err != nil {
 			return err
 		}
@@ -144,14 +151,14 @@ func IndexShard(sfile *tsdb.SeriesFile, indexPath, dataDir, walDir string, maxLo
 	return fs.RenameFile(tmpPath, indexPath)
 }
 
-func IndexTSMFile(index *tsi1.Index, path string, batchSize int, log *zap.Logger, verboseLogging bool) error {
+func IndexTSMFile(index *tsi1.Index, path string, batchSize int, keyring *encryption.Keyring, log *zap.Logger, verboseLogging bool) error {
 	f, err := os.Open(path)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	r, err := tsm1.NewTSMReader(f)
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderKeyring(keyring))
 	if err != nil {
 		log.Warn("Unable to read, skipping", zap.String("path", path), zap.Error(err))
 		return nil
@@ -0,0 +1,166 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+// ManifestFilename is the name of the file, written to the root of a backup,
+// that lists all other files in the backup.
+const ManifestFilename = "manifest.json"
+
+// Manifest describes the files in a backup.
+type Manifest struct {
+	Files []string `json:"files"`
+}
+
+// ReadManifest reads the manifest of the backup at path and verifies that
+// every file it lists is present.
+func ReadManifest(path string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(path, ManifestFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %v", err)
+	}
+
+	for _, f := range m.Files {
+		if _, err := os.Stat(filepath.Join(path, filepath.FromSlash(f))); err != nil {
+			return nil, fmt.Errorf("backup is incomplete: %v", err)
+		}
+	}
+	return &m, nil
+}
+
+var flags struct {
+	// Standard output, overridden for testing.
+	Stdout io.Writer
+
+	Host       string
+	Token      string
+	SkipVerify bool
+}
+
+// NewCommand creates the backup command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <path>",
+		Short: "Creates a backup of a running influxd instance",
+		Long: `This command creates a consistent backup of the time series data,
+		series file, index and metadata store of a running influxd instance and
+		writes it to a new directory at path. Writes are not stopped while the
+		backup is taken.
+
+		The backup can be loaded into a fresh instance with influxd restore.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: runE,
+	}
+
+	flags.Stdout = os.Stdout
+
+	cmd.Flags().StringVar(&flags.Host, "host", "http://localhost:9999", "HTTP address of the influxd instance")
+	cmd.Flags().StringVarP(&flags.Token, "token", "t", "", "Operator token; defaults to the token stored by influx setup")
+	cmd.Flags().BoolVar(&flags.SkipVerify, "skip-verify", false, "Skip TLS certificate verification")
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	token := flags.Token
+	if token == "" {
+		dir, err := fs.InfluxDir()
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, "credentials"))
+		if err != nil {
+			return fmt.Errorf("a token is required: %v", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+
+	client, err := http.NewHTTPClient(flags.Host, token, flags.SkipVerify)
+	if err != nil {
+		return err
+	}
+
+	return Backup(context.Background(), &http.BackupService{Client: client}, args[0], flags.Stdout)
+}
+
+// Backup creates a backup using svc and writes it, along with its manifest,
+// to the new directory at path. The server's copy is removed once all files
+// have been fetched.
+func Backup(ctx context.Context, svc influxdb.BackupService, path string, w io.Writer) error {
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+	if fis, err := ioutil.ReadDir(path); err != nil {
+		return err
+	} else if len(fis) > 0 {
+		return fmt.Errorf("backup path %q is not empty", path)
+	}
+
+	id, files, err := svc.CreateBackup(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = svc.DeleteBackup(ctx, id) }()
+
+	for _, f := range files {
+		if err := fetchFile(ctx, svc, id, f, path); err != nil {
+			return fmt.Errorf("failed to fetch %s: %v", f, err)
+		}
+		fmt.Fprintf(w, "Backed up %s\n", f)
+	}
+
+	b, err := json.Marshal(Manifest{Files: files})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, ManifestFilename), b, 0600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Backup complete: %d files written to %s\n", len(files), path)
+	return nil
+}
+
+func fetchFile(ctx context.Context, svc influxdb.BackupService, id int, name, root string) error {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid backup file name %q", name)
+	}
+
+	target := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := svc.FetchBackupFile(ctx, id, name, f); err != nil {
+		return err
+	} else if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -91,9 +91,6 @@ func assignOrgBucket(spec *gen.Spec) error {
 	}
 
 	s := kv.NewService(zap.NewNop(), store)
-	if err = s.Initialize(context.Background()); err != nil {
-		return err
-	}
 
 	org, err := s.FindOrganizationByName(context.Background(), flags.storageSpec.Organization)
 	if err != nil {
@@ -36,6 +36,9 @@ var buildTSIFlags = struct {
 	MaxLogFileSize int64  // optional. Defaults to tsi1.DefaultMaxIndexLogFileSize
 	MaxCacheSize   uint64 // optional. Defaults to tsm1.DefaultCacheMaxMemorySize
 
+	KeyFile    string // optional. Keys to decrypt TSM files and encrypt index files with.
+	WALKeyFile string // optional. Keys to decrypt WAL files with.
+
 	Concurrency int  // optional. Defaults to GOMAXPROCS(0)
 	Verbose     bool // optional. Defaults to false.
 }{
@@ -74,6 +77,10 @@ func NewBuildTSICommand() *cobra.Command {
 		batch-size refers to the size of the batches written into the index. 
 			Increasing this can improve performance but can result in much more
 			memory usage.
+
+		encryption-key-file is required if the TSM files are encrypted. The
+			index files are then encrypted with its first key, as the engine
+			would. wal-encryption-key-file is required if the WAL is encrypted.
 		`,
 		RunE: RunBuildTSI,
 	}
@@ -93,6 +100,8 @@ func NewBuildTSICommand() *cobra.Command {
 	cmd.Flags().Int64Var(&buildTSIFlags.MaxLogFileSize, "max-log-file-size", tsi1.DefaultMaxIndexLogFileSize, "optional: maximum log file size")
 	cmd.Flags().Uint64Var(&buildTSIFlags.MaxCacheSize, "max-cache-size", uint64(tsm1.DefaultCacheMaxMemorySize), "optional: maximum cache size")
 	cmd.Flags().IntVar(&buildTSIFlags.BatchSize, "batch-size", defaultBatchSize, "optional: set the size of the batches we write to the index. Setting this can have adverse affects on performance and heap requirements")
+	cmd.Flags().StringVar(&buildTSIFlags.KeyFile, "encryption-key-file", "", "file of hex-encoded keys, one per line, to decrypt encrypted TSM files and encrypt index files with")
+	cmd.Flags().StringVar(&buildTSIFlags.WALKeyFile, "wal-encryption-key-file", "", "file of hex-encoded keys, one per line, to decrypt encrypted WAL files with")
 	cmd.Flags().BoolVar(&buildTSIFlags.Verbose, "v", false, "verbose")
 
 	cmd.SetOutput(buildTSIFlags.Stdout)
@@ -115,6 +124,15 @@ func RunBuildTSI(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	keyring, err := loadKeyringFile(buildTSIFlags.KeyFile)
+	if err != nil {
+		return err
+	}
+	walKeyring, err := loadKeyringFile(buildTSIFlags.WALKeyFile)
+	if err != nil {
+		return err
+	}
+
 	log := logger.New(buildTSIFlags.Stdout)
 
 	sfile := tsdb.NewSeriesFile(buildTSIFlags.SeriesFilePath)
@@ -126,7 +144,7 @@ func RunBuildTSI(cmd *cobra.Command, args []string) error {
 
 	return buildtsi.IndexShard(sfile, buildTSIFlags.IndexPath, buildTSIFlags.DataPath, buildTSIFlags.WALPath,
 		buildTSIFlags.MaxLogFileSize, buildTSIFlags.MaxCacheSize, buildTSIFlags.BatchSize,
-		log, buildTSIFlags.Verbose)
+		keyring, walKeyring, log, buildTSIFlags.Verbose)
 }
 
 func isRoot() bool {
@@ -4,12 +4,14 @@ import (
 	"os"
 
 	"github.com/influxdata/influxdb/kit/errors"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/spf13/cobra"
 )
 
 var dumpWALFlags = struct {
 	findDuplicates bool
+	keyFile        string
 }{}
 
 func NewDumpWALCommand() *cobra.Command {
@@ -29,6 +31,9 @@ It has two modes of operation, depending on the --find-duplicates flag.
 --find-duplicates=true: for each file, the following is printed:
 	* The file name
 	* A list of keys in the file that have out of order timestamps
+
+Files compressed with zstd are read transparently. Encrypted files are decrypted
+with the keys in the file provided by the --encryption-key-file flag.
 `,
 		RunE: inspectDumpWAL,
 	}
@@ -36,22 +41,39 @@ It has two modes of operation, depending on the --find-duplicates flag.
 	dumpTSMWALCommand.Flags().BoolVarP(
 		&dumpWALFlags.findDuplicates,
 		"find-duplicates", "", false, "ignore dumping entries; only report keys in the WAL that are out of order")
+	dumpTSMWALCommand.Flags().StringVarP(
+		&dumpWALFlags.keyFile,
+		"encryption-key-file", "", "", "file of hex-encoded keys, one per line, to decrypt encrypted WAL files with")
 
 	return dumpTSMWALCommand
 }
 
 func inspectDumpWAL(cmd *cobra.Command, args []string) error {
+	keyring, err := loadKeyringFile(dumpWALFlags.keyFile)
+	if err != nil {
+		return err
+	}
+
 	dumper := &wal.Dump{
 		Stdout:         os.Stdout,
 		Stderr:         os.Stderr,
 		FileGlobs:      args,
 		FindDuplicates: dumpWALFlags.findDuplicates,
+		Keyring:        keyring,
 	}
 
 	if len(args) == 0 {
 		return errors.New("no files provided. aborting")
 	}
 
-	_, err := dumper.Run(true)
+	_, err = dumper.Run(true)
 	return err
 }
+
+// loadKeyringFile returns the keyring in the file at path, or nil if path is empty.
+func loadKeyringFile(path string) (*encryption.Keyring, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return encryption.LoadKeyringFile(path)
+}
@@ -18,6 +18,7 @@ func NewCommand() *cobra.Command {
 		NewExportBlocksCommand(),
 		NewExportIndexCommand(),
 		NewReportTSMCommand(),
+		NewReportTiersCommand(),
 		NewVerifyTSMCommand(),
 		NewVerifyWALCommand(),
 		NewReportTSICommand(),
@@ -0,0 +1,151 @@
+package inspect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+// reportTiersFlags defines the `report-tiers` Command.
+var reportTiersFlags = struct {
+	dataDir string
+	coldDir string
+	keyFile string
+}{}
+
+func NewReportTiersCommand() *cobra.Command {
+	reportTiersCommand := &cobra.Command{
+		Use:   "report-tiers",
+		Short: "Report the placement of TSM files across storage tiers",
+		Long: `
+This command reports which storage tier each TSM file of a storage engine is
+placed in: the hot tier, the engine's data directory, or the cold tier that
+fully compacted generations are moved to according to the cold tier policy.
+
+For each file, the following is output:
+
+	* The tier holding the file;
+	* The filename;
+	* The compaction level of the file;
+	* The size of the file; and
+	* The min and max timestamp associated with TSM data in the file.
+
+The summary section then outputs the number of files and bytes in each tier.
+
+Encrypted TSM files are read with the keys in the file provided by the
+--encryption-key-file flag.`,
+		RunE: inspectReportTiersF,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(err)
+	}
+	dir = filepath.Join(dir, "engine/data")
+	reportTiersCommand.Flags().StringVarP(&reportTiersFlags.dataDir, "data-dir", "", dir, fmt.Sprintf("use provided data directory (defaults to %s).", dir))
+	reportTiersCommand.Flags().StringVarP(&reportTiersFlags.coldDir, "cold-dir", "", "", "use provided cold tier directory.")
+	reportTiersCommand.Flags().StringVarP(&reportTiersFlags.keyFile, "encryption-key-file", "", "", "file of hex-encoded keys, one per line, to decrypt encrypted TSM files with")
+
+	return reportTiersCommand
+}
+
+// inspectReportTiersF runs the report-tiers tool.
+func inspectReportTiersF(cmd *cobra.Command, args []string) error {
+	keyring, err := loadKeyringFile(reportTiersFlags.keyFile)
+	if err != nil {
+		return err
+	}
+
+	tiers := []struct{ name, dir string }{{tsm1.TierHot, reportTiersFlags.dataDir}}
+	if reportTiersFlags.coldDir != "" {
+		tiers = append(tiers, struct{ name, dir string }{tsm1.TierCold, reportTiersFlags.coldDir})
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 8, 2, 1, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"Tier", "File", "Level", "Size", "Min Time", "Max Time"}, "\t"))
+
+	counts := make(map[string]int, len(tiers))
+	sizes := make(map[string]int64, len(tiers))
+	generations := make(map[int]map[string]struct{})
+	for _, tier := range tiers {
+		files, err := filepath.Glob(filepath.Join(tier.dir, fmt.Sprintf("*.%s", tsm1.TSMFileExtension)))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range files {
+			gen, seq, err := tsm1.DefaultParseFileName(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v. Skipping file.\n", err)
+				continue
+			}
+
+			file, err := os.OpenFile(path, os.O_RDONLY, 0600)
+			if err != nil {
+				return err
+			}
+
+			reader, err := tsm1.NewTSMReader(file, tsm1.WithTSMReaderKeyring(keyring))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s: %v. Skipping file.\n", path, err)
+				file.Close()
+				continue
+			}
+
+			minT, maxT := reader.TimeRange()
+			size := int64(reader.Size())
+			if err := reader.Close(); err != nil {
+				return err
+			}
+
+			level := strconv.Itoa(seq)
+			if seq >= 4 {
+				level = "4+"
+			}
+
+			fmt.Fprintln(tw, strings.Join([]string{
+				tier.name,
+				filepath.Base(path),
+				level,
+				strconv.FormatInt(size, 10),
+				time.Unix(0, minT).UTC().Format(time.RFC3339Nano),
+				time.Unix(0, maxT).UTC().Format(time.RFC3339Nano),
+			}, "\t"))
+
+			counts[tier.name]++
+			sizes[tier.name] += size
+			if generations[gen] == nil {
+				generations[gen] = make(map[string]struct{})
+			}
+			generations[gen][tier.name] = struct{}{}
+		}
+	}
+	tw.Flush()
+
+	// A generation is only in both tiers while it is being moved.
+	var split []int
+	for gen, placed := range generations {
+		if len(placed) > 1 {
+			split = append(split, gen)
+		}
+	}
+	sort.Ints(split)
+
+	fmt.Fprintln(os.Stdout, "\nSummary:")
+	for _, tier := range tiers {
+		fmt.Fprintf(os.Stdout, "  %s: %d files, %d bytes (%s)\n", tier.name, counts[tier.name], sizes[tier.name], tier.dir)
+	}
+	for _, gen := range split {
+		fmt.Fprintf(os.Stdout, "  generation %d has files in more than one tier\n", gen)
+	}
+	return nil
+}
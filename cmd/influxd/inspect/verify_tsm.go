@@ -13,7 +13,8 @@ import (
 // verifyTSMFlags defines the `verify-tsm` Command.
 var verifyTSMFlags = struct {
 	cli.OrgBucket
-	path string
+	path    string
+	keyFile string
 }{}
 
 func NewVerifyTSMCommand() *cobra.Command {
@@ -36,20 +37,31 @@ OPTIONS
 
 An optional organization or organization and bucket may be specified to limit
 the analysis.
+
+Encrypted files are decrypted with the keys in the file provided by the
+--encryption-key-file flag. Files whose key is not provided are listed as not
+verified.
 `,
 		RunE: verifyTSMF,
 	}
 
 	verifyTSMFlags.AddFlags(cmd)
+	cmd.Flags().StringVarP(&verifyTSMFlags.keyFile, "encryption-key-file", "", "", "file of hex-encoded keys, one per line, to decrypt encrypted TSM files with")
 
 	return cmd
 }
 
 func verifyTSMF(cmd *cobra.Command, args []string) error {
+	keyring, err := loadKeyringFile(verifyTSMFlags.keyFile)
+	if err != nil {
+		return err
+	}
+
 	verify := tsm1.VerifyTSM{
 		Stdout:   os.Stdout,
 		OrgID:    verifyTSMFlags.Org,
 		BucketID: verifyTSMFlags.Bucket,
+		Keyring:  keyring,
 	}
 
 	// resolve all pathspecs
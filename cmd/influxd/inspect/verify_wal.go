@@ -26,7 +26,12 @@ For each file, the following is output:
 In the summary section, the following is printed:
 	* The number of WAL files scanned;
 	* The number of WAL entries scanned;
-	* A list of files found to be corrupt`,
+	* A list of files found to be corrupt; and
+	* A list of encrypted files that could not be verified, as their key was
+	  not provided
+
+Files compressed with zstd are verified transparently. Encrypted files are
+decrypted with the keys in the file provided by the --encryption-key-file flag.`,
 		RunE: inspectVerifyWAL,
 	}
 
@@ -36,22 +41,30 @@ In the summary section, the following is printed:
 	}
 	dir = filepath.Join(dir, "engine/wal")
 	verifyWALCommand.Flags().StringVarP(&verifyWALFlags.dataDir, "data-dir", "", dir, fmt.Sprintf("use provided data directory (defaults to %s).", dir))
+	verifyWALCommand.Flags().StringVarP(&verifyWALFlags.keyFile, "encryption-key-file", "", "", "file of hex-encoded keys, one per line, to decrypt encrypted WAL files with")
 
 	return verifyWALCommand
 }
 
 var verifyWALFlags = struct {
 	dataDir string
+	keyFile string
 }{}
 
 // inspectReportTSMF runs the report-tsm tool.
 func inspectVerifyWAL(cmd *cobra.Command, args []string) error {
+	keyring, err := loadKeyringFile(verifyWALFlags.keyFile)
+	if err != nil {
+		return err
+	}
+
 	report := &wal.Verifier{
-		Stderr: os.Stderr,
-		Stdout: os.Stdout,
-		Dir:    verifyWALFlags.dataDir,
+		Stderr:  os.Stderr,
+		Stdout:  os.Stdout,
+		Dir:     verifyWALFlags.dataDir,
+		Keyring: keyring,
 	}
 
-	_, err := report.Run(true)
+	_, err = report.Run(true)
 	return err
 }
@@ -0,0 +1,107 @@
+package launcher_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/cmd/influxd/backup"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/cmd/influxd/restore"
+	"github.com/influxdata/influxdb/http"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLauncher_BackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd_backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	org1 := l.OnBoardOrFail(t, &influxdb.OnboardingRequest{
+		User:     "USER-1",
+		Password: "PASSWORD-1",
+		Org:      "ORG-01",
+		Bucket:   "BUCKET",
+	})
+	org2 := l.OnBoardOrFail(t, &influxdb.OnboardingRequest{
+		User:     "USER-2",
+		Password: "PASSWORD-1",
+		Org:      "ORG-02",
+		Bucket:   "BUCKET",
+	})
+
+	l.WriteOrFail(t, org1, `m,k=v1 f=100i 946684800000000000`)
+	l.WriteOrFail(t, org2, `m,k=v2 f=200i 946684800000000000`)
+
+	backupPath := filepath.Join(dir, "backup")
+	client, err := http.NewHTTPClient(l.URL(), org1.Auth.Token, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backup.Backup(ctx, &http.BackupService{Client: client}, backupPath, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	l.ShutdownOrFail(t, ctx)
+
+	qs := `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z)`
+	exp1 := `,result,table,_start,_stop,_time,_value,_field,_measurement,k` + "\r\n" +
+		`,_result,0,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z,2000-01-01T00:00:00Z,100,f,m,v1` + "\r\n\r\n"
+	exp2 := `,result,table,_start,_stop,_time,_value,_field,_measurement,k` + "\r\n" +
+		`,_result,0,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z,2000-01-01T00:00:00Z,200,f,m,v2` + "\r\n\r\n"
+
+	tests := []struct {
+		name         string
+		orgID        influxdb.ID
+		bucketID     influxdb.ID
+		fullMetadata bool
+		exp2         string
+	}{
+		{
+			name: "restore all",
+			exp2: exp2,
+		},
+		{
+			name:         "restore bucket",
+			orgID:        org1.Org.ID,
+			bucketID:     org1.Bucket.ID,
+			fullMetadata: true,
+			exp2:         "\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := launcher.NewTestLauncher()
+			if err := restore.Restore(restore.Options{
+				BackupPath: backupPath,
+				EnginePath: filepath.Join(r.Path, "engine"),
+				BoltPath:   filepath.Join(r.Path, bolt.DefaultFilename),
+				OrgID:      tt.orgID,
+				BucketID:   tt.bucketID,
+
+				FullMetadata: tt.fullMetadata,
+			}, zaptest.NewLogger(t)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := r.Run(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer r.ShutdownOrFail(t, ctx)
+
+			if got := r.FluxQueryOrFail(t, org1.Org, org1.Auth.Token, qs); !cmp.Equal(got, exp1) {
+				t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, exp1))
+			}
+			if got := r.FluxQueryOrFail(t, org2.Org, org2.Auth.Token, qs); !cmp.Equal(got, tt.exp2) {
+				t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, tt.exp2))
+			}
+		})
+	}
+}
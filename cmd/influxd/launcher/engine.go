@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"sync"
@@ -28,9 +29,12 @@ type Engine interface {
 	readservice.Viewer
 	storage.PointsWriter
 	storage.BucketDeleter
+	storage.BucketInvalidator
 	prom.PrometheusCollector
+	influxdb.BackupService
 
 	SeriesCardinality() int64
+	OrgSeriesCardinality() (map[influxdb.ID]int64, error)
 
 	WithLogger(log *zap.Logger)
 	Open(context.Context) error
@@ -113,6 +117,11 @@ func (t *TemporaryEngine) SeriesCardinality() int64 {
 	return t.engine.SeriesCardinality()
 }
 
+// OrgSeriesCardinality returns the number of series stored by each organization.
+func (t *TemporaryEngine) OrgSeriesCardinality() (map[influxdb.ID]int64, error) {
+	return t.engine.OrgSeriesCardinality()
+}
+
 // DeleteBucketRangePredicate will delete a bucket from the range and predicate.
 func (t *TemporaryEngine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
 	return t.engine.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred)
@@ -124,6 +133,11 @@ func (t *TemporaryEngine) DeleteBucket(ctx context.Context, orgID, bucketID infl
 	return t.engine.DeleteBucket(ctx, orgID, bucketID)
 }
 
+// InvalidateBucket drops the state the engine cached for a bucket.
+func (t *TemporaryEngine) InvalidateBucket(bucketID influxdb.ID) {
+	t.engine.InvalidateBucket(bucketID)
+}
+
 // WithLogger sets the logger on the engine. It must be called before Open.
 func (t *TemporaryEngine) WithLogger(log *zap.Logger) {
 	t.log = log.With(zap.String("service", "temporary_engine"))
@@ -155,6 +169,26 @@ func (t *TemporaryEngine) TagValues(ctx context.Context, orgID, bucketID influxd
 	return t.engine.TagValues(ctx, orgID, bucketID, tagKey, start, end, predicate)
 }
 
+// CreateBackup creates a backup of the time-series data.
+func (t *TemporaryEngine) CreateBackup(ctx context.Context) (int, []string, error) {
+	return t.engine.CreateBackup(ctx)
+}
+
+// FetchBackupFile writes a backup file to w.
+func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	return t.engine.FetchBackupFile(ctx, backupID, backupFile, w)
+}
+
+// DeleteBackup removes a backup.
+func (t *TemporaryEngine) DeleteBackup(ctx context.Context, backupID int) error {
+	return t.engine.DeleteBackup(ctx, backupID)
+}
+
+// InternalBackupPath returns the path of a backup within the temporary directory.
+func (t *TemporaryEngine) InternalBackupPath(backupID int) string {
+	return t.engine.InternalBackupPath(backupID)
+}
+
 // Flush will remove the time-series files and re-open the engine.
 func (t *TemporaryEngine) Flush(ctx context.Context) {
 	if err := t.Close(); err != nil {
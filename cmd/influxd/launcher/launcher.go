@@ -21,6 +21,7 @@ import (
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/cmd/influxd/inspect"
+	"github.com/influxdata/influxdb/downsample"
 	"github.com/influxdata/influxdb/endpoints"
 	"github.com/influxdata/influxdb/gather"
 	"github.com/influxdata/influxdb/http"
@@ -38,19 +39,22 @@ import (
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/readservice"
 	taskbackend "github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/backfill"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	"github.com/influxdata/influxdb/task/backend/executor"
 	"github.com/influxdata/influxdb/task/backend/middleware"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/backend/trigger"
 	"github.com/influxdata/influxdb/telemetry"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
-	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxdb/vault"
 	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -147,7 +151,7 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 		{
 			DestP:   &l.boltPath,
 			Flag:    "bolt-path",
-			Default: filepath.Join(dir, "influxd.bolt"),
+			Default: filepath.Join(dir, bolt.DefaultFilename),
 			Desc:    "path to boltdb database",
 		},
 		{
@@ -173,6 +177,47 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP: &l.StorageConfig.ColdEnginePath,
+			Flag:  "cold-engine-path",
+			Desc:  "path cold TSM files are moved to, typically on a larger and slower disk; disabled if not set",
+		},
+		{
+			DestP:   &l.StorageConfig.WAL.Compression,
+			Flag:    "wal-compression",
+			Default: tsm1.DefaultWALCompression,
+			Desc:    "compression of WAL entries (snappy or zstd)",
+		},
+		{
+			DestP: &l.StorageConfig.WAL.Encryption.KeyFile,
+			Flag:  "wal-encryption-key-file",
+			Desc:  "path to a file of hex-encoded AES-256 keys, one per line, to encrypt WAL segments with; the first key encrypts new segments",
+		},
+		{
+			DestP: &l.StorageConfig.WAL.Encryption.KeySecret,
+			Flag:  "wal-encryption-key-secret",
+			Desc:  "key of the secret holding the keys to encrypt WAL segments with, if not read from a file",
+		},
+		{
+			DestP: &l.StorageConfig.WAL.Encryption.KeySecretOrgID,
+			Flag:  "wal-encryption-key-secret-org-id",
+			Desc:  "id of the organization owning the WAL encryption key secret",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Encryption.KeyFile,
+			Flag:  "engine-encryption-key-file",
+			Desc:  "path to a file of hex-encoded AES-256 keys, one per line, to encrypt TSM and TSI index files with; the first key encrypts new files. TSM tombstone files, TSI log files and the series file are not encrypted",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Encryption.KeySecret,
+			Flag:  "engine-encryption-key-secret",
+			Desc:  "key of the secret holding the keys to encrypt TSM and TSI index files with, if not read from a file",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Encryption.KeySecretOrgID,
+			Flag:  "engine-encryption-key-secret-org-id",
+			Desc:  "id of the organization owning the engine encryption key secret",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
@@ -197,6 +242,18 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.taskRetryBackoff,
+			Flag:    "task-retry-backoff",
+			Default: executor.DefaultRetryBackoff,
+			Desc:    "delay before the first re-attempt of a failed task run, doubling with every further attempt",
+		},
+		{
+			DestP:   &l.taskMaxRetryBackoff,
+			Flag:    "task-max-retry-backoff",
+			Default: executor.DefaultMaxRetryBackoff,
+			Desc:    "longest delay between attempts of a failed task run",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -278,6 +335,9 @@ type Launcher struct {
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
 
+	taskRetryBackoff    time.Duration
+	taskMaxRetryBackoff time.Duration
+
 	logLevel          string
 	tracingType       string
 	reportingDisabled bool
@@ -304,6 +364,7 @@ type Launcher struct {
 
 	scheduler          *scheduler.TreeScheduler
 	executor           *executor.Executor
+	backfiller         *backfill.Backfiller
 	taskControlService taskbackend.TaskControlService
 
 	jaegerTracerCloser io.Closer
@@ -369,6 +430,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.log.Info("Stopping", zap.String("service", "task"))
 
 	m.scheduler.Stop()
+	m.backfiller.Close()
 
 	m.log.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
@@ -481,17 +543,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SessionLength: time.Duration(m.sessionLength) * time.Minute,
 	}
 
+	var (
+		kvStore     kv.Store
+		kvBackupSvc platform.KVBackupService
+	)
+
 	flushers := flushers{}
 	switch m.storeType {
 	case BoltStore:
 		store := bolt.NewKVStore(m.log.With(zap.String("service", "kvstore-bolt")), m.boltPath)
 		store.WithDB(m.boltClient.DB())
+		kvStore, kvBackupSvc = store, store
 		m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), store, serviceConfig)
 		if m.testing {
 			flushers = append(flushers, store)
 		}
 	case MemoryStore:
 		store := inmem.NewKVStore()
+		kvStore = store
 		m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), store, serviceConfig)
 		if m.testing {
 			flushers = append(flushers, store)
@@ -502,8 +571,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
-	if err := m.kvService.Initialize(ctx); err != nil {
-		m.log.Error("Failed to initialize kv service", zap.Error(err))
+	migrator := kv.NewMigrator(m.log.With(zap.String("service", "migrator")), kvStore, m.kvService.Migrations()...)
+	if err := migrator.Initialize(ctx); err != nil {
+		m.log.Error("Failed to initialize kv migrator", zap.Error(err))
+		return err
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		m.log.Error("Failed to apply kv migrations", zap.Error(err))
 		return err
 	}
 
@@ -525,6 +600,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		passwdsSvc                platform.PasswordsService                = m.kvService
 		dashboardSvc              platform.DashboardService                = m.kvService
 		dashboardLogSvc           platform.DashboardOperationLogService    = m.kvService
+		dbrpSvc                   platform.DBRPMappingService              = m.kvService
 		userLogSvc                platform.UserOperationLogService         = m.kvService
 		bucketLogSvc              platform.BucketOperationLogService       = m.kvService
 		orgLogSvc                 platform.OrganizationOperationLogService = m.kvService
@@ -536,6 +612,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		secretSvc                 platform.SecretService                   = m.kvService
 		lookupSvc                 platform.LookupService                   = m.kvService
 		notificationEndpointStore platform.NotificationEndpointService     = m.kvService
+		silenceSvc                platform.SilenceService                  = m.kvService
 	)
 
 	switch m.secretStore {
@@ -562,13 +639,19 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	engineOpts := []storage.Option{
+		storage.WithRetentionEnforcer(bucketSvc),
+		storage.WithBucketSeriesLimits(bucketSvc),
+		storage.WithBucketSchemas(m.kvService),
+		storage.WithSecretService(secretSvc),
+	}
 	if m.testing {
 		// the testing engine will write/read into a temporary directory
-		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		engine := NewTemporaryEngine(m.StorageConfig, engineOpts...)
 		flushers = append(flushers, engine)
 		m.engine = engine
 	} else {
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, engineOpts...)
 	}
 	m.engine.WithLogger(m.log)
 	if err := m.engine.Open(ctx); err != nil {
@@ -581,6 +664,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	var (
 		deleteService platform.DeleteService = m.engine
 		pointsWriter  storage.PointsWriter   = m.engine
+		readStore                            = readservice.NewStore(m.engine)
+		orgLimits                            = quota.NewEnforcer(m.kvService, m.engine.OrgSeriesCardinality)
 	)
 
 	// TODO(cwolff): Figure out a good default per-query memory limit:
@@ -592,7 +677,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	)
 
 	deps, err := influxdb.NewDependencies(
-		reads.NewReader(readservice.NewStore(m.engine)),
+		reads.NewReader(readStore),
 		m.engine,
 		authorizer.NewBucketService(bucketSvc),
 		authorizer.NewOrgService(orgSvc),
@@ -610,6 +695,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		QueueSize:                QueueSize,
 		Logger:                   m.log.With(zap.String("service", "storage-reads")),
 		ExecutorDependencies:     []flux.Dependency{deps},
+		OrgLimitEnforcer:         orgLimits,
 	})
 	if err != nil {
 		m.log.Error("Failed to create query controller", zap.Error(err))
@@ -630,8 +716,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			authSvc,
 			combinedTaskService,
 			combinedTaskService,
+			executor.WithRetryBackoff(m.taskRetryBackoff, m.taskMaxRetryBackoff),
 		)
 		m.executor = executor
+		m.backfiller = backfill.NewBackfiller(m.log.With(zap.String("service", "task-backfill")), combinedTaskService, executor)
+		// Scheduled and backfilled runs share the concurrency option of their task.
+		executor.SetLimitFunc(m.backfiller.ConcurrencyLimit(executor))
 		m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
 		schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
@@ -651,11 +741,19 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 		m.scheduler = sch
 		m.reg.MustRegister(sm.PrometheusCollectors()...)
+		// Tasks depending on other tasks run once their upstream tasks succeed.
+		executor.SetRunSucceededFunc(func(taskID platform.ID, scheduledFor time.Time) {
+			sch.Succeeded(scheduler.ID(taskID), scheduledFor)
+		})
+		// Tasks with a write trigger run soon after points are written to their bucket.
+		writeTrigger := trigger.NewWriteTrigger(m.log.With(zap.String("service", "task-write-trigger")), pointsWriter, executor)
+		pointsWriter = writeTrigger
 		coordLogger := m.log.With(zap.String("service", "task-coordinator"))
 		taskCoord := coordinator.NewCoordinator(
 			coordLogger,
 			sch,
-			executor)
+			executor,
+			coordinator.WithWriteWatcherOpt(writeTrigger))
 
 		taskSvc = middleware.New(combinedTaskService, taskCoord)
 		m.taskControlService = combinedTaskService
@@ -771,13 +869,21 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		ReadStore:            readStore,
 		DeleteService:        deleteService,
 		AuthorizationService: authSvc,
+		BackupService:        m.engine,
+		KVBackupService:      kvBackupSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		BucketService:                   downsample.NewBucketService(storage.NewBucketService(bucketSvc, m.engine), taskSvc),
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
+		BucketSchemaService:             storage.NewBucketSchemaService(m.kvService, m.engine),
+		TaskBackfillService:             m.backfiller,
+		OrgLimitsService:                orgLimits,
+		OrgLimitEnforcer:                orgLimits,
+		UsageService:                    orgLimits,
 		UserResourceMappingService:      userResourceSvc,
 		LabelService:                    labelSvc,
 		DashboardService:                dashboardSvc,
@@ -789,12 +895,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		VariableService:                 variableSvc,
 		PasswordsService:                passwdsSvc,
 		OnboardingService:               onboardingSvc,
-		InfluxQLService:                 nil, // No InfluxQL support
+		DBRPMappingService:              dbrpSvc,
+		InfluxQLService:                 storageQueryService,
 		FluxService:                     storageQueryService,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     endpoints.NewService(notificationEndpointStore, secretSvc, userResourceSvc, orgSvc),
+		SilenceService:                  silenceSvc,
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
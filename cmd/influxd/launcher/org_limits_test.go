@@ -0,0 +1,66 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+)
+
+func TestLauncher_OrgLimits(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	do := func(method, path, body string) (int, []byte) {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(l.NewHTTPRequestOrFail(t, method, path, l.Auth.Token, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, b
+	}
+
+	code, body := do("PUT", fmt.Sprintf("/api/v2/orgs/%s/limits", l.Org.ID), `{"writeRequestsPerSecond":1}`)
+	if code != nethttp.StatusOK {
+		t.Fatalf("unexpected status code %d updating limits: %s", code, body)
+	}
+
+	// Only one write per second is allowed, so one of the writes must be rejected.
+	writePath := fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID)
+	var rejected bool
+	for i := 0; i < 5 && !rejected; i++ {
+		code, body = do("POST", writePath, "m,k=v f=1 0")
+		switch code {
+		case nethttp.StatusNoContent:
+		case nethttp.StatusTooManyRequests:
+			rejected = true
+		default:
+			t.Fatalf("unexpected status code %d writing: %s", code, body)
+		}
+	}
+	if !rejected {
+		t.Fatal("expected a write to be rejected by the org write request limit")
+	}
+
+	code, body = do("GET", fmt.Sprintf("/api/v2/usage?orgID=%s", l.Org.ID), "")
+	if code != nethttp.StatusOK {
+		t.Fatalf("unexpected status code %d getting usage: %s", code, body)
+	}
+	var usage map[influxdb.UsageMetric]influxdb.Usage
+	if err := json.Unmarshal(body, &usage); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := usage[influxdb.UsageWriteRequestsRemaining]; !ok {
+		t.Errorf("expected remaining write requests in usage, got %s", body)
+	}
+}
@@ -0,0 +1,125 @@
+package launcher_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/prometheus/remote"
+)
+
+func TestLauncher_PrometheusRemoteWriteRead(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	post := func(path string, req proto.Message) []byte {
+		t.Helper()
+		b, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := fmt.Sprintf("%s/api/v2/prometheus/%s?org=%s&bucket=%s", l.URL(), path, l.Org.ID, l.Bucket.ID)
+		r, err := nethttp.NewRequest("POST", u, bytes.NewReader(snappy.Encode(nil, b)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Authorization", "Token "+l.Auth.Token)
+		r.Header.Set("Content-Encoding", "snappy")
+
+		resp, err := nethttp.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode/100 != 2 {
+			t.Fatalf("unexpected status code %d: %s", resp.StatusCode, body)
+		}
+		return body
+	}
+
+	post("write", &remote.WriteRequest{
+		Timeseries: []remote.TimeSeries{
+			{
+				Labels:  []remote.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}},
+				Samples: []remote.Sample{{Value: 1, Timestamp: 946684800000}, {Value: 0, Timestamp: 946684801000}},
+			},
+			{
+				Labels:  []remote.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "db"}},
+				Samples: []remote.Sample{{Value: 1, Timestamp: 946684800000}},
+			},
+		},
+	})
+	// Integer values written as line protocol are read back as float samples.
+	l.WritePointsOrFail(t, `up,job=api value=3i 946684800000000000`)
+
+	qs := `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> filter(fn: (r) => r.job == "node")`
+	exp := `,result,table,_start,_stop,_time,_value,_field,_measurement,job` + "\r\n" +
+		`,_result,0,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z,2000-01-01T00:00:00Z,1,value,up,node` + "\r\n" +
+		`,_result,0,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z,2000-01-01T00:00:01Z,0,value,up,node` + "\r\n\r\n"
+	if got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, qs); !cmp.Equal(got, exp) {
+		t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+
+	body := post("read", &remote.ReadRequest{
+		Queries: []remote.Query{
+			{
+				StartTimestampMs: 946684800000,
+				EndTimestampMs:   946684801000,
+				Matchers: []remote.LabelMatcher{
+					{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+					{Type: remote.LabelMatcher_RE, Name: "job", Value: "node|api"},
+				},
+			},
+			{
+				StartTimestampMs: 946684800000,
+				EndTimestampMs:   946684800000,
+				Matchers: []remote.LabelMatcher{
+					{Type: remote.LabelMatcher_NEQ, Name: "job", Value: "db"},
+					{Type: remote.LabelMatcher_NRE, Name: "job", Value: "a.*"},
+				},
+			},
+		},
+	})
+
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got remote.ReadResponse
+	if err := proto.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := func(job string) []remote.Label {
+		return []remote.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: job}}
+	}
+	want := remote.ReadResponse{
+		Results: []remote.QueryResult{
+			{
+				Timeseries: []remote.TimeSeries{
+					{Labels: labels("api"), Samples: []remote.Sample{{Value: 3, Timestamp: 946684800000}}},
+					{Labels: labels("node"), Samples: []remote.Sample{{Value: 1, Timestamp: 946684800000}, {Value: 0, Timestamp: 946684801000}}},
+				},
+			},
+			{
+				Timeseries: []remote.TimeSeries{
+					{Labels: labels("node"), Samples: []remote.Sample{{Value: 1, Timestamp: 946684800000}}},
+				},
+			},
+		},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected read response -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
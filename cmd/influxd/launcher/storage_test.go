@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -91,6 +93,63 @@ func TestLauncher_WriteAndQuery(t *testing.T) {
 	}
 }
 
+func TestLauncher_LegacyWriteAndQuery(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	do := func(req *nethttp.Request, code int) string {
+		t.Helper()
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := resp.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != code {
+			t.Fatalf("unexpected status code: %d, body: %s, headers: %v", resp.StatusCode, body, resp.Header)
+		}
+		return string(body)
+	}
+
+	// Map the 1.x database and retention policy to the bucket.
+	do(l.MustNewHTTPRequest("POST", "/api/v2/dbrps", fmt.Sprintf(
+		`{"cluster":%q,"database":"db0","retention_policy":"autogen","default":true,"organization_id":%q,"bucket_id":%q}`,
+		http.LegacyCluster, l.Org.ID, l.Bucket.ID,
+	)), nethttp.StatusCreated)
+
+	// Write with 1.x style credentials, where the password is the token.
+	req, err := nethttp.NewRequest("POST", l.URL()+"/write?db=db0&precision=s", strings.NewReader(`m,k=v f=100i 946684800`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth(l.User.Name, l.Auth.Token)
+	do(req, nethttp.StatusNoContent)
+
+	req, err = nethttp.NewRequest("GET", l.URL()+"/query?"+url.Values{
+		"db":    {"db0"},
+		"p":     {l.Auth.Token},
+		"epoch": {"s"},
+		"q":     {"SELECT f FROM m WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-02T00:00:00Z'"},
+	}.Encode(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{"results":[{"statement_id":0,"series":[{"name":"m","columns":["time","f"],"values":[[946684800,100]]}]}]}`
+	if got := strings.TrimSpace(do(req, nethttp.StatusOK)); got != exp {
+		t.Errorf("unexpected query results -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
 func TestLauncher_BucketDelete(t *testing.T) {
 	l := launcher.RunTestLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
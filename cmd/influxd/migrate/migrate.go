@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var flags struct {
+	BoltPath string
+}
+
+// NewCommand creates the migrate command and its sub-commands.
+func NewCommand() *cobra.Command {
+	base := &cobra.Command{
+		Use:   "migrate",
+		Short: "Lists, applies and reverts migrations of the bolt metadata store",
+		Long: `These commands manage the versioned migrations of the bolt metadata
+		store. influxd applies pending migrations when it starts; use these
+		commands to inspect or upgrade a bolt file explicitly, or to revert
+		migrations before downgrading. influxd must not be running.
+		`,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+	base.PersistentFlags().StringVar(&flags.BoltPath, "bolt-path", filepath.Join(dir, bolt.DefaultFilename), "Path to the bolt database")
+
+	base.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "Lists all migrations and whether they have been applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return run(func(ctx context.Context, m *kv.Migrator) error {
+					return list(ctx, m, cmd.OutOrStdout())
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "up",
+			Short: "Applies all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return run(func(ctx context.Context, m *kv.Migrator) error {
+					return m.Up(ctx)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Reverts the most recently applied migration",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return run(func(ctx context.Context, m *kv.Migrator) error {
+					return m.Down(ctx)
+				})
+			},
+		},
+	)
+
+	return base
+}
+
+func run(fn func(ctx context.Context, m *kv.Migrator) error) error {
+	if _, err := os.Stat(flags.BoltPath); err != nil {
+		return err
+	}
+
+	log := logger.New(os.Stdout)
+	ctx := context.Background()
+
+	store := bolt.NewKVStore(log.With(zap.String("service", "kvstore-bolt")), flags.BoltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	svc := kv.NewService(log.With(zap.String("store", "kv")), store)
+	m := kv.NewMigrator(log.With(zap.String("service", "migrator")), store, svc.Migrations()...)
+	if err := m.Initialize(ctx); err != nil {
+		return err
+	}
+
+	return fn(ctx, m)
+}
+
+func list(ctx context.Context, m *kv.Migrator, w io.Writer) error {
+	migrations, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 10, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tName\tState\tApplied At")
+	for _, migration := range migrations {
+		var appliedAt string
+		if migration.AppliedAt != nil {
+			appliedAt = migration.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", migration.ID, migration.Name, migration.State, appliedAt)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,74 @@
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/cmd/influxd/migrate"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "influxd_migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, bolt.DefaultFilename)
+	store := bolt.NewKVStore(zaptest.NewLogger(t), path)
+	if err := store.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		cmd := migrate.NewCommand()
+		cmd.SetOutput(&buf)
+		cmd.SetArgs(append(args, "--bolt-path", path))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("influxd migrate %s: %v", strings.Join(args, " "), err)
+		}
+		return buf.String()
+	}
+
+	specs := kv.NewService(zaptest.NewLogger(t), nil).Migrations()
+	first, last := specs[0].MigrationName(), specs[len(specs)-1].MigrationName()
+
+	state := func(out, name string) string {
+		for _, line := range strings.Split(out, "\n") {
+			if parts := strings.SplitN(line, " "+name+" ", 2); len(parts) == 2 {
+				return strings.Fields(parts[1])[0]
+			}
+		}
+		t.Fatalf("migration %q not listed:\n%s", name, out)
+		return ""
+	}
+
+	if out := execute("list"); state(out, first) != "down" || state(out, last) != "down" {
+		t.Fatalf("expected migrations to be down:\n%s", out)
+	}
+
+	execute("up")
+	if out := execute("list"); state(out, first) != "up" || state(out, last) != "up" {
+		t.Fatalf("expected migrations to be up:\n%s", out)
+	}
+
+	// Only the last migration is reverted.
+	execute("down")
+	if out := execute("list"); state(out, first) != "up" || state(out, last) != "down" {
+		t.Fatalf("expected last migration to be down:\n%s", out)
+	}
+}
@@ -0,0 +1,314 @@
+package restore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/influxdb/cmd/influxd/backup"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/pkg/encryption"
+	pkgfs "github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const defaultBatchSize = 10000
+
+var flags struct {
+	// Standard output, overridden for testing.
+	Stdout io.Writer
+
+	EnginePath   string
+	BoltPath     string
+	OrgID        string
+	BucketID     string
+	FullMetadata bool
+	KeyFile      string
+}
+
+// NewCommand creates the restore command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <path>",
+		Short: "Restores a backup into a fresh data directory",
+		Long: `This command restores a backup created by influxd backup into a new
+		engine directory and metadata store. influxd must not be running and the
+		engine path and bolt path must not already exist.
+
+		By default the whole instance is restored. When org-id and bucket-id are
+		given only the time series data of that bucket is restored; the series
+		file and index are rebuilt from the restored data. The metadata store is
+		not restored in that case, since it would bring back every organization,
+		bucket, token and task in the backup, unless full-metadata is set.
+
+		Restoring a single bucket of an engine with encrypted TSM files requires
+		encryption-key-file. The restored TSM and index files are encrypted with
+		its first key.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: runE,
+	}
+
+	flags.Stdout = os.Stdout
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	cmd.Flags().StringVar(&flags.EnginePath, "engine-path", filepath.Join(dir, "engine"), "Path to the engine directory to restore into")
+	cmd.Flags().StringVar(&flags.BoltPath, "bolt-path", filepath.Join(dir, bolt.DefaultFilename), "Path to the bolt database to restore into")
+	cmd.Flags().StringVar(&flags.OrgID, "org-id", "", "Organization ID of the bucket to restore")
+	cmd.Flags().StringVar(&flags.BucketID, "bucket-id", "", "ID of the bucket to restore; restores all buckets if empty")
+	cmd.Flags().BoolVar(&flags.FullMetadata, "full-metadata", false, "Restore the complete metadata store when restoring a single bucket")
+	cmd.Flags().StringVar(&flags.KeyFile, "encryption-key-file", "", "File of hex-encoded keys, one per line, to decrypt and encrypt TSM files with when restoring a single bucket")
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	opts := Options{
+		BackupPath: args[0],
+		EnginePath: flags.EnginePath,
+		BoltPath:   flags.BoltPath,
+
+		FullMetadata: flags.FullMetadata,
+	}
+
+	if flags.KeyFile != "" {
+		keyring, err := encryption.LoadKeyringFile(flags.KeyFile)
+		if err != nil {
+			return err
+		}
+		opts.Keyring = keyring
+	}
+
+	if flags.OrgID != "" || flags.BucketID != "" {
+		orgID, err := influxdb.IDFromString(flags.OrgID)
+		if err != nil {
+			return fmt.Errorf("invalid org-id: %v", err)
+		}
+		bucketID, err := influxdb.IDFromString(flags.BucketID)
+		if err != nil {
+			return fmt.Errorf("invalid bucket-id: %v", err)
+		}
+		opts.OrgID, opts.BucketID = *orgID, *bucketID
+	}
+
+	return Restore(opts, logger.New(flags.Stdout))
+}
+
+// Options configures a restore.
+type Options struct {
+	BackupPath string
+	EnginePath string
+	BoltPath   string
+
+	// OrgID and BucketID limit the restored time series data to a single
+	// bucket. If they are not valid, all data is restored.
+	OrgID    influxdb.ID
+	BucketID influxdb.ID
+
+	// FullMetadata restores the metadata store when restoring a single bucket.
+	// The metadata store is always restored when restoring all data.
+	FullMetadata bool
+
+	// Keyring decrypts the TSM files of a single bucket restore, and encrypts
+	// the restored TSM and index files with its active key. Files are copied as
+	// they are when restoring all data.
+	Keyring *encryption.Keyring
+}
+
+// Restore restores the backup at opts.BackupPath into fresh engine and bolt paths.
+func Restore(opts Options, log *zap.Logger) error {
+	if _, err := backup.ReadManifest(opts.BackupPath); err != nil {
+		return err
+	}
+
+	bucketOnly := opts.OrgID.Valid() && opts.BucketID.Valid()
+	restoreMetadata := !bucketOnly || opts.FullMetadata
+
+	paths := []string{opts.EnginePath}
+	if restoreMetadata {
+		paths = append(paths, opts.BoltPath)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists; restore requires a fresh data directory", path)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(opts.EnginePath, 0777); err != nil {
+		return err
+	}
+
+	var err error
+	if bucketOnly {
+		err = restoreBucket(opts, log)
+	} else {
+		err = restoreAll(opts, log)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !restoreMetadata {
+		log.Info("Skipping metadata for single bucket restore")
+		return nil
+	}
+
+	// A backup of an instance using the in-memory store has no metadata.
+	src := filepath.Join(opts.BackupPath, bolt.DefaultFilename)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		log.Warn("Backup has no metadata, skipping", zap.String("path", src))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	log.Info("Restoring metadata", zap.String("path", opts.BoltPath))
+	if err := os.MkdirAll(filepath.Dir(opts.BoltPath), 0777); err != nil {
+		return err
+	}
+	return pkgfs.CopyFile(src, opts.BoltPath)
+}
+
+// restoreAll copies all time series data, the series file and the index.
+func restoreAll(opts Options, log *zap.Logger) error {
+	for _, dir := range []string{
+		storage.DefaultEngineDirectoryName,
+		storage.DefaultSeriesFileDirectoryName,
+		storage.DefaultIndexDirectoryName,
+	} {
+		log.Info("Restoring directory", zap.String("dir", dir))
+		src, dst := filepath.Join(opts.BackupPath, dir), filepath.Join(opts.EnginePath, dir)
+		if err := pkgfs.CopyDir(src, dst, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreBucket copies the blocks of a single bucket from each TSM file in the
+// backup, then rebuilds the series file and index from the restored files.
+func restoreBucket(opts Options, log *zap.Logger) error {
+	src := filepath.Join(opts.BackupPath, storage.DefaultEngineDirectoryName)
+	dst := filepath.Join(opts.EnginePath, storage.DefaultEngineDirectoryName)
+	if err := os.MkdirAll(dst, 0777); err != nil {
+		return err
+	}
+
+	fis, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	name := tsdb.EncodeName(opts.OrgID, opts.BucketID)
+	for _, fi := range fis {
+		if filepath.Ext(fi.Name()) != "."+tsm1.TSMFileExtension {
+			continue
+		}
+
+		log.Info("Restoring bucket from TSM file", zap.String("path", fi.Name()))
+		if err := filterTSMFile(filepath.Join(src, fi.Name()), dst, name[:], opts.Keyring); err != nil {
+			return fmt.Errorf("failed to restore %s: %v", fi.Name(), err)
+		}
+	}
+
+	sfile := tsdb.NewSeriesFile(filepath.Join(opts.EnginePath, storage.DefaultSeriesFileDirectoryName))
+	sfile.Logger = log
+	if err := sfile.Open(context.Background()); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	return buildtsi.IndexShard(sfile, filepath.Join(opts.EnginePath, storage.DefaultIndexDirectoryName), dst, "",
+		tsi1.DefaultMaxIndexLogFileSize, uint64(tsm1.DefaultCacheMaxMemorySize), defaultBatchSize,
+		opts.Keyring, nil, log, false)
+}
+
+// filterTSMFile writes the blocks of path whose keys start with prefix to a file
+// of the same name in dir, along with the file's tombstones. Blocks are decrypted
+// and encrypted with keyring. No file is written if there are no matching blocks.
+func filterTSMFile(path, dir string, prefix []byte, keyring *encryption.Keyring) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderKeyring(keyring))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	target := filepath.Join(dir, filepath.Base(path))
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w, err := tsm1.NewTSMWriter(out, tsm1.WithEncryption(keyring))
+	if err != nil {
+		return err
+	}
+
+	var n int
+	iter := r.BlockIterator()
+	for iter.Next() {
+		key, minTime, maxTime, _, _, buf, err := iter.Read()
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if err := w.WriteBlock(key, minTime, maxTime, buf); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if n == 0 {
+		if err := out.Close(); err != nil {
+			return err
+		}
+		return os.Remove(target)
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return err
+	} else if err := w.Close(); err != nil {
+		return err
+	}
+
+	// Tombstones are keyed by series and field, so entries for other buckets
+	// are simply never matched.
+	for _, ts := range r.TombstoneFiles() {
+		if err := pkgfs.CopyFile(ts.Path, filepath.Join(dir, filepath.Base(ts.Path))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
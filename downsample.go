@@ -0,0 +1,111 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// DownsampleFunctions are the aggregate functions that can be used to downsample data.
+var DownsampleFunctions = []string{"mean", "median", "min", "max", "sum", "count", "first", "last"}
+
+// DownsampleTier is a downsampled copy of the data written to a bucket. The data
+// is aggregated into windows of Every using the aggregate Function, and written
+// to a bucket of its own that retains it for RetentionPeriod.
+type DownsampleTier struct {
+	Every           time.Duration `json:"every"`
+	Function        string        `json:"fn"`
+	RetentionPeriod time.Duration `json:"retentionPeriod"`
+
+	// BucketID and TaskID identify the bucket holding the downsampled data and
+	// the task writing it. Both are maintained by the server.
+	BucketID ID `json:"bucketID,omitempty"`
+	TaskID   ID `json:"taskID,omitempty"`
+}
+
+// Valid returns an error if the tier is not valid.
+func (t DownsampleTier) Valid() error {
+	if t.Every < time.Second || t.Every%time.Second != 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier window must be a whole number of seconds",
+		}
+	}
+
+	if t.RetentionPeriod < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tier retention period must not be negative",
+		}
+	}
+
+	for _, fn := range DownsampleFunctions {
+		if t.Function == fn {
+			return nil
+		}
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid downsample function %q", t.Function),
+	}
+}
+
+// ValidDownsampleTiers returns an error if any of the tiers of a bucket with the
+// retention period retentionPeriod is not valid. The tiers must be in order of
+// increasing windows, each window must be shorter than the retention period of
+// the bucket so that a window is complete before its data expires, and each tier
+// must retain its data longer than the bucket does.
+func ValidDownsampleTiers(retentionPeriod time.Duration, tiers []DownsampleTier) error {
+	if len(tiers) > 0 && retentionPeriod == InfiniteRetention {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample tiers require the bucket to have a retention period",
+		}
+	}
+
+	for i, t := range tiers {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+		if i > 0 && t.Every <= tiers[i-1].Every {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "downsample tiers must be in order of increasing windows",
+			}
+		}
+		if t.Every >= retentionPeriod {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "downsample tier window must be shorter than the retention period of the bucket",
+			}
+		}
+		if t.RetentionPeriod != InfiniteRetention && t.RetentionPeriod <= retentionPeriod {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "downsample tier retention period must be longer than the retention period of the bucket",
+			}
+		}
+	}
+	return nil
+}
+
+// ReadBucketID returns the ID of the bucket that holds the data of b starting
+// at start. That is b itself while start is within its retention period, and
+// otherwise the first downsampling tier that still retains data from start.
+// If no tier does, the tier with the largest window is used.
+func (b *Bucket) ReadBucketID(start, now time.Time) ID {
+	if b.RetentionPeriod == InfiniteRetention || !start.Before(now.Add(-b.RetentionPeriod)) {
+		return b.ID
+	}
+
+	id := b.ID
+	for _, t := range b.DownsampleTiers {
+		if !t.BucketID.Valid() {
+			continue
+		}
+		id = t.BucketID
+		if t.RetentionPeriod == InfiniteRetention || !start.Before(now.Add(-t.RetentionPeriod)) {
+			break
+		}
+	}
+	return id
+}
@@ -0,0 +1,237 @@
+// Package downsample maintains the downsampling tiers of buckets. Each tier is
+// kept in a bucket of its own, and written by a task that aggregates the data
+// written to the source bucket.
+package downsample
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketService = (*BucketService)(nil)
+
+// BucketService wraps an influxdb.BucketService and creates, updates and deletes
+// the buckets and tasks of the downsampling tiers of buckets along with them.
+type BucketService struct {
+	influxdb.BucketService
+
+	TaskService influxdb.TaskService
+}
+
+// NewBucketService returns a BucketService maintaining the downsampling tiers of
+// the buckets of s using the tasks of ts.
+func NewBucketService(s influxdb.BucketService, ts influxdb.TaskService) *BucketService {
+	return &BucketService{
+		BucketService: s,
+		TaskService:   ts,
+	}
+}
+
+// CreateBucket creates the bucket and its downsampling tiers.
+func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	tiers := b.DownsampleTiers
+	if len(tiers) == 0 {
+		return s.BucketService.CreateBucket(ctx, b)
+	}
+
+	if err := influxdb.ValidDownsampleTiers(b.RetentionPeriod, tiers); err != nil {
+		return err
+	}
+
+	b.DownsampleTiers = nil
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	created, err := s.createTiers(ctx, b, tiers)
+	if err == nil {
+		var ub *influxdb.Bucket
+		if ub, err = s.BucketService.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{DownsampleTiers: &created}); err == nil {
+			*b = *ub
+			return nil
+		}
+	}
+
+	// Remove everything created so far, so that creating the bucket can be retried.
+	s.deleteTiers(ctx, created)
+	s.BucketService.DeleteBucket(ctx, b.ID)
+	return err
+}
+
+// UpdateBucket updates the bucket, creating and deleting the buckets and tasks of
+// downsampling tiers that are added or removed by the update.
+func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if upd.DownsampleTiers == nil && upd.RetentionPeriod == nil {
+		return s.BucketService.UpdateBucket(ctx, id, upd)
+	}
+
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tiers depend on the retention period of the bucket, so they are
+	// validated again when either is updated.
+	retentionPeriod, updTiers := b.RetentionPeriod, b.DownsampleTiers
+	if upd.RetentionPeriod != nil {
+		retentionPeriod = *upd.RetentionPeriod
+	}
+	if upd.DownsampleTiers != nil {
+		updTiers = *upd.DownsampleTiers
+	}
+	if err := influxdb.ValidDownsampleTiers(retentionPeriod, updTiers); err != nil {
+		return nil, err
+	}
+
+	if upd.DownsampleTiers == nil {
+		return s.BucketService.UpdateBucket(ctx, id, upd)
+	}
+
+	// Tiers are identified by their window and function. Tiers that exist already
+	// are kept, with the retention period of their bucket updated.
+	existing := make(map[influxdb.DownsampleTier]influxdb.DownsampleTier, len(b.DownsampleTiers))
+	for _, t := range b.DownsampleTiers {
+		existing[tierKey(t)] = t
+	}
+
+	var tiers, added []influxdb.DownsampleTier
+	for _, t := range *upd.DownsampleTiers {
+		cur, ok := existing[tierKey(t)]
+		if !ok {
+			added = append(added, t)
+			continue
+		}
+		delete(existing, tierKey(t))
+
+		if cur.RetentionPeriod != t.RetentionPeriod {
+			if _, err := s.BucketService.UpdateBucket(ctx, cur.BucketID, influxdb.BucketUpdate{RetentionPeriod: &t.RetentionPeriod}); err != nil {
+				return nil, err
+			}
+		}
+		t.BucketID, t.TaskID = cur.BucketID, cur.TaskID
+		tiers = append(tiers, t)
+	}
+
+	created, err := s.createTiers(ctx, b, added)
+	if err != nil {
+		s.deleteTiers(ctx, created)
+		return nil, err
+	}
+
+	// Keep the tiers in order of increasing windows.
+	tiers = append(tiers, created...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Every < tiers[j].Every
+	})
+
+	upd.DownsampleTiers = &tiers
+	ub, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		s.deleteTiers(ctx, created)
+		return nil, err
+	}
+
+	removed := make([]influxdb.DownsampleTier, 0, len(existing))
+	for _, t := range existing {
+		removed = append(removed, t)
+	}
+	if err := s.deleteTiers(ctx, removed); err != nil {
+		return nil, err
+	}
+	return ub, nil
+}
+
+// DeleteBucket deletes the bucket along with the buckets and tasks of its
+// downsampling tiers.
+func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deleteTiers(ctx, b.DownsampleTiers); err != nil {
+		return err
+	}
+	return s.BucketService.DeleteBucket(ctx, id)
+}
+
+// createTiers creates the bucket and task of each of the tiers of b. The tiers
+// created before any error are returned along with it.
+func (s *BucketService) createTiers(ctx context.Context, b *influxdb.Bucket, tiers []influxdb.DownsampleTier) ([]influxdb.DownsampleTier, error) {
+	if len(tiers) == 0 {
+		return nil, nil
+	}
+
+	// The tasks run with the permissions of the user managing the bucket.
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]influxdb.DownsampleTier, 0, len(tiers))
+	for _, t := range tiers {
+		tb := &influxdb.Bucket{
+			OrgID:           b.OrgID,
+			Type:            influxdb.BucketTypeUser,
+			Name:            tierBucketName(b.Name, t),
+			Description:     "Downsampled data of bucket " + b.Name,
+			RetentionPeriod: t.RetentionPeriod,
+		}
+		if err := s.BucketService.CreateBucket(ctx, tb); err != nil {
+			return created, err
+		}
+		t.BucketID = tb.ID
+
+		task, err := s.TaskService.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           GenerateFlux(b, t),
+			Description:    "Maintains the downsampled data of bucket " + b.Name,
+			Status:         influxdb.TaskStatusActive,
+			OrganizationID: b.OrgID,
+			OwnerID:        auth.GetUserID(),
+		})
+		if err != nil {
+			s.BucketService.DeleteBucket(ctx, tb.ID)
+			return created, err
+		}
+		t.TaskID = task.ID
+
+		created = append(created, t)
+	}
+	return created, nil
+}
+
+// deleteTiers deletes the task and bucket of each of the tiers. Tasks and buckets
+// that no longer exist are skipped.
+func (s *BucketService) deleteTiers(ctx context.Context, tiers []influxdb.DownsampleTier) error {
+	for _, t := range tiers {
+		if t.TaskID.Valid() {
+			if err := s.TaskService.DeleteTask(ctx, t.TaskID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+		}
+		if t.BucketID.Valid() {
+			if err := s.BucketService.DeleteBucket(ctx, t.BucketID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tierKey returns the fields identifying the tier t.
+func tierKey(t influxdb.DownsampleTier) influxdb.DownsampleTier {
+	return influxdb.DownsampleTier{Every: t.Every, Function: t.Function}
+}
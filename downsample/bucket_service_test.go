@@ -0,0 +1,270 @@
+package downsample_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/downsample"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) (*downsample.BucketService, *kv.Service, context.Context, influxdb.ID) {
+	t.Helper()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: user.ID, OrgID: org.ID})
+	return downsample.NewBucketService(svc, svc), svc, ctx, org.ID
+}
+
+func TestBucketService_CreateBucket(t *testing.T) {
+	s, svc, ctx, orgID := newTestService(t)
+
+	b := &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            "telegraf",
+		RetentionPeriod: 7 * 24 * time.Hour,
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "mean", RetentionPeriod: 90 * 24 * time.Hour},
+			{Every: time.Hour, Function: "mean"},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.DownsampleTiers) != 2 {
+		t.Fatalf("got %d tiers, want 2", len(b.DownsampleTiers))
+	}
+
+	wantNames := []string{"telegraf_mean_1m", "telegraf_mean_1h"}
+	for i, tier := range b.DownsampleTiers {
+		tb, err := svc.FindBucketByID(ctx, tier.BucketID)
+		if err != nil {
+			t.Fatalf("tier %d bucket: %v", i, err)
+		}
+		if tb.Name != wantNames[i] {
+			t.Errorf("tier %d: got bucket name %q, want %q", i, tb.Name, wantNames[i])
+		}
+		if tb.RetentionPeriod != tier.RetentionPeriod {
+			t.Errorf("tier %d: got retention period %v, want %v", i, tb.RetentionPeriod, tier.RetentionPeriod)
+		}
+
+		task, err := svc.FindTaskByID(ctx, tier.TaskID)
+		if err != nil {
+			t.Fatalf("tier %d task: %v", i, err)
+		}
+		if !strings.Contains(task.Flux, "to(bucketID: \""+tier.BucketID.String()) {
+			t.Errorf("tier %d: task does not write to the tier bucket:\n%s", i, task.Flux)
+		}
+		if task.Status != string(influxdb.TaskStatusActive) {
+			t.Errorf("tier %d: got task status %q, want %q", i, task.Status, influxdb.TaskStatusActive)
+		}
+	}
+
+	// The tiers are stored with the bucket.
+	got, err := svc.FindBucketByID(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.DownsampleTiers) != 2 || got.DownsampleTiers[0] != b.DownsampleTiers[0] {
+		t.Errorf("got stored tiers %+v, want %+v", got.DownsampleTiers, b.DownsampleTiers)
+	}
+}
+
+func TestBucketService_CreateBucketInvalidTiers(t *testing.T) {
+	s, svc, ctx, orgID := newTestService(t)
+
+	b := &influxdb.Bucket{
+		OrgID: orgID,
+		Name:  "telegraf",
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "stddev"},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v, want code %q", err, influxdb.EInvalid)
+	}
+
+	if _, err := svc.FindBucketByName(ctx, orgID, "telegraf"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected bucket not to be created, got error %v", err)
+	}
+}
+
+func TestBucketService_UpdateBucket(t *testing.T) {
+	s, svc, ctx, orgID := newTestService(t)
+
+	b := &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            "telegraf",
+		RetentionPeriod: 7 * 24 * time.Hour,
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "mean", RetentionPeriod: 90 * 24 * time.Hour},
+			{Every: time.Hour, Function: "mean"},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	kept, removed := b.DownsampleTiers[0], b.DownsampleTiers[1]
+
+	// Keep the 1m tier with a shorter retention period, remove the 1h tier and
+	// add a 1d tier.
+	tiers := []influxdb.DownsampleTier{
+		{Every: time.Minute, Function: "mean", RetentionPeriod: 30 * 24 * time.Hour},
+		{Every: 24 * time.Hour, Function: "max"},
+	}
+	ub, err := s.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{DownsampleTiers: &tiers})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ub.DownsampleTiers) != 2 {
+		t.Fatalf("got %d tiers, want 2", len(ub.DownsampleTiers))
+	}
+	first, second := ub.DownsampleTiers[0], ub.DownsampleTiers[1]
+	if first.BucketID != kept.BucketID || first.TaskID != kept.TaskID {
+		t.Errorf("expected the 1m tier to be kept, got %+v", first)
+	}
+	if second.Every != 24*time.Hour || !second.BucketID.Valid() || !second.TaskID.Valid() {
+		t.Errorf("expected the 1d tier to be created, got %+v", second)
+	}
+
+	tb, err := svc.FindBucketByID(ctx, kept.BucketID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tb.RetentionPeriod != 30*24*time.Hour {
+		t.Errorf("got tier retention period %v, want %v", tb.RetentionPeriod, 30*24*time.Hour)
+	}
+
+	if _, err := svc.FindBucketByID(ctx, removed.BucketID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected removed tier bucket to be deleted, got error %v", err)
+	}
+	if _, err := svc.FindTaskByID(ctx, removed.TaskID); err == nil {
+		t.Errorf("expected removed tier task to be deleted")
+	}
+}
+
+func TestBucketService_UpdateBucketRetentionPeriod(t *testing.T) {
+	s, svc, ctx, orgID := newTestService(t)
+
+	b := &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            "telegraf",
+		RetentionPeriod: 7 * 24 * time.Hour,
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "mean", RetentionPeriod: 30 * 24 * time.Hour},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tier must retain its data longer than the bucket.
+	retentionPeriod := 90 * 24 * time.Hour
+	if _, err := s.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{RetentionPeriod: &retentionPeriod}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v, want code %q", err, influxdb.EInvalid)
+	}
+
+	got, err := svc.FindBucketByID(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RetentionPeriod != 7*24*time.Hour {
+		t.Errorf("got retention period %v, want %v", got.RetentionPeriod, 7*24*time.Hour)
+	}
+}
+
+func TestBucketService_DeleteBucket(t *testing.T) {
+	s, svc, ctx, orgID := newTestService(t)
+
+	b := &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            "telegraf",
+		RetentionPeriod: 7 * 24 * time.Hour,
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "mean"},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	tier := b.DownsampleTiers[0]
+
+	if err := s.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []influxdb.ID{b.ID, tier.BucketID} {
+		if _, err := svc.FindBucketByID(ctx, id); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Errorf("expected bucket %s to be deleted, got error %v", id, err)
+		}
+	}
+	if _, err := svc.FindTaskByID(ctx, tier.TaskID); err == nil {
+		t.Errorf("expected tier task to be deleted")
+	}
+}
+
+func TestGenerateFlux(t *testing.T) {
+	src := &influxdb.Bucket{ID: 1, OrgID: 2, Name: "telegraf"}
+
+	tests := []struct {
+		name string
+		tier influxdb.DownsampleTier
+		want string
+	}{
+		{
+			name: "numeric function",
+			tier: influxdb.DownsampleTier{Every: time.Minute, Function: "mean", BucketID: 3},
+			want: `import "influxdata/influxdb/types"
+
+option task = {name: "Downsample telegraf to telegraf_mean_1m", every: 60s, offset: 60s}
+
+from(bucketID: "0000000000000001", downsampleTiers: false)
+	|> range(start: -60s)
+	|> filter(fn: (r) =>
+		(types.isNumeric(v: r._value)))
+	|> aggregateWindow(every: 60s, fn: mean)
+	|> to(bucketID: "0000000000000003", orgID: "0000000000000002")`,
+		},
+		{
+			name: "any type function",
+			tier: influxdb.DownsampleTier{Every: time.Hour, Function: "last", BucketID: 3},
+			want: `option task = {name: "Downsample telegraf to telegraf_last_1h", every: 3600s, offset: 60s}
+
+from(bucketID: "0000000000000001", downsampleTiers: false)
+	|> range(start: -3600s)
+	|> aggregateWindow(every: 3600s, fn: last)
+	|> to(bucketID: "0000000000000003", orgID: "0000000000000002")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := downsample.GenerateFlux(src, tt.tier); got != tt.want {
+				t.Errorf("unexpected script:\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
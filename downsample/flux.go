@@ -0,0 +1,98 @@
+package downsample
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// tierBucketName returns the name of the bucket holding the data of tier t of
+// the bucket named name, such as "telegraf_mean_1m".
+func tierBucketName(name string, t influxdb.DownsampleTier) string {
+	return fmt.Sprintf("%s_%s_%s", name, t.Function, formatDuration(t.Every))
+}
+
+// taskOffset delays the runs of the downsampling tasks past the end of their
+// window, so that the points written shortly after the window closed are
+// still downsampled.
+const taskOffset = time.Minute
+
+// anyTypeFunctions are the downsample functions that aggregate values of any
+// type. The other functions only aggregate numeric values.
+var anyTypeFunctions = map[string]bool{
+	"first": true,
+	"last":  true,
+	"count": true,
+}
+
+// GenerateFlux returns the script of the task that downsamples the data of the
+// bucket src into the bucket of tier t. Every run aggregates the window of data
+// written since the previous run. Functions aggregating numeric values skip the
+// string and boolean fields. The source bucket is always read itself, so that a
+// late run never reads the data of a tier, possibly its own, instead.
+func GenerateFlux(src *influxdb.Bucket, t influxdb.DownsampleTier) string {
+	every := flux.Duration(int64(t.Every/time.Second), "s")
+
+	task := flux.DefineTaskOption(flux.Object(
+		flux.Property("name", flux.String(fmt.Sprintf("Downsample %s to %s", src.Name, tierBucketName(src.Name, t)))),
+		flux.Property("every", every),
+		flux.Property("offset", flux.Duration(int64(taskOffset/time.Second), "s")),
+	))
+
+	calls := []*ast.CallExpression{
+		flux.Call(flux.Identifier("range"), flux.Object(
+			flux.Property("start", flux.Negative(every)),
+		)),
+	}
+	var imports []*ast.ImportDeclaration
+	if !anyTypeFunctions[t.Function] {
+		imports = flux.Imports("influxdata/influxdb/types")
+		calls = append(calls, flux.Call(flux.Identifier("filter"), flux.Object(
+			flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.Call(
+					flux.Member("types", "isNumeric"),
+					flux.Object(flux.Property("v", flux.Member("r", "_value"))),
+				),
+			)),
+		)))
+	}
+	calls = append(calls,
+		flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+			flux.Property("every", every),
+			flux.Property("fn", flux.Identifier(t.Function)),
+		)),
+		flux.Call(flux.Identifier("to"), flux.Object(
+			flux.Property("bucketID", flux.String(t.BucketID.String())),
+			flux.Property("orgID", flux.String(src.OrgID.String())),
+		)),
+	)
+
+	pipe := flux.Pipe(
+		flux.Call(flux.Identifier("from"), flux.Object(
+			flux.Property("bucketID", flux.String(src.ID.String())),
+			flux.Property("downsampleTiers", flux.Bool(false)),
+		)),
+		calls...,
+	)
+
+	f := flux.File("", imports, []ast.Statement{task, flux.ExpressionStatement(pipe)})
+	return ast.Format(f)
+}
+
+// formatDuration formats d using the largest unit that represents it exactly.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -0,0 +1,161 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestValidDownsampleTiers(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		tiers     []influxdb.DownsampleTier
+		err       string
+	}{
+		{
+			name:      "valid tiers",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Minute, Function: "mean", RetentionPeriod: 90 * 24 * time.Hour},
+				{Every: time.Hour, Function: "mean"},
+			},
+		},
+		{
+			name:      "window shorter than a second",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Millisecond, Function: "mean"},
+			},
+			err: "downsample tier window must be a whole number of seconds",
+		},
+		{
+			name:      "unknown function",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Minute, Function: "stddev"},
+			},
+			err: `invalid downsample function "stddev"`,
+		},
+		{
+			name:      "negative retention period",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Minute, Function: "max", RetentionPeriod: -time.Hour},
+			},
+			err: "downsample tier retention period must not be negative",
+		},
+		{
+			name:      "windows out of order",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Hour, Function: "mean"},
+				{Every: time.Minute, Function: "mean"},
+			},
+			err: "downsample tiers must be in order of increasing windows",
+		},
+		{
+			name:      "infinite bucket retention",
+			retention: influxdb.InfiniteRetention,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Minute, Function: "mean"},
+			},
+			err: "downsample tiers require the bucket to have a retention period",
+		},
+		{
+			name:      "no tiers with infinite bucket retention",
+			retention: influxdb.InfiniteRetention,
+		},
+		{
+			name:      "window longer than bucket retention",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: 30 * day, Function: "mean"},
+			},
+			err: "downsample tier window must be shorter than the retention period of the bucket",
+		},
+		{
+			name:      "tier retention shorter than bucket retention",
+			retention: 7 * day,
+			tiers: []influxdb.DownsampleTier{
+				{Every: time.Minute, Function: "mean", RetentionPeriod: day},
+			},
+			err: "downsample tier retention period must be longer than the retention period of the bucket",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := influxdb.ValidDownsampleTiers(tt.retention, tt.tiers)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q", tt.err)
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.err {
+				t.Errorf("got error %q, want %q", got, tt.err)
+			}
+			if got := influxdb.ErrorCode(err); got != influxdb.EInvalid {
+				t.Errorf("got error code %q, want %q", got, influxdb.EInvalid)
+			}
+		})
+	}
+}
+
+func TestBucket_ReadBucketID(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	b := &influxdb.Bucket{
+		ID:              1,
+		RetentionPeriod: 7 * day,
+		DownsampleTiers: []influxdb.DownsampleTier{
+			{Every: time.Minute, Function: "mean", RetentionPeriod: 90 * day, BucketID: 2},
+			{Every: time.Hour, Function: "mean", BucketID: 3},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  influxdb.ID
+	}{
+		{
+			name:  "within raw retention",
+			start: now.Add(-time.Hour),
+			want:  1,
+		},
+		{
+			name:  "within first tier retention",
+			start: now.Add(-30 * day),
+			want:  2,
+		},
+		{
+			name:  "beyond first tier retention",
+			start: now.Add(-365 * day),
+			want:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.ReadBucketID(tt.start, now); got != tt.want {
+				t.Errorf("got bucket %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no tiers", func(t *testing.T) {
+		b := &influxdb.Bucket{ID: 1, RetentionPeriod: day}
+		if got := b.ReadBucketID(now.Add(-365*day), now); got != 1 {
+			t.Errorf("got bucket %s, want %s", got, influxdb.ID(1))
+		}
+	})
+}
@@ -24,6 +24,8 @@ const (
 	ETooManyRequests     = "too many requests"
 	EUnauthorized        = "unauthorized"
 	EMethodNotAllowed    = "method not allowed"
+	ETimeout             = "timeout"            // operation took longer than allowed
+	EResourceExhausted   = "resource exhausted" // operation needed more resources than allowed
 )
 
 // Error is the error struct of platform.
@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -35,15 +36,23 @@ type APIBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	ReadStore                       reads.Store
 	DeleteService                   influxdb.DeleteService
 	AuthorizationService            influxdb.AuthorizationService
+	BackupService                   influxdb.BackupService
+	KVBackupService                 influxdb.KVBackupService
 	BucketService                   influxdb.BucketService
+	BucketSchemaService             influxdb.BucketSchemaService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
+	OrgLimitsService                influxdb.OrgLimitsService
+	OrgLimitEnforcer                influxdb.OrgLimitEnforcer
+	UsageService                    influxdb.UsageService
 	UserResourceMappingService      influxdb.UserResourceMappingService
 	LabelService                    influxdb.LabelService
 	DashboardService                influxdb.DashboardService
+	DBRPMappingService              influxdb.DBRPMappingService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
 	BucketOperationLogService       influxdb.BucketOperationLogService
 	UserOperationLogService         influxdb.UserOperationLogService
@@ -55,6 +64,7 @@ type APIBackend struct {
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
 	TaskService                     influxdb.TaskService
+	TaskBackfillService             influxdb.TaskBackfillService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -65,6 +75,7 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	SilenceService                  influxdb.SilenceService
 }
 
 // PrometheusCollectors exposes the prometheus collectors associated with an APIBackend.
@@ -116,8 +127,13 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	authorizationBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
 	h.Mount(prefixAuthorization, NewAuthorizationHandler(b.Logger, authorizationBackend))
 
+	backupBackend := NewBackupBackend(b.Logger.With(zap.String("handler", "backup")), b)
+	backupBackend.BackupService = authorizer.NewBackupService(backupBackend.BackupService)
+	h.Mount(prefixBackup, NewBackupHandler(b.Logger, backupBackend))
+
 	bucketBackend := NewBucketBackend(b.Logger.With(zap.String("handler", "bucket")), b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.BucketSchemaService = authorizer.NewBucketSchemaService(b.BucketSchemaService, b.BucketService)
 	h.Mount(prefixBuckets, NewBucketHandler(b.Logger, bucketBackend))
 
 	checkBackend := NewCheckBackend(b.Logger.With(zap.String("handler", "check")), b)
@@ -131,6 +147,10 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
 	h.Mount(prefixDashboards, NewDashboardHandler(b.Logger, dashboardBackend))
 
+	dbrpBackend := NewDBRPMappingBackend(b.Logger.With(zap.String("handler", "dbrp")), b)
+	dbrpBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	h.Mount(prefixDBRPs, NewDBRPMappingHandler(b.Logger, dbrpBackend))
+
 	deleteBackend := NewDeleteBackend(b.Logger.With(zap.String("handler", "delete")), b)
 	h.Mount(prefixDelete, NewDeleteHandler(b.Logger, deleteBackend))
 
@@ -152,8 +172,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		b.UserResourceMappingService, b.OrganizationService)
 	h.Mount(prefixNotificationRules, NewNotificationRuleHandler(b.Logger, notificationRuleBackend))
 
+	prometheusBackend := NewPrometheusBackend(b.Logger.With(zap.String("handler", "prometheus")), b)
+	h.Mount(prefixPrometheus, NewPrometheusHandler(b.Logger, prometheusBackend))
+
 	orgBackend := NewOrgBackend(b.Logger.With(zap.String("handler", "org")), b)
 	orgBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	orgBackend.OrgLimitsService = authorizer.NewOrgLimitsService(b.OrgLimitsService)
 	h.Mount(prefixOrganizations, NewOrgHandler(b.Logger, orgBackend))
 
 	scraperBackend := NewScraperBackend(b.Logger.With(zap.String("handler", "scraper")), b)
@@ -178,6 +202,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount("/api/v2/swagger.json", newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler))
 
 	taskBackend := NewTaskBackend(b.Logger.With(zap.String("handler", "task")), b)
+	taskBackend.TaskBackfillService = authorizer.NewTaskBackfillService(b.TaskBackfillService, b.TaskService)
 	taskHandler := NewTaskHandler(b.Logger, taskBackend)
 	taskHandler.UserResourceMappingService = internalURM
 	h.Mount(prefixTasks, taskHandler)
@@ -187,6 +212,10 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixTelegrafPlugins, NewTelegrafHandler(b.Logger, telegrafBackend))
 	h.Mount(prefixTelegraf, NewTelegrafHandler(b.Logger, telegrafBackend))
 
+	usageHandler := NewUsageHandler(b.Logger.With(zap.String("handler", "usage")), b.HTTPErrorHandler)
+	usageHandler.UsageService = authorizer.NewUsageService(b.UsageService)
+	h.Mount(prefixUsage, usageHandler)
+
 	userBackend := NewUserBackend(b.Logger.With(zap.String("handler", "user")), b)
 	userBackend.UserService = authorizer.NewUserService(b.UserService)
 	userBackend.PasswordsService = authorizer.NewPasswordService(b.PasswordsService)
@@ -194,6 +223,10 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixMe, userHandler)
 	h.Mount(prefixUsers, userHandler)
 
+	silenceBackend := NewSilenceBackend(b.Logger.With(zap.String("handler", "silence")), b)
+	silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+	h.Mount(prefixSilences, NewSilenceHandler(b.Logger, silenceBackend))
+
 	variableBackend := NewVariableBackend(b.Logger.With(zap.String("handler", "variable")), b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.Mount(prefixVariables, NewVariableHandler(b.Logger, variableBackend))
@@ -201,6 +234,13 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	writeBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "write")), b)
 	h.Mount(prefixWrite, NewWriteHandler(b.Logger, writeBackend))
 
+	legacyWriteBackend := NewLegacyWriteBackend(b.Logger.With(zap.String("handler", "legacy_write")), b)
+	h.Mount(prefixLegacyWrite, NewLegacyWriteHandler(b.Logger, legacyWriteBackend))
+
+	legacyQueryBackend := NewLegacyQueryBackend(b.Logger.With(zap.String("handler", "legacy_query")), b)
+	legacyQueryBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	h.Mount(prefixLegacyQuery, NewLegacyQueryHandler(b.Logger, legacyQueryBackend))
+
 	for _, o := range opts {
 		o(h)
 	}
@@ -213,6 +253,7 @@ var apiLinks = map[string]interface{}{
 	"authorizations": "/api/v2/authorizations",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"dbrps":          "/api/v2/dbrps",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -230,6 +271,7 @@ var apiLinks = map[string]interface{}{
 	},
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
+	"silences": "/api/v2/silences",
 	"signout":  "/api/v2/signout",
 	"sources":  "/api/v2/sources",
 	"scrapers": "/api/v2/scrapers",
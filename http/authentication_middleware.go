@@ -82,6 +82,8 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	r = legacyAuthRequest(r)
+
 	ctx := r.Context()
 	scheme, err := ProbeAuthScheme(r)
 	if err != nil {
@@ -120,6 +122,34 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	h.Handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// legacyAuthRequest rewrites the credentials accepted by the influxdb 1.x /write
+// and /query endpoints into a token header. The 1.x API has no notion of tokens,
+// so the token is taken from the basic auth password or the p query parameter.
+func legacyAuthRequest(r *http.Request) *http.Request {
+	if r.URL.Path != prefixLegacyWrite && r.URL.Path != prefixLegacyQuery {
+		return r
+	}
+
+	if _, err := GetToken(r); err == nil {
+		return r
+	}
+
+	var token string
+	if _, p, ok := r.BasicAuth(); ok {
+		token = p
+	} else {
+		token = r.URL.Query().Get("p")
+	}
+	if token == "" {
+		return r
+	}
+
+	lr := r.WithContext(r.Context())
+	lr.Header = r.Header.Clone()
+	SetToken(token, lr)
+	return lr
+}
+
 func (h *AuthenticationHandler) isUserActive(ctx context.Context, auth platform.Authorizer) error {
 	u, err := h.UserService.FindUserByID(ctx, auth.GetUserID())
 	if err != nil {
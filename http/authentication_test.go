@@ -248,6 +248,69 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_LegacyCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		username string
+		password string
+		code     int
+	}{
+		{
+			name:     "basic auth password is the token on /write",
+			url:      "http://any.url/write?db=mydb",
+			username: "user",
+			password: "abc123",
+			code:     http.StatusOK,
+		},
+		{
+			name: "p parameter is the token on /query",
+			url:  "http://any.url/query?db=mydb&u=user&p=abc123",
+			code: http.StatusOK,
+		},
+		{
+			name: "wrong token on /query",
+			url:  "http://any.url/query?db=mydb&u=user&p=wrong",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "p parameter is ignored outside of the 1.x endpoints",
+			url:  "http://any.url/api/v2/write?p=abc123",
+			code: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := platformhttp.NewAuthenticationHandler(zaptest.NewLogger(t), platformhttp.ErrorHandler(0))
+			h.AuthorizationService = &mock.AuthorizationService{
+				FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+					if token != "abc123" {
+						return nil, fmt.Errorf("authorization not found")
+					}
+					return &platform.Authorization{}, nil
+				},
+			}
+			h.SessionService = mock.NewSessionService()
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", tt.url, nil)
+			if tt.username != "" {
+				r.SetBasicAuth(tt.username, tt.password)
+			}
+
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("expected status code to be %d got %d", want, got)
+			}
+		})
+	}
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
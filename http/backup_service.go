@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixBackup        = "/api/v2/backup"
+	backupIDParamName   = "backup_id"
+	backupFileParamName = "backup_file"
+	backupFilePath      = prefixBackup + "/:" + backupIDParamName + "/file/*" + backupFileParamName
+	backupIDPath        = prefixBackup + "/:" + backupIDParamName
+)
+
+// BackupBackend is all services and associated parameters required to construct
+// the BackupHandler.
+type BackupBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+}
+
+// NewBackupBackend returns a new instance of BackupBackend.
+func NewBackupBackend(log *zap.Logger, b *APIBackend) *BackupBackend {
+	return &BackupBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+	}
+}
+
+// BackupHandler creates backups of the time series data and metadata and
+// serves the backup files to clients.
+type BackupHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+}
+
+// NewBackupHandler creates a new handler at /api/v2/backup to receive backup requests.
+func NewBackupHandler(log *zap.Logger, b *BackupBackend) *BackupHandler {
+	h := &BackupHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		BackupService:   b.BackupService,
+		KVBackupService: b.KVBackupService,
+	}
+
+	h.HandlerFunc(http.MethodPost, prefixBackup, h.handleCreate)
+	h.HandlerFunc(http.MethodGet, backupFilePath, h.handleFetchFile)
+	h.HandlerFunc(http.MethodDelete, backupIDPath, h.handleDelete)
+	return h
+}
+
+// Backup is the response to a request to create a backup. Files are the names
+// of the backup files, relative to the backup, that can be fetched by the client.
+type Backup struct {
+	ID    int      `json:"id"`
+	Files []string `json:"files"`
+}
+
+func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreate")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, files, err := h.BackupService.CreateBackup(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.KVBackupService != nil {
+		if err := h.backupKV(ctx, id); err != nil {
+			_ = h.BackupService.DeleteBackup(ctx, id)
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "failed to backup metadata",
+				Err:  err,
+			}, w)
+			return
+		}
+		files = append(files, bolt.DefaultFilename)
+	}
+	h.log.Debug("Backup created", zap.Int("backup_id", id), zap.Int("files", len(files)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, Backup{ID: id, Files: files}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// backupKV writes a copy of the metadata store into the backup directory so
+// that it can be fetched like any other backup file.
+func (h *BackupHandler) backupKV(ctx context.Context, id int) error {
+	path := filepath.Join(h.BackupService.InternalBackupPath(id), bolt.DefaultFilename)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := h.KVBackupService.Backup(ctx, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (h *BackupHandler) handleFetchFile(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleFetchFile")
+	defer span.Finish()
+
+	ctx := r.Context()
+	params := httprouter.ParamsFromContext(ctx)
+
+	id, err := decodeBackupID(params.ByName(backupIDParamName))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	file := strings.TrimPrefix(params.ByName(backupFileParamName), "/")
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := h.BackupService.FetchBackupFile(ctx, id, file, w); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+}
+
+func (h *BackupHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleDelete")
+	defer span.Finish()
+
+	ctx := r.Context()
+	params := httprouter.ParamsFromContext(ctx)
+
+	id, err := decodeBackupID(params.ByName(backupIDParamName))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BackupService.DeleteBackup(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Backup deleted", zap.Int("backup_id", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeBackupID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup id %q", s),
+			Err:  err,
+		}
+	}
+	return id, nil
+}
+
+// BackupService is the client implementation of influxdb.BackupService.
+type BackupService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+
+// CreateBackup creates a backup on the server and returns the names of its files.
+// The metadata store is included in the files when the server supports it.
+func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var b Backup
+	err := s.Client.
+		Post(httpc.BodyEmpty, prefixBackup).
+		DecodeJSON(&b).
+		Do(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	return b.ID, b.Files, nil
+}
+
+// FetchBackupFile writes one backup file to w.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Get(prefixBackup, strconv.Itoa(backupID), "file", backupFile).
+		Accept("application/octet-stream").
+		Decode(func(resp *http.Response) error {
+			_, err := io.Copy(w, resp.Body)
+			return err
+		}).
+		Do(ctx)
+}
+
+// DeleteBackup removes the server's copy of a backup.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(prefixBackup, strconv.Itoa(backupID)).
+		Do(ctx)
+}
+
+// InternalBackupPath is not available over HTTP.
+func (s *BackupService) InternalBackupPath(backupID int) string {
+	panic("internal method not implemented here")
+}
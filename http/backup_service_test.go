@@ -0,0 +1,119 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+type kvBackupFunc func(ctx context.Context, w io.Writer) error
+
+func (fn kvBackupFunc) Backup(ctx context.Context, w io.Writer) error {
+	return fn(ctx, w)
+}
+
+func TestBackupService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup_service_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backupPath := filepath.Join(dir, "1")
+	if err := os.MkdirAll(filepath.Join(backupPath, "data"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(backupPath, "data", "000000001-000000001.tsm"), []byte("tsm"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := -1
+	svc := mock.NewBackupService()
+	svc.CreateBackupFn = func(ctx context.Context) (int, []string, error) {
+		return 1, []string{"data/000000001-000000001.tsm"}, nil
+	}
+	svc.InternalBackupPathFn = func(backupID int) string {
+		return filepath.Join(dir, "1")
+	}
+	svc.FetchBackupFileFn = func(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+		if backupID != 1 {
+			return &influxdb.Error{Code: influxdb.ENotFound, Msg: "backup not found"}
+		}
+		f, err := os.Open(filepath.Join(backupPath, filepath.FromSlash(backupFile)))
+		if err != nil {
+			return &influxdb.Error{Code: influxdb.ENotFound, Msg: "backup file not found"}
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	}
+	svc.DeleteBackupFn = func(ctx context.Context, backupID int) error {
+		deleted = backupID
+		return nil
+	}
+
+	backend := &BackupBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		BackupService:    svc,
+		KVBackupService: kvBackupFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "kv")
+			return err
+		}),
+	}
+	server := httptest.NewServer(NewBackupHandler(zaptest.NewLogger(t), backend))
+	defer server.Close()
+
+	client := BackupService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	ctx := context.Background()
+	id, files, err := client.CreateBackup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 1 {
+		t.Errorf("unexpected backup id: got %d want %d", id, 1)
+	}
+	if diff := cmp.Diff(files, []string{"data/000000001-000000001.tsm", bolt.DefaultFilename}); diff != "" {
+		t.Errorf("unexpected backup files -got/+want\n%s", diff)
+	}
+
+	for file, want := range map[string]string{
+		"data/000000001-000000001.tsm": "tsm",
+		bolt.DefaultFilename:           "kv",
+	} {
+		var buf bytes.Buffer
+		if err := client.FetchBackupFile(ctx, id, file, &buf); err != nil {
+			t.Fatalf("failed to fetch %s: %v", file, err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("unexpected contents of %s: got %q want %q", file, got, want)
+		}
+	}
+
+	err = client.FetchBackupFile(ctx, id, "missing", ioutil.Discard)
+	if got, want := influxdb.ErrorCode(err), influxdb.ENotFound; got != want {
+		t.Errorf("unexpected error code fetching missing file: got %q want %q", got, want)
+	}
+
+	if err := client.DeleteBackup(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != id {
+		t.Errorf("expected backup %d to be deleted, got %d", id, deleted)
+	}
+}
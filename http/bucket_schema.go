@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+)
+
+type bucketSchemaResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.BucketSchema
+}
+
+func newBucketSchemaResponse(s *influxdb.BucketSchema) *bucketSchemaResponse {
+	return &bucketSchemaResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/schema", s.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", s.BucketID),
+		},
+		BucketSchema: *s,
+	}
+}
+
+// handleGetBucketSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema route.
+func (h *BucketHandler) handleGetBucketSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.BucketSchemaService.FindBucketSchema(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketSchemaResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePutBucketSchema is the HTTP handler for the PUT /api/v2/buckets/:id/schema route.
+func (h *BucketHandler) handlePutBucketSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePutBucketSchemaRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.BucketSchemaService.UpdateBucketSchema(ctx, req.bucketID, req.schema)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketSchemaResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type putBucketSchemaRequest struct {
+	bucketID influxdb.ID
+	schema   influxdb.BucketSchema
+}
+
+func decodePutBucketSchemaRequest(ctx context.Context, r *http.Request) (*putBucketSchemaRequest, error) {
+	get, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &putBucketSchemaRequest{bucketID: get.BucketID}
+	if err := json.NewDecoder(r.Body).Decode(&req.schema); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if err := req.schema.Valid(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/httprouter"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_handleGetBucketSchema(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name                string
+		BucketSchemaService platform.BucketSchemaService
+		wants               wants
+	}{
+		{
+			name: "get the schema of a bucket",
+			BucketSchemaService: &mock.BucketSchemaService{
+				FindBucketSchemaFn: func(ctx context.Context, bucketID platform.ID) (*platform.BucketSchema, error) {
+					return &platform.BucketSchema{
+						BucketID: bucketID,
+						Measurements: []platform.MeasurementSchema{
+							{
+								Name:   "cpu",
+								Tags:   []string{"host"},
+								Fields: []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}},
+							},
+						},
+					}, nil
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/schema",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "bucketID": "020f755c3c082000",
+  "measurements": [
+    {
+      "name": "cpu",
+      "tags": ["host"],
+      "fields": [{"name": "usage", "type": "float"}]
+    }
+  ]
+}
+`,
+			},
+		},
+		{
+			name: "bucket not found",
+			BucketSchemaService: &mock.BucketSchemaService{
+				FindBucketSchemaFn: func(ctx context.Context, bucketID platform.ID) (*platform.BucketSchema, error) {
+					return nil, &platform.Error{
+						Code: platform.ENotFound,
+						Msg:  "bucket not found",
+					}
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucketBackend := NewMockBucketBackend(t)
+			bucketBackend.HTTPErrorHandler = ErrorHandler(0)
+			bucketBackend.BucketSchemaService = tt.BucketSchemaService
+			h := NewBucketHandler(zaptest.NewLogger(t), bucketBackend)
+
+			r := httptest.NewRequest("GET", "http://any.url", nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "020f755c3c082000"}}))
+
+			w := httptest.NewRecorder()
+
+			h.handleGetBucketSchema(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handleGetBucketSchema() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handleGetBucketSchema(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handleGetBucketSchema() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestService_handlePutBucketSchema(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		wants wants
+	}{
+		{
+			name: "replace the schema of a bucket",
+			body: `{"measurements": [{"name": "cpu", "tags": ["host"], "fields": [{"name": "usage", "type": "float"}]}]}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/buckets/020f755c3c082000/schema",
+    "bucket": "/api/v2/buckets/020f755c3c082000"
+  },
+  "bucketID": "020f755c3c082000",
+  "measurements": [
+    {
+      "name": "cpu",
+      "tags": ["host"],
+      "fields": [{"name": "usage", "type": "float"}]
+    }
+  ]
+}
+`,
+			},
+		},
+		{
+			name: "invalid field type",
+			body: `{"measurements": [{"name": "cpu", "fields": [{"name": "usage", "type": "double"}]}]}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code": "invalid", "message": "invalid field type \"double\""}`,
+			},
+		},
+		{
+			name: "invalid json",
+			body: `{"measurements": {}}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucketBackend := NewMockBucketBackend(t)
+			bucketBackend.HTTPErrorHandler = ErrorHandler(0)
+			h := NewBucketHandler(zaptest.NewLogger(t), bucketBackend)
+
+			r := httptest.NewRequest("PUT", "http://any.url", bytes.NewBufferString(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "020f755c3c082000"}}))
+
+			w := httptest.NewRecorder()
+
+			h.handlePutBucketSchema(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePutBucketSchema() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePutBucketSchema(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePutBucketSchema() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	BucketSchemaService        influxdb.BucketSchemaService
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -42,6 +43,7 @@ func NewBucketBackend(log *zap.Logger, b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		BucketSchemaService:        b.BucketSchemaService,
 	}
 }
 
@@ -57,6 +59,7 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	BucketSchemaService        influxdb.BucketSchemaService
 }
 
 const (
@@ -69,6 +72,7 @@ const (
 	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
 	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
 	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+	bucketsIDSchemaPath    = "/api/v2/buckets/:id/schema"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -84,6 +88,7 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		BucketSchemaService:        b.BucketSchemaService,
 	}
 
 	h.HandlerFunc("POST", prefixBuckets, h.handlePostBucket)
@@ -92,6 +97,8 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
+	h.HandlerFunc("GET", bucketsIDSchemaPath, h.handleGetBucketSchema)
+	h.HandlerFunc("PUT", bucketsIDSchemaPath, h.handlePutBucketSchema)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -132,13 +139,15 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Type                string          `json:"type"`
-	Description         string          `json:"description,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                   influxdb.ID      `json:"id,omitempty"`
+	OrgID                influxdb.ID      `json:"orgID,omitempty"`
+	Type                 string           `json:"type"`
+	Description          string           `json:"description,omitempty"`
+	Name                 string           `json:"name"`
+	RetentionPolicyName  string           `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules       []retentionRule  `json:"retentionRules"`
+	MaxSeriesCardinality int64            `json:"maxSeriesCardinality,omitempty"`
+	DownsampleTiers      []downsampleTier `json:"downsampleTiers,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -148,6 +157,56 @@ type retentionRule struct {
 	EverySeconds int64  `json:"everySeconds"`
 }
 
+// downsampleTier is a downsampling tier of a bucket with durations in seconds.
+type downsampleTier struct {
+	EverySeconds     int64       `json:"everySeconds"`
+	Function         string      `json:"fn"`
+	RetentionSeconds int64       `json:"retentionSeconds"`
+	BucketID         influxdb.ID `json:"bucketID,omitempty"`
+	TaskID           influxdb.ID `json:"taskID,omitempty"`
+}
+
+func newDownsampleTiers(ts []influxdb.DownsampleTier) []downsampleTier {
+	if ts == nil {
+		return nil
+	}
+
+	tiers := make([]downsampleTier, 0, len(ts))
+	for _, t := range ts {
+		tiers = append(tiers, downsampleTier{
+			EverySeconds:     int64(t.Every.Round(time.Second) / time.Second),
+			Function:         t.Function,
+			RetentionSeconds: int64(t.RetentionPeriod.Round(time.Second) / time.Second),
+			BucketID:         t.BucketID,
+			TaskID:           t.TaskID,
+		})
+	}
+	return tiers
+}
+
+func downsampleTiersToInfluxDB(ts []downsampleTier) []influxdb.DownsampleTier {
+	if ts == nil {
+		return nil
+	}
+
+	tiers := make([]influxdb.DownsampleTier, 0, len(ts))
+	for _, t := range ts {
+		tiers = append(tiers, influxdb.DownsampleTier{
+			Every:           time.Duration(t.EverySeconds) * time.Second,
+			Function:        t.Function,
+			RetentionPeriod: time.Duration(t.RetentionSeconds) * time.Second,
+			BucketID:        t.BucketID,
+			TaskID:          t.TaskID,
+		})
+	}
+	return tiers
+}
+
+var errNegativeMaxSeriesCardinality = &influxdb.Error{
+	Code: influxdb.EInvalid,
+	Msg:  "max series cardinality must not be negative",
+}
+
 func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
 	t := time.Duration(rr.EverySeconds) * time.Second
 	if t < time.Second {
@@ -179,14 +238,16 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 	}
 
 	return &influxdb.Bucket{
-		ID:                  b.ID,
-		OrgID:               b.OrgID,
-		Type:                influxdb.ParseBucketType(b.Type),
-		Description:         b.Description,
-		Name:                b.Name,
-		RetentionPolicyName: b.RetentionPolicyName,
-		RetentionPeriod:     d,
-		CRUDLog:             b.CRUDLog,
+		ID:                   b.ID,
+		OrgID:                b.OrgID,
+		Type:                 influxdb.ParseBucketType(b.Type),
+		Description:          b.Description,
+		Name:                 b.Name,
+		RetentionPolicyName:  b.RetentionPolicyName,
+		RetentionPeriod:      d,
+		MaxSeriesCardinality: b.MaxSeriesCardinality,
+		DownsampleTiers:      downsampleTiersToInfluxDB(b.DownsampleTiers),
+		CRUDLog:              b.CRUDLog,
 	}, nil
 }
 
@@ -205,22 +266,26 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 	}
 
 	return &bucket{
-		ID:                  pb.ID,
-		OrgID:               pb.OrgID,
-		Type:                pb.Type.String(),
-		Name:                pb.Name,
-		Description:         pb.Description,
-		RetentionPolicyName: pb.RetentionPolicyName,
-		RetentionRules:      rules,
-		CRUDLog:             pb.CRUDLog,
+		ID:                   pb.ID,
+		OrgID:                pb.OrgID,
+		Type:                 pb.Type.String(),
+		Name:                 pb.Name,
+		Description:          pb.Description,
+		RetentionPolicyName:  pb.RetentionPolicyName,
+		RetentionRules:       rules,
+		MaxSeriesCardinality: pb.MaxSeriesCardinality,
+		DownsampleTiers:      newDownsampleTiers(pb.DownsampleTiers),
+		CRUDLog:              pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name                 *string           `json:"name,omitempty"`
+	Description          *string           `json:"description,omitempty"`
+	RetentionRules       []retentionRule   `json:"retentionRules,omitempty"`
+	MaxSeriesCardinality *int64            `json:"maxSeriesCardinality,omitempty"`
+	DownsampleTiers      *[]downsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -239,11 +304,25 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		}
 	}
 
-	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
-	}, nil
+	if b.MaxSeriesCardinality != nil && *b.MaxSeriesCardinality < 0 {
+		return nil, errNegativeMaxSeriesCardinality
+	}
+
+	upd := &influxdb.BucketUpdate{
+		Name:                 b.Name,
+		Description:          b.Description,
+		RetentionPeriod:      &d,
+		MaxSeriesCardinality: b.MaxSeriesCardinality,
+	}
+
+	if b.DownsampleTiers != nil {
+		tiers := downsampleTiersToInfluxDB(*b.DownsampleTiers)
+		if tiers == nil {
+			tiers = []influxdb.DownsampleTier{}
+		}
+		upd.DownsampleTiers = &tiers
+	}
+	return upd, nil
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -252,9 +331,18 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:                 pb.Name,
+		Description:          pb.Description,
+		RetentionRules:       []retentionRule{},
+		MaxSeriesCardinality: pb.MaxSeriesCardinality,
+	}
+
+	if pb.DownsampleTiers != nil {
+		tiers := newDownsampleTiers(*pb.DownsampleTiers)
+		if tiers == nil {
+			tiers = []downsampleTier{}
+		}
+		up.DownsampleTiers = &tiers
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -346,11 +434,13 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 }
 
 type postBucketRequest struct {
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Name                string          `json:"name"`
-	Description         string          `json:"description"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	OrgID                influxdb.ID      `json:"orgID,omitempty"`
+	Name                 string           `json:"name"`
+	Description          string           `json:"description"`
+	RetentionPolicyName  string           `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules       []retentionRule  `json:"retentionRules"`
+	MaxSeriesCardinality int64            `json:"maxSeriesCardinality,omitempty"`
+	DownsampleTiers      []downsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 func (b postBucketRequest) Validate() error {
@@ -361,6 +451,9 @@ func (b postBucketRequest) Validate() error {
 		}
 
 	}
+	if b.MaxSeriesCardinality < 0 {
+		return errNegativeMaxSeriesCardinality
+	}
 	return nil
 }
 
@@ -377,12 +470,14 @@ func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
 	}
 
 	return &influxdb.Bucket{
-		OrgID:               b.OrgID,
-		Description:         b.Description,
-		Name:                b.Name,
-		Type:                influxdb.BucketTypeUser,
-		RetentionPolicyName: b.RetentionPolicyName,
-		RetentionPeriod:     dur,
+		OrgID:                b.OrgID,
+		Description:          b.Description,
+		Name:                 b.Name,
+		Type:                 influxdb.BucketTypeUser,
+		RetentionPolicyName:  b.RetentionPolicyName,
+		RetentionPeriod:      dur,
+		MaxSeriesCardinality: b.MaxSeriesCardinality,
+		DownsampleTiers:      downsampleTiersToInfluxDB(b.DownsampleTiers),
 	}, err
 }
 
@@ -34,6 +34,7 @@ func NewMockBucketBackend(t *testing.T) *BucketBackend {
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
 		OrganizationService:        mock.NewOrganizationService(),
+		BucketSchemaService:        mock.NewBucketSchemaService(),
 	}
 }
 
@@ -458,6 +459,142 @@ func TestService_handlePostBucket(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 			},
 		},
+		{
+			name: "create a new bucket with max series cardinality",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:                 "hello",
+					OrgID:                platformtesting.MustIDBase16("6f626f7274697320"),
+					MaxSeriesCardinality: 1000,
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/6f626f7274697320",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=6f626f7274697320&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "6f626f7274697320",
+  "type": "user",
+  "name": "hello",
+  "retentionRules": [],
+  "maxSeriesCardinality": 1000,
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "create a new bucket with downsample tiers",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						c.DownsampleTiers[0].BucketID = platformtesting.MustIDBase16("020f755c3c082001")
+						c.DownsampleTiers[0].TaskID = platformtesting.MustIDBase16("020f755c3c082002")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:  "hello",
+					OrgID: platformtesting.MustIDBase16("6f626f7274697320"),
+					DownsampleTiers: []platform.DownsampleTier{
+						{Every: time.Minute, Function: "mean", RetentionPeriod: 90 * 24 * time.Hour},
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/6f626f7274697320",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=6f626f7274697320&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "6f626f7274697320",
+  "type": "user",
+  "name": "hello",
+  "retentionRules": [],
+  "downsampleTiers": [
+    {
+      "everySeconds": 60,
+      "fn": "mean",
+      "retentionSeconds": 7776000,
+      "bucketID": "020f755c3c082001",
+      "taskID": "020f755c3c082002"
+    }
+  ],
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "create a new bucket with negative max series cardinality",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:                 "hello",
+					OrgID:                platformtesting.MustIDBase16("6f626f7274697320"),
+					MaxSeriesCardinality: -1,
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixDBRPs = "/api/v2/dbrps"
+)
+
+// DBRPMappingBackend is all services and associated parameters required to construct
+// the DBRPMappingHandler.
+type DBRPMappingBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingBackend returns a new instance of DBRPMappingBackend.
+func NewDBRPMappingBackend(log *zap.Logger, b *APIBackend) *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		DBRPMappingService: b.DBRPMappingService,
+	}
+}
+
+// DBRPMappingHandler is the handler for the database/retention policy mapping service.
+type DBRPMappingHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+}
+
+// NewDBRPMappingHandler returns a new instance of DBRPMappingHandler.
+func NewDBRPMappingHandler(log *zap.Logger, b *DBRPMappingBackend) *DBRPMappingHandler {
+	h := &DBRPMappingHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		DBRPMappingService: b.DBRPMappingService,
+	}
+
+	h.HandlerFunc("GET", prefixDBRPs, h.handleGetDBRPMappings)
+	h.HandlerFunc("POST", prefixDBRPs, h.handlePostDBRPMapping)
+	h.HandlerFunc("DELETE", prefixDBRPs, h.handleDeleteDBRPMapping)
+	return h
+}
+
+type getDBRPMappingsResponse struct {
+	Mappings []*influxdb.DBRPMapping `json:"dbrps"`
+}
+
+func (h *DBRPMappingHandler) handleGetDBRPMappings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeDBRPMappingFilter(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	mappings, _, err := h.DBRPMappingService.FindMany(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Dbrp mappings retrieved", zap.Int("count", len(mappings)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, getDBRPMappingsResponse{Mappings: mappings}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *DBRPMappingHandler) handlePostDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var m influxdb.DBRPMapping
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := m.Validate(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Create(ctx, &m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Dbrp mapping created", zap.String("database", m.Database), zap.String("retention_policy", m.RetentionPolicy))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, m); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *DBRPMappingHandler) handleDeleteDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qp := r.URL.Query()
+	cluster, db, rp := qp.Get("cluster"), qp.Get("db"), qp.Get("rp")
+	if cluster == "" || db == "" || rp == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "cluster, db and rp are required",
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Delete(ctx, cluster, db, rp); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Dbrp mapping deleted", zap.String("database", db), zap.String("retention_policy", rp))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeDBRPMappingFilter(r *http.Request) (influxdb.DBRPMappingFilter, error) {
+	var filter influxdb.DBRPMappingFilter
+	qp := r.URL.Query()
+	if cluster := qp.Get("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+	if db := qp.Get("db"); db != "" {
+		filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	if def := qp.Get("default"); def != "" {
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "default must be a boolean",
+				Err:  err,
+			}
+		}
+		filter.Default = &b
+	}
+	return filter, nil
+}
+
+// DBRPMappingService connects to Influx via HTTP using tokens to manage dbrp mappings.
+type DBRPMappingService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *DBRPMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	return s.Find(ctx, influxdb.DBRPMappingFilter{
+		Cluster:         &cluster,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+}
+
+// Find returns the first dbrp mapping that matches the filter.
+func (s *DBRPMappingService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	if filter.Cluster == nil && filter.Database == nil && filter.RetentionPolicy == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "no filter parameters provided",
+		}
+	}
+
+	ms, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "dbrp mapping not found",
+		}
+	}
+
+	return ms[0], nil
+}
+
+// FindMany returns a list of dbrp mappings that match the filter and the total count of matching mappings.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	var params [][2]string
+	if filter.Cluster != nil {
+		params = append(params, [2]string{"cluster", *filter.Cluster})
+	}
+	if filter.Database != nil {
+		params = append(params, [2]string{"db", *filter.Database})
+	}
+	if filter.RetentionPolicy != nil {
+		params = append(params, [2]string{"rp", *filter.RetentionPolicy})
+	}
+	if filter.Default != nil {
+		params = append(params, [2]string{"default", strconv.FormatBool(*filter.Default)})
+	}
+
+	var resp getDBRPMappingsResponse
+	err := s.Client.
+		Get(prefixDBRPs).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.Mappings, len(resp.Mappings), nil
+}
+
+// Create creates a new dbrp mapping, if a different mapping exists an error is returned.
+func (s *DBRPMappingService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	return s.Client.
+		PostJSON(m, prefixDBRPs).
+		DecodeJSON(m).
+		Do(ctx)
+}
+
+// Delete removes a dbrp mapping.
+func (s *DBRPMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	return s.Client.
+		Delete(prefixDBRPs).
+		QueryParams(
+			[2]string{"cluster", cluster},
+			[2]string{"db", db},
+			[2]string{"rp", rp},
+		).
+		Do(ctx)
+}
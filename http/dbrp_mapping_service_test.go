@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func initDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Populate(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	dbrpBackend := &DBRPMappingBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		log:                zaptest.NewLogger(t),
+		DBRPMappingService: svc,
+	}
+	handler := NewDBRPMappingHandler(zaptest.NewLogger(t), dbrpBackend)
+	server := httptest.NewServer(handler)
+	client := DBRPMappingService{
+		Client: mustNewHTTPClient(t, server.URL, ""),
+	}
+
+	return &client, server.Close
+}
+
+func TestDBRPMappingService(t *testing.T) {
+	t.Run("CreateDBRPMapping", func(t *testing.T) { influxdbtesting.CreateDBRPMapping(initDBRPMappingService, t) })
+	t.Run("FindDBRPMappingByKey", func(t *testing.T) { influxdbtesting.FindDBRPMappingByKey(initDBRPMappingService, t) })
+	t.Run("FindDBRPMappings", func(t *testing.T) { influxdbtesting.FindDBRPMappings(initDBRPMappingService, t) })
+	t.Run("FindDBRPMapping", func(t *testing.T) { influxdbtesting.FindDBRPMapping(initDBRPMappingService, t) })
+	t.Run("DeleteDBRPMapping", func(t *testing.T) { influxdbtesting.DeleteDBRPMapping(initDBRPMappingService, t) })
+}
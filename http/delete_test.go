@@ -230,7 +230,7 @@ func TestDelete(t *testing.T) {
 			},
 		},
 		{
-			name: "unsupported delete",
+			name: "delete with or and regex",
 			args: args{
 				queryParams: map[string][]string{
 					"org":    []string{"org1"},
@@ -239,7 +239,7 @@ func TestDelete(t *testing.T) {
 				body: []byte(`{
 					"start":"2009-01-01T23:00:00Z",
 					"stop":"2019-11-10T01:00:00Z",
-					"predicate": "tag1=\"v1\" and (tag2=\"v2\" or tag3=\"v3\")"
+					"predicate": "tag1=\"v1\" and (tag2=~/^v2$/ or tag3!~/v3/)"
 				}`),
 				authorizer: &influxdb.Authorization{
 					UserID: user1ID,
@@ -276,11 +276,8 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			wants: wants{
-				statusCode: http.StatusBadRequest,
-				body: `{
-					"code": "invalid",
-					"message": "invalid request; error parsing request json: the logical operator OR is not supported yet at position 25"
-				  }`,
+				statusCode: http.StatusNoContent,
+				body:       ``,
 			},
 		},
 		{
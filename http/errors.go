@@ -113,6 +113,10 @@ func (h ErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.Res
 	}
 	w.Header().Set(PlatformErrorCodeHeader, code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if httpCode == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+		// Quotas are replenished every second.
+		w.Header().Set("Retry-After", "1")
+	}
 	w.WriteHeader(httpCode)
 	var e struct {
 		Code    string `json:"code"`
@@ -157,4 +161,6 @@ var statusCodePlatformError = map[string]int{
 	platform.ETooManyRequests:     http.StatusTooManyRequests,
 	platform.EUnauthorized:        http.StatusUnauthorized,
 	platform.EMethodNotAllowed:    http.StatusMethodNotAllowed,
+	platform.ETimeout:             http.StatusGatewayTimeout,
+	platform.EResourceExhausted:   http.StatusServiceUnavailable,
 }
@@ -60,6 +60,41 @@ func TestEncodeErrorWithError(t *testing.T) {
 	}
 }
 
+func TestEncodeErrorTooManyRequests(t *testing.T) {
+	ctx := context.TODO()
+	err := &influxdb.Error{
+		Code: influxdb.ETooManyRequests,
+		Msg:  "organization has exceeded its limit of 1 write requests per second",
+	}
+
+	w := httptest.NewRecorder()
+
+	http.ErrorHandler(0).HandleHTTPError(ctx, err, w)
+
+	if w.Code != 429 {
+		t.Errorf("expected status code 429, got: %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected Retry-After: 1, got: %q", got)
+	}
+}
+
+func TestEncodeErrorResourceExhausted(t *testing.T) {
+	ctx := context.TODO()
+	err := &influxdb.Error{
+		Code: influxdb.EResourceExhausted,
+		Msg:  "run exceeded its memory limit",
+	}
+
+	w := httptest.NewRecorder()
+
+	http.ErrorHandler(0).HandleHTTPError(ctx, err, w)
+
+	if w.Code != 503 {
+		t.Errorf("expected status code 503, got: %d", w.Code)
+	}
+}
+
 func TestCheckError(t *testing.T) {
 	for _, tt := range []struct {
 		name  string
@@ -0,0 +1,259 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/NYTimes/gziphandler"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/jsonweb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyQuery = "/query"
+)
+
+// LegacyQueryBackend is all services and associated parameters required to construct
+// the LegacyQueryHandler.
+type LegacyQueryBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	QueryEventRecorder metric.EventRecorder
+
+	DBRPMappingService influxdb.DBRPMappingService
+	ProxyQueryService  query.ProxyQueryService
+}
+
+// NewLegacyQueryBackend returns a new instance of LegacyQueryBackend.
+func NewLegacyQueryBackend(log *zap.Logger, b *APIBackend) *LegacyQueryBackend {
+	return &LegacyQueryBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.InfluxQLService,
+	}
+}
+
+// LegacyQueryHandler implements the influxdb 1.x /query endpoint by transpiling
+// InfluxQL into flux and encoding the results in the 1.x JSON response format.
+type LegacyQueryHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	DBRPMappingService influxdb.DBRPMappingService
+	ProxyQueryService  query.ProxyQueryService
+
+	EventRecorder metric.EventRecorder
+}
+
+// Prefix provides the route prefix.
+func (*LegacyQueryHandler) Prefix() string {
+	return prefixLegacyQuery
+}
+
+// NewLegacyQueryHandler returns a new handler at /query for InfluxQL queries.
+func NewLegacyQueryHandler(log *zap.Logger, b *LegacyQueryBackend) *LegacyQueryHandler {
+	h := &LegacyQueryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.ProxyQueryService,
+		EventRecorder:      b.QueryEventRecorder,
+	}
+
+	// query reponses can optionally be gzip encoded
+	qh := gziphandler.GzipHandler(http.HandlerFunc(h.handleQuery))
+	h.Handler("GET", prefixLegacyQuery, qh)
+	h.Handler("POST", prefixLegacyQuery, qh)
+	return h
+}
+
+func (h *LegacyQueryHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleLegacyQuery"
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyQueryHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	log := h.log.With(logger.TraceFields(ctx)...)
+	if id, _, found := tracing.InfoFromContext(ctx); found {
+		w.Header().Set(traceIDHeader, id)
+	}
+
+	var orgID influxdb.ID
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	req, err := decodeLegacyQueryRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID, err = h.findOrgID(ctx, a, req.db, req.rp)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var token *influxdb.Authorization
+	switch a := a.(type) {
+	case *influxdb.Authorization:
+		token = a
+	case *influxdb.Session:
+		token = a.EphemeralAuth(orgID)
+	case *jsonweb.Token:
+		token = a.EphemeralAuth(orgID)
+	default:
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   op,
+			Err:  influxdb.ErrAuthorizerNotSupported,
+		}, w)
+		return
+	}
+
+	// Transform the context into one with the request's authorization.
+	ctx = pcontext.SetAuthorizer(ctx, token)
+
+	compiler := influxql.NewCompiler(h.DBRPMappingService)
+	compiler.Cluster = LegacyCluster
+	compiler.DB = req.db
+	compiler.RP = req.rp
+	compiler.Query = req.query
+
+	pr := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: orgID,
+			Compiler:       compiler,
+			Source:         r.Header.Get("User-Agent"),
+		},
+		Dialect: req.dialect,
+	}
+
+	req.dialect.SetHeaders(w)
+
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, pr); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		log.Info("Error writing response to client",
+			zap.String("handler", "influxql"),
+			zap.Error(err),
+		)
+	}
+}
+
+// findOrgID determines the organization a query runs against. Queries naming a database
+// run against the organization of its mapping; other queries fall back to the organization
+// of the token.
+func (h *LegacyQueryHandler) findOrgID(ctx context.Context, a influxdb.Authorizer, db, rp string) (influxdb.ID, error) {
+	if db != "" {
+		mapping, err := findLegacyDBRPMapping(ctx, h.DBRPMappingService, db, rp)
+		if err != nil {
+			return 0, err
+		}
+		return mapping.OrganizationID, nil
+	}
+
+	if auth, ok := a.(*influxdb.Authorization); ok {
+		return auth.OrgID, nil
+	}
+
+	return 0, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "database is required",
+	}
+}
+
+type legacyQueryRequest struct {
+	db      string
+	rp      string
+	query   string
+	dialect *influxql.Dialect
+}
+
+func decodeLegacyQueryRequest(r *http.Request) (*legacyQueryRequest, error) {
+	q := r.FormValue("q")
+	if q == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  `missing required parameter "q"`,
+		}
+	}
+
+	dialect := &influxql.Dialect{
+		Encoding: influxql.JSON,
+	}
+	if r.FormValue("pretty") == "true" {
+		dialect.Encoding = influxql.JSONPretty
+	}
+
+	switch epoch := r.FormValue("epoch"); epoch {
+	case "":
+		dialect.TimeFormat = influxql.RFC3339Nano
+	case "h":
+		dialect.TimeFormat = influxql.Hour
+	case "m":
+		dialect.TimeFormat = influxql.Minute
+	case "s":
+		dialect.TimeFormat = influxql.Second
+	case "ms":
+		dialect.TimeFormat = influxql.Millisecond
+	case "u", "us":
+		dialect.TimeFormat = influxql.Microsecond
+	case "n", "ns":
+		dialect.TimeFormat = influxql.Nanosecond
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch %q; valid epochs are h, m, s, ms, u, and ns", epoch),
+		}
+	}
+
+	return &legacyQueryRequest{
+		db:      r.FormValue("db"),
+		rp:      r.FormValue("rp"),
+		query:   q,
+		dialect: dialect,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLegacyQueryHandler_handleQuery(t *testing.T) {
+	// want is the expected output of the HTTP endpoint
+	type wants struct {
+		body       string
+		code       int
+		orgID      influxdb.ID
+		db         string
+		rp         string
+		query      string
+		timeFormat influxql.TimeFormat
+	}
+
+	tests := []struct {
+		name       string
+		auth       influxdb.Authorizer
+		params     url.Values
+		mapping    *influxdb.DBRPMapping
+		mappingErr error
+		wants      wants
+	}{
+		{
+			name:    "query runs against the organization of the database",
+			auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			params:  url.Values{"db": {"telegraf"}, "q": {"SELECT * FROM cpu"}},
+			mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			wants: wants{
+				code:       200,
+				body:       `{"results":[]}`,
+				orgID:      testDBRPMapping("043e0780ee2b1000", "04504b356e23b000").OrganizationID,
+				db:         "telegraf",
+				query:      "SELECT * FROM cpu",
+				timeFormat: influxql.RFC3339Nano,
+			},
+		},
+		{
+			name:    "epoch and retention policy are passed through",
+			auth:    bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			params:  url.Values{"db": {"telegraf"}, "rp": {"autogen"}, "epoch": {"ms"}, "q": {"SELECT * FROM cpu"}},
+			mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			wants: wants{
+				code:       200,
+				body:       `{"results":[]}`,
+				orgID:      testDBRPMapping("043e0780ee2b1000", "04504b356e23b000").OrganizationID,
+				db:         "telegraf",
+				rp:         "autogen",
+				query:      "SELECT * FROM cpu",
+				timeFormat: influxql.Millisecond,
+			},
+		},
+		{
+			name:   "missing query returns 400",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			params: url.Values{"db": {"telegraf"}},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"missing required parameter \"q\""}`,
+			},
+		},
+		{
+			name:   "invalid epoch returns 400",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			params: url.Values{"db": {"telegraf"}, "epoch": {"d"}, "q": {"SELECT * FROM cpu"}},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid epoch \"d\"; valid epochs are h, m, s, ms, u, and ns"}`,
+			},
+		},
+		{
+			name:       "unknown database returns 404",
+			auth:       bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			params:     url.Values{"db": {"telegraf"}, "q": {"SELECT * FROM cpu"}},
+			mappingErr: &influxdb.Error{Code: influxdb.ENotFound, Msg: "dbrp mapping not found"},
+			wants: wants{
+				code: 404,
+				body: `{"code":"not found","message":"dbrp mapping not found"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbrps := mock.NewDBRPMappingService()
+			dbrps.FindFn = func(ctx context.Context, f influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+				return tt.mapping, tt.mappingErr
+			}
+			dbrps.FindByFn = func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+				return tt.mapping, tt.mappingErr
+			}
+
+			var got *query.ProxyRequest
+			queries := &querymock.ProxyQueryService{
+				QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+					got = req
+					_, err := io.WriteString(w, `{"results":[]}`)
+					return flux.Statistics{}, err
+				},
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				DBRPMappingService: dbrps,
+				InfluxQLService:    queries,
+				QueryEventRecorder: &metric.NopEventRecorder{},
+			}
+			queryHandler := NewLegacyQueryHandler(zaptest.NewLogger(t), NewLegacyQueryBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(queryHandler, tt.auth)
+
+			r := httptest.NewRequest("GET", "http://localhost:9999/query?"+tt.params.Encode(), nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if tt.wants.code != 200 {
+				return
+			}
+
+			if got.Request.OrganizationID != tt.wants.orgID {
+				t.Errorf("unexpected organization: got %s want %s", got.Request.OrganizationID, tt.wants.orgID)
+			}
+
+			compiler, ok := got.Request.Compiler.(*influxql.Compiler)
+			if !ok {
+				t.Fatalf("unexpected compiler type %T", got.Request.Compiler)
+			}
+			if compiler.Cluster != LegacyCluster || compiler.DB != tt.wants.db || compiler.RP != tt.wants.rp || compiler.Query != tt.wants.query {
+				t.Errorf("unexpected compiler: got %+v", compiler)
+			}
+
+			dialect, ok := got.Dialect.(*influxql.Dialect)
+			if !ok {
+				t.Fatalf("unexpected dialect type %T", got.Dialect)
+			}
+			if dialect.TimeFormat != tt.wants.timeFormat {
+				t.Errorf("unexpected time format: got %v want %v", dialect.TimeFormat, tt.wants.timeFormat)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyWrite = "/write"
+
+	// LegacyCluster is the cluster name used to resolve database and retention policy
+	// pairs sent to the influxdb 1.x compatible endpoints.
+	LegacyCluster = "default"
+)
+
+// LegacyWriteBackend is all services and associated parameters required to construct
+// the LegacyWriteHandler.
+type LegacyWriteBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+
+	PointsWriter       storage.PointsWriter
+	DBRPMappingService influxdb.DBRPMappingService
+	OrgLimitEnforcer   influxdb.OrgLimitEnforcer
+}
+
+// NewLegacyWriteBackend returns a new instance of LegacyWriteBackend.
+func NewLegacyWriteBackend(log *zap.Logger, b *APIBackend) *LegacyWriteBackend {
+	return &LegacyWriteBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+
+		PointsWriter:       b.PointsWriter,
+		DBRPMappingService: b.DBRPMappingService,
+		OrgLimitEnforcer:   b.OrgLimitEnforcer,
+	}
+}
+
+// LegacyWriteHandler receives line protocol on the influxdb 1.x /write endpoint
+// and writes it to the bucket mapped to the requested database and retention policy.
+type LegacyWriteHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PointsWriter       storage.PointsWriter
+	DBRPMappingService influxdb.DBRPMappingService
+	OrgLimitEnforcer   influxdb.OrgLimitEnforcer
+
+	EventRecorder metric.EventRecorder
+}
+
+// Prefix provides the route prefix.
+func (*LegacyWriteHandler) Prefix() string {
+	return prefixLegacyWrite
+}
+
+// NewLegacyWriteHandler creates a new handler at /write to receive line protocol.
+func NewLegacyWriteHandler(log *zap.Logger, b *LegacyWriteBackend) *LegacyWriteHandler {
+	h := &LegacyWriteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PointsWriter:       b.PointsWriter,
+		DBRPMappingService: b.DBRPMappingService,
+		OrgLimitEnforcer:   b.OrgLimitEnforcer,
+		EventRecorder:      b.WriteEventRecorder,
+	}
+
+	h.HandlerFunc("POST", prefixLegacyWrite, h.handleWrite)
+	return h
+}
+
+func (h *LegacyWriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleLegacyWrite"
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyWriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	var requestBytes int
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	in := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		var err error
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   op,
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			}, w)
+			return
+		}
+		defer in.Close()
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLegacyWriteRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	log := h.log.With(zap.String("db", req.Database), zap.String("rp", req.RetentionPolicy))
+
+	mapping, err := findLegacyDBRPMapping(ctx, h.DBRPMappingService, req.Database, req.RetentionPolicy)
+	if err != nil {
+		log.Info("Failed to find dbrp mapping", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID = mapping.OrganizationID
+	span.LogKV("org_id", orgID, "bucket_id", mapping.BucketID)
+
+	p, err := influxdb.NewPermissionAtID(mapping.BucketID, influxdb.WriteAction, influxdb.BucketsResourceType, mapping.OrganizationID)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if !a.Allowed(*p) {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   op,
+			Msg:  "insufficient permissions for write",
+		}, w)
+		return
+	}
+
+	if err := allowWriteRequest(ctx, h.OrgLimitEnforcer, mapping.OrganizationID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		log.Error("Error reading body", zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	requestBytes = len(data)
+	if requestBytes == 0 {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  "writing requires points",
+		}, w)
+		return
+	}
+
+	if err := allowWriteBytes(ctx, h.OrgLimitEnforcer, mapping.OrganizationID, requestBytes); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	encoded := tsdb.EncodeName(mapping.OrganizationID, mapping.BucketID)
+	mm := models.EscapeMeasurement(encoded[:])
+	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
+	if err != nil {
+		log.Error("Error parsing points", zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		log.Error("Error writing points", zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  "unexpected error writing points to database",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type postLegacyWriteRequest struct {
+	Database        string
+	RetentionPolicy string
+	Precision       string
+}
+
+func decodeLegacyWriteRequest(ctx context.Context, r *http.Request) (*postLegacyWriteRequest, error) {
+	qp := r.URL.Query()
+	db := qp.Get("db")
+	if db == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeLegacyWriteRequest",
+			Msg:  "database is required",
+		}
+	}
+
+	// The 1.x API accepts the single letter forms of nanoseconds and microseconds.
+	p := qp.Get("precision")
+	switch p {
+	case "", "n":
+		p = "ns"
+	case "u":
+		p = "us"
+	}
+
+	if !models.ValidPrecision(p) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeLegacyWriteRequest",
+			Msg:  errInvalidPrecision,
+		}
+	}
+
+	return &postLegacyWriteRequest{
+		Database:        db,
+		RetentionPolicy: qp.Get("rp"),
+		Precision:       p,
+	}, nil
+}
+
+// findLegacyDBRPMapping resolves a database and retention policy pair to its mapping.
+// An empty retention policy resolves to the default mapping of the database.
+func findLegacyDBRPMapping(ctx context.Context, s influxdb.DBRPMappingService, db, rp string) (*influxdb.DBRPMapping, error) {
+	cluster := LegacyCluster
+	if rp != "" {
+		return s.FindBy(ctx, cluster, db, rp)
+	}
+
+	isDefault := true
+	return s.Find(ctx, influxdb.DBRPMappingFilter{
+		Cluster:  &cluster,
+		Database: &db,
+		Default:  &isDefault,
+	})
+}
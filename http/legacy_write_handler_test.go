@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	influxtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLegacyWriteHandler_handleWrite(t *testing.T) {
+	// state is the internal state of the dbrp mapping service and points writer
+	type state struct {
+		mapping    *influxdb.DBRPMapping // mapping to return from the dbrp mapping service
+		mappingErr error                 // err to return from the dbrp mapping service
+		writeErr   error                 // err to return from the points writer
+	}
+
+	// want is the expected output of the HTTP endpoint
+	type wants struct {
+		body   string
+		code   int
+		filter influxdb.DBRPMappingFilter
+	}
+
+	// request is sent to the HTTP endpoint
+	type request struct {
+		auth      influxdb.Authorizer
+		db        string
+		rp        string
+		precision string
+		body      string
+	}
+
+	cluster, db, isDefault := LegacyCluster, "telegraf", true
+
+	tests := []struct {
+		name    string
+		request request
+		state   state
+		wants   wants
+	}{
+		{
+			name: "simple body is accepted",
+			request: request{
+				db:   "telegraf",
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				filter: influxdb.DBRPMappingFilter{
+					Cluster:  &cluster,
+					Database: &db,
+					Default:  &isDefault,
+				},
+			},
+		},
+		{
+			name: "retention policy is resolved by key",
+			request: request{
+				db:        "telegraf",
+				rp:        "autogen",
+				precision: "u",
+				body:      "m1,t1=v1 f1=1 1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "missing database returns 400",
+			request: request{
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"database is required"}`,
+			},
+		},
+		{
+			name: "invalid precision returns 400",
+			request: request{
+				db:        "telegraf",
+				precision: "d",
+				body:      "m1,t1=v1 f1=1",
+				auth:      bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid precision; valid precision units are ns, us, ms, and s"}`,
+			},
+		},
+		{
+			name: "unknown database returns 404",
+			request: request{
+				db:   "telegraf",
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mappingErr: &influxdb.Error{Code: influxdb.ENotFound, Msg: "dbrp mapping not found"},
+			},
+			wants: wants{
+				code: 404,
+				body: `{"code":"not found","message":"dbrp mapping not found"}`,
+			},
+		},
+		{
+			name: "points writer error is an internal error",
+			request: request{
+				db:   "telegraf",
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mapping:  testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+				writeErr: fmt.Errorf("error"),
+			},
+			wants: wants{
+				code: 500,
+				body: `{"code":"internal error","message":"unexpected error writing points to database: error"}`,
+			},
+		},
+		{
+			name: "empty request body returns 400 error",
+			request: request{
+				db:   "telegraf",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"writing requires points"}`,
+			},
+		},
+		{
+			name: "forbidden to write with insufficient permission",
+			request: request{
+				db:   "telegraf",
+				body: "m1,t1=v1 f1=1",
+				auth: bucketWritePermission("043e0780ee2b1000", "000000000000000a"),
+			},
+			state: state{
+				mapping: testDBRPMapping("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 403,
+				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter influxdb.DBRPMappingFilter
+			dbrps := mock.NewDBRPMappingService()
+			dbrps.FindFn = func(ctx context.Context, f influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+				filter = f
+				return tt.state.mapping, tt.state.mappingErr
+			}
+			dbrps.FindByFn = func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+				return tt.state.mapping, tt.state.mappingErr
+			}
+
+			b := &APIBackend{
+				HTTPErrorHandler:   DefaultErrorHandler,
+				Logger:             zaptest.NewLogger(t),
+				DBRPMappingService: dbrps,
+				PointsWriter:       &mock.PointsWriter{Err: tt.state.writeErr},
+				WriteEventRecorder: &metric.NopEventRecorder{},
+			}
+			writeHandler := NewLegacyWriteHandler(zaptest.NewLogger(t), NewLegacyWriteBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(writeHandler, tt.request.auth)
+
+			r := httptest.NewRequest(
+				"POST",
+				"http://localhost:9999/write",
+				strings.NewReader(tt.request.body),
+			)
+
+			params := r.URL.Query()
+			params.Set("db", tt.request.db)
+			if tt.request.rp != "" {
+				params.Set("rp", tt.request.rp)
+			}
+			if tt.request.precision != "" {
+				params.Set("precision", tt.request.precision)
+			}
+			r.URL.RawQuery = params.Encode()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if tt.wants.filter.Database != nil {
+				if filter.Database == nil || *filter.Database != *tt.wants.filter.Database ||
+					filter.Cluster == nil || *filter.Cluster != *tt.wants.filter.Cluster ||
+					filter.Default == nil || *filter.Default != *tt.wants.filter.Default {
+					t.Errorf("unexpected dbrp mapping filter: got %v want %v", filter, tt.wants.filter)
+				}
+			}
+		})
+	}
+}
+
+func testDBRPMapping(org, bucket string) *influxdb.DBRPMapping {
+	return &influxdb.DBRPMapping{
+		Cluster:         LegacyCluster,
+		Database:        "telegraf",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  influxtesting.MustIDBase16(org),
+		BucketID:        influxtesting.MustIDBase16(bucket),
+	}
+}
@@ -27,6 +27,7 @@ type OrgBackend struct {
 	SecretService                   influxdb.SecretService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	OrgLimitsService                influxdb.OrgLimitsService
 }
 
 // NewOrgBackend is a datasource used by the org handler.
@@ -41,6 +42,7 @@ func NewOrgBackend(log *zap.Logger, b *APIBackend) *OrgBackend {
 		SecretService:                   b.SecretService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		OrgLimitsService:                b.OrgLimitsService,
 	}
 }
 
@@ -56,6 +58,7 @@ type OrgHandler struct {
 	SecretService                   influxdb.SecretService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	OrgLimitsService                influxdb.OrgLimitsService
 }
 
 const (
@@ -71,6 +74,7 @@ const (
 	organizationsIDSecretsDeletePath = "/api/v2/orgs/:id/secrets/delete"
 	organizationsIDLabelsPath        = "/api/v2/orgs/:id/labels"
 	organizationsIDLabelsIDPath      = "/api/v2/orgs/:id/labels/:lid"
+	organizationsIDLimitsPath        = "/api/v2/orgs/:id/limits"
 )
 
 func checkOrganziationExists(handler *OrgHandler) kithttp.Middleware {
@@ -108,6 +112,7 @@ func NewOrgHandler(log *zap.Logger, b *OrgBackend) *OrgHandler {
 		SecretService:                   b.SecretService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		OrgLimitsService:                b.OrgLimitsService,
 	}
 
 	h.HandlerFunc("POST", prefixOrganizations, h.handlePostOrg)
@@ -146,6 +151,9 @@ func NewOrgHandler(log *zap.Logger, b *OrgBackend) *OrgHandler {
 	// TODO(desa): need a way to specify which secrets to delete. this should work for now
 	h.HandlerFunc("POST", organizationsIDSecretsDeletePath, h.handleDeleteSecrets)
 
+	h.HandlerFunc("GET", organizationsIDLimitsPath, h.handleGetLimits)
+	h.HandlerFunc("PUT", organizationsIDLimitsPath, h.handlePutLimits)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -592,6 +600,96 @@ func decodeDeleteSecretsRequest(ctx context.Context, r *http.Request) (*deleteSe
 	return req, nil
 }
 
+type orgLimitsResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.OrgLimits
+}
+
+func newOrgLimitsResponse(l *influxdb.OrgLimits) *orgLimitsResponse {
+	return &orgLimitsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/orgs/%s/limits", l.OrgID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", l.OrgID),
+		},
+		OrgLimits: *l,
+	}
+}
+
+// handleGetLimits is the HTTP handler for the GET /api/v2/orgs/:id/limits route.
+func (h *OrgHandler) handleGetLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	l, err := h.OrgLimitsService.FindOrgLimits(ctx, req.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgLimitsResponse(l)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePutLimits is the HTTP handler for the PUT /api/v2/orgs/:id/limits route.
+func (h *OrgHandler) handlePutLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePutLimitsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	l, err := h.OrgLimitsService.UpdateOrgLimits(ctx, req.orgID, req.limits)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgLimitsResponse(l)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type putLimitsRequest struct {
+	orgID  influxdb.ID
+	limits influxdb.OrgLimits
+}
+
+func decodePutLimitsRequest(ctx context.Context, r *http.Request) (*putLimitsRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	req := &putLimitsRequest{orgID: i}
+	if err := json.NewDecoder(r.Body).Decode(&req.limits); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	return req, nil
+}
+
 const (
 	organizationPath = "/api/v2/orgs"
 )
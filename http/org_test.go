@@ -29,6 +29,7 @@ func NewMockOrgBackend(t *testing.T) *OrgBackend {
 		SecretService:                   mock.NewSecretService(),
 		LabelService:                    mock.NewLabelService(),
 		UserService:                     mock.NewUserService(),
+		OrgLimitsService:                mock.NewOrgLimitsService(),
 	}
 }
 
@@ -378,3 +379,82 @@ func TestSecretService_handleDeleteSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestOrgHandler_handlePutLimits(t *testing.T) {
+	type args struct {
+		orgID platform.ID
+		body  string
+	}
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "replace limits of org",
+			args: args{
+				orgID: 1,
+				body:  `{"writeRequestsPerSecond":10,"concurrentQueries":2}`,
+			},
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/orgs/0000000000000001/limits",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "orgID": "0000000000000001",
+  "writeRequestsPerSecond": 10,
+  "writeBytesPerSecond": 0,
+  "concurrentQueries": 2,
+  "maxSeriesCardinality": 0
+}
+`,
+			},
+		},
+		{
+			name: "invalid json is rejected",
+			args: args{
+				orgID: 1,
+				body:  `{"concurrentQueries":"two"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgBackend := NewMockOrgBackend(t)
+			orgBackend.HTTPErrorHandler = ErrorHandler(0)
+			h := NewOrgHandler(zaptest.NewLogger(t), orgBackend)
+
+			u := fmt.Sprintf("http://any.url/api/v2/orgs/%s/limits", tt.args.orgID)
+			r := httptest.NewRequest("PUT", u, bytes.NewBufferString(tt.args.body))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("handlePutLimits() = %v, want %v", res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePutLimits(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePutLimits() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
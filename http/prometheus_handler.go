@@ -0,0 +1,349 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixPrometheus      = "/api/v2/prometheus"
+	prefixPrometheusWrite = prefixPrometheus + "/write"
+	prefixPrometheusRead  = prefixPrometheus + "/read"
+)
+
+// PrometheusBackend is all services and associated parameters required to construct
+// the PrometheusHandler.
+type PrometheusBackend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+	OrgLimitEnforcer    influxdb.OrgLimitEnforcer
+}
+
+// NewPrometheusBackend returns a new instance of PrometheusBackend.
+func NewPrometheusBackend(log *zap.Logger, b *APIBackend) *PrometheusBackend {
+	return &PrometheusBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		OrgLimitEnforcer:    b.OrgLimitEnforcer,
+	}
+}
+
+// PrometheusHandler implements the Prometheus remote write and remote read
+// endpoints. Both accept snappy compressed protobuf requests for the bucket
+// identified by the org and bucket query parameters.
+type PrometheusHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+	OrgLimitEnforcer    influxdb.OrgLimitEnforcer
+
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+}
+
+// Prefix provides the route prefix.
+func (*PrometheusHandler) Prefix() string {
+	return prefixPrometheus
+}
+
+// NewPrometheusHandler creates a new handler at /api/v2/prometheus for Prometheus remote storage.
+func NewPrometheusHandler(log *zap.Logger, b *PrometheusBackend) *PrometheusHandler {
+	h := &PrometheusHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		OrgLimitEnforcer:    b.OrgLimitEnforcer,
+		WriteEventRecorder:  b.WriteEventRecorder,
+		QueryEventRecorder:  b.QueryEventRecorder,
+	}
+
+	h.HandlerFunc("POST", prefixPrometheusWrite, h.handleWrite)
+	h.HandlerFunc("POST", prefixPrometheusRead, h.handleRead)
+	return h
+}
+
+func (h *PrometheusHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePrometheusWrite"
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	var requestBytes int
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.WriteEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	org, bucket, err := h.findBucket(ctx, r, influxdb.WriteAction, op)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID = org.ID
+	span.LogKV("org_id", org.ID, "bucket_id", bucket.ID)
+
+	log := h.log.With(zap.Stringer("org", org.ID), zap.Stringer("bucket", bucket.ID))
+
+	if err := allowWriteRequest(ctx, h.OrgLimitEnforcer, org.ID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req remote.WriteRequest
+	requestBytes, err = decodeSnappyProto(r, &req, op)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := allowWriteBytes(ctx, h.OrgLimitEnforcer, org.ID, requestBytes); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	points, dropped, err := remote.WriteRequestToPoints(&req, org.ID, bucket.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+	if dropped > 0 {
+		log.Debug("Dropped samples with unsupported values", zap.Int("dropped", dropped))
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		log.Error("Error writing points", zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  "unexpected error writing points to database",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PrometheusHandler) handleRead(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePrometheusRead"
+	span, r := tracing.ExtractFromHTTPRequest(r, "PrometheusHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.QueryEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	org, bucket, err := h.findBucket(ctx, r, influxdb.ReadAction, op)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID = org.ID
+	span.LogKV("org_id", org.ID, "bucket_id", bucket.ID)
+
+	var req remote.ReadRequest
+	if _, err := decodeSnappyProto(r, &req, op); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	source := h.ReadStore.GetSource(uint64(org.ID), uint64(bucket.ID))
+	resp := &remote.ReadResponse{Results: make([]remote.QueryResult, 0, len(req.Queries))}
+	for i := range req.Queries {
+		result, err := h.readQuery(ctx, &req.Queries[i], source)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   op,
+				Msg:  fmt.Sprintf("failed to read query %d: %v", i, err),
+				Err:  err,
+			}, w)
+			return
+		}
+		resp.Results = append(resp.Results, *result)
+	}
+
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(snappy.Encode(nil, data)); err != nil {
+		h.log.Info("Failed to write prometheus read response", zap.Error(err))
+	}
+}
+
+func (h *PrometheusHandler) readQuery(ctx context.Context, q *remote.Query, source proto.Message) (*remote.QueryResult, error) {
+	req, err := remote.QueryToReadFilterRequest(q, source)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := h.ReadStore.ReadFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rs == nil {
+		return &remote.QueryResult{}, nil
+	}
+	return remote.ResultSetToQueryResult(rs)
+}
+
+// findBucket returns the organization and bucket of the request and checks that
+// the authorizer of the request is allowed to perform action on the bucket.
+func (h *PrometheusHandler) findBucket(ctx context.Context, r *http.Request, action influxdb.Action, op string) (*influxdb.Organization, *influxdb.Bucket, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := r.URL.Query().Get(Bucket)
+	if name == "" {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  "bucket is required",
+		}
+	}
+
+	filter := influxdb.BucketFilter{OrganizationID: &org.ID}
+	if id, err := influxdb.IDFromString(name); err == nil {
+		filter.ID = id
+	} else {
+		filter.Name = &name
+	}
+	bucket, err := h.BucketService.FindBucket(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := influxdb.NewPermissionAtID(bucket.ID, action, influxdb.BucketsResourceType, org.ID)
+	if err != nil {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}
+	}
+
+	if !a.Allowed(*p) {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   op,
+			Msg:  fmt.Sprintf("insufficient permissions for %s", action),
+		}
+	}
+	return org, bucket, nil
+}
+
+// decodeSnappyProto reads a snappy compressed protobuf message from the body
+// of r into msg, returning the size of the compressed body.
+func decodeSnappyProto(r *http.Request, msg proto.Message, op string) (int, error) {
+	compressed, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return len(compressed), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  "request body is not snappy compressed",
+			Err:  err,
+		}
+	}
+
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return len(compressed), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   op,
+			Msg:  "request body is not a valid protobuf message",
+			Err:  err,
+		}
+	}
+	return len(compressed), nil
+}
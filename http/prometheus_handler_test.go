@@ -0,0 +1,215 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http/metric"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPrometheusHandler_handleWrite(t *testing.T) {
+	// state is the internal state of the org and bucket services and points writer
+	type state struct {
+		org      *influxdb.Organization
+		bucket   *influxdb.Bucket
+		writeErr error
+	}
+
+	// want is the expected output of the HTTP endpoint
+	type wants struct {
+		body   string
+		code   int
+		points []string
+	}
+
+	// request is sent to the HTTP endpoint
+	type request struct {
+		auth   influxdb.Authorizer
+		org    string
+		bucket string
+		body   []byte
+	}
+
+	encode := func(req *remote.WriteRequest) []byte {
+		b, err := proto.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return snappy.Encode(nil, b)
+	}
+
+	tests := []struct {
+		name    string
+		request request
+		state   state
+		wants   wants
+	}{
+		{
+			name: "samples are written as points",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body: encode(&remote.WriteRequest{
+					Timeseries: []remote.TimeSeries{
+						{
+							Labels: []remote.Label{
+								{Name: "__name__", Value: "up"},
+								{Name: "job", Value: "node"},
+								{Name: "empty", Value: ""},
+							},
+							Samples: []remote.Sample{{Value: 1, Timestamp: 1000}, {Value: 0, Timestamp: 2000}},
+						},
+					},
+				}),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+				points: []string{
+					"\x04>\a\x80\xee+\x10\x00\x04PK5n#\xb0\x00,\x00=up,job=node,\xff=value value=1 1000000000",
+					"\x04>\a\x80\xee+\x10\x00\x04PK5n#\xb0\x00,\x00=up,job=node,\xff=value value=0 2000000000",
+				},
+			},
+		},
+		{
+			name: "time series without a metric name returns 400",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body: encode(&remote.WriteRequest{
+					Timeseries: []remote.TimeSeries{
+						{
+							Labels:  []remote.Label{{Name: "job", Value: "node"}},
+							Samples: []remote.Sample{{Value: 1, Timestamp: 1000}},
+						},
+					},
+				}),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"time series is missing the __name__ label"}`,
+			},
+		},
+		{
+			name: "uncompressed body returns 400",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				body:   []byte("m1,t1=v1 f1=1"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"request body is not snappy compressed: snappy: corrupt input"}`,
+			},
+		},
+		{
+			name: "missing bucket returns 400",
+			request: request{
+				org:  "043e0780ee2b1000",
+				auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org: testOrg("043e0780ee2b1000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"bucket is required"}`,
+			},
+		},
+		{
+			name: "forbidden to write with insufficient permission",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				auth:   bucketWritePermission("043e0780ee2b1000", "000000000000000a"),
+				body:   encode(&remote.WriteRequest{}),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 403,
+				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return tt.state.org, nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return tt.state.bucket, nil
+			}
+			pw := &mock.PointsWriter{Err: tt.state.writeErr}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				PointsWriter:        pw,
+				WriteEventRecorder:  &metric.NopEventRecorder{},
+				QueryEventRecorder:  &metric.NopEventRecorder{},
+			}
+			promHandler := NewPrometheusHandler(zaptest.NewLogger(t), NewPrometheusBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(promHandler, tt.request.auth)
+
+			r := httptest.NewRequest(
+				"POST",
+				"http://localhost:9999/api/v2/prometheus/write",
+				bytes.NewReader(tt.request.body),
+			)
+
+			params := r.URL.Query()
+			params.Set("org", tt.request.org)
+			params.Set("bucket", tt.request.bucket)
+			r.URL.RawQuery = params.Encode()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.wants.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+
+			if got, want := w.Body.String(), tt.wants.body; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if got, want := len(pw.Points), len(tt.wants.points); got != want {
+				t.Fatalf("unexpected number of points: got %d want %d", got, want)
+			}
+			for i, p := range pw.Points {
+				if got, want := p.String(), tt.wants.points[i]; got != want {
+					t.Errorf("unexpected point %d: got %q want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
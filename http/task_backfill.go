@@ -0,0 +1,239 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskService)(nil)
+
+type taskBackfillResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.TaskBackfill
+}
+
+func newTaskBackfillResponse(b *influxdb.TaskBackfill) *taskBackfillResponse {
+	return &taskBackfillResponse{
+		Links: map[string]string{
+			"self": taskIDBackfillPath(b.TaskID),
+			"task": taskIDPath(b.TaskID),
+		},
+		TaskBackfill: *b,
+	}
+}
+
+// handlePostTaskBackfill is the HTTP handler for the POST /api/v2/tasks/:id/backfill route.
+func (h *TaskHandler) handlePostTaskBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostTaskBackfillRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskBackfillService.BackfillTask(ctx, req.TaskID, req.Start, req.Stop)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskBackfillResponse(b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetTaskBackfill is the HTTP handler for the GET /api/v2/tasks/:id/backfill route.
+func (h *TaskHandler) handleGetTaskBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskBackfillTaskID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskBackfillService.FindTaskBackfill(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskBackfillResponse(b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteTaskBackfill is the HTTP handler for the DELETE /api/v2/tasks/:id/backfill route.
+func (h *TaskHandler) handleDeleteTaskBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskBackfillTaskID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskBackfillService.CancelTaskBackfill(ctx, taskID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type postTaskBackfillRequest struct {
+	TaskID influxdb.ID
+	Start  time.Time `json:"start"`
+	Stop   time.Time `json:"stop"`
+}
+
+func decodePostTaskBackfillRequest(ctx context.Context, r *http.Request) (*postTaskBackfillRequest, error) {
+	taskID, err := decodeTaskBackfillTaskID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &postTaskBackfillRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	req.TaskID = taskID
+
+	if err := influxdb.ValidTaskBackfillRange(req.Start, req.Stop, time.Now()); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeTaskBackfillTaskID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "you must provide a task ID",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+func taskIDBackfillPath(id influxdb.ID) string {
+	return path.Join(prefixTasks, id.String(), "backfill")
+}
+
+// BackfillTask starts a backfill of the task over the range.
+func (t TaskService) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(struct {
+		Start time.Time `json:"start"`
+		Stop  time.Time `json:"stop"`
+	}{start, stop})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	return t.doTaskBackfill(req)
+}
+
+// FindTaskBackfill returns the progress of the latest backfill of the task.
+func (t TaskService) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	return t.doTaskBackfill(req)
+}
+
+// CancelTaskBackfill stops the running backfill of the task.
+func (t TaskService) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+func (t TaskService) doTaskBackfill(req *http.Request) (*influxdb.TaskBackfill, error) {
+	hc := NewClient(req.URL.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var b taskBackfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b.TaskBackfill, nil
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/httprouter"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_handlePostTaskBackfill(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		wants wants
+	}{
+		{
+			name: "backfill a task",
+			body: `{"start": "2019-10-01T00:00:00Z", "stop": "2019-11-01T00:00:00Z"}`,
+			wants: wants{
+				statusCode: http.StatusCreated,
+				body: `
+{
+  "links": {
+    "self": "/api/v2/tasks/020f755c3c082000/backfill",
+    "task": "/api/v2/tasks/020f755c3c082000"
+  },
+  "taskID": "020f755c3c082000",
+  "start": "2019-10-01T00:00:00Z",
+  "stop": "2019-11-01T00:00:00Z",
+  "status": "running",
+  "total": 0,
+  "succeeded": 0,
+  "failed": 0,
+  "createdAt": "0001-01-01T00:00:00Z",
+  "finishedAt": "0001-01-01T00:00:00Z"
+}
+`,
+			},
+		},
+		{
+			name: "stop before start",
+			body: `{"start": "2019-11-01T00:00:00Z", "stop": "2019-10-01T00:00:00Z"}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code": "invalid", "message": "backfill start must be before stop"}`,
+			},
+		},
+		{
+			name: "stop in the future",
+			body: `{"start": "2019-10-01T00:00:00Z", "stop": "2999-01-01T00:00:00Z"}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code": "invalid", "message": "backfill stop must not be in the future"}`,
+			},
+		},
+		{
+			name: "missing stop",
+			body: `{"start": "2019-11-01T00:00:00Z"}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code": "invalid", "message": "backfill start and stop are required"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewBufferString(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "020f755c3c082000"}}))
+
+			w := httptest.NewRecorder()
+
+			h.handlePostTaskBackfill(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostTaskBackfill() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePostTaskBackfill(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePostTaskBackfill() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handleGetTaskBackfill(t *testing.T) {
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	h := NewTaskHandler(zaptest.NewLogger(t), taskBackend)
+
+	r := httptest.NewRequest("GET", "http://any.url", nil)
+	r = r.WithContext(context.WithValue(
+		context.Background(),
+		httprouter.ParamsKey,
+		httprouter.Params{{Key: "id", Value: "020f755c3c082000"}}))
+
+	w := httptest.NewRecorder()
+
+	h.handleGetTaskBackfill(w, r)
+
+	if res := w.Result(); res.StatusCode != http.StatusNotFound {
+		t.Errorf("handleGetTaskBackfill() = %v, want %v", res.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+)
+
+type taskDAGResponse struct {
+	Links map[string]string `json:"links"`
+	*influxdb.TaskDAG
+}
+
+func newTaskDAGResponse(dag *influxdb.TaskDAG) *taskDAGResponse {
+	return &taskDAGResponse{
+		Links: map[string]string{
+			"self": path.Join(prefixTasks, dag.TaskID.String(), "dag"),
+			"task": taskIDPath(dag.TaskID),
+		},
+		TaskDAG: dag,
+	}
+}
+
+// handleGetTaskDAG is the HTTP handler for the GET /api/v2/tasks/:id/dag route.
+func (h *TaskHandler) handleGetTaskDAG(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.ENotFound,
+			Msg:  "failed to find task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tasks, err := h.findOrgTasks(ctx, task)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dag, err := influxdb.NewTaskDAG(task.ID, tasks)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskDAGResponse(dag)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// findOrgTasks returns all the tasks of the organization of the task, including the task.
+func (h *TaskHandler) findOrgTasks(ctx context.Context, task *influxdb.Task) ([]*influxdb.Task, error) {
+	var (
+		all   []*influxdb.Task
+		found bool
+	)
+	filter := influxdb.TaskFilter{
+		OrganizationID: &task.OrganizationID,
+		Limit:          influxdb.TaskMaxPageSize,
+	}
+	for {
+		tasks, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			found = found || t.ID == task.ID
+		}
+		all = append(all, tasks...)
+		if len(tasks) < filter.Limit {
+			break
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+
+	if !found {
+		all = append(all, task)
+	}
+	return all, nil
+}
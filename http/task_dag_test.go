@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_handleGetTaskDAG(t *testing.T) {
+	tasks := []*influxdb.Task{
+		{ID: 1, OrganizationID: 10, Name: "rollup", Status: "active"},
+		{ID: 2, OrganizationID: 10, Name: "aggregate", Status: "active", DependsOn: []influxdb.ID{1}},
+		{ID: 3, OrganizationID: 10, Name: "unrelated", Status: "active"},
+	}
+
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		for _, t := range tasks {
+			if t.ID == id {
+				return t, nil
+			}
+		}
+		return nil, influxdb.ErrTaskNotFound
+	}
+	ts.FindTasksFn = func(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+		if filter.OrganizationID == nil || *filter.OrganizationID != 10 {
+			t.Errorf("expected the tasks of organization 10 to be listed, got filter %+v", filter)
+		}
+		return tasks, len(tasks), nil
+	}
+
+	taskBackend := NewMockTaskBackend(t)
+	taskBackend.HTTPErrorHandler = ErrorHandler(0)
+	taskBackend.TaskService = ts
+	h := NewTaskHandler(zaptest.NewLogger(t), taskBackend)
+
+	r := httptest.NewRequest("GET", "http://any.url", nil)
+	r = r.WithContext(context.WithValue(
+		context.Background(),
+		httprouter.ParamsKey,
+		httprouter.Params{{Key: "id", Value: "0000000000000002"}}))
+
+	w := httptest.NewRecorder()
+
+	h.handleGetTaskDAG(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("handleGetTaskDAG() = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	want := `
+{
+  "links": {
+    "self": "/api/v2/tasks/0000000000000002/dag",
+    "task": "/api/v2/tasks/0000000000000002"
+  },
+  "taskID": "0000000000000002",
+  "nodes": [
+    {"id": "0000000000000001", "name": "rollup", "status": "active"},
+    {"id": "0000000000000002", "name": "aggregate", "status": "active"}
+  ],
+  "edges": [
+    {"from": "0000000000000001", "to": "0000000000000002"}
+  ]
+}
+`
+	if eq, diff, err := jsonEqual(string(body), want); err != nil {
+		t.Errorf("handleGetTaskDAG(). error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("handleGetTaskDAG() = ***%s***", diff)
+	}
+}
@@ -35,6 +35,7 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TaskBackfillService        influxdb.TaskBackfillService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -49,6 +50,7 @@ func NewTaskBackend(log *zap.Logger, b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskBackfillService:        b.TaskBackfillService,
 	}
 }
 
@@ -65,6 +67,7 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TaskBackfillService        influxdb.TaskBackfillService
 }
 
 const (
@@ -81,6 +84,8 @@ const (
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
+	tasksIDBackfillPath    = "/api/v2/tasks/:id/backfill"
+	tasksIDDAGPath         = "/api/v2/tasks/:id/dag"
 )
 
 // NewTaskHandler returns a new instance of TaskHandler.
@@ -97,6 +102,7 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskBackfillService:        b.TaskBackfillService,
 	}
 
 	h.HandlerFunc("GET", prefixTasks, h.handleGetTasks)
@@ -139,6 +145,12 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostTaskBackfill)
+	h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetTaskBackfill)
+	h.HandlerFunc("DELETE", tasksIDBackfillPath, h.handleDeleteTaskBackfill)
+
+	h.HandlerFunc("GET", tasksIDDAGPath, h.handleGetTaskDAG)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -153,23 +165,25 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 }
 
 type Task struct {
-	ID              influxdb.ID            `json:"id"`
-	OrganizationID  influxdb.ID            `json:"orgID"`
-	Organization    string                 `json:"org"`
-	OwnerID         influxdb.ID            `json:"ownerID"`
-	Name            string                 `json:"name"`
-	Description     string                 `json:"description,omitempty"`
-	Status          string                 `json:"status"`
-	Flux            string                 `json:"flux"`
-	Every           string                 `json:"every,omitempty"`
-	Cron            string                 `json:"cron,omitempty"`
-	Offset          string                 `json:"offset,omitempty"`
-	LatestCompleted string                 `json:"latestCompleted,omitempty"`
-	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
-	LastRunError    string                 `json:"lastRunError,omitempty"`
-	CreatedAt       string                 `json:"createdAt,omitempty"`
-	UpdatedAt       string                 `json:"updatedAt,omitempty"`
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	ID              influxdb.ID                `json:"id"`
+	OrganizationID  influxdb.ID                `json:"orgID"`
+	Organization    string                     `json:"org"`
+	OwnerID         influxdb.ID                `json:"ownerID"`
+	Name            string                     `json:"name"`
+	Description     string                     `json:"description,omitempty"`
+	Status          string                     `json:"status"`
+	Flux            string                     `json:"flux"`
+	Every           string                     `json:"every,omitempty"`
+	Cron            string                     `json:"cron,omitempty"`
+	Offset          string                     `json:"offset,omitempty"`
+	LatestCompleted string                     `json:"latestCompleted,omitempty"`
+	LastRunStatus   string                     `json:"lastRunStatus,omitempty"`
+	LastRunError    string                     `json:"lastRunError,omitempty"`
+	CreatedAt       string                     `json:"createdAt,omitempty"`
+	UpdatedAt       string                     `json:"updatedAt,omitempty"`
+	Metadata        map[string]interface{}     `json:"metadata,omitempty"`
+	DependsOn       []influxdb.ID              `json:"dependsOn,omitempty"`
+	OnWrite         *influxdb.TaskWriteTrigger `json:"onWrite,omitempty"`
 }
 
 type taskResponse struct {
@@ -215,6 +229,8 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+		DependsOn:       t.DependsOn,
+		OnWrite:         t.OnWrite,
 	}
 }
 
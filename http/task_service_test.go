@@ -58,6 +58,7 @@ func NewMockTaskBackend(t *testing.T) *TaskBackend {
 		UserResourceMappingService: newInMemKVSVC(t),
 		LabelService:               mock.NewLabelService(),
 		UserService:                mock.NewUserService(),
+		TaskBackfillService:        mock.NewTaskBackfillService(),
 	}
 }
 
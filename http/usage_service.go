@@ -11,6 +11,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const prefixUsage = "/api/v2/usage"
+
 // UsageHandler represents an HTTP API handler for usages.
 type UsageHandler struct {
 	*httprouter.Router
@@ -23,11 +25,12 @@ type UsageHandler struct {
 // NewUsageHandler returns a new instance of UsageHandler.
 func NewUsageHandler(log *zap.Logger, he platform.HTTPErrorHandler) *UsageHandler {
 	h := &UsageHandler{
-		Router: NewRouter(he),
-		log:    log,
+		Router:           NewRouter(he),
+		HTTPErrorHandler: he,
+		log:              log,
 	}
 
-	h.HandlerFunc("GET", "/api/v2/usage", h.handleGetUsage)
+	h.HandlerFunc("GET", prefixUsage, h.handleGetUsage)
 	return h
 }
 
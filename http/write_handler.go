@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/influxdata/httprouter"
@@ -31,6 +32,7 @@ type WriteBackend struct {
 	PointsWriter        storage.PointsWriter
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
+	OrgLimitEnforcer    influxdb.OrgLimitEnforcer
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
@@ -43,6 +45,7 @@ func NewWriteBackend(log *zap.Logger, b *APIBackend) *WriteBackend {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		OrgLimitEnforcer:    b.OrgLimitEnforcer,
 	}
 }
 
@@ -57,6 +60,10 @@ type WriteHandler struct {
 
 	PointsWriter storage.PointsWriter
 
+	// OrgLimitEnforcer, if set, rejects writes of organizations that have
+	// exceeded their limits.
+	OrgLimitEnforcer influxdb.OrgLimitEnforcer
+
 	EventRecorder metric.EventRecorder
 }
 
@@ -69,6 +76,15 @@ const (
 	prefixWrite          = "/api/v2/write"
 	errInvalidGzipHeader = "gzipped HTTP body contains an invalid header"
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
+	errInvalidValidation = "invalid validation mode; valid modes are strict and lenient"
+)
+
+// Validation modes of writes. In strict mode a batch containing a line that cannot
+// be parsed is rejected as a whole. In lenient mode the valid lines of a batch are
+// written, and the lines that are rejected are reported in the response.
+const (
+	writeValidationStrict  = "strict"
+	writeValidationLenient = "lenient"
 )
 
 // NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
@@ -81,6 +97,7 @@ func NewWriteHandler(log *zap.Logger, b *WriteBackend) *WriteHandler {
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
+		OrgLimitEnforcer:    b.OrgLimitEnforcer,
 		EventRecorder:       b.WriteEventRecorder,
 	}
 
@@ -201,6 +218,11 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := allowWriteRequest(ctx, h.OrgLimitEnforcer, org.ID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	// TODO(jeff): we should be publishing with the org and bucket instead of
 	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
 	// be sure to remove this when it is there!
@@ -229,9 +251,20 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	span, _ = tracing.StartSpanFromContextWithOperationName(ctx, "encoding and parsing")
+	if err := allowWriteBytes(ctx, h.OrgLimitEnforcer, org.ID, requestBytes); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	encoded := tsdb.EncodeName(org.ID, bucket.ID)
 	mm := models.EscapeMeasurement(encoded[:])
+
+	if req.Validation == writeValidationLenient {
+		h.writeLenient(ctx, w, log, data, mm, req.Precision)
+		return
+	}
+
+	span, _ = tracing.StartSpanFromContextWithOperationName(ctx, "encoding and parsing")
 	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
 	span.LogKV("values_total", len(points))
 	span.Finish()
@@ -245,6 +278,17 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		// Points dropped by the points writer, such as points rejected by the
+		// schema of the bucket, are not written, which the client must know.
+		if _, ok := err.(tsdb.PartialWriteError); ok {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EUnprocessableEntity,
+				Op:   "http/handleWrite",
+				Msg:  err.Error(),
+			}, w)
+			return
+		}
+
 		log.Error("Error writing points", zap.Error(err))
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EInternal,
@@ -258,6 +302,100 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// writeResponse reports the outcome of a lenient write.
+type writeResponse struct {
+	Accepted int            `json:"accepted"`
+	Rejected []rejectedLine `json:"rejected"`
+}
+
+// rejectedLine is a line of a lenient write that was not written.
+type rejectedLine struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// writeLenient writes the valid lines of data. Lines that cannot be parsed, and
+// lines with points dropped by the points writer, such as points with field type
+// conflicts, are rejected. If any line is rejected, the response lists them by
+// line number.
+func (h *WriteHandler) writeLenient(ctx context.Context, w http.ResponseWriter, log *zap.Logger, data, mm []byte, precision string) {
+	span, _ := tracing.StartSpanFromContextWithOperationName(ctx, "encoding and parsing")
+	points, lines, lineErrs := models.ParseLinesWithPrecision(data, mm, time.Now(), precision)
+	span.LogKV("values_total", len(points))
+	span.Finish()
+
+	rejected := make(map[int]string, len(lineErrs))
+	for _, e := range lineErrs {
+		rejected[e.Line] = e.Err.Error()
+	}
+
+	if len(points) > 0 {
+		err := h.PointsWriter.WritePoints(ctx, points)
+		if pwe, ok := err.(tsdb.PartialWriteError); ok {
+			lineOf := make(map[models.Point]int, len(points))
+			for i, p := range points {
+				lineOf[p] = lines[i]
+			}
+			// Only the first reason a line was rejected for is reported.
+			for _, d := range pwe.DroppedPoints {
+				line, ok := lineOf[d.Point]
+				if _, seen := rejected[line]; ok && !seen {
+					rejected[line] = d.Reason
+				}
+			}
+		} else if err != nil {
+			log.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleWrite",
+				Msg:  "unexpected error writing points to database",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	if len(rejected) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	res := writeResponse{Rejected: make([]rejectedLine, 0, len(rejected))}
+	for i, line := range lines {
+		if _, ok := rejected[line]; !ok && (i == 0 || lines[i-1] != line) {
+			res.Accepted++
+		}
+	}
+	for line, reason := range rejected {
+		res.Rejected = append(res.Rejected, rejectedLine{Line: line, Error: reason})
+	}
+	sort.Slice(res.Rejected, func(i, j int) bool {
+		return res.Rejected[i].Line < res.Rejected[j].Line
+	})
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		log.Info("Error encoding write response", zap.Error(err))
+	}
+}
+
+// allowWriteRequest checks the write request rate and series cardinality limits
+// of the organization orgID, if limits are enforced.
+func allowWriteRequest(ctx context.Context, e influxdb.OrgLimitEnforcer, orgID influxdb.ID) error {
+	if e == nil {
+		return nil
+	}
+	return e.AllowWriteRequest(ctx, orgID)
+}
+
+// allowWriteBytes checks the write byte rate limit of the organization orgID,
+// if limits are enforced.
+func allowWriteBytes(ctx context.Context, e influxdb.OrgLimitEnforcer, orgID influxdb.ID, n int) error {
+	if e == nil {
+		return nil
+	}
+	return e.AllowWriteBytes(ctx, orgID, n)
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
@@ -273,17 +411,32 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
+	v := qp.Get("validation")
+	if v == "" {
+		v = writeValidationStrict
+	}
+
+	if v != writeValidationStrict && v != writeValidationLenient {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  errInvalidValidation,
+		}
+	}
+
 	return &postWriteRequest{
-		Bucket:    qp.Get("bucket"),
-		Org:       qp.Get("org"),
-		Precision: p,
+		Bucket:     qp.Get("bucket"),
+		Org:        qp.Get("org"),
+		Precision:  p,
+		Validation: v,
 	}, nil
 }
 
 type postWriteRequest struct {
-	Org       string
-	Bucket    string
-	Precision string
+	Org        string
+	Bucket     string
+	Precision  string
+	Validation string
 }
 
 // WriteService sends data over HTTP to influxdb via line protocol.
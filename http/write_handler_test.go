@@ -15,7 +15,9 @@ import (
 	"github.com/influxdata/influxdb/http/metric"
 	httpmock "github.com/influxdata/influxdb/http/mock"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
 	influxtesting "github.com/influxdata/influxdb/testing"
+	"github.com/influxdata/influxdb/tsdb"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -84,6 +86,8 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 		bucket    *influxdb.Bucket       // bucket to return in bucket service
 		bucketErr error                  // err to return in bucket service
 		writeErr  error                  // err to return from the points writer
+		limitErr  error                  // err to return when checking the org write request limit
+		bytesErr  error                  // err to return when checking the org write bytes limit
 	}
 
 	// want is the expected output of the HTTP endpoint
@@ -94,10 +98,11 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 
 	// request is sent to the HTTP endpoint
 	type request struct {
-		auth   influxdb.Authorizer
-		org    string
-		bucket string
-		body   string
+		auth       influxdb.Authorizer
+		org        string
+		bucket     string
+		body       string
+		validation string
 	}
 
 	tests := []struct {
@@ -222,6 +227,98 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"invalid","message":"unable to parse 'invalid': missing fields"}`,
 			},
 		},
+		{
+			name: "partial write in strict mode returns 422",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:      testOrg("043e0780ee2b1000"),
+				bucket:   testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				writeErr: tsdb.PartialWriteError{Reason: "series type mismatch: already Float but got Integer", Dropped: 1},
+			},
+			wants: wants{
+				code: 422,
+				body: `{"code":"unprocessable entity","message":"partial write: series type mismatch: already Float but got Integer dropped=1"}`,
+			},
+		},
+		{
+			name: "schema violation in strict mode returns 422",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				writeErr: tsdb.PartialWriteError{
+					Reason:  `field "f1" of measurement "m1" must be of type integer but got float by the schema of bucket 04504b356e23b000`,
+					Dropped: 1,
+				},
+			},
+			wants: wants{
+				code: 422,
+				body: `{"code":"unprocessable entity","message":"partial write: field \"f1\" of measurement \"m1\" must be of type integer but got float by the schema of bucket 04504b356e23b000 dropped=1"}`,
+			},
+		},
+		{
+			name: "invalid validation mode returns 400",
+			request: request{
+				org:        "043e0780ee2b1000",
+				bucket:     "04504b356e23b000",
+				body:       "m1,t1=v1 f1=1",
+				auth:       bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				validation: "loose",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 400,
+				body: `{"code":"invalid","message":"invalid validation mode; valid modes are strict and lenient"}`,
+			},
+		},
+		{
+			name: "lenient write of valid lines is accepted",
+			request: request{
+				org:        "043e0780ee2b1000",
+				bucket:     "04504b356e23b000",
+				body:       "m1,t1=v1 f1=1\nm1,t1=v2 f1=2",
+				auth:       bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				validation: "lenient",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "lenient write reports invalid lines",
+			request: request{
+				org:        "043e0780ee2b1000",
+				bucket:     "04504b356e23b000",
+				body:       "m1,t1=v1 f1=1\ninvalid\nm1,t1=v2 f1=2\nm1 f1=",
+				auth:       bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+				validation: "lenient",
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 200,
+				body: `{"accepted":2,"rejected":[{"line":2,"error":"unable to parse 'invalid': missing fields"},{"line":4,"error":"unable to parse 'm1 f1=': missing field value"}]}` + "\n",
+			},
+		},
 		{
 			name: "forbidden to write with insufficient permission",
 			request: request{
@@ -239,6 +336,48 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
 			},
 		},
+		{
+			name: "org over its write request limit returns 429",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				limitErr: &influxdb.Error{
+					Code: influxdb.ETooManyRequests,
+					Msg:  "organization has exceeded its limit of 10 write requests per second",
+				},
+			},
+			wants: wants{
+				code: 429,
+				body: `{"code":"too many requests","message":"organization has exceeded its limit of 10 write requests per second"}`,
+			},
+		},
+		{
+			name: "org over its write bytes limit returns 429",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,t1=v1 f1=1",
+				auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+				bytesErr: &influxdb.Error{
+					Code: influxdb.ETooManyRequests,
+					Msg:  "organization has exceeded its limit of 1 write bytes per second",
+				},
+			},
+			wants: wants{
+				code: 429,
+				body: `{"code":"too many requests","message":"organization has exceeded its limit of 1 write bytes per second"}`,
+			},
+		},
 		{
 			// authorization extraction happens in a different middleware.
 			name: "no authorizer is an internal error",
@@ -266,12 +405,20 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
 				return tt.state.bucket, tt.state.bucketErr
 			}
+			limits := mock.NewOrgLimitEnforcer()
+			limits.AllowWriteRequestFn = func(context.Context, influxdb.ID) error {
+				return tt.state.limitErr
+			}
+			limits.AllowWriteBytesFn = func(context.Context, influxdb.ID, int) error {
+				return tt.state.bytesErr
+			}
 
 			b := &APIBackend{
 				HTTPErrorHandler:    DefaultErrorHandler,
 				Logger:              zaptest.NewLogger(t),
 				OrganizationService: orgs,
 				BucketService:       buckets,
+				OrgLimitEnforcer:    limits,
 				PointsWriter:        &mock.PointsWriter{Err: tt.state.writeErr},
 				WriteEventRecorder:  &metric.NopEventRecorder{},
 			}
@@ -287,6 +434,9 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 			params := r.URL.Query()
 			params.Set("org", tt.request.org)
 			params.Set("bucket", tt.request.bucket)
+			if tt.request.validation != "" {
+				params.Set("validation", tt.request.validation)
+			}
 			r.URL.RawQuery = params.Encode()
 
 			w := httptest.NewRecorder()
@@ -302,6 +452,74 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 	}
 }
 
+func TestWriteHandler_handleWriteLenientDroppedPoints(t *testing.T) {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+		return testOrg("043e0780ee2b1000"), nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		return testBucket("043e0780ee2b1000", "04504b356e23b000"), nil
+	}
+
+	// Drop the points with integer fields, as if the fields were floats already.
+	var written []models.Point
+	writer := pointsWriterFunc(func(ctx context.Context, points []models.Point) error {
+		var pwe tsdb.PartialWriteError
+		for _, p := range points {
+			fi := p.FieldIterator()
+			fi.Next()
+			if fi.Type() == models.Integer {
+				pwe.Dropped++
+				pwe.DroppedPoints = append(pwe.DroppedPoints, tsdb.DroppedPoint{
+					Point:  p,
+					Reason: "series type mismatch: already Float but got Integer",
+				})
+				continue
+			}
+			written = append(written, p)
+		}
+		if pwe.Dropped > 0 {
+			return pwe
+		}
+		return nil
+	})
+
+	b := &APIBackend{
+		HTTPErrorHandler:    DefaultErrorHandler,
+		Logger:              zaptest.NewLogger(t),
+		OrganizationService: orgs,
+		BucketService:       buckets,
+		PointsWriter:        writer,
+		WriteEventRecorder:  &metric.NopEventRecorder{},
+	}
+	writeHandler := NewWriteHandler(zaptest.NewLogger(t), NewWriteBackend(zaptest.NewLogger(t), b))
+	handler := httpmock.NewAuthMiddlewareHandler(writeHandler, bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"))
+
+	body := "m1,t1=v1 f1=1\nm1,t1=v2 f1=2i\nm1,t1=v3 f1=3,f2=4i\nm1,t1=v4 f1=4"
+	r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/write?org=043e0780ee2b1000&bucket=04504b356e23b000&validation=lenient", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Errorf("unexpected status code: got %d want %d", got, want)
+	}
+	want := `{"accepted":2,"rejected":[{"line":2,"error":"series type mismatch: already Float but got Integer"},{"line":3,"error":"series type mismatch: already Float but got Integer"}]}` + "\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("unexpected body: got %s want %s", got, want)
+	}
+	if got, want := len(written), 3; got != want {
+		t.Errorf("got %d points written, want %d", got, want)
+	}
+}
+
+// pointsWriterFunc is a storage.PointsWriter calling a function.
+type pointsWriterFunc func(ctx context.Context, points []models.Point) error
+
+func (f pointsWriterFunc) WritePoints(ctx context.Context, points []models.Point) error {
+	return f(ctx, points)
+}
+
 var DefaultErrorHandler = ErrorHandler(0)
 
 func bucketWritePermission(org, bucket string) *influxdb.Authorization {
@@ -25,6 +25,10 @@ func ToStatus(err *platform.Error) (*status.Status, error) {
 		c = codes.InvalidArgument
 	case platform.EUnavailable:
 		c = codes.Unavailable
+	case platform.ETimeout:
+		c = codes.DeadlineExceeded
+	case platform.EResourceExhausted:
+		c = codes.ResourceExhausted
 	}
 
 	buf, jerr := json.Marshal(err)
@@ -656,6 +656,14 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.MaxSeriesCardinality != nil {
+		b.MaxSeriesCardinality = *upd.MaxSeriesCardinality
+	}
+
+	if upd.DownsampleTiers != nil {
+		b.DownsampleTiers = *upd.DownsampleTiers
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
@@ -768,7 +776,7 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
-	return nil
+	return s.deleteBucketSchema(ctx, tx, id)
 }
 
 const bucketOperationLogKeyPrefix = "bucket"
@@ -0,0 +1,141 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var bucketSchemasBucket = []byte("bucketschemasv1")
+
+var _ influxdb.BucketSchemaService = (*Service)(nil)
+
+func (s *Service) initializeBucketSchemas(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(bucketSchemasBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindBucketSchema returns the schema of the bucket bucketID.
+func (s *Service) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	var schema *influxdb.BucketSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findBucketByID(ctx, tx, bucketID); err != nil {
+			return err
+		}
+
+		sch, err := s.findBucketSchema(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		schema = sch
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *Service) findBucketSchema(ctx context.Context, tx Tx, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	key, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(bucketSchemasBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return &influxdb.BucketSchema{BucketID: bucketID, Measurements: []influxdb.MeasurementSchema{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var schema influxdb.BucketSchema
+	if err := json.Unmarshal(v, &schema); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &schema, nil
+}
+
+// UpdateBucketSchema replaces the schema of the bucket bucketID. A schema without
+// measurements removes the schema of the bucket.
+func (s *Service) UpdateBucketSchema(ctx context.Context, bucketID influxdb.ID, schema influxdb.BucketSchema) (*influxdb.BucketSchema, error) {
+	if err := schema.Valid(); err != nil {
+		return nil, err
+	}
+	schema.BucketID = bucketID
+	if schema.Measurements == nil {
+		schema.Measurements = []influxdb.MeasurementSchema{}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findBucketByID(ctx, tx, bucketID); err != nil {
+			return err
+		}
+		if len(schema.Measurements) == 0 {
+			return s.deleteBucketSchema(ctx, tx, bucketID)
+		}
+		return s.putBucketSchema(ctx, tx, &schema)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (s *Service) putBucketSchema(ctx context.Context, tx Tx, schema *influxdb.BucketSchema) error {
+	key, err := schema.BucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(schema)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(bucketSchemasBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, v)
+}
+
+func (s *Service) deleteBucketSchema(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	key, err := bucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(bucketSchemasBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(key); err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
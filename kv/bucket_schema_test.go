@@ -0,0 +1,82 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestBoltBucketSchemaService(t *testing.T) {
+	influxdbtesting.BucketSchemaService(initBoltBucketSchemaService, t)
+}
+
+func TestInmemBucketSchemaService(t *testing.T) {
+	influxdbtesting.BucketSchemaService(initInmemBucketSchemaService, t)
+}
+
+func initBoltBucketSchemaService(f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	s, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBucketSchemaService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemBucketSchemaService(f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	s, closeInmem, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBucketSchemaService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initBucketSchemaService(s kv.Store, f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing bucket schema service: %v", err)
+	}
+
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations: %v", err)
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, schema := range f.BucketSchemas {
+		if _, err := svc.UpdateBucketSchema(ctx, schema.BucketID, schema); err != nil {
+			t.Fatalf("failed to populate bucket schemas: %v", err)
+		}
+	}
+
+	return svc, func() {
+		for _, b := range f.Buckets {
+			if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+				t.Logf("failed to remove bucket: %v", err)
+			}
+		}
+		for _, o := range f.Organizations {
+			if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organization: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestBoltDBRPMappingService(t *testing.T) {
+	t.Run("CreateDBRPMapping", func(t *testing.T) { influxdbtesting.CreateDBRPMapping(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMappingByKey", func(t *testing.T) { influxdbtesting.FindDBRPMappingByKey(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMappings", func(t *testing.T) { influxdbtesting.FindDBRPMappings(initBoltDBRPMappingService, t) })
+	t.Run("FindDBRPMapping", func(t *testing.T) { influxdbtesting.FindDBRPMapping(initBoltDBRPMappingService, t) })
+	t.Run("DeleteDBRPMapping", func(t *testing.T) { influxdbtesting.DeleteDBRPMapping(initBoltDBRPMappingService, t) })
+}
+
+func initBoltDBRPMappingService(f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	s, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initDBRPMappingService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initDBRPMappingService(s kv.Store, f influxdbtesting.DBRPMappingFields, t *testing.T) (influxdb.DBRPMappingService, func()) {
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing dbrp mapping service: %v", err)
+	}
+
+	if err := f.Populate(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+
+	return svc, func() {
+		if err := influxdbtesting.CleanupDBRPMappings(ctx, svc); err != nil {
+			t.Logf("failed to remove dbrp mappings: %v", err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var migrationBucket = []byte("migrationsv1")
+
+// MigrationState is the state of a migration in a store.
+type MigrationState uint
+
+const (
+	// DownMigrationState is the state of a migration that has not been applied.
+	DownMigrationState MigrationState = iota
+	// UpMigrationState is the state of a migration that has been applied.
+	UpMigrationState
+)
+
+// String returns a string representation of a migration state.
+func (s MigrationState) String() string {
+	switch s {
+	case DownMigrationState:
+		return "down"
+	case UpMigrationState:
+		return "up"
+	default:
+		return "unknown"
+	}
+}
+
+// Migration is a record of a migration and whether it has been applied.
+type Migration struct {
+	ID        influxdb.ID    `json:"id"`
+	Name      string         `json:"name"`
+	State     MigrationState `json:"-"`
+	AppliedAt *time.Time     `json:"appliedAt,omitempty"`
+}
+
+// MigrationSpec is a single versioned change to the contents of a store.
+// Up applies the change and Down reverts it; each runs inside the same
+// update transaction that records the migration's new state.
+type MigrationSpec interface {
+	MigrationName() string
+	Up(ctx context.Context, tx Tx) error
+	Down(ctx context.Context, tx Tx) error
+}
+
+// MigrationFunc is a function that applies or reverts a migration.
+type MigrationFunc func(ctx context.Context, tx Tx) error
+
+// AnonymousMigration is a MigrationSpec built from a name and a pair of functions.
+type AnonymousMigration struct {
+	name string
+	up   MigrationFunc
+	down MigrationFunc
+}
+
+// NewAnonymousMigration returns a MigrationSpec named name that runs up and down.
+func NewAnonymousMigration(name string, up, down MigrationFunc) *AnonymousMigration {
+	return &AnonymousMigration{name: name, up: up, down: down}
+}
+
+// MigrationName returns the name of the migration.
+func (m *AnonymousMigration) MigrationName() string { return m.name }
+
+// Up applies the migration.
+func (m *AnonymousMigration) Up(ctx context.Context, tx Tx) error { return m.up(ctx, tx) }
+
+// Down reverts the migration.
+func (m *AnonymousMigration) Down(ctx context.Context, tx Tx) error { return m.down(ctx, tx) }
+
+// ErrMigrationSpecNotFound is returned when a store has applied a migration
+// that is not known to the migrator, e.g. after a downgrade.
+func ErrMigrationSpecNotFound(id influxdb.ID, name string) error {
+	return &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  fmt.Sprintf("migration %s %q has been applied but is not known to this version", id, name),
+	}
+}
+
+// Migrator applies an ordered list of migrations to a store and records the
+// migrations that have been applied in the store itself. Migration IDs are
+// derived from their position in the list, so migrations must only ever be
+// appended.
+type Migrator struct {
+	logger *zap.Logger
+	store  Store
+
+	Specs []MigrationSpec
+
+	now func() time.Time
+}
+
+// NewMigrator returns a new Migrator for store with the provided migrations.
+func NewMigrator(logger *zap.Logger, store Store, specs ...MigrationSpec) *Migrator {
+	return &Migrator{
+		logger: logger,
+		store:  store,
+		Specs:  specs,
+		now:    time.Now,
+	}
+}
+
+// AddMigrations appends migrations to the migrator.
+func (m *Migrator) AddMigrations(specs ...MigrationSpec) {
+	m.Specs = append(m.Specs, specs...)
+}
+
+// Initialize creates the bucket used to record applied migrations.
+func (m *Migrator) Initialize(ctx context.Context) error {
+	return m.store.Update(ctx, func(tx Tx) error {
+		_, err := tx.Bucket(migrationBucket)
+		return err
+	})
+}
+
+// List returns all known migrations and their state in the store.
+func (m *Migrator) List(ctx context.Context) (migrations []Migration, err error) {
+	err = m.store.View(ctx, func(tx Tx) error {
+		migrations, err = m.list(tx)
+		return err
+	})
+	return migrations, err
+}
+
+func (m *Migrator) list(tx Tx) ([]Migration, error) {
+	applied, err := m.applied(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(m.Specs))
+	for i, spec := range m.Specs {
+		migration := Migration{
+			ID:   influxdb.ID(i + 1),
+			Name: spec.MigrationName(),
+		}
+
+		if a, ok := applied[migration.ID]; ok {
+			if a.Name != migration.Name {
+				return nil, &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  fmt.Sprintf("migration %s was applied as %q but is now %q", migration.ID, a.Name, migration.Name),
+				}
+			}
+			migration.State = UpMigrationState
+			migration.AppliedAt = a.AppliedAt
+			delete(applied, migration.ID)
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	for id, a := range applied {
+		return nil, ErrMigrationSpecNotFound(id, a.Name)
+	}
+
+	// Migrations build on each other, so an applied migration must never follow
+	// one that is down.
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].State == UpMigrationState && migrations[i-1].State == DownMigrationState {
+			return nil, &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("migration %s is applied but migration %s is not", migrations[i].ID, migrations[i-1].ID),
+			}
+		}
+	}
+
+	return migrations, nil
+}
+
+func (m *Migrator) applied(tx Tx) (map[influxdb.ID]Migration, error) {
+	b, err := tx.Bucket(migrationBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	applied := map[influxdb.ID]Migration{}
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		var migration Migration
+		if err := json.Unmarshal(v, &migration); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("invalid migration record %q", k),
+				Err:  err,
+			}
+		}
+		migration.State = UpMigrationState
+		applied[migration.ID] = migration
+	}
+	return applied, cur.Err()
+}
+
+// Up applies all migrations that are down, in order. Each migration is applied
+// and recorded in its own transaction.
+func (m *Migrator) Up(ctx context.Context) error {
+	migrations, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, migration := range migrations {
+		if migration.State == UpMigrationState {
+			continue
+		}
+
+		m.logger.Info("Applying migration", zap.Stringer("id", migration.ID), zap.String("name", migration.Name))
+		if err := m.store.Update(ctx, func(tx Tx) error {
+			if err := m.Specs[i].Up(ctx, tx); err != nil {
+				return err
+			}
+			return m.putMigration(tx, migration)
+		}); err != nil {
+			return &influxdb.Error{
+				Msg: fmt.Sprintf("failed to apply migration %s %q", migration.ID, migration.Name),
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
+
+// Down reverts the most recently applied migration. It does nothing if no
+// migration is applied.
+func (m *Migrator) Down(ctx context.Context) error {
+	migrations, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.State == DownMigrationState {
+			continue
+		}
+
+		m.logger.Info("Reverting migration", zap.Stringer("id", migration.ID), zap.String("name", migration.Name))
+		if err := m.store.Update(ctx, func(tx Tx) error {
+			if err := m.Specs[i].Down(ctx, tx); err != nil {
+				return err
+			}
+			return m.deleteMigration(tx, migration)
+		}); err != nil {
+			return &influxdb.Error{
+				Msg: fmt.Sprintf("failed to revert migration %s %q", migration.ID, migration.Name),
+				Err: err,
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func (m *Migrator) putMigration(tx Tx, migration Migration) error {
+	b, err := tx.Bucket(migrationBucket)
+	if err != nil {
+		return err
+	}
+
+	key, err := migration.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	now := m.now().UTC()
+	migration.AppliedAt = &now
+
+	v, err := json.Marshal(migration)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, v)
+}
+
+func (m *Migrator) deleteMigration(tx Tx, migration Migration) error {
+	b, err := tx.Bucket(migrationBucket)
+	if err != nil {
+		return err
+	}
+
+	key, err := migration.ID.Encode()
+	if err != nil {
+		return err
+	}
+	return b.Delete(key)
+}
@@ -0,0 +1,225 @@
+package kv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+var migrationTestBucket = []byte("migrationtestv1")
+
+// putMigration returns a migration that writes key on the way up and removes it on the way down.
+func putMigration(name string, key []byte) kv.MigrationSpec {
+	return kv.NewAnonymousMigration(name,
+		func(ctx context.Context, tx kv.Tx) error {
+			b, err := tx.Bucket(migrationTestBucket)
+			if err != nil {
+				return err
+			}
+			return b.Put(key, []byte(name))
+		},
+		func(ctx context.Context, tx kv.Tx) error {
+			b, err := tx.Bucket(migrationTestBucket)
+			if err != nil {
+				return err
+			}
+			return b.Delete(key)
+		},
+	)
+}
+
+func hasKey(t *testing.T, store kv.Store, key []byte) bool {
+	t.Helper()
+
+	var found bool
+	err := store.View(context.Background(), func(tx kv.Tx) error {
+		b, err := tx.Bucket(migrationTestBucket)
+		if err != nil {
+			return err
+		}
+		_, err = b.Get(key)
+		if kv.IsNotFound(err) {
+			return nil
+		}
+		found = err == nil
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+func assertStates(t *testing.T, m *kv.Migrator, states ...kv.MigrationState) {
+	t.Helper()
+
+	migrations, err := m.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != len(states) {
+		t.Fatalf("got %d migrations, expected %d", len(migrations), len(states))
+	}
+	for i, migration := range migrations {
+		if migration.ID != influxdb.ID(i+1) {
+			t.Errorf("migration %d: got id %s, expected %s", i, migration.ID, influxdb.ID(i+1))
+		}
+		if migration.State != states[i] {
+			t.Errorf("migration %d: got state %s, expected %s", i, migration.State, states[i])
+		}
+		if (migration.AppliedAt != nil) != (migration.State == kv.UpMigrationState) {
+			t.Errorf("migration %d: unexpected applied at %v in state %s", i, migration.AppliedAt, migration.State)
+		}
+	}
+}
+
+func newMigrator(t *testing.T, store kv.Store, specs ...kv.MigrationSpec) *kv.Migrator {
+	t.Helper()
+
+	m := kv.NewMigrator(zaptest.NewLogger(t), store, specs...)
+	if err := m.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	m := newMigrator(t, store, putMigration("first", []byte("a")), putMigration("second", []byte("b")))
+	assertStates(t, m, kv.DownMigrationState, kv.DownMigrationState)
+
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertStates(t, m, kv.UpMigrationState, kv.UpMigrationState)
+	if !hasKey(t, store, []byte("a")) || !hasKey(t, store, []byte("b")) {
+		t.Fatal("expected both migrations to be applied")
+	}
+
+	// Migrations added by a later version are applied on the next up.
+	m.AddMigrations(putMigration("third", []byte("c")))
+	assertStates(t, m, kv.UpMigrationState, kv.UpMigrationState, kv.DownMigrationState)
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertStates(t, m, kv.UpMigrationState, kv.UpMigrationState, kv.UpMigrationState)
+
+	if err := m.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertStates(t, m, kv.UpMigrationState, kv.UpMigrationState, kv.DownMigrationState)
+	if hasKey(t, store, []byte("c")) {
+		t.Fatal("expected third migration to be reverted")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := m.Down(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertStates(t, m, kv.DownMigrationState, kv.DownMigrationState, kv.DownMigrationState)
+	if hasKey(t, store, []byte("a")) || hasKey(t, store, []byte("b")) {
+		t.Fatal("expected all migrations to be reverted")
+	}
+}
+
+func TestMigrator_UpFailure(t *testing.T) {
+	// The inmem store does not roll back failed updates, so bolt is used here.
+	store, closeStore, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	fail := kv.NewAnonymousMigration("fail",
+		func(ctx context.Context, tx kv.Tx) error {
+			b, err := tx.Bucket(migrationTestBucket)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte("partial"), []byte("fail")); err != nil {
+				return err
+			}
+			return errors.New("migration failed")
+		},
+		func(ctx context.Context, tx kv.Tx) error { return nil },
+	)
+
+	m := newMigrator(t, store, putMigration("first", []byte("a")), fail, putMigration("third", []byte("c")))
+	if err := m.Up(ctx); err == nil {
+		t.Fatal("expected error applying migrations")
+	}
+
+	assertStates(t, m, kv.UpMigrationState, kv.DownMigrationState, kv.DownMigrationState)
+	if hasKey(t, store, []byte("partial")) {
+		t.Fatal("expected failed migration to be rolled back")
+	}
+	if hasKey(t, store, []byte("c")) {
+		t.Fatal("expected migrations after a failure not to be applied")
+	}
+}
+
+func TestMigrator_Mismatch(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	m := newMigrator(t, store, putMigration("first", []byte("a")), putMigration("second", []byte("b")))
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// An older version does not know about the second migration.
+	older := newMigrator(t, store, putMigration("first", []byte("a")))
+	if _, err := older.List(ctx); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict listing unknown migration, got %v", err)
+	}
+	if err := older.Up(ctx); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict applying migrations, got %v", err)
+	}
+
+	renamed := newMigrator(t, store, putMigration("first", []byte("a")), putMigration("renamed", []byte("b")))
+	if _, err := renamed.List(ctx); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict listing renamed migration, got %v", err)
+	}
+}
+
+func TestService_Migrations(t *testing.T) {
+	store, closeStore, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	m := newMigrator(t, store, svc.Migrations()...)
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	states := make([]kv.MigrationState, len(svc.Migrations()))
+	for i := range states {
+		states[i] = kv.UpMigrationState
+	}
+	assertStates(t, m, states...)
+
+	// The migrated store can be used without calling Initialize.
+	if err := svc.CreateOrganization(ctx, &influxdb.Organization{Name: "org"}); err != nil {
+		t.Fatal(err)
+	}
+}
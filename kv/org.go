@@ -484,7 +484,7 @@ func (s *Service) DeleteOrganization(ctx context.Context, id influxdb.ID) error
 		if pe := s.deleteOrganization(ctx, tx, id); pe != nil {
 			return pe
 		}
-		return nil
+		return s.deleteOrgLimits(ctx, tx, id)
 	})
 	if err != nil {
 		return &influxdb.Error{
@@ -0,0 +1,134 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var orgLimitsBucket = []byte("orglimitsv1")
+
+var _ influxdb.OrgLimitsService = (*Service)(nil)
+
+func (s *Service) initializeOrgLimits(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(orgLimitsBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindOrgLimits returns the limits of the organization orgID.
+func (s *Service) FindOrgLimits(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+	var l *influxdb.OrgLimits
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, orgID); err != nil {
+			return err
+		}
+
+		limits, err := s.findOrgLimits(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		l = limits
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *Service) findOrgLimits(ctx context.Context, tx Tx, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+	key, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgLimitsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return &influxdb.OrgLimits{OrgID: orgID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var l influxdb.OrgLimits
+	if err := json.Unmarshal(v, &l); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &l, nil
+}
+
+// UpdateOrgLimits replaces the limits of the organization orgID.
+func (s *Service) UpdateOrgLimits(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error) {
+	if err := l.Valid(); err != nil {
+		return nil, err
+	}
+	l.OrgID = orgID
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, orgID); err != nil {
+			return err
+		}
+		return s.putOrgLimits(ctx, tx, &l)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (s *Service) putOrgLimits(ctx context.Context, tx Tx, l *influxdb.OrgLimits) error {
+	key, err := l.OrgID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(l)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgLimitsBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, v)
+}
+
+func (s *Service) deleteOrgLimits(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	key, err := orgID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(orgLimitsBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(key); err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestBoltOrgLimitsService(t *testing.T) {
+	influxdbtesting.OrgLimitsService(initBoltOrgLimitsService, t)
+}
+
+func TestInmemOrgLimitsService(t *testing.T) {
+	influxdbtesting.OrgLimitsService(initInmemOrgLimitsService, t)
+}
+
+func initBoltOrgLimitsService(f influxdbtesting.OrgLimitsFields, t *testing.T) (influxdb.OrgLimitsService, func()) {
+	s, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initOrgLimitsService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemOrgLimitsService(f influxdbtesting.OrgLimitsFields, t *testing.T) (influxdb.OrgLimitsService, func()) {
+	s, closeInmem, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initOrgLimitsService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeInmem()
+	}
+}
+
+func initOrgLimitsService(s kv.Store, f influxdbtesting.OrgLimitsFields, t *testing.T) (influxdb.OrgLimitsService, func()) {
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing org limits service: %v", err)
+	}
+
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations: %v", err)
+		}
+	}
+	for _, l := range f.OrgLimits {
+		if _, err := svc.UpdateOrgLimits(ctx, l.OrgID, l); err != nil {
+			t.Fatalf("failed to populate org limits: %v", err)
+		}
+	}
+
+	return svc, func() {
+		for _, o := range f.Organizations {
+			if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organization: %v", err)
+			}
+		}
+	}
+}
@@ -78,95 +78,62 @@ type ServiceConfig struct {
 	Clock         clock.Clock
 }
 
-// Initialize creates Buckets needed.
+// Initialize creates Buckets needed by applying every migration without
+// recording it. It is meant for stores that are never migrated, such as those
+// used in tests; a store managed by a Migrator must only be migrated by it.
 func (s *Service) Initialize(ctx context.Context) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
-		if err := s.initializeAuths(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeDocuments(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeBuckets(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeDashboards(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeKVLog(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeLabels(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeOnboarding(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeOrgs(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeTasks(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializePasswords(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeScraperTargets(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeSecrets(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeSessions(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeSources(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeTelegraf(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeURMs(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.variableStore.Init(ctx, tx); err != nil {
-			return err
-		}
-
-		if err := s.initializeVariablesOrgIndex(tx); err != nil {
-			return err
-		}
-
-		if err := s.checkStore.Init(ctx, tx); err != nil {
-			return err
-
-		}
-
-		if err := s.initializeNotificationRule(ctx, tx); err != nil {
-			return err
+		for _, m := range s.Migrations() {
+			if err := m.Up(ctx, tx); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+}
 
-		if err := s.endpointStore.Init(ctx, tx); err != nil {
-			return err
-		}
+// Migrations returns the ordered list of migrations of the data stored by the
+// service. New migrations must be appended to the end of the list.
+func (s *Service) Migrations() []MigrationSpec {
+	return []MigrationSpec{
+		bucketMigration("create authorization buckets", s.initializeAuths),
+		bucketMigration("create document buckets", s.initializeDocuments),
+		bucketMigration("create bucket metadata buckets", s.initializeBuckets),
+		bucketMigration("create dashboard buckets", s.initializeDashboards),
+		bucketMigration("create dbrp mapping buckets", s.initializeDBRPMappings),
+		bucketMigration("create kv log buckets", s.initializeKVLog),
+		bucketMigration("create label buckets", s.initializeLabels),
+		bucketMigration("create onboarding buckets", s.initializeOnboarding),
+		bucketMigration("create organization buckets", s.initializeOrgs),
+		bucketMigration("create task buckets", s.initializeTasks),
+		bucketMigration("create password buckets", s.initializePasswords),
+		bucketMigration("create scraper target buckets", s.initializeScraperTargets),
+		bucketMigration("create secret buckets", s.initializeSecrets),
+		bucketMigration("create session buckets", s.initializeSessions),
+		bucketMigration("create source buckets", s.initializeSources),
+		bucketMigration("create telegraf config buckets", s.initializeTelegraf),
+		bucketMigration("create user resource mapping buckets", s.initializeURMs),
+		bucketMigration("create variable buckets", func(ctx context.Context, tx Tx) error {
+			if err := s.variableStore.Init(ctx, tx); err != nil {
+				return err
+			}
+			return s.initializeVariablesOrgIndex(tx)
+		}),
+		bucketMigration("create check buckets", s.checkStore.Init),
+		bucketMigration("create notification rule buckets", s.initializeNotificationRule),
+		bucketMigration("create notification endpoint buckets", s.endpointStore.Init),
+		bucketMigration("create user buckets", s.initializeUsers),
+		bucketMigration("create org limits bucket", s.initializeOrgLimits),
+		bucketMigration("create bucket schemas bucket", s.initializeBucketSchemas),
+		bucketMigration("create silences bucket", s.initializeSilences),
+	}
+}
 
-		return s.initializeUsers(ctx, tx)
+// bucketMigration returns a migration named name that creates buckets using up.
+// Buckets are never removed, so reverting the migration keeps them.
+func bucketMigration(name string, up MigrationFunc) MigrationSpec {
+	return NewAnonymousMigration(name, up, func(context.Context, Tx) error {
+		return nil
 	})
 }
 
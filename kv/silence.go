@@ -0,0 +1,294 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var silenceBucket = []byte("silencesv1")
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+func (s *Service) initializeSilences(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(silenceBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		silence, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		sl = silence
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrSilenceNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sl influxdb.Silence
+	if err := json.Unmarshal(v, &sl); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &sl, nil
+}
+
+// FindSilences returns a list of silences that match the filter and the total
+// count of matching silences.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	var ss []*influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if filter.ID != nil {
+			sl, err := s.findSilenceByID(ctx, tx, *filter.ID)
+			if err != nil {
+				return err
+			}
+			if filter.OrgID == nil || sl.OrgID == *filter.OrgID {
+				ss = append(ss, sl)
+			}
+			return nil
+		}
+
+		silences, err := s.findSilences(ctx, tx, filter, opt...)
+		if err != nil {
+			return err
+		}
+		ss = silences
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return ss, len(ss), nil
+}
+
+func (s *Service) findSilences(ctx context.Context, tx Tx, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, error) {
+	var offset, limit, count int
+	if len(opt) > 0 {
+		offset = opt[0].Offset
+		limit = opt[0].Limit
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := []*influxdb.Silence{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		var sl influxdb.Silence
+		if err := json.Unmarshal(v, &sl); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filter.OrgID != nil && sl.OrgID != *filter.OrgID {
+			continue
+		}
+		if count >= offset {
+			ss = append(ss, &sl)
+		}
+		count++
+		if limit > 0 && len(ss) >= limit {
+			break
+		}
+	}
+	return ss, nil
+}
+
+// CreateSilence creates a new silence and sets sl.ID with the new identifier.
+// The tasks of the notification rules matching the silence are updated to
+// apply it.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := sl.Valid(); err != nil {
+		return err
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, sl.OrgID); err != nil {
+			return err
+		}
+
+		sl.ID = s.IDGenerator.ID()
+		now := s.TimeGenerator.Now()
+		sl.SetCreatedAt(now)
+		sl.SetUpdatedAt(now)
+		if err := s.putSilence(ctx, tx, sl); err != nil {
+			return err
+		}
+		return s.updateSilencedNotificationTasks(ctx, tx, sl)
+	})
+}
+
+// UpdateSilence updates a single silence with the changeset. The tasks of
+// the notification rules matching the silence before or after the update are
+// updated to apply it.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		old, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *old
+		upd.Apply(&updated)
+		if err := updated.Valid(); err != nil {
+			return err
+		}
+		updated.SetUpdatedAt(s.TimeGenerator.Now())
+
+		if err := s.putSilence(ctx, tx, &updated); err != nil {
+			return err
+		}
+		if err := s.updateSilencedNotificationTasks(ctx, tx, old, &updated); err != nil {
+			return err
+		}
+		sl = &updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// DeleteSilence removes a silence by ID. The tasks of the notification rules
+// matching the silence are updated to no longer apply it.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		sl, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		key, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+		return s.updateSilencedNotificationTasks(ctx, tx, sl)
+	})
+}
+
+func (s *Service) putSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	key, err := sl.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, v)
+}
+
+// setNotificationRuleSilences sets the silences of the organization of the
+// notification rule that apply to it and have not yet expired.
+func (s *Service) setNotificationRuleSilences(ctx context.Context, tx Tx, r influxdb.NotificationRule) error {
+	orgID := r.GetOrgID()
+	silences, err := s.findSilences(ctx, tx, influxdb.SilenceFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeGenerator.Now()
+	var active []*influxdb.Silence
+	for _, sl := range silences {
+		if !sl.Expired(now) && sl.Silences(r) {
+			active = append(active, sl)
+		}
+	}
+	r.SetSilences(active)
+	return nil
+}
+
+// updateSilencedNotificationTasks regenerates the tasks of the notification
+// rules matched by any of the silences.
+func (s *Service) updateSilencedNotificationTasks(ctx context.Context, tx Tx, silences ...*influxdb.Silence) error {
+	var nrs []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		for _, sl := range silences {
+			if sl.Silences(nr) {
+				nrs = append(nrs, nr)
+				break
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range nrs {
+		if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Silences(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			Name:   "http",
+			OrgID:  &org.ID,
+			Status: influxdb.Active,
+		},
+		URL:        "http://localhost:7777",
+		Method:     "POST",
+		AuthMethod: "none",
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, e, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	newRule := func(name, region string) influxdb.NotificationRule {
+		nr := &rule.HTTP{
+			Base: rule.Base{
+				Name:       name,
+				OrgID:      org.ID,
+				EndpointID: *e.ID,
+				Every:      &notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "h"}}},
+				TagRules: []notification.TagRule{
+					{
+						Tag:      influxdb.Tag{Key: "region", Value: region},
+						Operator: influxdb.Equal,
+					},
+				},
+				StatusRules: []notification.StatusRule{
+					{CurrentLevel: notification.Critical},
+				},
+			},
+		}
+		nrc := influxdb.NotificationRuleCreate{
+			NotificationRule: nr,
+			Status:           influxdb.Active,
+		}
+		if err := svc.CreateNotificationRule(ctx, nrc, 1); err != nil {
+			t.Fatal(err)
+		}
+		return nr
+	}
+	west := newRule("west", "us-west")
+	east := newRule("east", "us-east")
+
+	silenced := func(nr influxdb.NotificationRule) bool {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.GetTaskID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Contains(task.Flux, `_sent: "silenced"`)
+	}
+
+	now := time.Now().UTC()
+	sl := &influxdb.Silence{
+		OrgID:     org.ID,
+		Name:      "maintenance",
+		Tags:      []influxdb.Tag{{Key: "region", Value: "us-west"}},
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(time.Hour),
+	}
+	if err := svc.CreateSilence(ctx, sl); err != nil {
+		t.Fatal(err)
+	}
+	if !silenced(west) {
+		t.Error("expected the task of the matching rule to apply the silence")
+	}
+	if silenced(east) {
+		t.Error("expected the task of the other rule not to apply the silence")
+	}
+
+	ss, n, err := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ss[0].ID != sl.ID {
+		t.Fatalf("unexpected silences: %v", ss)
+	}
+
+	eastTags := []influxdb.Tag{{Key: "region", Value: "us-east"}}
+	if _, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{Tags: &eastTags}); err != nil {
+		t.Fatal(err)
+	}
+	if silenced(west) {
+		t.Error("expected the task of the rule no longer matching to drop the silence")
+	}
+	if !silenced(east) {
+		t.Error("expected the task of the newly matching rule to apply the silence")
+	}
+
+	past := now.Add(-time.Minute)
+	if _, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{EndTime: &past}); err != nil {
+		t.Fatal(err)
+	}
+	if silenced(east) {
+		t.Error("expected the task not to apply an expired silence")
+	}
+
+	if err := svc.DeleteSilence(ctx, sl.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindSilenceByID(ctx, sl.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected silence to be deleted, got %v", err)
+	}
+}
@@ -35,25 +35,27 @@ var _ influxdb.TaskService = (*Service)(nil)
 var _ backend.TaskControlService = (*Service)(nil)
 
 type kvTask struct {
-	ID              influxdb.ID            `json:"id"`
-	Type            string                 `json:"type,omitempty"`
-	OrganizationID  influxdb.ID            `json:"orgID"`
-	Organization    string                 `json:"org"`
-	OwnerID         influxdb.ID            `json:"ownerID"`
-	Name            string                 `json:"name"`
-	Description     string                 `json:"description,omitempty"`
-	Status          string                 `json:"status"`
-	Flux            string                 `json:"flux"`
-	Every           string                 `json:"every,omitempty"`
-	Cron            string                 `json:"cron,omitempty"`
-	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
-	LastRunError    string                 `json:"lastRunError,omitempty"`
-	Offset          influxdb.Duration      `json:"offset,omitempty"`
-	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
-	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
-	CreatedAt       time.Time              `json:"createdAt,omitempty"`
-	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	ID              influxdb.ID                `json:"id"`
+	Type            string                     `json:"type,omitempty"`
+	OrganizationID  influxdb.ID                `json:"orgID"`
+	Organization    string                     `json:"org"`
+	OwnerID         influxdb.ID                `json:"ownerID"`
+	Name            string                     `json:"name"`
+	Description     string                     `json:"description,omitempty"`
+	Status          string                     `json:"status"`
+	Flux            string                     `json:"flux"`
+	Every           string                     `json:"every,omitempty"`
+	Cron            string                     `json:"cron,omitempty"`
+	LastRunStatus   string                     `json:"lastRunStatus,omitempty"`
+	LastRunError    string                     `json:"lastRunError,omitempty"`
+	Offset          influxdb.Duration          `json:"offset,omitempty"`
+	LatestCompleted time.Time                  `json:"latestCompleted,omitempty"`
+	LatestScheduled time.Time                  `json:"latestScheduled,omitempty"`
+	CreatedAt       time.Time                  `json:"createdAt,omitempty"`
+	UpdatedAt       time.Time                  `json:"updatedAt,omitempty"`
+	Metadata        map[string]interface{}     `json:"metadata,omitempty"`
+	DependsOn       []influxdb.ID              `json:"dependsOn,omitempty"`
+	OnWrite         *influxdb.TaskWriteTrigger `json:"onWrite,omitempty"`
 }
 
 func kvToInfluxTask(k *kvTask) *influxdb.Task {
@@ -77,6 +79,8 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		CreatedAt:       k.CreatedAt,
 		UpdatedAt:       k.UpdatedAt,
 		Metadata:        k.Metadata,
+		DependsOn:       k.DependsOn,
+		OnWrite:         k.OnWrite,
 	}
 }
 
@@ -594,6 +598,8 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
+		DependsOn:       tc.DependsOn,
+		OnWrite:         tc.OnWrite,
 	}
 
 	if opt.Offset != nil {
@@ -605,6 +611,13 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	}
 
+	if err := s.validateTaskDependencies(ctx, tx, task); err != nil {
+		return nil, err
+	}
+	if err := s.validateTaskWriteTrigger(ctx, tx, task); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -749,6 +762,25 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.DependsOn != nil {
+		task.DependsOn = *upd.DependsOn
+		if err := s.validateTaskDependencies(ctx, tx, task); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = updatedAt
+	}
+
+	if upd.OnWrite != nil {
+		task.OnWrite = upd.OnWrite
+		if !upd.OnWrite.BucketID.Valid() {
+			task.OnWrite = nil
+		}
+		if err := s.validateTaskWriteTrigger(ctx, tx, task); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = updatedAt
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -793,6 +825,112 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 	return task, bucket.Put(key, taskBytes)
 }
 
+// validateTaskDependencies returns an error if the task cannot depend on its
+// upstream tasks, either because they are not tasks of its organization or
+// because they depend on the task themselves.
+func (s *Service) validateTaskDependencies(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	seen := make(map[influxdb.ID]bool, len(task.DependsOn))
+	for _, id := range task.DependsOn {
+		switch {
+		case id == task.ID:
+			return influxdb.ErrInvalidTaskDependency(id, "a task cannot depend on itself")
+		case seen[id]:
+			return influxdb.ErrInvalidTaskDependency(id, "duplicate upstream task")
+		}
+		seen[id] = true
+
+		up, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound {
+			return influxdb.ErrInvalidTaskDependency(id, "task not found")
+		}
+		if err != nil {
+			return err
+		}
+		if up.OrganizationID != task.OrganizationID {
+			return influxdb.ErrInvalidTaskDependency(id, "task belongs to another organization")
+		}
+	}
+
+	// walk up from the upstream tasks looking for a path back to the task.
+	visited := make(map[influxdb.ID]bool)
+	queue := append([]influxdb.ID(nil), task.DependsOn...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == task.ID {
+			return influxdb.ErrTaskDependencyCycle(task.ID)
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		up, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		queue = append(queue, up.DependsOn...)
+	}
+	return nil
+}
+
+// checkNoTaskDependents returns an error if any task of the organization of task
+// lists task as one of its upstream tasks.
+func (s *Service) checkNoTaskDependents(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	filter := influxdb.TaskFilter{
+		OrganizationID: &task.OrganizationID,
+		Limit:          influxdb.TaskMaxPageSize,
+	}
+	for {
+		ts, _, err := s.findTasksByOrg(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		for _, t := range ts {
+			for _, up := range t.DependsOn {
+				if up == task.ID {
+					return influxdb.ErrTaskHasDependents(task.ID, t.ID)
+				}
+			}
+		}
+		if len(ts) < filter.Limit {
+			return nil
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+}
+
+// validateTaskWriteTrigger returns an error if the task cannot be triggered by
+// writes to the bucket of its write trigger.
+func (s *Service) validateTaskWriteTrigger(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	if task.OnWrite == nil {
+		return nil
+	}
+
+	b, err := s.findBucketByID(ctx, tx, task.OnWrite.BucketID)
+	if err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return influxdb.ErrInvalidTaskWriteTrigger("bucket not found")
+		}
+		return err
+	}
+	if b.OrgID != task.OrganizationID {
+		return influxdb.ErrInvalidTaskWriteTrigger("bucket belongs to another organization")
+	}
+
+	debounce, err := task.OnWrite.DebounceDuration()
+	if err != nil {
+		return influxdb.ErrInvalidTaskWriteTrigger("debounce: " + err.Error())
+	}
+	if debounce < time.Second {
+		return influxdb.ErrInvalidTaskWriteTrigger("debounce must be at least 1 second")
+	}
+	return nil
+}
+
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
@@ -831,6 +969,11 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return err
 	}
 
+	// a dependent task would wait forever on an upstream task that no longer exists.
+	if err := s.checkNoTaskDependents(ctx, tx, task); err != nil {
+		return err
+	}
+
 	// remove the orgs index
 	orgKey, err := taskOrgKey(task.OrganizationID, task.ID)
 	if err != nil {
@@ -239,6 +239,136 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_TaskDependencies(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	create := func(name string, dependsOn ...influxdb.ID) (*influxdb.Task, error) {
+		return ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           `option task = {name: "` + name + `", every: 1h} from(bucket:"test") |> range(start:-1h)`,
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+			DependsOn:      dependsOn,
+		})
+	}
+
+	a, err := create("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := create("b", a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := create("c", b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := ts.Service.FindTaskByID(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]influxdb.ID{b.ID}, found.DependsOn); diff != "" {
+		t.Errorf("unexpected upstream tasks -want/+got:\n%s", diff)
+	}
+
+	if _, err := create("d", a.ID, a.ID); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected duplicate upstream tasks to be invalid, got %v", err)
+	}
+	if _, err := create("d", influxdb.ID(1)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a missing upstream task to be invalid, got %v", err)
+	}
+
+	// a -> b -> c -> a is a cycle.
+	if _, err := ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{c.ID}}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected a cycle to be invalid, got %v", err)
+	}
+	if _, err := ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{a.ID}}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected a task depending on itself to be invalid, got %v", err)
+	}
+
+	// c no longer depends on anything.
+	updated, err := ts.Service.UpdateTask(ctx, c.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.DependsOn) != 0 {
+		t.Errorf("expected no upstream tasks, got %v", updated.DependsOn)
+	}
+	if _, err := ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &[]influxdb.ID{c.ID}}); err != nil {
+		t.Errorf("expected a to depend on c once the cycle is broken, got %v", err)
+	}
+
+	// b depends on a, so a cannot be deleted before b.
+	if err := ts.Service.DeleteTask(ctx, a.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected deleting a task with dependents to conflict, got %v", err)
+	}
+	for _, id := range []influxdb.ID{b.ID, a.ID, c.ID} {
+		if err := ts.Service.DeleteTask(ctx, id); err != nil {
+			t.Fatalf("DeleteTask(%s): %v", id, err)
+		}
+	}
+}
+
+func TestService_TaskWriteTrigger(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	b := &influxdb.Bucket{OrgID: ts.Org.ID, Name: "trigger"}
+	if err := ts.Service.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(onWrite *influxdb.TaskWriteTrigger) (*influxdb.Task, error) {
+		return ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           `option task = {name: "a", every: 1h} from(bucket:"test") |> range(start:-1h)`,
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+			OnWrite:        onWrite,
+		})
+	}
+
+	trigger := &influxdb.TaskWriteTrigger{BucketID: b.ID, Measurement: "cpu", Tags: map[string]string{"host": "a"}, Debounce: "10s"}
+	task, err := create(trigger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := ts.Service.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(trigger, found.OnWrite); diff != "" {
+		t.Errorf("unexpected write trigger -want/+got:\n%s", diff)
+	}
+
+	if _, err := create(&influxdb.TaskWriteTrigger{BucketID: influxdb.ID(1)}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a missing bucket to be invalid, got %v", err)
+	}
+	if _, err := create(&influxdb.TaskWriteTrigger{BucketID: b.ID, Debounce: "10ms"}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a debounce under a second to be invalid, got %v", err)
+	}
+
+	// a trigger without a bucket removes the trigger of the task.
+	updated, err := ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{OnWrite: &influxdb.TaskWriteTrigger{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.OnWrite != nil {
+		t.Errorf("expected no write trigger, got %+v", updated.OnWrite)
+	}
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, close, err := NewTestBoltStore(t)
 	if err != nil {
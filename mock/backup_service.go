@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+
+// BackupService is a mock implementation of influxdb.BackupService.
+type BackupService struct {
+	CreateBackupFn       func(ctx context.Context) (int, []string, error)
+	FetchBackupFileFn    func(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+	DeleteBackupFn       func(ctx context.Context, backupID int) error
+	InternalBackupPathFn func(backupID int) string
+}
+
+// NewBackupService returns a mock BackupService where its methods will return
+// zero values.
+func NewBackupService() *BackupService {
+	return &BackupService{
+		CreateBackupFn: func(ctx context.Context) (int, []string, error) {
+			return 0, nil, fmt.Errorf("not implemented")
+		},
+		FetchBackupFileFn: func(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+			return fmt.Errorf("not implemented")
+		},
+		DeleteBackupFn: func(ctx context.Context, backupID int) error {
+			return fmt.Errorf("not implemented")
+		},
+		InternalBackupPathFn: func(backupID int) string {
+			return ""
+		},
+	}
+}
+
+// CreateBackup creates a backup.
+func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+	return s.CreateBackupFn(ctx)
+}
+
+// FetchBackupFile writes a backup file to w.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	return s.FetchBackupFileFn(ctx, backupID, backupFile, w)
+}
+
+// DeleteBackup removes a backup.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	return s.DeleteBackupFn(ctx, backupID)
+}
+
+// InternalBackupPath returns the on-disk location of a backup.
+func (s *BackupService) InternalBackupPath(backupID int) string {
+	return s.InternalBackupPathFn(backupID)
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService is a mock implementation of influxdb.BucketSchemaService.
+type BucketSchemaService struct {
+	FindBucketSchemaFn   func(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error)
+	UpdateBucketSchemaFn func(ctx context.Context, bucketID influxdb.ID, s influxdb.BucketSchema) (*influxdb.BucketSchema, error)
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where no bucket has
+// a schema and updates are returned unchanged.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindBucketSchemaFn: func(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+			return &influxdb.BucketSchema{BucketID: bucketID, Measurements: []influxdb.MeasurementSchema{}}, nil
+		},
+		UpdateBucketSchemaFn: func(ctx context.Context, bucketID influxdb.ID, s influxdb.BucketSchema) (*influxdb.BucketSchema, error) {
+			s.BucketID = bucketID
+			return &s, nil
+		},
+	}
+}
+
+// FindBucketSchema returns the schema of a bucket.
+func (s *BucketSchemaService) FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	return s.FindBucketSchemaFn(ctx, bucketID)
+}
+
+// UpdateBucketSchema replaces the schema of a bucket.
+func (s *BucketSchemaService) UpdateBucketSchema(ctx context.Context, bucketID influxdb.ID, schema influxdb.BucketSchema) (*influxdb.BucketSchema, error) {
+	return s.UpdateBucketSchemaFn(ctx, bucketID, schema)
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.OrgLimitsService = (*OrgLimitsService)(nil)
+
+// OrgLimitsService is a mock implementation of influxdb.OrgLimitsService.
+type OrgLimitsService struct {
+	FindOrgLimitsFn   func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error)
+	UpdateOrgLimitsFn func(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error)
+}
+
+// NewOrgLimitsService returns a mock OrgLimitsService where every organization
+// is unlimited and updates are returned unchanged.
+func NewOrgLimitsService() *OrgLimitsService {
+	return &OrgLimitsService{
+		FindOrgLimitsFn: func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+			return &influxdb.OrgLimits{OrgID: orgID}, nil
+		},
+		UpdateOrgLimitsFn: func(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error) {
+			l.OrgID = orgID
+			return &l, nil
+		},
+	}
+}
+
+// FindOrgLimits returns the limits of an organization.
+func (s *OrgLimitsService) FindOrgLimits(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+	return s.FindOrgLimitsFn(ctx, orgID)
+}
+
+// UpdateOrgLimits replaces the limits of an organization.
+func (s *OrgLimitsService) UpdateOrgLimits(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error) {
+	return s.UpdateOrgLimitsFn(ctx, orgID, l)
+}
+
+var _ influxdb.OrgLimitEnforcer = (*OrgLimitEnforcer)(nil)
+
+// OrgLimitEnforcer is a mock implementation of influxdb.OrgLimitEnforcer.
+type OrgLimitEnforcer struct {
+	AllowWriteRequestFn func(ctx context.Context, orgID influxdb.ID) error
+	AllowWriteBytesFn   func(ctx context.Context, orgID influxdb.ID, n int) error
+	AcquireQueryFn      func(ctx context.Context, orgID influxdb.ID) (func(), error)
+}
+
+// NewOrgLimitEnforcer returns a mock OrgLimitEnforcer that allows everything.
+func NewOrgLimitEnforcer() *OrgLimitEnforcer {
+	return &OrgLimitEnforcer{
+		AllowWriteRequestFn: func(ctx context.Context, orgID influxdb.ID) error {
+			return nil
+		},
+		AllowWriteBytesFn: func(ctx context.Context, orgID influxdb.ID, n int) error {
+			return nil
+		},
+		AcquireQueryFn: func(ctx context.Context, orgID influxdb.ID) (func(), error) {
+			return func() {}, nil
+		},
+	}
+}
+
+// AllowWriteRequest reports whether the organization may make another write request.
+func (e *OrgLimitEnforcer) AllowWriteRequest(ctx context.Context, orgID influxdb.ID) error {
+	return e.AllowWriteRequestFn(ctx, orgID)
+}
+
+// AllowWriteBytes reports whether the organization may write n bytes.
+func (e *OrgLimitEnforcer) AllowWriteBytes(ctx context.Context, orgID influxdb.ID, n int) error {
+	return e.AllowWriteBytesFn(ctx, orgID, n)
+}
+
+// AcquireQuery reserves one of the concurrent queries of the organization.
+func (e *OrgLimitEnforcer) AcquireQuery(ctx context.Context, orgID influxdb.ID) (func(), error) {
+	return e.AcquireQueryFn(ctx, orgID)
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskBackfillService)(nil)
+
+// TaskBackfillService is a mock implementation of influxdb.TaskBackfillService.
+type TaskBackfillService struct {
+	BackfillTaskFn       func(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error)
+	FindTaskBackfillFn   func(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error)
+	CancelTaskBackfillFn func(ctx context.Context, taskID influxdb.ID) error
+}
+
+// NewTaskBackfillService returns a mock TaskBackfillService where backfills
+// start without runs and no task has a backfill to find or cancel.
+func NewTaskBackfillService() *TaskBackfillService {
+	return &TaskBackfillService{
+		BackfillTaskFn: func(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+			return &influxdb.TaskBackfill{TaskID: taskID, Start: start, Stop: stop, Status: influxdb.TaskBackfillRunning}, nil
+		},
+		FindTaskBackfillFn: func(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+			return nil, influxdb.ErrTaskBackfillNotFound
+		},
+		CancelTaskBackfillFn: func(ctx context.Context, taskID influxdb.ID) error {
+			return influxdb.ErrTaskBackfillNotFound
+		},
+	}
+}
+
+// BackfillTask starts a backfill of the task over the range.
+func (s *TaskBackfillService) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	return s.BackfillTaskFn(ctx, taskID, start, stop)
+}
+
+// FindTaskBackfill returns the progress of the latest backfill of the task.
+func (s *TaskBackfillService) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	return s.FindTaskBackfillFn(ctx, taskID)
+}
+
+// CancelTaskBackfill stops the running backfill of the task.
+func (s *TaskBackfillService) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	return s.CancelTaskBackfillFn(ctx, taskID)
+}
@@ -417,6 +417,73 @@ func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 	return points, nil
 }
 
+// LineError is an error parsing a single line of line protocol.
+type LineError struct {
+	Line int // Line number, starting at 1.
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ParseLinesWithPrecision is similar to ParsePointsWithPrecision, but parses each
+// line independently of the others. It returns the points of all lines that are
+// valid along with the line number of each point, and an error for each line
+// that is not. A line that is not valid contributes no points.
+func ParseLinesWithPrecision(buf []byte, mm []byte, defaultTime time.Time, precision string) (points []Point, lines []int, errs []LineError) {
+	points = make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	lines = make([]int, 0, cap(points))
+
+	var (
+		pos, prev int
+		line      = 1
+		block     []byte
+	)
+	for pos < len(buf) {
+		// Line numbers count every newline, including those within a block.
+		line += bytes.Count(buf[prev:pos], []byte{'\n'})
+		prev = pos
+
+		pos, block = scanLine(buf, pos)
+		pos++
+
+		if len(block) == 0 {
+			continue
+		}
+
+		// lines which start with '#' are comments
+		start := skipWhitespace(block, 0)
+
+		// If line is all whitespace, just skip it
+		if start >= len(block) || block[start] == '#' {
+			continue
+		}
+
+		// strip the newline if one is present
+		if block[len(block)-1] == '\n' {
+			block = block[:len(block)-1]
+		}
+
+		n := len(points)
+		parsed, err := parsePointsAppend(points, block[start:], mm, defaultTime, precision, true)
+		if err != nil {
+			points = points[:n]
+			errs = append(errs, LineError{
+				Line: line,
+				Err:  fmt.Errorf("unable to parse '%s': %v", string(block[start:]), err),
+			})
+			continue
+		}
+
+		points = parsed
+		for i := n; i < len(points); i++ {
+			lines = append(lines, line)
+		}
+	}
+	return points, lines, errs
+}
+
 func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) ([]Point, error) {
 	// scan the first block which is measurement[,tag1=value1,tag2=value=2...]
 	pos, key, err := scanKey(buf, 0)
@@ -2134,6 +2134,43 @@ cpu,host=serverA,region=us-east value=1.0 946730096789012345`,
 	}
 }
 
+func TestParseLinesWithPrecision(t *testing.T) {
+	batch := `# comment
+cpu,host=a value=1,count=2i 1
+
+cpu,host=b value= 1
+mem,host=a text="multi
+line" 1
+cpu,host=c value=3 1
+cpu,host=d value=1 bad`
+
+	pts, lines, errs := models.ParseLinesWithPrecision([]byte(batch), []byte("mm"), time.Unix(0, 0), "ns")
+
+	// Every field of a line is parsed into a point of its own.
+	if got, exp := len(pts), 4; got != exp {
+		t.Fatalf("got %d points, exp %d", got, exp)
+	}
+	if got, exp := lines, []int{2, 2, 5, 7}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got lines %v, exp %v", got, exp)
+	}
+	if got, exp := pts[3].String(), "mm,\x00=cpu,host=c,\xff=value value=3 1"; got != exp {
+		t.Errorf("got point %q, exp %q", got, exp)
+	}
+
+	if got, exp := len(errs), 2; got != exp {
+		t.Fatalf("got %d errors, exp %d: %v", got, exp, errs)
+	}
+	if got, exp := errs[0].Line, 4; got != exp {
+		t.Errorf("got error on line %d, exp %d", got, exp)
+	}
+	if got, exp := errs[1].Line, 8; got != exp {
+		t.Errorf("got error on line %d, exp %d", got, exp)
+	}
+	if got, exp := errs[1].Error(), "line 8: unable to parse 'cpu,host=d value=1 bad': bad timestamp"; got != exp {
+		t.Errorf("got error %q, exp %q", got, exp)
+	}
+}
+
 func TestNewPointEscaped(t *testing.T) {
 	// commas
 	pt := models.MustNewPoint("cpu,main", models.NewTags(map[string]string{"tag,bar": "value"}), models.Fields{"name,bar": 1.0}, time.Unix(0, 0))
@@ -31,6 +31,8 @@ type NotificationRule interface {
 	GetLimit() *Limit
 	GenerateFlux(NotificationEndpoint) (string, error)
 	MatchesTags(tags []Tag) bool
+	// SetSilences sets the silences applied by GenerateFlux.
+	SetSilences(silences []*Silence)
 }
 
 // NotificationRuleStore represents a service for managing notification rule.
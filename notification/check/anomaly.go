@@ -0,0 +1,368 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Anomaly)(nil)
+
+// Anomaly baseline methods.
+const (
+	// AnomalyMethodMean learns the baseline of a series as the mean and the
+	// standard deviation of its values.
+	AnomalyMethodMean = "mean"
+	// AnomalyMethodMedian learns the baseline of a series as the median and
+	// the interquartile range of its values, normalized to a standard
+	// deviation. It is less sensitive to past outliers than the mean.
+	AnomalyMethodMedian = "median"
+)
+
+// iqrToStddev scales an interquartile range to the standard deviation of a
+// normal distribution.
+const iqrToStddev = 1.349
+
+// Anomaly is the anomaly check. It learns a baseline per series from the
+// values of its query over Window and sets the status level of the latest
+// values by their number of deviations from the baseline. The deviation
+// score of a value is available to the status message as r._anomaly_score.
+type Anomaly struct {
+	Base
+	// Window is the duration of history the baseline is learned from.
+	Window *notification.Duration `json:"window,omitempty"`
+	Method string                 `json:"method"`
+	// Thresholds are the number of deviations from the baseline that set
+	// each level.
+	Thresholds []AnomalyThreshold `json:"thresholds"`
+}
+
+// AnomalyThreshold sets Level for values more than Deviations away from the
+// baseline, in either direction.
+type AnomalyThreshold struct {
+	Level      notification.CheckLevel `json:"level"`
+	Deviations float64                 `json:"deviations"`
+}
+
+// Type returns the type of the check.
+func (c Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (c Anomaly) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	if c.Window == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly window is required",
+		}
+	}
+	if c.Every != nil && c.Window.TimeDuration() <= c.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly window must be greater than the interval",
+		}
+	}
+	if c.Method != AnomalyMethodMean && c.Method != AnomalyMethodMedian {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("anomaly method must be %q or %q", AnomalyMethodMean, AnomalyMethodMedian),
+		}
+	}
+	if len(c.Thresholds) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly check must have at least one threshold",
+		}
+	}
+	levels := make(map[notification.CheckLevel]bool)
+	for _, th := range c.Thresholds {
+		if th.Deviations <= 0 {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly threshold deviations must be greater than 0",
+			}
+		}
+		if levels[th.Level] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("anomaly check has more than one %s threshold", th.Level),
+			}
+		}
+		levels[th.Level] = true
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the anomaly check provided.
+func (c Anomaly) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Anomaly) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(c.Query.Text)
+	replaceDurationsWithEvery(p, c.Every)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	fields := getFields(p)
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	// The baseline is the same query over the window of the check.
+	baseline := parser.ParseSource(c.Query.Text)
+	replaceDurationsWithEvery(baseline, c.Every)
+	replaceRangeStart(baseline, c.Window)
+	removeStopFromRange(baseline)
+	addCreateEmptyFalseToAggregateWindow(baseline)
+	if len(baseline.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(baseline.Files))
+	}
+	baselineQuery, err := pipelineExpression(baseline.Files[0])
+	if err != nil {
+		return nil, err
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor")...)
+	f.Body = append(f.Body, c.generateFluxASTBody(baselineQuery, fields[0])...)
+
+	return p, nil
+}
+
+func replaceRangeStart(pkg *ast.Package, start *notification.Duration) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if id, ok := call.Callee.(*ast.Identifier); ok && id.Name == "range" {
+				for _, args := range call.Arguments {
+					if obj, ok := args.(*ast.ObjectExpression); ok {
+						for _, prop := range obj.Properties {
+							if prop.Key.Key() == "start" {
+								newStart := (ast.DurationLiteral)(*start)
+								prop.Value = flux.Negative(&newStart)
+							}
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+func (c Anomaly) generateFluxASTBody(baselineQuery ast.Expression, field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("anomaly"))
+	statements = append(statements, c.generateFluxASTBaseline(baselineQuery)...)
+	statements = append(statements, c.generateFluxASTThresholdFunctions()...)
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	statements = append(statements, c.generateFluxASTChecksFunction(field))
+	return statements
+}
+
+// baselineStats returns the columns of the baseline statistics of the method
+// and the aggregate computing each of them.
+func (c Anomaly) baselineStats() ([]string, []*ast.CallExpression) {
+	if c.Method == AnomalyMethodMedian {
+		quantile := func(q float64) *ast.CallExpression {
+			return flux.Call(flux.Identifier("quantile"), flux.Object(
+				flux.Property("q", flux.Float(q)),
+				flux.Property("method", flux.String("exact_mean")),
+			))
+		}
+		return []string{"_baseline", "_q1", "_q3"}, []*ast.CallExpression{
+			flux.Call(flux.Identifier("median"), flux.Object()),
+			quantile(0.25),
+			quantile(0.75),
+		}
+	}
+	return []string{"_baseline", "_deviation"}, []*ast.CallExpression{
+		flux.Call(flux.Identifier("mean"), flux.Object()),
+		flux.Call(flux.Identifier("stddev"), flux.Object()),
+	}
+}
+
+func (c Anomaly) generateFluxASTBaseline(baselineQuery ast.Expression) []ast.Statement {
+	statements := []ast.Statement{
+		flux.DefineVariable("baseline", flux.Pipe(baselineQuery, dropColumns("_start", "_stop"), toFloat())),
+	}
+
+	columns, aggregates := c.baselineStats()
+	for i, column := range columns {
+		statements = append(statements, flux.DefineVariable("baseline"+column, flux.Pipe(
+			flux.Identifier("baseline"),
+			aggregates[i],
+			flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.ObjectWith("r",
+					flux.Property(column, flux.Member("r", "_value")),
+					flux.Property("_order", flux.Integer(0)),
+				),
+			)))),
+		)))
+	}
+	return statements
+}
+
+// toFloat casts the values to floats, so that the statistics and the score of
+// integer fields type check.
+func toFloat() *ast.CallExpression {
+	return flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+		flux.FunctionParams("r"),
+		flux.ObjectWith("r", flux.Property("_value", flux.Call(
+			flux.Identifier("float"),
+			flux.Object(flux.Property("v", flux.Member("r", "_value"))),
+		))),
+	))))
+}
+
+func (c Anomaly) generateFluxASTThresholdFunctions() []ast.Statement {
+	statements := make([]ast.Statement, 0, len(c.Thresholds))
+	for _, th := range c.Thresholds {
+		fn := flux.Function(flux.FunctionParams("r"), flux.Or(
+			flux.GreaterThan(flux.Member("r", "_anomaly_score"), flux.Float(th.Deviations)),
+			flux.LessThan(flux.Member("r", "_anomaly_score"), flux.Float(-th.Deviations)),
+		))
+		statements = append(statements, flux.DefineVariable(strings.ToLower(th.Level.String()), fn))
+	}
+	return statements
+}
+
+// generateFluxASTChecksFunction joins the latest values of each series with
+// its baseline statistics. The statistics are a row per series, so they are
+// sorted before the values of the series and filled into them.
+func (c Anomaly) generateFluxASTChecksFunction(field string) ast.Statement {
+	columns, _ := c.baselineStats()
+
+	tables := make([]ast.Expression, 0, len(columns)+1)
+	for _, column := range columns {
+		tables = append(tables, flux.Identifier("baseline"+column))
+	}
+	tables = append(tables, flux.Pipe(
+		flux.Identifier("data"),
+		dropColumns("_start", "_stop"),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+			flux.FunctionParams("r"),
+			flux.ObjectWith("r",
+				flux.Property("_value", flux.Call(
+					flux.Identifier("float"),
+					flux.Object(flux.Property("v", flux.Member("r", "_value"))),
+				)),
+				flux.Property("_order", flux.Integer(1)),
+			),
+		)))),
+	))
+
+	calls := []*ast.CallExpression{
+		flux.Call(flux.Identifier("sort"), flux.Object(
+			flux.Property("columns", flux.Array(flux.String("_order"))),
+		)),
+	}
+	for _, column := range columns {
+		calls = append(calls, flux.Call(flux.Identifier("fill"), flux.Object(
+			flux.Property("column", flux.String(column)),
+			flux.Property("usePrevious", flux.Bool(true)),
+		)))
+	}
+	calls = append(calls, flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", flux.Function(
+		flux.FunctionParams("r"),
+		flux.Equal(flux.Member("r", "_order"), flux.Integer(1)),
+	)))))
+
+	if c.Method == AnomalyMethodMedian {
+		calls = append(calls, flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+			flux.FunctionParams("r"),
+			flux.ObjectWith("r", flux.Property("_deviation", &ast.BinaryExpression{
+				Operator: ast.DivisionOperator,
+				Left:     flux.Subtract(flux.Member("r", "_q3"), flux.Member("r", "_q1")),
+				Right:    flux.Float(iqrToStddev),
+			})),
+		)))))
+	}
+
+	score := flux.If(
+		flux.GreaterThan(flux.Member("r", "_deviation"), flux.Float(0)),
+		&ast.BinaryExpression{
+			Operator: ast.DivisionOperator,
+			Left:     flux.Subtract(flux.Member("r", "_value"), flux.Member("r", "_baseline")),
+			Right:    flux.Member("r", "_deviation"),
+		},
+		flux.Float(0),
+	)
+	calls = append(calls,
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(
+			flux.FunctionParams("r"),
+			flux.ObjectWith("r",
+				flux.Property("_anomaly_score", score),
+				flux.Property(field, flux.Member("r", "_value")),
+			),
+		)))),
+		dropColumns("_order", "_field", "_value"),
+		c.generateFluxASTChecksCall(),
+	)
+
+	union := flux.Call(flux.Identifier("union"), flux.Object(flux.Property("tables", flux.Array(tables...))))
+	return flux.ExpressionStatement(flux.Pipe(union, calls...))
+}
+
+func (c Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	for _, th := range c.Thresholds {
+		lvl := strings.ToLower(th.Level.String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+func dropColumns(columns ...string) *ast.CallExpression {
+	cols := make([]ast.Expression, 0, len(columns))
+	for _, c := range columns {
+		cols = append(cols, flux.String(c))
+	}
+	return flux.Call(flux.Identifier("drop"), flux.Object(flux.Property("columns", flux.Array(cols...))))
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(c),
+			Type:         c.Type(),
+		})
+}
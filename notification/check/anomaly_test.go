@@ -0,0 +1,311 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	type args struct {
+		anomaly check.Anomaly
+	}
+	type wants struct {
+		script string
+	}
+
+	base := check.Base{
+		ID:   10,
+		Name: "moo",
+		Tags: []influxdb.Tag{
+			{Key: "aaa", Value: "vaaa"},
+		},
+		Every:                 mustDuration("1h"),
+		StatusMessageTemplate: "whoa! {r.usage_user}",
+		Query: influxdb.DashboardQuery{
+			Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "mean baseline",
+			args: args{
+				anomaly: check.Anomaly{
+					Base:   base,
+					Window: mustDuration("7d"),
+					Method: check.AnomalyMethodMean,
+					Thresholds: []check.AnomalyThreshold{
+						{Level: notification.Warn, Deviations: 2},
+						{Level: notification.Critical, Deviations: 3},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+baseline = from(bucket: "foo")
+	|> range(start: -7d)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+	|> drop(columns: ["_start", "_stop"])
+	|> map(fn: (r) =>
+		({r with _value: float(v: r._value)}))
+baseline_baseline = baseline
+	|> mean()
+	|> map(fn: (r) =>
+		({r with _baseline: r._value, _order: 0}))
+baseline_deviation = baseline
+	|> stddev()
+	|> map(fn: (r) =>
+		({r with _deviation: r._value, _order: 0}))
+warn = (r) =>
+	(r._anomaly_score > 2.0 or r._anomaly_score < -2.0)
+crit = (r) =>
+	(r._anomaly_score > 3.0 or r._anomaly_score < -3.0)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+union(tables: [baseline_baseline, baseline_deviation, data
+	|> drop(columns: ["_start", "_stop"])
+	|> map(fn: (r) =>
+		({r with _value: float(v: r._value), _order: 1}))])
+	|> sort(columns: ["_order"])
+	|> fill(column: "_baseline", usePrevious: true)
+	|> fill(column: "_deviation", usePrevious: true)
+	|> filter(fn: (r) =>
+		(r._order == 1))
+	|> map(fn: (r) =>
+		({r with _anomaly_score: if r._deviation > 0.0 then (r._value - r._baseline) / r._deviation else 0.0, usage_user: r._value}))
+	|> drop(columns: ["_order", "_field", "_value"])
+	|> monitor.check(
+		data: check,
+		messageFn: messageFn,
+		warn: warn,
+		crit: crit,
+	)`,
+			},
+		},
+		{
+			name: "median baseline",
+			args: args{
+				anomaly: check.Anomaly{
+					Base:   base,
+					Window: mustDuration("7d"),
+					Method: check.AnomalyMethodMedian,
+					Thresholds: []check.AnomalyThreshold{
+						{Level: notification.Critical, Deviations: 3},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+
+data = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+baseline = from(bucket: "foo")
+	|> range(start: -7d)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 1h, fn: mean, createEmpty: false)
+	|> drop(columns: ["_start", "_stop"])
+	|> map(fn: (r) =>
+		({r with _value: float(v: r._value)}))
+baseline_baseline = baseline
+	|> median()
+	|> map(fn: (r) =>
+		({r with _baseline: r._value, _order: 0}))
+baseline_q1 = baseline
+	|> quantile(q: 0.25, method: "exact_mean")
+	|> map(fn: (r) =>
+		({r with _q1: r._value, _order: 0}))
+baseline_q3 = baseline
+	|> quantile(q: 0.75, method: "exact_mean")
+	|> map(fn: (r) =>
+		({r with _q3: r._value, _order: 0}))
+crit = (r) =>
+	(r._anomaly_score > 3.0 or r._anomaly_score < -3.0)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+union(tables: [baseline_baseline, baseline_q1, baseline_q3, data
+	|> drop(columns: ["_start", "_stop"])
+	|> map(fn: (r) =>
+		({r with _value: float(v: r._value), _order: 1}))])
+	|> sort(columns: ["_order"])
+	|> fill(column: "_baseline", usePrevious: true)
+	|> fill(column: "_q1", usePrevious: true)
+	|> fill(column: "_q3", usePrevious: true)
+	|> filter(fn: (r) =>
+		(r._order == 1))
+	|> map(fn: (r) =>
+		({r with _deviation: (r._q3 - r._q1) / 1.349}))
+	|> map(fn: (r) =>
+		({r with _anomaly_score: if r._deviation > 0.0 then (r._value - r._baseline) / r._deviation else 0.0, usage_user: r._value}))
+	|> drop(columns: ["_order", "_field", "_value"])
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.args.anomaly.GenerateFlux()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tt.wants.script, s)
+		})
+	}
+}
+
+// TestAnomaly_GenerateFlux_integers runs the flux of the check on an integer
+// field, with the statuses returned rather than written.
+func TestAnomaly_GenerateFlux_integers(t *testing.T) {
+	now := time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)
+	data := `#datatype,string,long,dateTime:RFC3339,long,string,string,string
+#group,false,false,false,false,true,true,true
+#default,_result,,,,,,
+,result,table,_time,_value,_field,_measurement,host
+,,0,2020-01-07T03:00:00Z,11,usage_user,cpu,a
+,,0,2020-01-07T04:00:00Z,10,usage_user,cpu,a
+,,0,2020-01-07T05:00:00Z,12,usage_user,cpu,a
+,,0,2020-01-07T06:00:00Z,9,usage_user,cpu,a
+,,0,2020-01-07T07:00:00Z,11,usage_user,cpu,a
+,,0,2020-01-07T08:00:00Z,10,usage_user,cpu,a
+,,0,2020-01-07T09:00:00Z,12,usage_user,cpu,a
+,,0,2020-01-07T10:00:00Z,9,usage_user,cpu,a
+,,0,2020-01-07T11:00:00Z,11,usage_user,cpu,a
+,,0,2020-01-07T12:00:00Z,10,usage_user,cpu,a
+,,0,2020-01-07T13:00:00Z,12,usage_user,cpu,a
+,,0,2020-01-07T14:00:00Z,9,usage_user,cpu,a
+,,0,2020-01-07T15:00:00Z,11,usage_user,cpu,a
+,,0,2020-01-07T16:00:00Z,10,usage_user,cpu,a
+,,0,2020-01-07T17:00:00Z,12,usage_user,cpu,a
+,,0,2020-01-07T18:00:00Z,9,usage_user,cpu,a
+,,0,2020-01-07T19:00:00Z,11,usage_user,cpu,a
+,,0,2020-01-07T20:00:00Z,10,usage_user,cpu,a
+,,0,2020-01-07T21:00:00Z,12,usage_user,cpu,a
+,,0,2020-01-07T22:00:00Z,9,usage_user,cpu,a
+,,0,2020-01-07T23:30:00Z,30,usage_user,cpu,a
+`
+
+	for _, method := range []string{check.AnomalyMethodMean, check.AnomalyMethodMedian} {
+		t.Run(method, func(t *testing.T) {
+			c := check.Anomaly{
+				Base: check.Base{
+					ID:                    10,
+					Name:                  "moo",
+					Every:                 mustDuration("1h"),
+					StatusMessageTemplate: "whoa!",
+					Query: influxdb.DashboardQuery{
+						Text: `import "csv"
+csv.from(csv: "` + data + `") |> range(start: -1d) |> filter(fn: (r) => r._field == "usage_user")`,
+					},
+				},
+				Window: mustDuration("7d"),
+				Method: method,
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical, Deviations: 3},
+				},
+			}
+
+			p, err := c.GenerateFluxAST()
+			if err != nil {
+				t.Fatal(err)
+			}
+			write := parser.ParseSource(`option monitor.write = (tables=<-) => tables`)
+			p.Files[0].Body = append(write.Files[0].Body, p.Files[0].Body...)
+
+			levels := runFlux(t, p, now, "_level")
+			if want := []string{"crit"}; !assert.Equal(t, want, levels) {
+				t.Log(c.GenerateFlux())
+			}
+		})
+	}
+}
+
+// runFlux runs the flux of p and returns the string values of column.
+func runFlux(t *testing.T, p *ast.Package, now time.Time, column string) []string {
+	t.Helper()
+
+	ctx := dependenciestest.Default().Inject(context.Background())
+	q, err := lang.CompileAST(p, now).Start(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	var values []string
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				j := -1
+				for i, c := range cr.Cols() {
+					if c.Label == column {
+						j = i
+					}
+				}
+				if j < 0 {
+					return nil
+				}
+				for i := 0; i < cr.Len(); i++ {
+					values = append(values, cr.Strings(j).ValueString(i))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
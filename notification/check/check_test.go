@@ -135,6 +135,59 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "range threshold min can't be larger than max",
 			},
 		},
+		{
+			name: "anomaly window not greater than interval",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					StatusMessageTemplate: "temp1",
+					Every:                 mustDuration("1h"),
+				},
+				Window: mustDuration("1h"),
+				Method: check.AnomalyMethodMean,
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical, Deviations: 3},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly window must be greater than the interval",
+			},
+		},
+		{
+			name: "bad anomaly method",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("7d"),
+				Method: "mode",
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical, Deviations: 3},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `anomaly method must be "mean" or "median"`,
+			},
+		},
+		{
+			name: "duplicate anomaly level",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("7d"),
+				Method: check.AnomalyMethodMedian,
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Critical, Deviations: 3},
+					{Level: notification.Critical, Deviations: 4},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check has more than one CRIT threshold",
+			},
+		},
 	}
 	for _, c := range cases {
 		got := c.src.Valid()
@@ -243,6 +296,41 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple anomaly",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Window: mustDuration("7d"),
+				Method: check.AnomalyMethodMedian,
+				Thresholds: []check.AnomalyThreshold{
+					{Level: notification.Warn, Deviations: 2},
+					{Level: notification.Critical, Deviations: 3.5},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		fn := func(t *testing.T) {
@@ -231,30 +231,40 @@ func getFields(pkg *ast.Package) []string {
 }
 
 func assignPipelineToData(f *ast.File) error {
+	exp, err := pipelineExpression(f)
+	if err != nil {
+		return err
+	}
+
+	f.Body[0] = flux.DefineVariable("data", exp)
+	return nil
+}
+
+// pipelineExpression returns the pipeline of the single statement of f,
+// without a trailing yield.
+func pipelineExpression(f *ast.File) (ast.Expression, error) {
 	if len(f.Body) != 1 {
-		return fmt.Errorf("expected there to be a single statement in the flux script body, recieved %d", len(f.Body))
+		return nil, fmt.Errorf("expected there to be a single statement in the flux script body, recieved %d", len(f.Body))
 	}
 
 	stmt := f.Body[0]
 
 	e, ok := stmt.(*ast.ExpressionStatement)
 	if !ok {
-		return fmt.Errorf("statement is not an *ast.Expression statement, recieved %T", stmt)
+		return nil, fmt.Errorf("statement is not an *ast.Expression statement, recieved %T", stmt)
 	}
 
 	exp := e.Expression
 
 	pipe, ok := exp.(*ast.PipeExpression)
 	if !ok {
-		return fmt.Errorf("expression is not an *ast.PipeExpression statement, recieved %T", exp)
+		return nil, fmt.Errorf("expression is not an *ast.PipeExpression statement, recieved %T", exp)
 	}
 
 	if id, ok := pipe.Call.Callee.(*ast.Identifier); ok && id.Name == "yield" {
 		exp = pipe.Argument
 	}
-
-	f.Body[0] = flux.DefineVariable("data", exp)
-	return nil
+	return exp, nil
 }
 
 func (t Threshold) generateFluxASTBody(field string) []ast.Statement {
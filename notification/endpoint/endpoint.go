@@ -12,12 +12,18 @@ const (
 	SlackType     = "slack"
 	PagerDutyType = "pagerduty"
 	HTTPType      = "http"
+	SMTPType      = "smtp"
+	OpsgenieType  = "opsgenie"
+	TeamsType     = "teams"
 )
 
 var typeToEndpoint = map[string](func() influxdb.NotificationEndpoint){
 	SlackType:     func() influxdb.NotificationEndpoint { return &Slack{} },
 	PagerDutyType: func() influxdb.NotificationEndpoint { return &PagerDuty{} },
 	HTTPType:      func() influxdb.NotificationEndpoint { return &HTTP{} },
+	SMTPType:      func() influxdb.NotificationEndpoint { return &SMTP{} },
+	OpsgenieType:  func() influxdb.NotificationEndpoint { return &Opsgenie{} },
+	TeamsType:     func() influxdb.NotificationEndpoint { return &Teams{} },
 }
 
 // UnmarshalJSON will convert the bytes to notification endpoint.
@@ -136,6 +136,118 @@ func TestValidEndpoint(t *testing.T) {
 				Msg:  "invalid http username/password for basic auth",
 			},
 		},
+		{
+			name: "invalid http content template",
+			src: &endpoint.HTTP{
+				Base:            goodBase,
+				URL:             "localhost",
+				Method:          http.MethodPost,
+				AuthMethod:      "none",
+				ContentTemplate: `{"text": "${r._message}"`,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http endpoint content template is not valid JSON",
+			},
+		},
+		{
+			name: "valid http content template",
+			src: &endpoint.HTTP{
+				Base:            goodBase,
+				URL:             "localhost",
+				Method:          http.MethodPost,
+				AuthMethod:      "none",
+				ContentTemplate: `{"text": "${r._check_name} is ${r._level}"}`,
+			},
+			err: nil,
+		},
+		{
+			name: "http content template placeholder outside of a string",
+			src: &endpoint.HTTP{
+				Base:            goodBase,
+				URL:             "localhost",
+				Method:          http.MethodPost,
+				AuthMethod:      "none",
+				ContentTemplate: `{"value": ${r._value}}`,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http endpoint content template is not valid JSON",
+			},
+		},
+		{
+			name: "invalid smtp tls mode",
+			src: &endpoint.SMTP{
+				Base:    goodBase,
+				Host:    "smtp.example.com",
+				Port:    587,
+				TLSMode: "ssl",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid smtp tls mode",
+			},
+		},
+		{
+			name: "smtp username without password",
+			src: &endpoint.SMTP{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLSMode:  "starttls",
+				Username: influxdb.SecretField{Key: id1 + "-smtp-username"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid smtp username/password, provide both or neither",
+			},
+		},
+		{
+			name: "empty smtp to",
+			src: &endpoint.SMTP{
+				Base:    goodBase,
+				Host:    "smtp.example.com",
+				Port:    587,
+				TLSMode: "starttls",
+				From:    "influxdb@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp endpoint to addresses are empty",
+			},
+		},
+		{
+			name: "valid smtp",
+			src: &endpoint.SMTP{
+				Base:    goodBase,
+				Host:    "smtp.example.com",
+				Port:    25,
+				TLSMode: "none",
+				From:    "influxdb@example.com",
+				To:      []string{"oncall@example.com"},
+			},
+			err: nil,
+		},
+		{
+			name: "empty opsgenie api key",
+			src: &endpoint.Opsgenie{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "opsgenie api key is invalid",
+			},
+		},
+		{
+			name: "empty teams url",
+			src: &endpoint.Teams{
+				Base: goodBase,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "teams webhook URL is invalid",
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -226,6 +338,61 @@ func TestJSON(t *testing.T) {
 				Password:   influxdb.SecretField{Key: "password-key"},
 			},
 		},
+		{
+			name: "simple smtp",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLSMode:  "starttls",
+				Username: influxdb.SecretField{Key: "username-key"},
+				Password: influxdb.SecretField{Key: "password-key"},
+				From:     "influxdb@example.com",
+				To:       []string{"oncall@example.com", "ops@example.com"},
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &endpoint.Opsgenie{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL:    "https://api.eu.opsgenie.com/v2/alerts",
+				APIKey: influxdb.SecretField{Key: "api-key"},
+			},
+		},
+		{
+			name: "simple teams",
+			src: &endpoint.Teams{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				URL: influxdb.SecretField{Key: "url-key"},
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -365,6 +532,59 @@ func TestBackFill(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "smtp with username and password",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Username: influxdb.SecretField{
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Value: strPtr("password1"),
+				},
+			},
+			target: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Username: influxdb.SecretField{
+					Key:   id1 + "-smtp-username",
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Key:   id1 + "-smtp-password",
+					Value: strPtr("password1"),
+				},
+			},
+		},
+		{
+			name: "opsgenie api key",
+			src: &endpoint.Opsgenie{
+				Base:   goodBase,
+				APIKey: influxdb.SecretField{Value: strPtr("key1")},
+			},
+			target: &endpoint.Opsgenie{
+				Base: goodBase,
+				APIKey: influxdb.SecretField{
+					Key:   id1 + "-opsgenie-api-key",
+					Value: strPtr("key1"),
+				},
+			},
+		},
+		{
+			name: "teams url",
+			src: &endpoint.Teams{
+				Base: goodBase,
+				URL:  influxdb.SecretField{Value: strPtr("https://example.webhook.office.com/webhookb2/1")},
+			},
+			target: &endpoint.Teams{
+				Base: goodBase,
+				URL: influxdb.SecretField{
+					Key:   id1 + "-teams-url",
+					Value: strPtr("https://example.webhook.office.com/webhookb2/1"),
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		c.src.BackfillSecretKeys()
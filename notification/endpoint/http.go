@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 
 	"github.com/influxdata/influxdb"
 )
@@ -62,6 +63,10 @@ func (s HTTP) SecretFields() []influxdb.SecretField {
 	return arr
 }
 
+// ContentTemplatePlaceholder matches the ${r.column} placeholders of a content
+// template, the column is its first submatch.
+var ContentTemplatePlaceholder = regexp.MustCompile(`\$\{r\.(\w+)\}`)
+
 var goodHTTPAuthMethod = map[string]bool{
 	"none":   true,
 	"basic":  true,
@@ -115,6 +120,13 @@ func (s HTTP) Valid() error {
 			Msg:  "invalid http token for bearer auth",
 		}
 	}
+	// the placeholders are interpolated within JSON strings.
+	if s.ContentTemplate != "" && !json.Valid([]byte(ContentTemplatePlaceholder.ReplaceAllString(s.ContentTemplate, "x"))) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "http endpoint content template is not valid JSON",
+		}
+	}
 
 	return nil
 }
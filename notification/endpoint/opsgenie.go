@@ -0,0 +1,90 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationEndpoint = &Opsgenie{}
+
+const opsgenieAPIKeySuffix = "-opsgenie-api-key"
+
+// DefaultOpsgenieURL is the alert API of the Opsgenie US instance.
+const DefaultOpsgenieURL = "https://api.opsgenie.com/v2/alerts"
+
+// Opsgenie is the notification endpoint config of opsgenie.
+type Opsgenie struct {
+	Base
+	// URL is the alert API of the Opsgenie instance, it defaults to
+	// DefaultOpsgenieURL, i.e.: https://api.eu.opsgenie.com/v2/alerts for the EU instance.
+	URL string `json:"url,omitempty"`
+	// APIKey is the key of an API integration, sent as a GenieKey authorization.
+	APIKey influxdb.SecretField `json:"apiKey"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Opsgenie) BackfillSecretKeys() {
+	if s.APIKey.Key == "" && s.APIKey.Value != nil {
+		s.APIKey.Key = s.idStr() + opsgenieAPIKeySuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Opsgenie) SecretFields() []influxdb.SecretField {
+	return []influxdb.SecretField{
+		s.APIKey,
+	}
+}
+
+// AlertURL returns the alert API the notifications are sent to.
+func (s Opsgenie) AlertURL() string {
+	if s.URL == "" {
+		return DefaultOpsgenieURL
+	}
+	return s.URL
+}
+
+// Valid returns error if some configuration is invalid
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.URL != "" {
+		if _, err := url.Parse(s.URL); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("opsgenie endpoint URL is invalid: %s", err.Error()),
+			}
+		}
+	}
+	if s.APIKey.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie api key is invalid",
+		}
+	}
+	return nil
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Opsgenie) Type() string {
+	return OpsgenieType
+}
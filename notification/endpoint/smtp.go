@@ -0,0 +1,129 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationEndpoint = &SMTP{}
+
+const (
+	smtpUsernameSuffix = "-smtp-username"
+	smtpPasswordSuffix = "-smtp-password"
+)
+
+// SMTP is the notification endpoint config of an SMTP server emailing notifications.
+type SMTP struct {
+	Base
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// TLSMode is one of none, starttls or tls.
+	TLSMode  string               `json:"tlsMode"`
+	Username influxdb.SecretField `json:"username,omitempty"`
+	Password influxdb.SecretField `json:"password,omitempty"`
+	From     string               `json:"from"`
+	To       []string             `json:"to"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *SMTP) BackfillSecretKeys() {
+	if s.Username.Key == "" && s.Username.Value != nil {
+		s.Username.Key = s.idStr() + smtpUsernameSuffix
+	}
+	if s.Password.Key == "" && s.Password.Value != nil {
+		s.Password.Key = s.idStr() + smtpPasswordSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s SMTP) SecretFields() []influxdb.SecretField {
+	arr := make([]influxdb.SecretField, 0)
+	if s.Username.Key != "" {
+		arr = append(arr, s.Username)
+	}
+	if s.Password.Key != "" {
+		arr = append(arr, s.Password)
+	}
+	return arr
+}
+
+var goodSMTPTLSMode = map[string]bool{
+	"none":     true,
+	"starttls": true,
+	"tls":      true,
+}
+
+// Valid returns error if some configuration is invalid
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint host is empty",
+		}
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint port is invalid",
+		}
+	}
+	if !goodSMTPTLSMode[s.TLSMode] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid smtp tls mode",
+		}
+	}
+	if (s.Username.Key == "") != (s.Password.Key == "") {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid smtp username/password, provide both or neither",
+		}
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint from address is invalid: %s", err.Error()),
+		}
+	}
+	if len(s.To) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint to addresses are empty",
+		}
+	}
+	for _, to := range s.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("smtp endpoint to address %q is invalid: %s", to, err.Error()),
+			}
+		}
+	}
+	return nil
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s SMTP) Type() string {
+	return SMTPType
+}
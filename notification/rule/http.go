@@ -3,6 +3,7 @@ package rule
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb"
@@ -49,6 +50,9 @@ func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
 	if e.AuthMethod == "bearer" || e.AuthMethod == "basic" {
 		packages = append(packages, "influxdata/influxdb/secrets")
 	}
+	if e.ContentTemplate != "" {
+		packages = append(packages, "strings")
+	}
 
 	return flux.Imports(packages...)
 }
@@ -61,7 +65,7 @@ func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateAllStateChanges()...)
-	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateFluxASTNotifyPipe(e))
 
 	return statements
 }
@@ -121,23 +125,35 @@ func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
 	return flux.DefineVariable("endpoint", call)
 }
 
-func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
-	endpointBody := flux.Call(
-		flux.Member("json", "encode"),
-		flux.Object(flux.Property("v", flux.Identifier("body"))),
-	)
+func (s *HTTP) generateFluxASTNotifyPipe(e *endpoint.HTTP) ast.Statement {
 	headers := flux.Property("headers", flux.Identifier("headers"))
 
-	endpointProps := []*ast.Property{
-		headers,
-		flux.Property("data", endpointBody),
+	var endpointFn *ast.FunctionExpression
+	if e.ContentTemplate != "" {
+		// the content template is the body as is, once its ${r.column}
+		// placeholders are interpolated with the JSON escaped column values.
+		endpointBody := flux.Call(
+			flux.Identifier("bytes"),
+			flux.Object(flux.Property("v", contentTemplateExpression(e.ContentTemplate))),
+		)
+		endpointFn = flux.FuncBlock(flux.FunctionParams("r"),
+			generateEscape(),
+			&ast.ReturnStatement{
+				Argument: flux.Object(headers, flux.Property("data", endpointBody)),
+			},
+		)
+	} else {
+		endpointBody := flux.Call(
+			flux.Member("json", "encode"),
+			flux.Object(flux.Property("v", flux.Identifier("body"))),
+		)
+		endpointFn = flux.FuncBlock(flux.FunctionParams("r"),
+			s.generateBody(),
+			&ast.ReturnStatement{
+				Argument: flux.Object(headers, flux.Property("data", endpointBody)),
+			},
+		)
 	}
-	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
-		s.generateBody(),
-		&ast.ReturnStatement{
-			Argument: flux.Object(endpointProps...),
-		},
-	)
 
 	props := []*ast.Property{}
 	props = append(props, flux.Property("data", flux.Identifier("notification")))
@@ -149,6 +165,68 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
 }
 
+// contentTemplateExpression returns the string interpolating the placeholders
+// of the content template with the escape function of generateEscape.
+func contentTemplateExpression(tmpl string) *ast.StringExpression {
+	var parts []ast.StringExpressionPart
+	text := func(s string) {
+		if s == "" {
+			return
+		}
+		// the parts are formatted as is, escape them as a string literal.
+		s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `${`, `\${`).Replace(s)
+		parts = append(parts, &ast.TextPart{Value: s})
+	}
+
+	last := 0
+	for _, m := range endpoint.ContentTemplatePlaceholder.FindAllStringSubmatchIndex(tmpl, -1) {
+		text(tmpl[last:m[0]])
+		parts = append(parts, &ast.InterpolatedPart{
+			Expression: flux.Call(
+				flux.Identifier("escape"),
+				flux.Object(flux.Property("v", flux.Member("r", tmpl[m[2]:m[3]]))),
+			),
+		})
+		last = m[1]
+	}
+	text(tmpl[last:])
+
+	return &ast.StringExpression{Parts: parts}
+}
+
+// generateEscape defines escape as the JSON encoding of a value as a string,
+// without its quotes, to be interpolated within a JSON string.
+func generateEscape() ast.Statement {
+	encoded := flux.Call(
+		flux.Identifier("string"),
+		flux.Object(flux.Property("v", flux.Call(
+			flux.Member("json", "encode"),
+			flux.Object(flux.Property("v", flux.Call(
+				flux.Identifier("string"),
+				flux.Object(flux.Property("v", flux.Identifier("v"))),
+			))),
+		))),
+	)
+	unquoted := flux.Call(
+		flux.Member("strings", "substring"),
+		flux.Object(
+			flux.Property("v", flux.Identifier("s")),
+			flux.Property("start", flux.Integer(1)),
+			flux.Property("end", flux.Subtract(
+				flux.Call(
+					flux.Member("strings", "strlen"),
+					flux.Object(flux.Property("v", flux.Identifier("s"))),
+				),
+				flux.Integer(1),
+			)),
+		),
+	)
+	return flux.DefineVariable("escape", flux.FuncBlock(flux.FunctionParams("v"),
+		flux.DefineVariable("s", encoded),
+		&ast.ReturnStatement{Argument: unquoted},
+	))
+}
+
 func (s *HTTP) generateBody() ast.Statement {
 	// {r with "_version": 1}
 	props := []*ast.Property{
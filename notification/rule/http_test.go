@@ -1,12 +1,22 @@
 package rule_test
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/rule"
+	_ "github.com/influxdata/influxdb/query/builtin"
 )
 
 func TestHTTP_GenerateFlux(t *testing.T) {
@@ -297,3 +307,264 @@ all_statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestHTTP_GenerateFlux_contentTemplate(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+import "strings"
+
+option task = {name: "foo", every: 1h}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: endpoint(mapFn: (r) => {
+		escape = (v) => {
+			s = string(v: json.encode(v: string(v: v)))
+
+			return strings.substring(v: s, start: 1, end: strings.strlen(v: s) - 1)
+		}
+
+		return {headers: headers, data: bytes(v: "{\"text\": \"${escape(v: r._check_name)} is ${escape(v: r._level)}\", \"cost\": \"$${escape(v: r._value)}\", \"note\": \"\${total}\"}")}
+	}))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL:             "http://localhost:7777",
+		ContentTemplate: `{"text": "${r._check_name} is ${r._level}", "cost": "$${r._value}", "note": "${total}"}`,
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestHTTP_GenerateFlux_silences(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+state_changes = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+silenced = (r) =>
+	(r._time >= 2020-01-02T03:00:00Z and r._time < 2020-01-02T05:00:00Z or r._time >= 2020-01-09T03:00:00Z and r._time < 2020-01-09T05:30:00Z)
+
+state_changes
+	|> filter(fn: silenced)
+	|> monitor.notify(data: notification, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "silenced"}))))
+
+all_statuses = state_changes
+	|> filter(fn: (r) =>
+		(not silenced(r: r)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = {r with _version: 1}
+
+		return {headers: headers, data: json.encode(v: body)}
+	}))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+			Silences: []*influxdb.Silence{
+				{
+					StartTime: time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2020, 1, 2, 5, 0, 0, 0, time.UTC),
+				},
+				{
+					StartTime: time.Date(2020, 1, 9, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2020, 1, 9, 5, 30, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestHTTP_GenerateFlux_silenceTags(t *testing.T) {
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+			Silences: []*influxdb.Silence{
+				{
+					Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+					StartTime: time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2020, 1, 2, 5, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	silenced := `silenced = (r) =>
+	(r._time >= 2020-01-02T03:00:00Z and r._time < 2020-01-02T05:00:00Z and r.host == "db1")`
+	if !strings.Contains(f, silenced) {
+		t.Fatalf("expected silence to match its tags, got:\n%s", f)
+	}
+
+	// Read the statuses of both hosts from csv instead of the monitoring
+	// bucket, and do not log the notifications. Both notify calls produce a
+	// result, so one of them is named.
+	const data = `
+#datatype,string,long,dateTime:RFC3339,string,string,string
+#group,false,false,false,true,true,false
+#default,_result,,,,,
+,result,table,_time,_measurement,host,_level
+,,0,2020-01-02T04:00:00Z,statuses,db1,crit
+,,1,2020-01-02T04:00:00Z,statuses,db2,crit
+`
+	f = strings.Replace(f, `import "experimental"`, `import "experimental"
+import "csv"`, 1)
+	f = strings.Replace(f, `option task = {name: "foo", every: 1h}`, `option task = {name: "foo", every: 1h}
+option monitor.log = (tables=<-) => tables`, 1)
+	f += `
+	|> yield(name: "notified")`
+	f = strings.Replace(f, `statuses = monitor.from(start: -2h)`, "statuses = csv.from(csv: \""+strings.Replace(data, "\n", "\\n", -1)+"\")", 1)
+
+	var bodies []string
+	deps := dependenciestest.Default()
+	deps.Deps.HTTPClient = &http.Client{
+		Transport: dependenciestest.RoundTripFunc(func(req *http.Request) *http.Response {
+			body, _ := ioutil.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				Header:     make(http.Header),
+			}
+		}),
+	}
+
+	prog, err := lang.Compile(f, time.Date(2020, 1, 2, 4, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := prog.Start(deps.Inject(context.Background()), &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(flux.Table) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"host":"db2"`) {
+		t.Fatalf("expected only db2 to be notified, got %v", bodies)
+	}
+}
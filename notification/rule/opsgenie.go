@@ -0,0 +1,151 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// Opsgenie is the notification rule config of opsgenie, creating an alert per status.
+type Opsgenie struct {
+	Base
+	MessageTemplate string `json:"messageTemplate"`
+}
+
+// GenerateFlux generates a flux script for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(opsgenieEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFluxAST(e *endpoint.Opsgenie) (*ast.Package, error) {
+	f := flux.File(
+		s.Name,
+		flux.Imports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets", "experimental"),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Opsgenie) generateFluxASTBody(e *endpoint.Opsgenie) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateAllStateChanges()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+func (s *Opsgenie) generateFluxASTSecrets(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.APIKey.Key))))
+
+	return flux.DefineVariable("opsgenie_secret", call)
+}
+
+func (s *Opsgenie) generateFluxASTEndpoint(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.AlertURL()))))
+
+	return flux.DefineVariable("opsgenie_endpoint", call)
+}
+
+func (s *Opsgenie) generateFluxASTNotifyPipe() ast.Statement {
+	// the alias deduplicates the alerts of a check in Opsgenie.
+	alias := flux.Add(
+		flux.Add(flux.Member("notification", "_notification_rule_id"), flux.String("-")),
+		flux.Member("r", "_check_id"),
+	)
+	body := flux.Object(
+		flux.Property("message", flux.String(s.MessageTemplate)),
+		flux.Property("alias", alias),
+		flux.Property("description", flux.Member("r", "_message")),
+		flux.Property("priority", s.generateOpsgeniePriority()),
+		flux.Property("source", flux.Member("notification", "_notification_rule_name")),
+		flux.Property("entity", flux.Member("r", "_source_measurement")),
+	)
+	headers := flux.Object(
+		flux.Dictionary("Content-Type", flux.String("application/json")),
+		flux.Dictionary("Authorization", flux.Add(flux.String("GenieKey "), flux.Identifier("opsgenie_secret"))),
+	)
+
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("body", body),
+		&ast.ReturnStatement{
+			Argument: flux.Object(
+				flux.Property("headers", headers),
+				flux.Property("data", flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Identifier("body"))))),
+			),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("opsgenie_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+// generateOpsgeniePriority maps the level to the Opsgenie priority, from P1 (critical) to P5 (informational).
+func (s *Opsgenie) generateOpsgeniePriority() ast.Expression {
+	level := flux.Member("r", "_level")
+	return flux.If(
+		flux.Equal(level, flux.String("crit")),
+		flux.String("P1"),
+		flux.If(
+			flux.Equal(level, flux.String("warn")),
+			flux.String("P3"),
+			flux.String("P5"),
+		),
+	)
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie msg template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Opsgenie) Type() string {
+	return "opsgenie"
+}
@@ -0,0 +1,87 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestOpsgenie_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets.get(key: "opsgenie_key")
+opsgenie_endpoint = http.endpoint(url: "https://api.opsgenie.com/v2/alerts")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: opsgenie_endpoint(mapFn: (r) => {
+		body = {
+			message: "${r._check_name} is ${r._level}",
+			alias: notification._notification_rule_id + "-" + r._check_id,
+			description: r._message,
+			priority: if r._level == "crit" then "P1" else if r._level == "warn" then "P3" else "P5",
+			source: notification._notification_rule_name,
+			entity: r._source_measurement,
+		}
+
+		return {headers: {"Content-Type": "application/json", "Authorization": "GenieKey " + opsgenie_secret}, data: json.encode(v: body)}
+	}))`
+
+	s := &rule.Opsgenie{
+		MessageTemplate: "${r._check_name} is ${r._level}",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Any,
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.Opsgenie{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		APIKey: influxdb.SecretField{
+			Key: "opsgenie_key",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
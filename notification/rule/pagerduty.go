@@ -79,7 +79,16 @@ func (s *PagerDuty) generateFluxASTBody(e *endpoint.PagerDuty) []ast.Statement {
 	statements = append(statements, s.generateFluxASTEndpoint(e))
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
 	statements = append(statements, s.generateFluxASTStatuses())
-	statements = append(statements, s.generateFluxASTNotifyPipe(e.ClientURL))
+	source := "statuses"
+	if len(s.Silences) > 0 {
+		statements = append(statements, s.generateFluxASTSilences(source, "unsilenced_statuses")...)
+		source = "unsilenced_statuses"
+	}
+	if s.groupsStatuses() {
+		statements = append(statements, s.generateFluxASTGroupStatuses(source, "grouped_statuses")...)
+		source = "grouped_statuses"
+	}
+	statements = append(statements, s.generateFluxASTNotifyPipe(source, e.ClientURL))
 
 	return statements
 }
@@ -98,7 +107,7 @@ func (s *PagerDuty) generateFluxASTEndpoint(e *endpoint.PagerDuty) ast.Statement
 	return flux.DefineVariable("pagerduty_endpoint", call)
 }
 
-func (s *PagerDuty) generateFluxASTNotifyPipe(url string) ast.Statement {
+func (s *PagerDuty) generateFluxASTNotifyPipe(source, url string) ast.Statement {
 	endpointProps := []*ast.Property{}
 
 	// routing_key:
@@ -170,7 +179,7 @@ func (s *PagerDuty) generateFluxASTNotifyPipe(url string) ast.Statement {
 
 	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
 
-	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("statuses"), call))
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier(source), call))
 }
 
 func severityFromLevel() *ast.CallExpression {
@@ -14,8 +14,11 @@ import (
 
 var typeToRule = map[string](func() influxdb.NotificationRule){
 	"slack":     func() influxdb.NotificationRule { return &Slack{} },
+	"smtp":      func() influxdb.NotificationRule { return &SMTP{} },
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
+	"opsgenie":  func() influxdb.NotificationRule { return &Opsgenie{} },
+	"teams":     func() influxdb.NotificationRule { return &Teams{} },
 }
 
 // UnmarshalJSON will convert
@@ -57,8 +60,21 @@ type Base struct {
 	RunbookLink string                    `json:"runbookLink"`
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	// GroupBy are the tag keys grouping the statuses of a check and level
+	// into a single notification. Without them, statuses are notified per
+	// series.
+	GroupBy []string `json:"groupBy,omitempty"`
+	// RepeatInterval is the interval at which a group that is still firing
+	// is notified again. The current statuses matching the status rules are
+	// notified rather than their state changes, and groups notified within
+	// the interval are not sent.
+	RepeatInterval *notification.Duration `json:"repeatInterval,omitempty"`
 	*influxdb.Limit
 	influxdb.CRUDLog
+
+	// Silences are the silences applied when generating the flux of the rule.
+	// They are maintained by the server and not stored with the rule.
+	Silences []*influxdb.Silence `json:"-"`
 }
 
 func (b Base) valid() error {
@@ -103,6 +119,20 @@ func (b Base) valid() error {
 			return err
 		}
 	}
+	for _, key := range b.GroupBy {
+		if key == "" {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule groupBy tag key can't be empty",
+			}
+		}
+	}
+	if b.RepeatInterval != nil && b.RepeatInterval.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Notification Rule repeatInterval must be larger than 0",
+		}
+	}
 	if b.Limit != nil {
 		if b.Limit.Every <= 0 || b.Limit.Rate <= 0 {
 			return &influxdb.Error{
@@ -126,8 +156,15 @@ func (b *Base) generateFluxASTNotificationDefinition(e influxdb.NotificationEndp
 func (b *Base) generateAllStateChanges() []ast.Statement {
 	stmts := []ast.Statement{}
 	tables := []ast.Expression{}
+	seen := make(map[string]bool)
 	for _, r := range b.StatusRules {
 		stmt, table := b.generateStateChanges(r)
+		// With a repeat interval, rules with the same current level match
+		// the same statuses.
+		if seen[table.Name] {
+			continue
+		}
+		seen[table.Name] = true
 		tables = append(tables, table)
 		stmts = append(stmts, stmt)
 	}
@@ -181,15 +218,253 @@ func (b *Base) generateAllStateChanges() []ast.Statement {
 		)
 	}
 
-	stmts = append(stmts, flux.DefineVariable("all_statuses", pipe))
+	name := "all_statuses"
+	if b.groupsStatuses() {
+		name = "ungrouped_statuses"
+	}
+
+	if len(b.Silences) == 0 {
+		stmts = append(stmts, flux.DefineVariable(name, pipe))
+	} else {
+		stmts = append(stmts, flux.DefineVariable("state_changes", pipe))
+		stmts = append(stmts, b.generateFluxASTSilences("state_changes", name)...)
+	}
 
+	if b.groupsStatuses() {
+		stmts = append(stmts, b.generateFluxASTGroupStatuses(name, "all_statuses")...)
+	}
 	return stmts
 }
 
+// groupsStatuses returns true if the statuses are grouped into a single
+// notification per group, see generateFluxASTGroupStatuses.
+func (b *Base) groupsStatuses() bool {
+	return len(b.GroupBy) > 0 || b.RepeatInterval != nil
+}
+
+// groupColumns returns the columns identifying the group of a status: its
+// check, its level and the GroupBy tags.
+func (b *Base) groupColumns() []ast.Expression {
+	columns := []ast.Expression{flux.String("_check_id"), flux.String("_level")}
+	for _, key := range b.GroupBy {
+		columns = append(columns, flux.String(key))
+	}
+	return columns
+}
+
+// seriesColumns are the group key columns that differ between the series of
+// the statuses and of the notifications logged for them. Dropping them leaves
+// both grouped by the tags of the status series.
+var seriesColumns = []string{"_start", "_stop", "_measurement"}
+
+// notificationColumns are the group key columns added to the statuses when
+// they are logged as notifications, see monitor.notify.
+var notificationColumns = []string{
+	"_notification_rule_id",
+	"_notification_rule_name",
+	"_notification_endpoint_id",
+	"_notification_endpoint_name",
+	"_sent",
+}
+
+func dropColumns(columns ...[]string) *ast.CallExpression {
+	var exprs []ast.Expression
+	for _, cols := range columns {
+		for _, c := range cols {
+			exprs = append(exprs, flux.String(c))
+		}
+	}
+	return flux.Call(
+		flux.Identifier("drop"),
+		flux.Object(flux.Property("columns", flux.Array(exprs...))),
+	)
+}
+
+// generateFluxASTGroupStatuses defines name as the latest status of each group
+// of source. The statuses are grouped by check, level and the GroupBy tags, or
+// kept per series when GroupBy is empty. With a repeat interval, groups
+// already notified within the interval, as recorded by the notifications
+// logged to _monitoring, are dropped so a still-firing alert is only sent
+// again once the interval passed.
+func (b *Base) generateFluxASTGroupStatuses(source, name string) []ast.Statement {
+	group := flux.Call(
+		flux.Identifier("group"),
+		flux.Object(flux.Property("columns", flux.Array(b.groupColumns()...))),
+	)
+
+	if b.RepeatInterval == nil {
+		return []ast.Statement{
+			flux.DefineVariable(name, flux.Pipe(
+				flux.Identifier(source),
+				group,
+				flux.Call(
+					flux.Identifier("sort"),
+					flux.Object(
+						flux.Property("columns", flux.Array(flux.String("_time"))),
+						flux.Property("desc", flux.Bool(true)),
+					),
+				),
+				flux.Call(
+					flux.Identifier("limit"),
+					flux.Object(flux.Property("n", flux.Integer(1))),
+				),
+			)),
+		}
+	}
+
+	notified := func(v int64) *ast.CallExpression {
+		return flux.Call(
+			flux.Identifier("map"),
+			flux.Object(flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.ObjectWith("r", flux.Property("_notified", flux.Integer(v))),
+			))),
+		)
+	}
+
+	logs := flux.Call(
+		flux.Member("monitor", "logs"),
+		flux.Object(
+			flux.Property("start", flux.Negative((*ast.DurationLiteral)(b.RepeatInterval))),
+			flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.And(
+					flux.Equal(flux.Member("r", "_notification_rule_id"), flux.String(b.ID.String())),
+					flux.Equal(flux.Member("r", "_sent"), flux.String("true")),
+				),
+			)),
+		),
+	)
+	sent := []*ast.CallExpression{notified(1)}
+	pending := []*ast.CallExpression{notified(0)}
+	var statuses []*ast.CallExpression
+	if len(b.GroupBy) > 0 {
+		statuses = append(statuses, group)
+	} else {
+		// Keep the series of the statuses as the groups, the tables of both
+		// sides with the same series are merged by the sort below.
+		sent = append(sent, dropColumns(seriesColumns, notificationColumns))
+		pending = append(pending, dropColumns(seriesColumns))
+	}
+	statuses = append(statuses,
+		flux.Call(
+			flux.Identifier("sort"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_notified"), flux.String("_time"))),
+				flux.Property("desc", flux.Bool(true)),
+			),
+		),
+		flux.Call(
+			flux.Identifier("limit"),
+			flux.Object(flux.Property("n", flux.Integer(1))),
+		),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.Equal(flux.Member("r", "_notified"), flux.Integer(0)),
+			))),
+		),
+		dropColumns([]string{"_notified"}),
+	)
+
+	return []ast.Statement{
+		flux.DefineVariable("sent_notifications", flux.Pipe(logs, sent...)),
+		flux.DefineVariable("pending_notifications", flux.Pipe(flux.Identifier(source), pending...)),
+		flux.DefineVariable(name, flux.Pipe(
+			flux.Call(
+				flux.Identifier("union"),
+				flux.Object(flux.Property("tables", flux.Array(
+					flux.Identifier("sent_notifications"),
+					flux.Identifier("pending_notifications"),
+				))),
+			),
+			statuses...,
+		)),
+	}
+}
+
+// generateFluxASTSilences defines name as the statuses of source outside of
+// the silences of the rule. A status is silenced if it is within the window
+// of a silence and has all of its tags. The silenced statuses are recorded as
+// notifications with _sent set to "silenced", without calling the endpoint.
+func (b *Base) generateFluxASTSilences(source, name string) []ast.Statement {
+	var silenced ast.Expression
+	for _, s := range b.Silences {
+		var window ast.Expression = flux.And(
+			flux.GreaterThanEqual(flux.Member("r", "_time"), flux.DateTime(s.StartTime.UTC())),
+			flux.LessThan(flux.Member("r", "_time"), flux.DateTime(s.EndTime.UTC())),
+		)
+		for _, t := range s.Tags {
+			tr := notification.TagRule{Tag: t, Operator: influxdb.Equal}
+			window = flux.And(window, tr.GenerateFluxAST())
+		}
+		if silenced == nil {
+			silenced = window
+			continue
+		}
+		silenced = flux.Or(silenced, window)
+	}
+
+	silencedEndpoint := &ast.FunctionExpression{
+		Params: []*ast.Property{
+			{
+				Key:   flux.Identifier("tables"),
+				Value: &ast.PipeLiteral{},
+			},
+		},
+		Body: flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(
+				flux.Identifier("map"),
+				flux.Object(
+					flux.Property("fn", flux.Function(
+						flux.FunctionParams("r"),
+						flux.ObjectWith("r", flux.Property("_sent", flux.String("silenced"))),
+					)),
+				),
+			),
+		),
+	}
+
+	return []ast.Statement{
+		flux.DefineVariable("silenced", flux.Function(flux.FunctionParams("r"), silenced)),
+		flux.ExpressionStatement(flux.Pipe(
+			flux.Identifier(source),
+			flux.Call(
+				flux.Identifier("filter"),
+				flux.Object(flux.Property("fn", flux.Identifier("silenced"))),
+			),
+			flux.Call(
+				flux.Member("monitor", "notify"),
+				flux.Object(
+					flux.Property("data", flux.Identifier("notification")),
+					flux.Property("endpoint", silencedEndpoint),
+				),
+			),
+		)),
+		flux.DefineVariable(name, flux.Pipe(
+			flux.Identifier(source),
+			flux.Call(
+				flux.Identifier("filter"),
+				flux.Object(flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.Not(flux.Call(flux.Identifier("silenced"), flux.Object(flux.Property("r", flux.Identifier("r"))))),
+				))),
+			),
+		)),
+	}
+}
+
 func (b *Base) generateStateChanges(r notification.StatusRule) (ast.Statement, *ast.Identifier) {
 	var name string
 	var pipe *ast.PipeExpression
-	if r.PreviousLevel == nil && r.CurrentLevel == notification.Any {
+	// With a repeat interval, the current statuses are notified rather than
+	// the state changes, so that an alert still firing is sent again once the
+	// interval passed. Statuses already notified within the interval are
+	// dropped by generateFluxASTGroupStatuses.
+	current := r.PreviousLevel == nil || b.RepeatInterval != nil
+	if current && r.CurrentLevel == notification.Any {
 		pipe = flux.Pipe(
 			flux.Identifier("statuses"),
 			flux.Call(
@@ -204,7 +479,7 @@ func (b *Base) generateStateChanges(r notification.StatusRule) (ast.Statement, *
 			),
 		)
 		name = strings.ToLower(r.CurrentLevel.String())
-	} else if r.PreviousLevel == nil {
+	} else if current {
 		pipe = flux.Pipe(
 			flux.Identifier("statuses"),
 			flux.Call(
@@ -335,6 +610,11 @@ func (b *Base) ClearPrivateData() {
 	b.TaskID = 0
 }
 
+// SetSilences sets the silences applied when generating the flux of the rule.
+func (b *Base) SetSilences(silences []*influxdb.Silence) {
+	b.Silences = silences
+}
+
 // MatchesTags returns true if the Rule matches all of the tags
 func (b *Base) MatchesTags(tags []influxdb.Tag) bool {
 	if len(tags) == 0 {
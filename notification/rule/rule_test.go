@@ -121,6 +121,42 @@ func TestValidRule(t *testing.T) {
 				Msg:  "Offset should not be equal or greater than the interval",
 			},
 		},
+		{
+			name: "empty group by tag key",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Every:      mustDuration("1m"),
+					GroupBy:    []string{"cluster", ""},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule groupBy tag key can't be empty",
+			},
+		},
+		{
+			name: "zero repeat interval",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:             influxTesting.MustIDBase16(id1),
+					Name:           "name1",
+					OwnerID:        influxTesting.MustIDBase16(id2),
+					OrgID:          influxTesting.MustIDBase16(id3),
+					EndpointID:     1,
+					Every:          mustDuration("1m"),
+					RepeatInterval: mustDuration("0s"),
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule repeatInterval must be larger than 0",
+			},
+		},
 		{
 			name: "empty slack message",
 			src: &rule.Slack{
@@ -197,6 +233,24 @@ func TestValidRule(t *testing.T) {
 				Msg:  `if limit is set, limit and limitEvery must be larger than 0`,
 			},
 		},
+		{
+			name: "empty smtp subject template",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Name:       "name1",
+					Every:      mustDuration("1h"),
+				},
+				BodyTemplate: "${r._message}",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp subject template is empty",
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -286,6 +340,64 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "msg1",
 			},
 		},
+		{
+			name: "smtp with templates",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+			},
+		},
+		{
+			name: "simple opsgenie",
+			src: &rule.Opsgenie{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				MessageTemplate: "msg1",
+			},
+		},
+		{
+			name: "simple teams",
+			src: &rule.Teams{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Title:           "title1",
+				MessageTemplate: "msg1",
+			},
+		},
 		{
 			name: "simple pagerDuty",
 			src: &rule.PagerDuty{
@@ -339,6 +339,204 @@ all_statuses
 				},
 			},
 		},
+		{
+			name: "with group by",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+ungrouped_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+all_statuses = ungrouped_statuses
+	|> group(columns: ["_check_id", "_level", "cluster"])
+	|> sort(columns: ["_time"], desc: true)
+	|> limit(n: 1)
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					GroupBy: []string{"cluster"},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with repeat interval",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+ungrouped_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+sent_notifications = monitor.logs(start: -4h, fn: (r) =>
+	(r._notification_rule_id == "0000000000000001" and r._sent == "true"))
+	|> map(fn: (r) =>
+		({r with _notified: 1}))
+pending_notifications = ungrouped_statuses
+	|> map(fn: (r) =>
+		({r with _notified: 0}))
+all_statuses = union(tables: [sent_notifications, pending_notifications])
+	|> group(columns: ["_check_id", "_level", "cluster"])
+	|> sort(columns: ["_notified", "_time"], desc: true)
+	|> limit(n: 1)
+	|> filter(fn: (r) =>
+		(r._notified == 0))
+	|> drop(columns: ["_notified"])
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					GroupBy:        []string{"cluster"},
+					RepeatInterval: mustDuration("4h"),
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with repeat interval per series",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+ungrouped_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+sent_notifications = monitor.logs(start: -4h, fn: (r) =>
+	(r._notification_rule_id == "0000000000000001" and r._sent == "true"))
+	|> map(fn: (r) =>
+		({r with _notified: 1}))
+	|> drop(columns: ["_start", "_stop", "_measurement", "_notification_rule_id", "_notification_rule_name", "_notification_endpoint_id", "_notification_endpoint_name", "_sent"])
+pending_notifications = ungrouped_statuses
+	|> map(fn: (r) =>
+		({r with _notified: 0}))
+	|> drop(columns: ["_start", "_stop", "_measurement"])
+all_statuses = union(tables: [sent_notifications, pending_notifications])
+	|> sort(columns: ["_notified", "_time"], desc: true)
+	|> limit(n: 1)
+	|> filter(fn: (r) =>
+		(r._notified == 0))
+	|> drop(columns: ["_notified"])
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel:  notification.Critical,
+							PreviousLevel: statusRulePtr(notification.Warn),
+						},
+						{
+							CurrentLevel:  notification.Critical,
+							PreviousLevel: statusRulePtr(notification.Ok),
+						},
+					},
+					RepeatInterval: mustDuration("4h"),
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,142 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// SMTP is the notification rule config of smtp, emailing the statuses.
+// The subject and body templates are Flux string literals, rendered from the
+// status fields with interpolation, i.e.: "${r._check_name} is ${r._level}".
+type SMTP struct {
+	Base
+	SubjectTemplate string `json:"subjectTemplate"`
+	BodyTemplate    string `json:"bodyTemplate"`
+}
+
+// GenerateFlux generates a flux script for the smtp notification rule.
+func (s *SMTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	smtpEndpoint, ok := e.(*endpoint.SMTP)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an SMTP endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(smtpEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the smtp notification rule.
+func (s *SMTP) GenerateFluxAST(e *endpoint.SMTP) (*ast.Package, error) {
+	packages := []string{"influxdata/influxdb/monitor", "influxdata/influxdb/smtp"}
+	if e.Username.Key != "" {
+		packages = append(packages, "influxdata/influxdb/secrets")
+	}
+	packages = append(packages, "experimental")
+	f := flux.File(
+		s.Name,
+		flux.Imports(packages...),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *SMTP) generateFluxASTBody(e *endpoint.SMTP) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateAllStateChanges()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+func (s *SMTP) generateFluxASTEndpoint(e *endpoint.SMTP) ast.Statement {
+	to := make([]ast.Expression, 0, len(e.To))
+	for _, addr := range e.To {
+		to = append(to, flux.String(addr))
+	}
+
+	props := []*ast.Property{
+		flux.Property("host", flux.String(e.Host)),
+		flux.Property("port", flux.Integer(int64(e.Port))),
+		flux.Property("tls", flux.String(e.TLSMode)),
+	}
+	if e.Username.Key != "" {
+		props = append(props,
+			flux.Property("username", flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Username.Key))))),
+			flux.Property("password", flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Password.Key))))),
+		)
+	}
+	props = append(props,
+		flux.Property("from", flux.String(e.From)),
+		flux.Property("to", flux.Array(to...)),
+	)
+	call := flux.Call(flux.Member("smtp", "endpoint"), flux.Object(props...))
+
+	return flux.DefineVariable("smtp_endpoint", call)
+}
+
+func (s *SMTP) generateFluxASTNotifyPipe() ast.Statement {
+	endpointProps := []*ast.Property{
+		flux.Property("subject", flux.String(s.SubjectTemplate)),
+		flux.Property("body", flux.String(s.BodyTemplate)),
+	}
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("smtp_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.SubjectTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp subject template is empty",
+		}
+	}
+	if s.BodyTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp body template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s SMTP) Type() string {
+	return "smtp"
+}
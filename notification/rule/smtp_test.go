@@ -0,0 +1,98 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestSMTP_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/smtp"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+smtp_endpoint = smtp.endpoint(
+	host: "smtp.example.com",
+	port: 587,
+	tls: "starttls",
+	username: secrets.get(key: "smtp_username"),
+	password: secrets.get(key: "smtp_password"),
+	from: "influxdb@example.com",
+	to: ["oncall@example.com", "ops@example.com"],
+)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h, fn: (r) =>
+	(r.foo == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({subject: "${r._check_name} is ${r._level}", body: "${r._message}"})))`
+
+	s := &rule.SMTP{
+		SubjectTemplate: "${r._check_name} is ${r._level}",
+		BodyTemplate:    "${r._message}",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			TagRules: []notification.TagRule{
+				{
+					Tag: influxdb.Tag{
+						Key:   "foo",
+						Value: "bar",
+					},
+					Operator: influxdb.Equal,
+				},
+			},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.SMTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		Host:     "smtp.example.com",
+		Port:     587,
+		TLSMode:  "starttls",
+		Username: influxdb.SecretField{Key: "smtp_username"},
+		Password: influxdb.SecretField{Key: "smtp_password"},
+		From:     "influxdb@example.com",
+		To:       []string{"oncall@example.com", "ops@example.com"},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		panic(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
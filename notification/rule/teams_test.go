@@ -0,0 +1,87 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestTeams_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+teams_endpoint = http.endpoint(url: secrets.get(key: "teams_url"))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+warn = statuses
+	|> filter(fn: (r) =>
+		(r._level == "warn"))
+all_statuses = warn
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: teams_endpoint(mapFn: (r) => {
+		body = {
+			"@type": "MessageCard",
+			"@context": "https://schema.org/extensions",
+			summary: "${r._check_name}",
+			title: "${r._check_name}",
+			text: "${r._message}",
+			themeColor: if r._level == "crit" then "d13f3f" else if r._level == "warn" then "f0a30a" else "32b08c",
+		}
+
+		return {headers: {"Content-Type": "application/json"}, data: json.encode(v: body)}
+	}))`
+
+	s := &rule.Teams{
+		Title:           "${r._check_name}",
+		MessageTemplate: "${r._message}",
+		Base: rule.Base{
+			ID:         1,
+			EndpointID: 2,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Warn,
+				},
+			},
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.Teams{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: influxdb.SecretField{
+			Key: "teams_url",
+		},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
@@ -0,0 +1,72 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// OrgLimits are the resource limits of an organization. A limit of zero
+// means the resource is unlimited.
+type OrgLimits struct {
+	OrgID ID `json:"orgID"`
+
+	// WriteRequestsPerSecond is the number of write requests the organization
+	// may make per second.
+	WriteRequestsPerSecond int `json:"writeRequestsPerSecond"`
+	// WriteBytesPerSecond is the number of request body bytes the organization
+	// may write per second.
+	WriteBytesPerSecond int `json:"writeBytesPerSecond"`
+	// ConcurrentQueries is the number of queries the organization may run at
+	// the same time.
+	ConcurrentQueries int `json:"concurrentQueries"`
+	// MaxSeriesCardinality is the number of series the organization may store
+	// across all of its buckets.
+	MaxSeriesCardinality int64 `json:"maxSeriesCardinality"`
+}
+
+// Valid returns an error if any of the limits is negative.
+func (l OrgLimits) Valid() error {
+	for _, f := range []struct {
+		name  string
+		value int64
+	}{
+		{"writeRequestsPerSecond", int64(l.WriteRequestsPerSecond)},
+		{"writeBytesPerSecond", int64(l.WriteBytesPerSecond)},
+		{"concurrentQueries", int64(l.ConcurrentQueries)},
+		{"maxSeriesCardinality", l.MaxSeriesCardinality},
+	} {
+		if f.value < 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s must not be negative", f.name),
+			}
+		}
+	}
+	return nil
+}
+
+// OrgLimitsService manages the limits of organizations.
+type OrgLimitsService interface {
+	// FindOrgLimits returns the limits of an organization. An organization
+	// without limits has all of its limits set to zero.
+	FindOrgLimits(ctx context.Context, orgID ID) (*OrgLimits, error)
+
+	// UpdateOrgLimits replaces the limits of an organization.
+	UpdateOrgLimits(ctx context.Context, orgID ID, l OrgLimits) (*OrgLimits, error)
+}
+
+// OrgLimitEnforcer enforces the limits of organizations. Requests that
+// exceed a limit fail with an error with code ETooManyRequests.
+type OrgLimitEnforcer interface {
+	// AllowWriteRequest reports whether the organization may make another
+	// write request.
+	AllowWriteRequest(ctx context.Context, orgID ID) error
+
+	// AllowWriteBytes reports whether the organization may write a request
+	// body of n bytes.
+	AllowWriteBytes(ctx context.Context, orgID ID, n int) error
+
+	// AcquireQuery reserves one of the concurrent queries of the organization.
+	// The returned release function must be called when the query is done.
+	AcquireQuery(ctx context.Context, orgID ID) (release func(), err error)
+}
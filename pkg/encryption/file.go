@@ -0,0 +1,421 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Files are encrypted with envelope encryption. The data of each file is sealed
+// with a random data key, and the data key is sealed with a key of the keyring,
+// the master key, and stored in the header of the file:
+//
+//	┌─────────┬─────────┬────────────┬────────────┬─────────┬──────────────────┐
+//	│  Magic  │ Version │ Chunk Size │ Key ID Len │ Key ID  │ Wrapped Data Key │
+//	│ 4 bytes │ 1 byte  │  4 bytes   │   1 byte   │ N bytes │     60 bytes     │
+//	└─────────┴─────────┴────────────┴────────────┴─────────┴──────────────────┘
+//
+// The wrapped data key is prefixed with its random nonce, and authenticates the
+// header before it. The data follows the header as a sequence of chunks, each
+// holding chunk size bytes of data, except for the last, sealed with AES-GCM using
+// the data key. The nonce of a chunk is its index, which is safe as a data key only
+// ever encrypts one file, and the last chunk is marked in its additional data so a
+// truncated file cannot be mistaken for a complete one.
+var fileMagic = [4]byte{0x00, 'E', 'N', 'C'}
+
+const (
+	fileVersion = 1
+
+	// fileHeaderSize is the size of the header, excluding the key ID and the
+	// wrapped data key.
+	fileHeaderSize = len(fileMagic) + 6
+
+	// DefaultChunkSize is the size of the chunks of data sealed by a FileWriter.
+	DefaultChunkSize = 64 * 1024
+
+	// maxChunkSize bounds the chunk size read from a header.
+	maxChunkSize = 16 * 1024 * 1024
+)
+
+// ErrFileCorrupt is returned when the data of an encrypted file cannot be
+// authenticated.
+var ErrFileCorrupt = errors.New("encrypted file corrupt")
+
+// fileHeader is the header of an encrypted file.
+type fileHeader struct {
+	chunkSize int
+	keyID     string
+	dataKey   []byte // the wrapped data key
+}
+
+// readFileHeader reads the header of the file read by r, returning false if the
+// file is not encrypted.
+func readFileHeader(r io.ReaderAt) (fileHeader, int, bool, error) {
+	var hdr [fileHeaderSize]byte
+	if n, err := r.ReadAt(hdr[:], 0); n < len(fileMagic) || string(hdr[:len(fileMagic)]) != string(fileMagic[:]) {
+		if err != nil && err != io.EOF {
+			return fileHeader{}, 0, false, err
+		}
+		return fileHeader{}, 0, false, nil
+	} else if n < len(hdr) {
+		return fileHeader{}, 0, true, ErrFileCorrupt
+	}
+
+	if version := hdr[4]; version != fileVersion {
+		return fileHeader{}, 0, true, fmt.Errorf("unsupported encrypted file version: %d", version)
+	}
+
+	h := fileHeader{chunkSize: int(binary.BigEndian.Uint32(hdr[5:9]))}
+	if h.chunkSize == 0 || h.chunkSize > maxChunkSize {
+		return fileHeader{}, 0, true, ErrFileCorrupt
+	}
+
+	b := make([]byte, int(hdr[9])+wrappedKeySize)
+	if _, err := r.ReadAt(b, int64(fileHeaderSize)); err == io.EOF {
+		return fileHeader{}, 0, true, ErrFileCorrupt
+	} else if err != nil {
+		return fileHeader{}, 0, true, err
+	}
+	h.keyID, h.dataKey = string(b[:hdr[9]]), b[hdr[9]:]
+
+	return h, fileHeaderSize + len(b), true, nil
+}
+
+// appendFileHeader appends the header of a file, excluding its wrapped data key,
+// to b.
+func appendFileHeader(b []byte, chunkSize int, keyID string) []byte {
+	b = append(b, fileMagic[:]...)
+	b = append(b, fileVersion, 0, 0, 0, 0, byte(len(keyID)))
+	binary.BigEndian.PutUint32(b[len(b)-5:], uint32(chunkSize))
+	return append(b, keyID...)
+}
+
+// wrappedKeySize is the size of a data key sealed with AES-GCM and prefixed with
+// its nonce.
+const wrappedKeySize = 12 + KeySize + 16
+
+// FileKeyID returns the ID of the master key the file read by r is encrypted with,
+// or an empty string if the file is not encrypted.
+func FileKeyID(r io.ReaderAt) (string, error) {
+	h, _, _, err := readFileHeader(r)
+	return h.keyID, err
+}
+
+// ReadFile returns the data of the encrypted file of the given size read by r. It
+// returns a KeyNotFoundError if the master key of the file is not in k, and
+// ErrFileCorrupt if the file has been modified or truncated.
+func ReadFile(r io.ReaderAt, size int64, k *Keyring) ([]byte, error) {
+	fr, err := NewFileReader(r, size, k)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, fr.Size())
+	if _, err := fr.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fileReaderCacheN is the number of decrypted chunks cached by a FileReader.
+const fileReaderCacheN = 16
+
+// FileReader reads the data of an encrypted file, decrypting its chunks as they
+// are read. The most recently read chunks are cached. It is safe for concurrent
+// use.
+type FileReader struct {
+	r         io.ReaderAt
+	keyID     string
+	aead      cipher.AEAD // opens chunks with the data key
+	offset    int64       // offset of the first chunk
+	chunkSize int64
+	chunkN    int64 // number of chunks
+	size      int64 // size of the data
+
+	mu    sync.Mutex
+	cache []fileChunk // most recently used first
+}
+
+// fileChunk is a decrypted chunk of a file.
+type fileChunk struct {
+	i    int64
+	data []byte
+}
+
+// NewFileReader returns a FileReader for the encrypted file of the given size read
+// by r. It returns a KeyNotFoundError if the master key of the file is not in k, and
+// ErrFileCorrupt if the file has been truncated.
+func NewFileReader(r io.ReaderAt, size int64, k *Keyring) (*FileReader, error) {
+	h, n, ok, err := readFileHeader(r)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("file not encrypted")
+	}
+
+	master, err := k.AEAD(h.keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := master.NonceSize()
+	key, err := master.Open(nil, h.dataKey[:ns], h.dataKey[ns:], appendFileHeader(nil, h.chunkSize, h.keyID))
+	if err != nil {
+		return nil, ErrFileCorrupt
+	}
+
+	aead, err := NewAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every file holds at least one, possibly empty, chunk.
+	overhead := int64(aead.Overhead())
+	sealedSize := int64(h.chunkSize) + overhead
+	body := size - int64(n)
+	if body < overhead {
+		return nil, ErrFileCorrupt
+	}
+	chunkN := (body + sealedSize - 1) / sealedSize
+	if body-(chunkN-1)*sealedSize < overhead {
+		return nil, ErrFileCorrupt
+	}
+
+	fr := &FileReader{
+		r:         r,
+		keyID:     h.keyID,
+		aead:      aead,
+		offset:    int64(n),
+		chunkSize: int64(h.chunkSize),
+		chunkN:    chunkN,
+		size:      body - chunkN*overhead,
+	}
+
+	// Open the last chunk, so a truncated file is detected before it is read.
+	if _, err := fr.chunk(chunkN - 1); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// KeyID returns the ID of the master key the file is encrypted with.
+func (r *FileReader) KeyID() string { return r.keyID }
+
+// Size returns the size of the data of the file.
+func (r *FileReader) Size() int64 { return r.size }
+
+// ReadAt reads len(p) bytes of data into p, starting at offset off of the data. It
+// returns ErrFileCorrupt if a chunk read cannot be authenticated.
+func (r *FileReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	var n int
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+
+		data, err := r.chunk(off / r.chunkSize)
+		if err != nil {
+			return n, err
+		}
+
+		m := copy(p[n:], data[off%r.chunkSize:])
+		n += m
+		off += int64(m)
+	}
+	return n, nil
+}
+
+// Free drops the cached chunks.
+func (r *FileReader) Free() {
+	r.mu.Lock()
+	r.cache = nil
+	r.mu.Unlock()
+}
+
+// chunk returns the data of chunk i, from the cache if it was read recently.
+func (r *FileReader) chunk(i int64) ([]byte, error) {
+	r.mu.Lock()
+	for j, c := range r.cache {
+		if c.i == i {
+			copy(r.cache[1:j+1], r.cache[:j])
+			r.cache[0] = c
+			r.mu.Unlock()
+			return c.data, nil
+		}
+	}
+	r.mu.Unlock()
+
+	// Chunks are read and opened outside the lock so concurrent reads of
+	// different chunks do not wait on each other.
+	sealedSize := r.chunkSize + int64(r.aead.Overhead())
+	off := r.offset + i*sealedSize
+	b := make([]byte, sealedSize)
+	n, err := r.r.ReadAt(b, off)
+	if err != nil && !(err == io.EOF && i == r.chunkN-1) {
+		if err == io.EOF {
+			return nil, ErrFileCorrupt
+		}
+		return nil, err
+	}
+
+	data, err := openChunk(r.aead, b[:0], b[:n], uint64(i), i == r.chunkN-1)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if len(r.cache) < fileReaderCacheN {
+		r.cache = append(r.cache, fileChunk{})
+	}
+	copy(r.cache[1:], r.cache)
+	r.cache[0] = fileChunk{i: i, data: data}
+	r.mu.Unlock()
+	return data, nil
+}
+
+// chunkNonce returns the nonce of chunk i.
+func chunkNonce(aead cipher.AEAD, i uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], i)
+	return nonce
+}
+
+// chunkData returns the additional data of a chunk.
+func chunkData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// openChunk appends the data of chunk i, sealed in b, to dst.
+func openChunk(aead cipher.AEAD, dst, b []byte, i uint64, last bool) ([]byte, error) {
+	dst, err := aead.Open(dst, chunkNonce(aead, i), b, chunkData(last))
+	if err != nil {
+		return nil, ErrFileCorrupt
+	}
+	return dst, nil
+}
+
+// FileWriter encrypts the data written to it with a new data key, wrapped by the
+// active key of a keyring, and writes it to an underlying writer. The header is
+// written along with the first chunk, so nothing is written for an empty file.
+type FileWriter struct {
+	w         io.Writer
+	keyring   *Keyring
+	chunkSize int
+
+	aead   cipher.AEAD // seals chunks with the data key
+	buf    []byte      // data of the current chunk
+	sealed []byte      // buffer of sealed chunks
+	chunk  uint64      // index of the current chunk
+	err    error
+}
+
+// NewFileWriter returns a FileWriter writing the data written to it to w, encrypted
+// with the active key of k.
+func NewFileWriter(w io.Writer, k *Keyring) *FileWriter {
+	return &FileWriter{w: w, keyring: k, chunkSize: DefaultChunkSize}
+}
+
+// Write encrypts and writes the chunks filled by p. The last chunk is only written
+// once the writer is closed.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data follows it, as the last
+		// chunk is sealed differently.
+		if len(w.buf) == w.chunkSize {
+			if w.err = w.writeChunk(false); w.err != nil {
+				return 0, w.err
+			}
+		}
+
+		if w.buf == nil {
+			w.buf = make([]byte, 0, w.chunkSize)
+		}
+		i := len(p)
+		if free := w.chunkSize - len(w.buf); i > free {
+			i = free
+		}
+		w.buf, p = append(w.buf, p[:i]...), p[i:]
+	}
+	return n, nil
+}
+
+// Close writes the last chunk of the file. It does not close the underlying writer.
+func (w *FileWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	} else if w.buf == nil {
+		return nil
+	}
+
+	if w.err = w.writeChunk(true); w.err != nil {
+		return w.err
+	}
+	w.err = errors.New("encrypted file writer closed")
+	return nil
+}
+
+// writeChunk seals and writes the current chunk, preceded by the header for the
+// first chunk.
+func (w *FileWriter) writeChunk(last bool) error {
+	b := w.sealed[:0]
+	if w.aead == nil {
+		hdr, err := w.header()
+		if err != nil {
+			return err
+		}
+		b = append(b, hdr...)
+	}
+
+	b = w.aead.Seal(b, chunkNonce(w.aead, w.chunk), w.buf, chunkData(last))
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+	w.sealed = b
+
+	w.chunk++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// header generates the data key of the file and returns the header holding it.
+func (w *FileWriter) header() ([]byte, error) {
+	master, err := w.keyring.AEAD(w.keyring.ActiveID())
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, master.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	hdr := appendFileHeader(nil, w.chunkSize, w.keyring.ActiveID())
+	b := append(hdr, nonce...)
+	b = master.Seal(b, nonce, key, hdr)
+
+	if w.aead, err = NewAEAD(key); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
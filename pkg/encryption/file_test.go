@@ -0,0 +1,163 @@
+package encryption_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/encryption"
+)
+
+func mustNewKeyring(t *testing.T, keys ...byte) *encryption.Keyring {
+	t.Helper()
+
+	var b [][]byte
+	for _, k := range keys {
+		b = append(b, bytes.Repeat([]byte{k}, encryption.KeySize))
+	}
+
+	k, err := encryption.NewKeyring(b...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func mustEncrypt(t *testing.T, k *encryption.Keyring, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := encryption.NewFileWriter(&buf, k)
+	// Write in uneven pieces to cross chunk boundaries.
+	for p := data; len(p) > 0; {
+		n := 1000
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadFile(t *testing.T) {
+	k := mustNewKeyring(t, 1)
+	for _, n := range []int{1, 100, encryption.DefaultChunkSize - 1, encryption.DefaultChunkSize, encryption.DefaultChunkSize + 1, 3*encryption.DefaultChunkSize + 17} {
+		data := make([]byte, n)
+		rand.Read(data)
+
+		b := mustEncrypt(t, k, data)
+		// Short random data may appear in the ciphertext by chance.
+		if n >= 100 && bytes.Contains(b, data[:n/2+1]) {
+			t.Fatalf("unexpected plaintext in encrypted file of %d bytes", n)
+		}
+
+		if id, err := encryption.FileKeyID(bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		} else if id != k.ActiveID() {
+			t.Fatalf("unexpected key id: got %s, exp %s", id, k.ActiveID())
+		}
+
+		got, err := encryption.ReadFile(bytes.NewReader(b), int64(len(b)), k)
+		if err != nil {
+			t.Fatalf("read file of %d bytes: %v", n, err)
+		} else if !bytes.Equal(got, data) {
+			t.Fatalf("data mismatch for file of %d bytes", n)
+		}
+	}
+}
+
+func TestFileReader_ReadAt(t *testing.T) {
+	k := mustNewKeyring(t, 1)
+	data := make([]byte, 40*encryption.DefaultChunkSize+123)
+	rand.Read(data)
+	b := mustEncrypt(t, k, data)
+
+	r, err := encryption.NewFileReader(bytes.NewReader(b), int64(len(b)), k)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := r.Size(), int64(len(data)); got != exp {
+		t.Fatalf("unexpected size: got %d, exp %d", got, exp)
+	} else if r.KeyID() != k.ActiveID() {
+		t.Fatalf("unexpected key id: %s", r.KeyID())
+	}
+
+	// Read ranges across chunk boundaries, from more chunks than are cached.
+	for i := 0; i < 1000; i++ {
+		off := rand.Int63n(int64(len(data)))
+		n := rand.Intn(3 * encryption.DefaultChunkSize)
+		if i%100 == 0 {
+			r.Free()
+		}
+
+		p := make([]byte, n)
+		m, err := r.ReadAt(p, off)
+		if exp := int64(len(data)) - off; int64(n) > exp {
+			if err != io.EOF || int64(m) != exp {
+				t.Fatalf("read %d bytes at %d: got %d bytes, err %v", n, off, m, err)
+			}
+		} else if err != nil || m != n {
+			t.Fatalf("read %d bytes at %d: got %d bytes, err %v", n, off, m, err)
+		}
+		if !bytes.Equal(p[:m], data[off:off+int64(m)]) {
+			t.Fatalf("data mismatch reading %d bytes at %d", n, off)
+		}
+	}
+}
+
+func TestFileWriter_Empty(t *testing.T) {
+	if b := mustEncrypt(t, mustNewKeyring(t, 1), nil); len(b) != 0 {
+		t.Fatalf("unexpected data written for empty file: %d bytes", len(b))
+	}
+}
+
+func TestFileKeyID_NotEncrypted(t *testing.T) {
+	for _, b := range [][]byte{nil, {0x16, 0xD1}, []byte("TSI1 not encrypted")} {
+		if id, err := encryption.FileKeyID(bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		} else if id != "" {
+			t.Fatalf("unexpected key id: %s", id)
+		}
+	}
+}
+
+func TestReadFile_MissingKey(t *testing.T) {
+	b := mustEncrypt(t, mustNewKeyring(t, 1, 2), []byte("data"))
+
+	for _, k := range []*encryption.Keyring{nil, mustNewKeyring(t, 2)} {
+		if _, err := encryption.ReadFile(bytes.NewReader(b), int64(len(b)), k); !encryption.IsKeyNotFound(err) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Keys that have been rotated still decrypt existing files.
+	if got, err := encryption.ReadFile(bytes.NewReader(b), int64(len(b)), mustNewKeyring(t, 2, 1)); err != nil {
+		t.Fatal(err)
+	} else if string(got) != "data" {
+		t.Fatalf("unexpected data: %q", got)
+	}
+}
+
+func TestReadFile_Corrupt(t *testing.T) {
+	k := mustNewKeyring(t, 1)
+	data := make([]byte, 2*encryption.DefaultChunkSize+100)
+	b := mustEncrypt(t, k, data)
+
+	// Drop the last chunk, leaving a file ending on a chunk boundary.
+	truncated := b[:len(b)-(100+16)]
+
+	modified := append([]byte(nil), b...)
+	modified[len(modified)-1] ^= 0xff
+
+	for _, c := range [][]byte{truncated, modified, b[:len(b)-1]} {
+		if _, err := encryption.ReadFile(bytes.NewReader(c), int64(len(c)), k); err != encryption.ErrFileCorrupt {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package encryption provides the keys and ciphers used to encrypt data at rest.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// KeySize is the size in bytes of the AES-256 keys held by a Keyring.
+const KeySize = 32
+
+// ErrNoKeys is returned when creating a Keyring without any keys.
+var ErrNoKeys = errors.New("no encryption keys")
+
+// KeyNotFoundError is returned when a key is not held by a Keyring.
+type KeyNotFoundError struct {
+	ID string
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return fmt.Sprintf("encryption key %s not found", e.ID)
+}
+
+// IsKeyNotFound returns true if err is, or wraps, a KeyNotFoundError.
+func IsKeyNotFound(err error) bool {
+	var e *KeyNotFoundError
+	return errors.As(err, &e)
+}
+
+// KeyID returns the identifier of key. It is stored alongside encrypted data to
+// find the key needed to decrypt it, and does not reveal the key.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Keyring holds the keys used to encrypt and decrypt data. The active key is
+// used to encrypt new data; the other keys remain available to decrypt existing
+// data while keys are rotated.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyring returns a keyring holding keys. The first key is the active key.
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	k := &Keyring{keys: make(map[string][]byte, len(keys))}
+	for i, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("invalid encryption key size %d, expected %d", len(key), KeySize)
+		}
+
+		id := KeyID(key)
+		if i == 0 {
+			k.active = id
+		}
+		k.keys[id] = append([]byte(nil), key...)
+	}
+	return k, nil
+}
+
+// ParseKeyring returns a keyring holding the hex-encoded keys in s, one per line.
+// Blank lines and lines starting with # are ignored. The first key is the active
+// key.
+func ParseKeyring(s string) (*Keyring, error) {
+	var keys [][]byte
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key on line %d: %v", i+1, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyring(keys...)
+}
+
+// LoadKeyringFile returns a keyring holding the keys in the file at path, in the
+// format read by ParseKeyring.
+func LoadKeyringFile(path string) (*Keyring, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := ParseKeyring(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return k, nil
+}
+
+// ActiveID returns the identifier of the active key.
+func (k *Keyring) ActiveID() string { return k.active }
+
+// Key returns the key identified by id.
+func (k *Keyring) Key(id string) ([]byte, error) {
+	if k != nil {
+		if key, ok := k.keys[id]; ok {
+			return key, nil
+		}
+	}
+	return nil, &KeyNotFoundError{ID: id}
+}
+
+// AEAD returns an AES-GCM cipher using the key identified by id.
+func (k *Keyring) AEAD(id string) (cipher.AEAD, error) {
+	key, err := k.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	return NewAEAD(key)
+}
+
+// NewAEAD returns an AES-GCM cipher using key.
+func NewAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
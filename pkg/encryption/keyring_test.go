@@ -0,0 +1,63 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/encryption"
+)
+
+func TestParseKeyring(t *testing.T) {
+	k1, k2 := bytes.Repeat([]byte{1}, encryption.KeySize), bytes.Repeat([]byte{2}, encryption.KeySize)
+
+	k, err := encryption.ParseKeyring("# rotated keys\n" + hex.EncodeToString(k1) + "\n\n" + hex.EncodeToString(k2) + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := k.ActiveID(), encryption.KeyID(k1); got != exp {
+		t.Fatalf("unexpected active key: got %s, exp %s", got, exp)
+	}
+
+	for _, key := range [][]byte{k1, k2} {
+		if got, err := k.Key(encryption.KeyID(key)); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(got, key) {
+			t.Fatalf("unexpected key: got %x, exp %x", got, key)
+		}
+	}
+
+	if _, err := k.Key("missing"); !encryption.IsKeyNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseKeyring_Invalid(t *testing.T) {
+	for _, s := range []string{"", "# no keys", "zz", hex.EncodeToString([]byte("short"))} {
+		if _, err := encryption.ParseKeyring(s); err == nil {
+			t.Fatalf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestKeyring_AEAD(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, encryption.KeySize)
+	k, err := encryption.NewKeyring(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := k.AEAD(k.ActiveID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, nonce, []byte("data"), nil)
+	if got, err := aead.Open(nil, nonce, sealed, nil); err != nil {
+		t.Fatal(err)
+	} else if string(got) != "data" {
+		t.Fatalf("unexpected plaintext: %q", got)
+	}
+}
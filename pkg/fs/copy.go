@@ -0,0 +1,70 @@
+package fs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CopyFile copies the contents of the file at src to a new file at dst. The
+// new file is synced to disk before returning.
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fi.Mode())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// CopyDir recursively copies the directory at src to dst, which must not exist.
+// Files for which skip returns true are not copied; skip may be nil.
+func CopyDir(src, dst string, skip func(path string, fi os.FileInfo) bool) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if skip != nil && skip(path, fi) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.Mkdir(target, 0777)
+		case fi.Mode().IsRegular():
+			return CopyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
@@ -157,3 +157,40 @@ func MustReadAllFile(path string) string {
 	}
 	return string(data)
 }
+
+func TestCopyDir(t *testing.T) {
+	src, err := ioutil.TempDir("", "fs-copy-src-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer MustRemoveAll(src)
+
+	if err := os.MkdirAll(filepath.Join(src, "a", "b"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "a", "b", "keep"), []byte("keep"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "a", "skip"), []byte("skip"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp, err := ioutil.TempDir("", "fs-copy-dst-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer MustRemoveAll(tmp)
+
+	dst := filepath.Join(tmp, "dst")
+	skip := func(path string, fi os.FileInfo) bool { return fi.Name() == "skip" }
+	if err := fs.CopyDir(src, dst, skip); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := MustReadAllFile(filepath.Join(dst, "a", "b", "keep")), "keep"; got != exp {
+		t.Fatalf("got contents %q, expected %q", got, exp)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "a", "skip")); !os.IsNotExist(err) {
+		t.Fatalf("expected skipped file to not be copied, got %v", err)
+	}
+}
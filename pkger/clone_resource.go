@@ -123,6 +123,21 @@ func checkToResource(ch influxdb.Check, name string) Resource {
 			thresholds = append(thresholds, convertThreshold(th))
 		}
 		r[fieldCheckThresholds] = thresholds
+	case *icheck.Anomaly:
+		r[fieldKind] = KindCheckAnomaly.title()
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(r, map[string]*notification.Duration{
+			fieldCheckWindow: cT.Window,
+		})
+		r[fieldCheckMethod] = cT.Method
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, Resource{
+				fieldLevel:           th.Level.String(),
+				fieldCheckDeviations: th.Deviations,
+			})
+		}
+		r[fieldCheckThresholds] = thresholds
 	}
 	return r
 }
@@ -418,6 +433,9 @@ func endpointToResource(e influxdb.NotificationEndpoint, name string) Resource {
 		r[fieldNotificationEndpointHTTPMethod] = actual.Method
 		r[fieldNotificationEndpointURL] = actual.URL
 		r[fieldType] = actual.AuthMethod
+		assignNonZeroStrings(r, map[string]string{
+			fieldNotificationEndpointContentTemplate: actual.ContentTemplate,
+		})
 		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
 			fieldNotificationEndpointPassword: actual.Password,
 			fieldNotificationEndpointToken:    actual.Token,
@@ -435,6 +453,30 @@ func endpointToResource(e influxdb.NotificationEndpoint, name string) Resource {
 		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.SMTP:
+		r[fieldKind] = KindNotificationEndpointSMTP.title()
+		r[fieldNotificationEndpointHost] = actual.Host
+		r[fieldNotificationEndpointPort] = actual.Port
+		r[fieldNotificationEndpointTLSMode] = actual.TLSMode
+		r[fieldNotificationEndpointFrom] = actual.From
+		r[fieldNotificationEndpointTo] = actual.To
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
+	case *endpoint.Opsgenie:
+		r[fieldKind] = KindNotificationEndpointOpsgenie.title()
+		assignNonZeroStrings(r, map[string]string{
+			fieldNotificationEndpointURL: actual.URL,
+		})
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointAPIKey: actual.APIKey,
+		})
+	case *endpoint.Teams:
+		r[fieldKind] = KindNotificationEndpointTeams.title()
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointURL: actual.URL,
+		})
 	}
 
 	return r
@@ -455,9 +497,13 @@ func ruleToResource(iRule influxdb.NotificationRule, endpointName, name string)
 
 	assignBase := func(base rule.Base) {
 		assignNonZeroFluxDurs(r, map[string]*notification.Duration{
-			fieldEvery:  base.Every,
-			fieldOffset: base.Offset,
+			fieldEvery:                          base.Every,
+			fieldOffset:                         base.Offset,
+			fieldNotificationRuleRepeatInterval: base.RepeatInterval,
 		})
+		if len(base.GroupBy) > 0 {
+			r[fieldNotificationRuleGroupBy] = base.GroupBy
+		}
 
 		var tagRes []Resource
 		for _, tRule := range base.TagRules {
@@ -496,6 +542,17 @@ func ruleToResource(iRule influxdb.NotificationRule, endpointName, name string)
 		assignBase(t.Base)
 		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(r, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.SMTP:
+		assignBase(t.Base)
+		r[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		r[fieldNotificationRuleMessageTemplate] = t.BodyTemplate
+	case *rule.Opsgenie:
+		assignBase(t.Base)
+		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+	case *rule.Teams:
+		assignBase(t.Base)
+		r[fieldNotificationRuleTitle] = t.Title
+		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 	}
 
 	return r
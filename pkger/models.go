@@ -23,6 +23,7 @@ const (
 	KindUnknown                       Kind = ""
 	KindBucket                        Kind = "bucket"
 	KindCheck                         Kind = "check"
+	KindCheckAnomaly                  Kind = "check_anomaly"
 	KindCheckDeadman                  Kind = "check_deadman"
 	KindCheckThreshold                Kind = "check_threshold"
 	KindDashboard                     Kind = "dashboard"
@@ -31,6 +32,9 @@ const (
 	KindNotificationEndpointPagerDuty Kind = "notification_endpoint_pager_duty"
 	KindNotificationEndpointHTTP      Kind = "notification_endpoint_http"
 	KindNotificationEndpointSlack     Kind = "notification_endpoint_slack"
+	KindNotificationEndpointSMTP      Kind = "notification_endpoint_smtp"
+	KindNotificationEndpointOpsgenie  Kind = "notification_endpoint_opsgenie"
+	KindNotificationEndpointTeams     Kind = "notification_endpoint_teams"
 	KindNotificationRule              Kind = "notification_rule"
 	KindPackage                       Kind = "package"
 	KindTask                          Kind = "task"
@@ -41,6 +45,7 @@ const (
 var kinds = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
 	KindCheckThreshold:                true,
 	KindDashboard:                     true,
@@ -49,6 +54,9 @@ var kinds = map[Kind]bool{
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
+	KindNotificationEndpointTeams:     true,
 	KindNotificationRule:              true,
 	KindPackage:                       true,
 	KindTask:                          true,
@@ -59,6 +67,7 @@ var kinds = map[Kind]bool{
 var kindsUniqByName = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
 	KindCheckThreshold:                true,
 	KindLabel:                         true,
@@ -66,6 +75,9 @@ var kindsUniqByName = map[Kind]bool{
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
+	KindNotificationEndpointTeams:     true,
 	KindVariable:                      true,
 }
 
@@ -105,7 +117,7 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	switch k {
 	case KindBucket:
 		return influxdb.BucketsResourceType
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckDeadman, KindCheckThreshold:
 		return influxdb.ChecksResourceType
 	case KindDashboard:
 		return influxdb.DashboardsResourceType
@@ -114,7 +126,10 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointOpsgenie,
+		KindNotificationEndpointTeams:
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
@@ -715,6 +730,10 @@ type (
 		LabelAssociations []SummaryLabel      `json:"labelAssociations"`
 		Offset            string              `json:"offset"`
 		MessageTemplate   string              `json:"messageTemplate"`
+		SubjectTemplate   string              `json:"subjectTemplate,omitempty"`
+		Title             string              `json:"title,omitempty"`
+		GroupBy           []string            `json:"groupBy,omitempty"`
+		RepeatInterval    string              `json:"repeatInterval,omitempty"`
 		Status            influxdb.Status     `json:"status"`
 		StatusRules       []SummaryStatusRule `json:"statusRules"`
 		TagRules          []SummaryTagRule    `json:"tagRules"`
@@ -946,16 +965,20 @@ type checkKind int
 const (
 	checkKindDeadman checkKind = iota + 1
 	checkKindThreshold
+	checkKindAnomaly
 )
 
 const (
 	fieldCheckAllValues             = "allValues"
+	fieldCheckDeviations            = "deviations"
+	fieldCheckMethod                = "method"
 	fieldCheckReportZero            = "reportZero"
 	fieldCheckStaleTime             = "staleTime"
 	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
 	fieldCheckTags                  = "tags"
 	fieldCheckThresholds            = "thresholds"
 	fieldCheckTimeSince             = "timeSince"
+	fieldCheckWindow                = "window"
 )
 
 type check struct {
@@ -966,6 +989,7 @@ type check struct {
 	description   string
 	every         time.Duration
 	level         string
+	method        string
 	offset        time.Duration
 	query         string
 	reportZero    bool
@@ -975,6 +999,8 @@ type check struct {
 	tags          []struct{ k, v string }
 	timeSince     time.Duration
 	thresholds    []threshold
+	window        time.Duration
+	deviations    []anomalyThreshold
 
 	labels sortedLabels
 
@@ -1045,6 +1071,13 @@ func (c *check) summarize() SummaryCheck {
 			StaleTime:  toNotificationDuration(c.staleTime),
 			TimeSince:  toNotificationDuration(c.timeSince),
 		}
+	case checkKindAnomaly:
+		sum.Check = &icheck.Anomaly{
+			Base:       base,
+			Window:     toNotificationDuration(c.window),
+			Method:     c.method,
+			Thresholds: toInfluxAnomalyThresholds(c.deviations...),
+		}
 	}
 	return sum
 }
@@ -1090,6 +1123,31 @@ func (c *check) valid() []validationErr {
 				vErrs = append(vErrs, fail)
 			}
 		}
+	case checkKindAnomaly:
+		if c.window <= c.every {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckWindow,
+				Msg:   "duration value must be provided that is > every",
+			})
+		}
+		if c.method != icheck.AnomalyMethodMean && c.method != icheck.AnomalyMethodMedian {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckMethod,
+				Msg:   fmt.Sprintf("must be 1 in [%s, %s]; got=%q", icheck.AnomalyMethodMean, icheck.AnomalyMethodMedian, c.method),
+			})
+		}
+		if len(c.deviations) == 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckThresholds,
+				Msg:   "must provide at least 1 threshold entry",
+			})
+		}
+		for i, th := range c.deviations {
+			for _, fail := range th.valid() {
+				fail.Index = intPtr(i)
+				vErrs = append(vErrs, fail)
+			}
+		}
 	}
 	return vErrs
 }
@@ -1181,6 +1239,39 @@ func toInfluxThresholds(thresholds ...threshold) []icheck.ThresholdConfig {
 	return iThresh
 }
 
+type anomalyThreshold struct {
+	level      string
+	deviations float64
+}
+
+func (t anomalyThreshold) valid() []validationErr {
+	var vErrs []validationErr
+	if notification.ParseCheckLevel(t.level) == notification.Unknown {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", t.level),
+		})
+	}
+	if t.deviations <= 0 {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldCheckDeviations,
+			Msg:   "must be greater than 0",
+		})
+	}
+	return vErrs
+}
+
+func toInfluxAnomalyThresholds(thresholds ...anomalyThreshold) []icheck.AnomalyThreshold {
+	var iThresh []icheck.AnomalyThreshold
+	for _, th := range thresholds {
+		iThresh = append(iThresh, icheck.AnomalyThreshold{
+			Level:      notification.ParseCheckLevel(th.level),
+			Deviations: th.deviations,
+		})
+	}
+	return iThresh
+}
+
 type assocMapKey struct {
 	resType influxdb.ResourceType
 	name    string
@@ -1348,6 +1439,9 @@ const (
 	notificationKindHTTP notificationKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindSMTP
+	notificationKindOpsgenie
+	notificationKindTeams
 )
 
 const (
@@ -1357,12 +1451,24 @@ const (
 )
 
 const (
-	fieldNotificationEndpointHTTPMethod = "method"
-	fieldNotificationEndpointPassword   = "password"
-	fieldNotificationEndpointRoutingKey = "routingKey"
-	fieldNotificationEndpointToken      = "token"
-	fieldNotificationEndpointURL        = "url"
-	fieldNotificationEndpointUsername   = "username"
+	notificationSMTPDefaultPort    = 25
+	notificationSMTPDefaultTLSMode = "starttls"
+)
+
+const (
+	fieldNotificationEndpointAPIKey          = "apiKey"
+	fieldNotificationEndpointContentTemplate = "contentTemplate"
+	fieldNotificationEndpointFrom            = "from"
+	fieldNotificationEndpointHost            = "host"
+	fieldNotificationEndpointHTTPMethod      = "method"
+	fieldNotificationEndpointPassword        = "password"
+	fieldNotificationEndpointPort            = "port"
+	fieldNotificationEndpointRoutingKey      = "routingKey"
+	fieldNotificationEndpointTLSMode         = "tlsMode"
+	fieldNotificationEndpointTo              = "to"
+	fieldNotificationEndpointToken           = "token"
+	fieldNotificationEndpointURL             = "url"
+	fieldNotificationEndpointUsername        = "username"
 )
 
 type notificationEndpoint struct {
@@ -1379,6 +1485,14 @@ type notificationEndpoint struct {
 	httpType    string
 	url         string
 	username    references
+	host        string
+	port        int
+	tlsMode     string
+	from        string
+	to          []string
+	apiKey      references
+	urlRef      references
+	contentTmpl string
 
 	labels sortedLabels
 
@@ -1435,9 +1549,10 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 	switch n.kind {
 	case notificationKindHTTP:
 		e := &endpoint.HTTP{
-			Base:   base,
-			URL:    n.url,
-			Method: n.method,
+			Base:            base,
+			URL:             n.url,
+			Method:          n.method,
+			ContentTemplate: n.contentTmpl,
 		}
 		switch n.httpType {
 		case notificationHTTPAuthTypeBasic:
@@ -1463,10 +1578,49 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindSMTP:
+		e := &endpoint.SMTP{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.port,
+			TLSMode:  n.tlsMode,
+			Username: n.username.SecretField(),
+			Password: n.password.SecretField(),
+			From:     n.from,
+			To:       n.to,
+		}
+		if e.Port == 0 {
+			e.Port = notificationSMTPDefaultPort
+		}
+		if e.TLSMode == "" {
+			e.TLSMode = notificationSMTPDefaultTLSMode
+		}
+		sum.NotificationEndpoint = e
+	case notificationKindOpsgenie:
+		sum.NotificationEndpoint = &endpoint.Opsgenie{
+			Base:   base,
+			URL:    n.url,
+			APIKey: n.apiKey.SecretField(),
+		}
+	case notificationKindTeams:
+		sum.NotificationEndpoint = &endpoint.Teams{
+			Base: base,
+			URL:  n.urlRef.SecretField(),
+		}
 	}
 	return sum
 }
 
+// requiresURL returns true when the endpoint kind is a plain, mandatory url.
+func (n *notificationEndpoint) requiresURL() bool {
+	switch n.kind {
+	case notificationKindOpsgenie, notificationKindSMTP, notificationKindTeams:
+		return false
+	default:
+		return true
+	}
+}
+
 var validEndpointHTTPMethods = map[string]bool{
 	"DELETE":  true,
 	"GET":     true,
@@ -1479,7 +1633,7 @@ var validEndpointHTTPMethods = map[string]bool{
 
 func (n *notificationEndpoint) valid() []validationErr {
 	var failures []validationErr
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
+	if _, err := url.Parse(n.url); n.requiresURL() && (err != nil || n.url == "") {
 		failures = append(failures, validationErr{
 			Field: fieldNotificationEndpointURL,
 			Msg:   "must be valid url",
@@ -1495,6 +1649,59 @@ func (n *notificationEndpoint) valid() []validationErr {
 	}
 
 	switch n.kind {
+	case notificationKindOpsgenie:
+		if !n.apiKey.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointAPIKey,
+				Msg:   "must be provide",
+			})
+		}
+	case notificationKindTeams:
+		if !n.urlRef.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must be provide",
+			})
+		}
+	case notificationKindSMTP:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must provide non empty string",
+			})
+		}
+		if n.port < 0 || n.port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   "must be a valid port",
+			})
+		}
+		switch n.tlsMode {
+		case "", "none", "starttls", "tls":
+		default:
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointTLSMode,
+				Msg:   fmt.Sprintf("invalid tls mode provided %q; valid tls mode is 1 in [none, starttls, tls]", n.tlsMode),
+			})
+		}
+		if n.username.hasValue() != n.password.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPassword,
+				Msg:   "must provide both or neither of username and password",
+			})
+		}
+		if n.from == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must provide non empty string",
+			})
+		}
+		if len(n.to) == 0 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointTo,
+				Msg:   "must provide at least 1",
+			})
+		}
 	case notificationKindPagerDuty:
 		if !n.routingKey.hasValue() {
 			failures = append(failures, validationErr{
@@ -1562,9 +1769,13 @@ const (
 	fieldNotificationRuleChannel         = "channel"
 	fieldNotificationRuleCurrentLevel    = "currentLevel"
 	fieldNotificationRuleEndpointName    = "endpointName"
+	fieldNotificationRuleGroupBy         = "groupBy"
 	fieldNotificationRuleMessageTemplate = "messageTemplate"
 	fieldNotificationRulePreviousLevel   = "previousLevel"
+	fieldNotificationRuleRepeatInterval  = "repeatInterval"
 	fieldNotificationRuleStatusRules     = "statusRules"
+	fieldNotificationRuleSubjectTemplate = "subjectTemplate"
+	fieldNotificationRuleTitle           = "title"
 	fieldNotificationRuleTagRules        = "tagRules"
 )
 
@@ -1573,14 +1784,18 @@ type notificationRule struct {
 	orgID influxdb.ID
 	name  string
 
-	channel     string
-	description string
-	every       time.Duration
-	msgTemplate string
-	offset      time.Duration
-	status      string
-	statusRules []struct{ curLvl, prevLvl string }
-	tagRules    []struct{ k, v, op string }
+	channel        string
+	description    string
+	every          time.Duration
+	msgTemplate    string
+	subjTemplate   string
+	title          string
+	offset         time.Duration
+	groupBy        []string
+	repeatInterval time.Duration
+	status         string
+	statusRules    []struct{ curLvl, prevLvl string }
+	tagRules       []struct{ k, v, op string }
 
 	endpointID   influxdb.ID
 	endpointName string
@@ -1628,6 +1843,10 @@ func (r *notificationRule) summarize() SummaryNotificationRule {
 		LabelAssociations: toSummaryLabels(r.labels...),
 		Offset:            r.offset.String(),
 		MessageTemplate:   r.msgTemplate,
+		SubjectTemplate:   r.subjTemplate,
+		Title:             r.title,
+		GroupBy:           r.groupBy,
+		RepeatInterval:    durToStr(r.repeatInterval),
 		Status:            r.Status(),
 		StatusRules:       toSummaryStatusRules(r.statusRules),
 		TagRules:          toSummaryTagRules(r.tagRules),
@@ -1643,6 +1862,10 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 		OrgID:       r.orgID,
 		Every:       toNotificationDuration(r.every),
 		Offset:      toNotificationDuration(r.offset),
+		GroupBy:     r.groupBy,
+	}
+	if r.repeatInterval > 0 {
+		base.RepeatInterval = toNotificationDuration(r.repeatInterval)
 	}
 	for _, sr := range r.statusRules {
 		var prevLvl *notification.CheckLevel
@@ -1679,6 +1902,23 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case "smtp":
+		return &rule.SMTP{
+			Base:            base,
+			SubjectTemplate: r.subjTemplate,
+			BodyTemplate:    r.msgTemplate,
+		}
+	case "opsgenie":
+		return &rule.Opsgenie{
+			Base:            base,
+			MessageTemplate: r.msgTemplate,
+		}
+	case "teams":
+		return &rule.Teams{
+			Base:            base,
+			Title:           r.title,
+			MessageTemplate: r.msgTemplate,
+		}
 	}
 	return nil
 }
@@ -2452,8 +2692,8 @@ type color struct {
 }
 
 // TODO:
-//  - verify templates are desired
-//  - template colors so references can be shared
+//   - verify templates are desired
+//   - template colors so references can be shared
 type colors []*color
 
 func (c colors) influxViewColors() []influxdb.ViewColor {
@@ -2490,8 +2730,9 @@ func (c colors) strings() []string {
 }
 
 // TODO: looks like much of these are actually getting defaults in
-//  the UI. looking at sytem charts, seeign lots of failures for missing
-//  color types or no colors at all.
+//
+//	the UI. looking at sytem charts, seeign lots of failures for missing
+//	color types or no colors at all.
 func (c colors) hasTypes(types ...string) []validationErr {
 	tMap := make(map[string]bool)
 	for _, cc := range c {
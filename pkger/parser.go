@@ -561,6 +561,7 @@ func (p *Pkg) graphChecks() *parseErr {
 	}{
 		{kind: KindCheckThreshold, checkKind: checkKindThreshold},
 		{kind: KindCheckDeadman, checkKind: checkKindDeadman},
+		{kind: KindCheckAnomaly, checkKind: checkKindAnomaly},
 	}
 	var pErr parseErr
 	for _, k := range checkKinds {
@@ -578,6 +579,7 @@ func (p *Pkg) graphChecks() *parseErr {
 				description:   r.stringShort(fieldDescription),
 				every:         r.durationShort(fieldEvery),
 				level:         r.stringShort(fieldLevel),
+				method:        normStr(r.stringShort(fieldCheckMethod)),
 				offset:        r.durationShort(fieldOffset),
 				query:         strings.TrimSpace(r.stringShort(fieldQuery)),
 				reportZero:    r.boolShort(fieldCheckReportZero),
@@ -585,6 +587,7 @@ func (p *Pkg) graphChecks() *parseErr {
 				status:        normStr(r.stringShort(fieldStatus)),
 				statusMessage: r.stringShort(fieldCheckStatusMessageTemplate),
 				timeSince:     r.durationShort(fieldCheckTimeSince),
+				window:        r.durationShort(fieldCheckWindow),
 			}
 			for _, tagRes := range r.slcResource(fieldCheckTags) {
 				ch.tags = append(ch.tags, struct{ k, v string }{
@@ -593,6 +596,13 @@ func (p *Pkg) graphChecks() *parseErr {
 				})
 			}
 			for _, th := range r.slcResource(fieldCheckThresholds) {
+				if k.checkKind == checkKindAnomaly {
+					ch.deviations = append(ch.deviations, anomalyThreshold{
+						level:      strings.TrimSpace(strings.ToUpper(th.stringShort(fieldLevel))),
+						deviations: th.float64Short(fieldCheckDeviations),
+					})
+					continue
+				}
 				ch.thresholds = append(ch.thresholds, threshold{
 					threshType: thresholdType(normStr(th.stringShort(fieldType))),
 					allVals:    th.boolShort(fieldCheckAllValues),
@@ -676,6 +686,18 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointSMTP,
+			notificationKind: notificationKindSMTP,
+		},
+		{
+			kind:             KindNotificationEndpointOpsgenie,
+			notificationKind: notificationKindOpsgenie,
+		},
+		{
+			kind:             KindNotificationEndpointTeams,
+			notificationKind: notificationKindTeams,
+		},
 	}
 
 	var pErr parseErr
@@ -700,6 +722,14 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 				token:       r.references(fieldNotificationEndpointToken),
 				url:         r.stringShort(fieldNotificationEndpointURL),
 				username:    r.references(fieldNotificationEndpointUsername),
+				host:        r.stringShort(fieldNotificationEndpointHost),
+				port:        r.intShort(fieldNotificationEndpointPort),
+				tlsMode:     normStr(r.stringShort(fieldNotificationEndpointTLSMode)),
+				from:        r.stringShort(fieldNotificationEndpointFrom),
+				to:          r.slcStr(fieldNotificationEndpointTo),
+				apiKey:      r.references(fieldNotificationEndpointAPIKey),
+				urlRef:      r.references(fieldNotificationEndpointURL),
+				contentTmpl: r.stringShort(fieldNotificationEndpointContentTemplate),
 			}
 			failures := p.parseNestedLabels(r, func(l *label) error {
 				endpoint.labels = append(endpoint.labels, l)
@@ -708,7 +738,10 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 			})
 			sort.Sort(endpoint.labels)
 
-			refs := []references{endpoint.password, endpoint.routingKey, endpoint.token, endpoint.username}
+			refs := []references{endpoint.password, endpoint.routingKey, endpoint.token, endpoint.username, endpoint.apiKey}
+			if nk.notificationKind == notificationKindTeams {
+				refs = append(refs, endpoint.urlRef)
+			}
 			for _, ref := range refs {
 				if secret := ref.Secret; secret != "" {
 					p.mSecrets[secret] = false
@@ -732,14 +765,18 @@ func (p *Pkg) graphNotificationRules() *parseErr {
 	p.mNotificationRules = make([]*notificationRule, 0)
 	return p.eachResource(KindNotificationRule, 1, func(r Resource) []validationErr {
 		rule := &notificationRule{
-			name:         r.Name(),
-			endpointName: r.stringShort(fieldNotificationRuleEndpointName),
-			description:  r.stringShort(fieldDescription),
-			channel:      r.stringShort(fieldNotificationRuleChannel),
-			every:        r.durationShort(fieldEvery),
-			msgTemplate:  r.stringShort(fieldNotificationRuleMessageTemplate),
-			offset:       r.durationShort(fieldOffset),
-			status:       normStr(r.stringShort(fieldStatus)),
+			name:           r.Name(),
+			endpointName:   r.stringShort(fieldNotificationRuleEndpointName),
+			description:    r.stringShort(fieldDescription),
+			channel:        r.stringShort(fieldNotificationRuleChannel),
+			every:          r.durationShort(fieldEvery),
+			msgTemplate:    r.stringShort(fieldNotificationRuleMessageTemplate),
+			subjTemplate:   r.stringShort(fieldNotificationRuleSubjectTemplate),
+			title:          r.stringShort(fieldNotificationRuleTitle),
+			offset:         r.durationShort(fieldOffset),
+			groupBy:        r.slcStr(fieldNotificationRuleGroupBy),
+			repeatInterval: r.durationShort(fieldNotificationRuleRepeatInterval),
+			status:         normStr(r.stringShort(fieldStatus)),
 		}
 
 		for _, sRule := range r.slcResource(fieldNotificationRuleStatusRules) {
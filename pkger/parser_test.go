@@ -448,7 +448,7 @@ spec:
 	t.Run("pkg with checks", func(t *testing.T) {
 		testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
 			sum := pkg.Summary()
-			require.Len(t, sum.Checks, 2)
+			require.Len(t, sum.Checks, 3)
 
 			check1 := sum.Checks[0]
 			thresholdCheck, ok := check1.Check.(*icheck.Threshold)
@@ -519,6 +519,29 @@ spec:
 			assert.True(t, deadmanCheck.ReportZero)
 			assert.Len(t, check2.LabelAssociations, 1)
 
+			check3 := sum.Checks[2]
+			anomalyCheck, ok := check3.Check.(*icheck.Anomaly)
+			require.Truef(t, ok, "got: %#v", check3)
+
+			expectedBase = icheck.Base{
+				Name:                  "check_2",
+				Description:           "desc_2",
+				Every:                 mustDuration(t, time.Hour),
+				Offset:                mustDuration(t, 5*time.Minute),
+				StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+			}
+			expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"cpu\")\n  |> filter(fn: (r) => r._field == \"usage_idle\")\n  |> aggregateWindow(every: 1h, fn: mean)"
+			assert.Equal(t, expectedBase, anomalyCheck.Base)
+			assert.Equal(t, influxdb.Active, check3.Status)
+			assert.Equal(t, mustDuration(t, 7*24*time.Hour), anomalyCheck.Window)
+			assert.Equal(t, icheck.AnomalyMethodMedian, anomalyCheck.Method)
+			expectedAnomalyThresholds := []icheck.AnomalyThreshold{
+				{Level: notification.Warn, Deviations: 2.0},
+				{Level: notification.Critical, Deviations: 3.5},
+			}
+			assert.Equal(t, expectedAnomalyThresholds, anomalyCheck.Thresholds)
+			assert.Len(t, check3.LabelAssociations, 1)
+
 			containsLabelMappings(t, sum.LabelMappings,
 				labelMapping{
 					labelName: "label_1",
@@ -530,6 +553,11 @@ spec:
 					resName:   "check_1",
 					resType:   influxdb.ChecksResourceType,
 				},
+				labelMapping{
+					labelName: "label_1",
+					resName:   "check_2",
+					resType:   influxdb.ChecksResourceType,
+				},
 			)
 		})
 
@@ -831,6 +859,60 @@ spec:
       staleTime: 10m
       statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
       timeSince: 90s
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "window not greater than every",
+						validationErrs: 1,
+						valFields:      []string{fieldCheckWindow},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 1h
+      query: from("bucketer")
+      window: 1h
+      method: mean
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      thresholds:
+        - level: CRIT
+          deviations: 3.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "invalid method and deviations",
+						validationErrs: 2,
+						valFields:      []string{fieldCheckMethod, fieldCheckDeviations},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 1h
+      query: from("bucketer")
+      window: 168h
+      method: mode
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      thresholds:
+        - level: CRIT
+          deviations: -3.0
 `,
 					},
 				},
@@ -3530,6 +3612,74 @@ spec:
       name: dupe
       url: example.com
       status: rando bad status
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "missing smtp host and recipients",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointHost, fieldNotificationEndpointTo},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_SMTP
+      name: name1
+      from: influxdb@example.com
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "invalid smtp tls mode",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointTLSMode},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_SMTP
+      name: name1
+      host: localhost
+      tlsMode: ssl
+      from: influxdb@example.com
+      to:
+        - oncall@example.com
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "missing smtp password",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointPassword},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_SMTP
+      name: name1
+      host: localhost
+      username: user
+      from: influxdb@example.com
+      to:
+        - oncall@example.com
 `,
 					},
 				},
@@ -3541,6 +3691,97 @@ spec:
 		})
 	})
 
+	t.Run("pkg with opsgenie, teams and templated http notification endpoints", func(t *testing.T) {
+		testfileRunner(t, "testdata/notification_endpoint_opsgenie_teams.yml", func(t *testing.T, pkg *Pkg) {
+			expectedEndpoints := []SummaryNotificationEndpoint{
+				{
+					NotificationEndpoint: &endpoint.HTTP{
+						Base: endpoint.Base{
+							Name:   "webhook_notification_endpoint",
+							Status: influxdb.TaskStatusActive,
+						},
+						URL:             "https://www.example.com/endpoint/webhook",
+						AuthMethod:      "none",
+						Method:          "POST",
+						ContentTemplate: `{"text": "${r._check_name} is ${r._level}"}`,
+					},
+				},
+				{
+					NotificationEndpoint: &endpoint.Opsgenie{
+						Base: endpoint.Base{
+							Name:        "opsgenie_notification_endpoint",
+							Description: "opsgenie desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						URL:    "https://api.eu.opsgenie.com/v2/alerts",
+						APIKey: influxdb.SecretField{Value: strPtr("secret api-key")},
+					},
+				},
+				{
+					NotificationEndpoint: &endpoint.Teams{
+						Base: endpoint.Base{
+							Name:        "teams_notification_endpoint",
+							Description: "teams desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						URL: influxdb.SecretField{Key: "teams-url"},
+					},
+				},
+			}
+
+			sum := pkg.Summary()
+			endpoints := sum.NotificationEndpoints
+			require.Len(t, endpoints, len(expectedEndpoints))
+			for i := range expectedEndpoints {
+				assert.Equalf(t, expectedEndpoints[i].NotificationEndpoint, endpoints[i].NotificationEndpoint, "index=%d", i)
+			}
+			_, ok := pkg.mSecrets["teams-url"]
+			assert.True(t, ok)
+		})
+	})
+
+	t.Run("pkg with smtp notification endpoints", func(t *testing.T) {
+		testfileRunner(t, "testdata/notification_endpoint_smtp.yml", func(t *testing.T, pkg *Pkg) {
+			expectedEndpoints := []SummaryNotificationEndpoint{
+				{
+					NotificationEndpoint: &endpoint.SMTP{
+						Base: endpoint.Base{
+							Name:   "smtp_defaults_notification_endpoint",
+							Status: influxdb.TaskStatusActive,
+						},
+						Host:    "localhost",
+						Port:    25,
+						TLSMode: "starttls",
+						From:    "influxdb@example.com",
+						To:      []string{"oncall@example.com"},
+					},
+				},
+				{
+					NotificationEndpoint: &endpoint.SMTP{
+						Base: endpoint.Base{
+							Name:        "smtp_notification_endpoint",
+							Description: "smtp desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						Host:     "smtp.example.com",
+						Port:     587,
+						TLSMode:  "starttls",
+						Username: influxdb.SecretField{Value: strPtr("secret username")},
+						Password: influxdb.SecretField{Value: strPtr("secret password")},
+						From:     "influxdb@example.com",
+						To:       []string{"oncall@example.com", "ops@example.com"},
+					},
+				},
+			}
+
+			endpoints := pkg.Summary().NotificationEndpoints
+			require.Len(t, endpoints, len(expectedEndpoints))
+			for i := range expectedEndpoints {
+				assert.Equalf(t, expectedEndpoints[i].NotificationEndpoint, endpoints[i].NotificationEndpoint, "index=%d", i)
+			}
+		})
+	})
+
 	t.Run("pkg with notification rules", func(t *testing.T) {
 		testfileRunner(t, "testdata/notification_rule", func(t *testing.T, pkg *Pkg) {
 			sum := pkg.Summary()
@@ -3553,6 +3794,8 @@ spec:
 			assert.Equal(t, "desc_0", rule.Description)
 			assert.Equal(t, (10 * time.Minute).String(), rule.Every)
 			assert.Equal(t, (30 * time.Second).String(), rule.Offset)
+			assert.Equal(t, []string{"cluster"}, rule.GroupBy)
+			assert.Equal(t, (4 * time.Hour).String(), rule.RepeatInterval)
 			expectedMsgTempl := "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }"
 			assert.Equal(t, expectedMsgTempl, rule.MessageTemplate)
 			assert.Equal(t, influxdb.Active, rule.Status)
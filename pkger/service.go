@@ -270,15 +270,19 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 	var kindPriorities = map[Kind]int{
 		KindLabel:                         1,
 		KindBucket:                        2,
-		KindCheckDeadman:                  3,
-		KindCheckThreshold:                4,
-		KindNotificationEndpointHTTP:      5,
-		KindNotificationEndpointPagerDuty: 6,
-		KindNotificationEndpointSlack:     7,
-		KindNotificationRule:              8,
-		KindVariable:                      9,
-		KindTelegraf:                      10,
-		KindDashboard:                     11,
+		KindCheckAnomaly:                  3,
+		KindCheckDeadman:                  4,
+		KindCheckThreshold:                5,
+		KindNotificationEndpointHTTP:      6,
+		KindNotificationEndpointPagerDuty: 7,
+		KindNotificationEndpointSlack:     8,
+		KindNotificationEndpointSMTP:      9,
+		KindNotificationEndpointOpsgenie:  10,
+		KindNotificationEndpointTeams:     11,
+		KindNotificationRule:              12,
+		KindVariable:                      13,
+		KindTelegraf:                      14,
+		KindDashboard:                     15,
 	}
 
 	sort.Slice(pkg.Spec.Resources, func(i, j int) bool {
@@ -554,6 +558,7 @@ func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone
 		}
 		newResource = bucketToResource(*bkt, r.Name)
 	case r.Kind.is(KindCheck),
+		r.Kind.is(KindCheckAnomaly),
 		r.Kind.is(KindCheckDeadman),
 		r.Kind.is(KindCheckThreshold):
 		ch, err := s.checkSVC.FindCheckByID(ctx, r.ID)
@@ -576,7 +581,10 @@ func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone
 	case r.Kind.is(KindNotificationEndpoint),
 		r.Kind.is(KindNotificationEndpointHTTP),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointSMTP),
+		r.Kind.is(KindNotificationEndpointOpsgenie),
+		r.Kind.is(KindNotificationEndpointTeams):
 		e, err := s.endpointSVC.FindNotificationEndpointByID(ctx, r.ID)
 		if err != nil {
 			return nil, err
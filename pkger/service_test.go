@@ -138,7 +138,7 @@ func TestService(t *testing.T) {
 				require.NoError(t, err)
 
 				checks := diff.Checks
-				require.Len(t, checks, 2)
+				require.Len(t, checks, 3)
 				check0 := checks[0]
 				assert.True(t, check0.IsNew())
 				assert.Equal(t, "check_0", check0.Name)
@@ -533,7 +533,7 @@ func TestService(t *testing.T) {
 					sum, err := svc.Apply(context.TODO(), orgID, 0, pkg)
 					require.NoError(t, err)
 
-					require.Len(t, sum.Checks, 2)
+					require.Len(t, sum.Checks, 3)
 
 					containsWithID := func(t *testing.T, name string) {
 						for _, actualNotification := range sum.Checks {
@@ -548,7 +548,7 @@ func TestService(t *testing.T) {
 						assert.Fail(t, "did not find notification by name: "+name)
 					}
 
-					for _, expectedName := range []string{"check_0", "check_1"} {
+					for _, expectedName := range []string{"check_0", "check_1", "check_2"} {
 						containsWithID(t, expectedName)
 					}
 				})
@@ -859,7 +859,7 @@ func TestService(t *testing.T) {
 				testLabelMappingFn(
 					t,
 					"testdata/checks.yml",
-					2, // 1 for each check
+					3, // 1 for each check
 					func() []ServiceSetterFn {
 						fakeCheckSVC := mock.NewCheckService()
 						fakeCheckSVC.CreateCheckFn = func(ctx context.Context, c influxdb.CheckCreate, id influxdb.ID) error {
@@ -1531,6 +1531,18 @@ func TestService(t *testing.T) {
 							Level:      notification.Critical,
 						},
 					},
+					{
+						name:    "anomaly",
+						newName: "new name",
+						expected: &icheck.Anomaly{
+							Base:   newThresholdBase(2),
+							Window: mustDuration(t, 7*24*time.Hour),
+							Method: icheck.AnomalyMethodMean,
+							Thresholds: []icheck.AnomalyThreshold{
+								{Level: notification.Critical, Deviations: 3},
+							},
+						},
+					},
 				}
 
 				for _, tt := range tests {
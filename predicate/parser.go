@@ -2,6 +2,8 @@ package predicate
 
 import (
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
 
 	"github.com/influxdata/influxdb"
@@ -20,12 +22,40 @@ type buffer [3]struct {
 // to the predicate node
 type parser struct {
 	sc        *influxql.Scanner
-	i         int // buffer index
-	n         int // buffer size
+	r         *byteReader // source of sc
+	offset    int         // offset of the start of sc within the statement
+	i         int         // buffer index
+	n         int         // buffer size
 	openParen int
 	buf       buffer
 }
 
+// byteReader reads a statement one byte at a time, so that everything it has
+// returned has been consumed by the scanner. This lets the parser scan regexes,
+// which the scanner cannot distinguish from division without context.
+type byteReader struct {
+	s string
+	i int
+}
+
+func (r *byteReader) Read(b []byte) (int, error) {
+	if r.i >= len(r.s) {
+		return 0, io.EOF
+	}
+	if len(b) == 0 {
+		return 0, nil
+	}
+	b[0] = r.s[r.i]
+	r.i++
+	return 1, nil
+}
+
+func newParser(sts string) *parser {
+	p := &parser{r: &byteReader{s: sts}}
+	p.sc = influxql.NewScanner(p.r)
+	return p
+}
+
 // scan returns the next token from the underlying scanner.
 // If a token has been unscanned then read that instead.
 func (p *parser) scan() (tok influxql.Token, pos influxql.Pos, lit string) {
@@ -39,6 +69,7 @@ func (p *parser) scan() (tok influxql.Token, pos influxql.Pos, lit string) {
 	p.i = (p.i + 1) % len(p.buf)
 	buf := &p.buf[p.i]
 	buf.tok, buf.pos, buf.lit = p.sc.Scan()
+	buf.pos.Char += p.offset
 
 	return p.curr()
 }
@@ -62,98 +93,177 @@ func (p *parser) scanIgnoreWhitespace() (tok influxql.Token, pos influxql.Pos, l
 	return
 }
 
+// scanRegex scans a regex delimited by slashes directly from the statement,
+// and restarts the scanner after it. It must be called immediately after
+// scanning a regex operator, when the scanner has not read ahead.
+func (p *parser) scanRegex() (string, error) {
+	s, i := p.r.s, p.r.i
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r') {
+		i++
+	}
+
+	pos := i
+	if i >= len(s) || s[i] != '/' {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("expected regex at position %d", pos),
+		}
+	}
+
+	var re strings.Builder
+	for i++; ; i++ {
+		if i >= len(s) {
+			return "", &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("unterminated regex at position %d", pos),
+			}
+		}
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == '/' {
+			re.WriteByte('/')
+			i++
+			continue
+		}
+		if s[i] == '/' {
+			break
+		}
+		re.WriteByte(s[i])
+	}
+
+	if _, err := regexp.Compile(re.String()); err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid regex at position %d: %v", pos, err),
+		}
+	}
+
+	p.r.i = i + 1
+	p.offset = p.r.i
+	p.sc = influxql.NewScanner(p.r)
+	return re.String(), nil
+}
+
 // Parse the predicate statement.
+//
+// Tag rules are combined with AND and OR, where AND binds tighter than OR,
+// and may be grouped with parentheses.
 func Parse(sts string) (n Node, err error) {
 	if sts == "" {
 		return nil, nil
 	}
-	p := new(parser)
-	p.sc = influxql.NewScanner(strings.NewReader(sts))
-	return p.parseLogicalNode()
+	p := newParser(sts)
+	n, err = p.parseLogicalNode()
+	if err != nil {
+		return nil, err
+	}
+
+	tok, pos, _ := p.scanIgnoreWhitespace()
+	switch tok {
+	case influxql.EOF:
+		return n, nil
+	case influxql.RPAREN:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("extra ) seen"),
+		}
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+		}
+	}
 }
 
+// parseLogicalNode parses tag rules and groups joined by OR.
 func (p *parser) parseLogicalNode() (Node, error) {
-	n := new(LogicalNode)
+	n, err := p.parseAndNode()
+	if err != nil {
+		return nil, err
+	}
 	for {
+		if tok, _, _ := p.scanIgnoreWhitespace(); tok != influxql.OR {
+			p.unscan()
+			return n, nil
+		}
+		n1, err := p.parseAndNode()
+		if err != nil {
+			return nil, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalOr,
+		}
+	}
+}
+
+// parseAndNode parses tag rules and groups joined by AND.
+func (p *parser) parseAndNode() (Node, error) {
+	n, err := p.parsePrimaryNode()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if tok, _, _ := p.scanIgnoreWhitespace(); tok != influxql.AND {
+			p.unscan()
+			return n, nil
+		}
+		n1, err := p.parsePrimaryNode()
+		if err != nil {
+			return nil, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalAnd,
+		}
+	}
+}
+
+// parsePrimaryNode parses a single tag rule or a parenthesized group.
+func (p *parser) parsePrimaryNode() (Node, error) {
+	tok, pos, _ := p.scanIgnoreWhitespace()
+	switch tok {
+	case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
+		p.unscan()
+		return p.parseTagRuleNode()
+	case influxql.LPAREN:
+		p.openParen++
+		n, err := p.parseLogicalNode()
+		if err != nil {
+			return nil, err
+		}
 		tok, pos, _ := p.scanIgnoreWhitespace()
 		switch tok {
-		case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
-			p.unscan()
-			tr, err := p.parseTagRuleNode()
-			if err != nil {
-				return *n, err
-			}
-			if n.Children[0] == nil {
-				n.Children[0] = tr
-			} else {
-				n.Children[1] = tr
-			}
-		case influxql.AND:
-			n.Operator = LogicalAnd
-			if n.Children[1] == nil {
-				continue
-			}
-			var n1 Node
-			var err error
-			if tokNext := p.peekTok(); tokNext == influxql.LPAREN {
-				n1, err = p.parseLogicalNode()
-			} else {
-				n1, err = p.parseTagRuleNode()
-			}
-			if err != nil {
-				return *n, err
-			}
-			n = &LogicalNode{
-				Children: [2]Node{*n, n1},
-				Operator: LogicalAnd,
-			}
-		case influxql.OR:
-			return *n, &influxdb.Error{
-				Code: influxdb.EInvalid,
-				Msg:  fmt.Sprintf("the logical operator OR is not supported yet at position %d", pos.Char),
-			}
-		case influxql.LPAREN:
-			p.openParen++
-			currParen := p.openParen
-			n1, err := p.parseLogicalNode()
-			if err != nil {
-				return *n, err
-			}
-			if p.openParen != currParen-1 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  fmt.Sprintf("extra ( seen"),
-				}
-			}
-			if n.Children[0] == nil {
-				n.Children[0] = n1
-			} else {
-				n.Children[1] = n1
-			}
 		case influxql.RPAREN:
 			p.openParen--
-			fallthrough
+			return n, nil
 		case influxql.EOF:
-			if p.openParen < 0 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  fmt.Sprintf("extra ) seen"),
-				}
-			}
-			if n.Children[1] == nil {
-				return n.Children[0], nil
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("extra ( seen"),
 			}
-			return *n, nil
 		default:
-			return *n, &influxdb.Error{
+			return nil, &influxdb.Error{
 				Code: influxdb.EInvalid,
 				Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
 			}
 		}
+	case influxql.EOF:
+		if p.openParen > 0 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("extra ( seen"),
+			}
+		}
+		fallthrough
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+		}
 	}
 }
 
 func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
+	var err error
 	n := new(TagRuleNode)
 	// scan the key
 	tok, pos, lit := p.scanIgnoreWhitespace()
@@ -178,12 +288,11 @@ func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
 		n.Operator = influxdb.NotEqual
 		goto scanRegularTagValue
 	case influxql.EQREGEX:
-		fallthrough
+		n.Operator = influxdb.RegexEqual
+		goto scanRegexTagValue
 	case influxql.NEQREGEX:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("operator: %q at position: %d is not supported yet", tok.String(), pos.Char),
-		}
+		n.Operator = influxdb.NotRegexEqual
+		goto scanRegexTagValue
 	default:
 		return *n, &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -191,6 +300,11 @@ func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
 		}
 	}
 	// scan the value
+scanRegexTagValue:
+	if n.Value, err = p.scanRegex(); err != nil {
+		return *n, err
+	}
+	return *n, nil
 scanRegularTagValue:
 	tok, pos, lit = p.scanIgnoreWhitespace()
 	switch tok {
@@ -219,13 +333,3 @@ scanRegularTagValue:
 		}
 	}
 }
-
-// peekRune returns the next rune that would be read by the scanner.
-func (p *parser) peekTok() influxql.Token {
-	tok, _, _ := p.scanIgnoreWhitespace()
-	if tok != influxql.EOF {
-		p.unscan()
-	}
-
-	return tok
-}
@@ -2,13 +2,11 @@ package predicate
 
 import (
 	"fmt"
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	influxtesting "github.com/influxdata/influxdb/testing"
-	"github.com/influxdata/influxql"
 )
 
 func TestParseNode(t *testing.T) {
@@ -40,9 +38,49 @@ func TestParseNode(t *testing.T) {
 		},
 		{
 			str: ` abc="opq" Or gender="male" OR temp=1123`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "opq"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "gender", Value: "male"}},
+				}},
+				TagRuleNode{Tag: influxdb.Tag{Key: "temp", Value: "1123"}},
+			}},
+		},
+		{
+			str: `a=1 or b=2 and c=3 or d=4`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "a", Value: "1"}},
+					LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+						TagRuleNode{Tag: influxdb.Tag{Key: "b", Value: "2"}},
+						TagRuleNode{Tag: influxdb.Tag{Key: "c", Value: "3"}},
+					}},
+				}},
+				TagRuleNode{Tag: influxdb.Tag{Key: "d", Value: "4"}},
+			}},
+		},
+		{
+			str: `(host="a" or host=~/^web-\d+$/) and _measurement!~ /^(cpu|mem)$/`,
+			node: LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: "a"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: `^web-\d+$`}, Operator: influxdb.RegexEqual},
+				}},
+				TagRuleNode{Tag: influxdb.Tag{Key: "_measurement", Value: "^(cpu|mem)$"}, Operator: influxdb.NotRegexEqual},
+			}},
+		},
+		{
+			str: `a=~/x/ or`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  "the logical operator OR is not supported yet at position 11",
+				Msg:  "bad logical expression, at position 10",
+			},
+		},
+		{
+			str: `a=1 b=2`,
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "bad logical expression, at position 4",
 			},
 		},
 		{
@@ -133,17 +171,36 @@ func TestParseTagRule(t *testing.T) {
 			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "false"}, Operator: influxdb.Equal},
 		},
 		{
-			str: `abc!~/^payments\./`,
+			str:  `abc!~/^payments\./`,
+			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: `^payments\.`}, Operator: influxdb.NotRegexEqual},
+		},
+		{
+			str:  `abc =~  /^payments\./`,
+			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: `^payments\.`}, Operator: influxdb.RegexEqual},
+		},
+		{
+			str:  `abc=~/a\/b/`,
+			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "a/b"}, Operator: influxdb.RegexEqual},
+		},
+		{
+			str: `abc=~"opq"`,
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `expected regex at position 5`,
+			},
+		},
+		{
+			str: `abc=~/opq`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "!~" at position: 3 is not supported yet`,
+				Msg:  `unterminated regex at position 5`,
 			},
 		},
 		{
-			str: `abc=~/^payments\./`,
+			str: `abc=~/(opq/`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "=~" at position: 3 is not supported yet`,
+				Msg:  "invalid regex at position 5: error parsing regexp: missing closing ): `(opq`",
 			},
 		},
 		{
@@ -155,8 +212,7 @@ func TestParseTagRule(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		p := new(parser)
-		p.sc = influxql.NewScanner(strings.NewReader(c.str))
+		p := newParser(c.str)
 		tr, err := p.parseTagRuleNode()
 		influxtesting.ErrorsEqual(t, err, c.err)
 		if c.err == nil {
@@ -0,0 +1,261 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	// MetricNameLabel is the label holding the metric name, which is stored
+	// as the measurement.
+	MetricNameLabel = "__name__"
+
+	// FieldKey is the field that sample values are stored in.
+	FieldKey = "value"
+
+	// measurementTag and fieldTag are the keys of the measurement and field
+	// tags in series read from storage.
+	measurementTag = "_measurement"
+	fieldTag       = "_field"
+)
+
+// ErrMissingMetricName is returned when a time series has no metric name.
+var ErrMissingMetricName = errors.New("time series is missing the " + MetricNameLabel + " label")
+
+// WriteRequestToPoints converts the time series of req to points in the bucket
+// identified by orgID and bucketID. The metric name is the measurement, all
+// other labels are tags and sample values are written to the FieldKey field.
+// Samples that are NaN or infinite can not be stored and are dropped; the number
+// of dropped samples is returned.
+func WriteRequestToPoints(req *WriteRequest, orgID, bucketID influxdb.ID) (points []models.Point, dropped int, err error) {
+	name := tsdb.EncodeName(orgID, bucketID)
+
+	for _, ts := range req.Timeseries {
+		tags := make(map[string]string, len(ts.Labels)+2)
+		for _, l := range ts.Labels {
+			// Prometheus treats an empty label value as an absent label.
+			if l.Value == "" {
+				continue
+			}
+			if l.Name == MetricNameLabel {
+				tags[models.MeasurementTagKey] = l.Value
+				continue
+			}
+			tags[l.Name] = l.Value
+		}
+		if tags[models.MeasurementTagKey] == "" {
+			return nil, 0, ErrMissingMetricName
+		}
+		tags[models.FieldKeyTagKey] = FieldKey
+		t := models.NewTags(tags)
+
+		for _, s := range ts.Samples {
+			if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				dropped++
+				continue
+			}
+
+			p, err := models.NewPoint(string(name[:]), t, models.Fields{FieldKey: s.Value}, time.Unix(0, s.Timestamp*int64(time.Millisecond)))
+			if err != nil {
+				return nil, 0, err
+			}
+			points = append(points, p)
+		}
+	}
+	return points, dropped, nil
+}
+
+// QueryToReadFilterRequest converts q to a request that reads the matching
+// series from source, a read source returned by reads.Store.GetSource.
+func QueryToReadFilterRequest(q *Query, source proto.Message) (*datatypes.ReadFilterRequest, error) {
+	any, err := types.MarshalAny(source)
+	if err != nil {
+		return nil, err
+	}
+
+	pred, err := matchersToPredicate(q.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &datatypes.ReadFilterRequest{
+		ReadSource: any,
+		Predicate:  pred,
+	}
+	req.Range.Start = q.StartTimestampMs * int64(time.Millisecond)
+	// The query range is inclusive of its end, whereas the storage range is not.
+	req.Range.End = (q.EndTimestampMs + 1) * int64(time.Millisecond)
+	return req, nil
+}
+
+func matchersToPredicate(matchers []LabelMatcher) (*datatypes.Predicate, error) {
+	// Only the field samples are written to can be read back.
+	root := comparisonNode(datatypes.ComparisonEqual, models.FieldKeyTagKey, &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_StringValue{StringValue: FieldKey},
+	})
+
+	for _, m := range matchers {
+		key := m.Name
+		if key == MetricNameLabel {
+			key = models.MeasurementTagKey
+		}
+
+		var (
+			comp  datatypes.Node_Comparison
+			value *datatypes.Node
+		)
+		switch m.Type {
+		case LabelMatcher_EQ, LabelMatcher_NEQ:
+			comp = datatypes.ComparisonEqual
+			if m.Type == LabelMatcher_NEQ {
+				comp = datatypes.ComparisonNotEqual
+			}
+			value = &datatypes.Node{
+				NodeType: datatypes.NodeTypeLiteral,
+				Value:    &datatypes.Node_StringValue{StringValue: m.Value},
+			}
+		case LabelMatcher_RE, LabelMatcher_NRE:
+			comp = datatypes.ComparisonRegex
+			if m.Type == LabelMatcher_NRE {
+				comp = datatypes.ComparisonNotRegex
+			}
+			// Prometheus regular expressions are fully anchored.
+			value = &datatypes.Node{
+				NodeType: datatypes.NodeTypeLiteral,
+				Value:    &datatypes.Node_RegexValue{RegexValue: "^(?:" + m.Value + ")$"},
+			}
+		default:
+			return nil, fmt.Errorf("unknown label matcher type %s", m.Type)
+		}
+
+		root = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{root, comparisonNode(comp, key, value)},
+		}
+	}
+
+	return &datatypes.Predicate{Root: root}, nil
+}
+
+func comparisonNode(comp datatypes.Node_Comparison, key string, value *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: comp},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			value,
+		},
+	}
+}
+
+// ResultSetToQueryResult reads all series of rs into a query result, sorted
+// by their labels. Numeric values are returned as float samples; series of
+// other types are skipped. rs is closed when ResultSetToQueryResult returns.
+func ResultSetToQueryResult(rs reads.ResultSet) (*QueryResult, error) {
+	defer rs.Close()
+
+	result := &QueryResult{}
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			continue
+		}
+
+		samples, err := readSamples(cur)
+		cur.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		result.Timeseries = append(result.Timeseries, TimeSeries{
+			Labels:  tagsToLabels(rs.Tags()),
+			Samples: samples,
+		})
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Timeseries, func(i, j int) bool {
+		return lessLabels(result.Timeseries[i].Labels, result.Timeseries[j].Labels)
+	})
+	return result, nil
+}
+
+// lessLabels reports whether the sorted labels a order before b.
+func lessLabels(a, b []Label) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].Name != b[i].Name {
+			return a[i].Name < b[i].Name
+		}
+		if a[i].Value != b[i].Value {
+			return a[i].Value < b[i].Value
+		}
+	}
+	return len(a) < len(b)
+}
+
+func readSamples(cur cursors.Cursor) ([]Sample, error) {
+	var samples []Sample
+	appendSample := func(ts int64, v float64) {
+		samples = append(samples, Sample{Timestamp: ts / int64(time.Millisecond), Value: v})
+	}
+
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, a.Values[i])
+			}
+		}
+	case cursors.IntegerArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, float64(a.Values[i]))
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				appendSample(ts, float64(a.Values[i]))
+			}
+		}
+	}
+	return samples, cur.Err()
+}
+
+func tagsToLabels(tags models.Tags) []Label {
+	labels := make([]Label, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case fieldTag:
+			continue
+		case measurementTag:
+			labels = append(labels, Label{Name: MetricNameLabel, Value: string(t.Value)})
+		default:
+			labels = append(labels, Label{Name: string(t.Key), Value: string(t.Value)})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
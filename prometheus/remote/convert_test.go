@@ -0,0 +1,73 @@
+package remote_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/prometheus/remote"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+)
+
+func TestWriteRequestToPoints_DropsUnsupportedValues(t *testing.T) {
+	req := &remote.WriteRequest{
+		Timeseries: []remote.TimeSeries{
+			{
+				Labels: []remote.Label{{Name: "__name__", Value: "up"}},
+				Samples: []remote.Sample{
+					{Value: math.NaN(), Timestamp: 1},
+					{Value: math.Inf(1), Timestamp: 2},
+					{Value: 1, Timestamp: 3},
+				},
+			},
+		},
+	}
+
+	points, dropped, err := remote.WriteRequestToPoints(req, influxdb.ID(1), influxdb.ID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 2 {
+		t.Errorf("got %d dropped samples, expected 2", dropped)
+	}
+	if len(points) != 1 {
+		t.Fatalf("got %d points, expected 1", len(points))
+	}
+	if got, exp := points[0].UnixNano(), int64(3000000); got != exp {
+		t.Errorf("got timestamp %d, expected %d", got, exp)
+	}
+}
+
+func TestQueryToReadFilterRequest(t *testing.T) {
+	q := &remote.Query{
+		StartTimestampMs: 1,
+		EndTimestampMs:   2,
+		Matchers: []remote.LabelMatcher{
+			{Type: remote.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+			{Type: remote.LabelMatcher_NRE, Name: "job", Value: "a|b"},
+		},
+	}
+
+	req, err := remote.QueryToReadFilterRequest(q, &datatypes.ReadFilterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := req.Range.Start, int64(1000000); got != exp {
+		t.Errorf("got range start %d, expected %d", got, exp)
+	}
+	if got, exp := req.Range.End, int64(3000000); got != exp {
+		t.Errorf("got range end %d, expected %d", got, exp)
+	}
+
+	exp := "'\xff' = \"value\" AND '\x00' = \"up\" AND 'job' !~ /^(?:a|b)$/"
+	if got := reads.PredicateToExprString(req.Predicate); got != exp {
+		t.Errorf("got predicate %q, expected %q", got, exp)
+	}
+
+	q.Matchers = []remote.LabelMatcher{{Type: 42, Name: "job"}}
+	if _, err := remote.QueryToReadFilterRequest(q, &datatypes.ReadFilterRequest{}); err == nil {
+		t.Error("expected error for unknown matcher type")
+	}
+}
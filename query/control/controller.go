@@ -64,6 +64,8 @@ type Controller struct {
 	log *zap.Logger
 
 	dependencies []flux.Dependency
+
+	orgLimits influxdb.OrgLimitEnforcer
 }
 
 type Config struct {
@@ -99,6 +101,10 @@ type Config struct {
 	MetricLabelKeys []string
 
 	ExecutorDependencies []flux.Dependency
+
+	// OrgLimitEnforcer, if set, limits the number of queries each organization
+	// is allowed to run concurrently.
+	OrgLimitEnforcer influxdb.OrgLimitEnforcer
 }
 
 // complete will fill in the defaults, validate the configuration, and
@@ -182,6 +188,7 @@ func New(config Config) (*Controller, error) {
 		metrics:      newControllerMetrics(c.MetricLabelKeys),
 		labelKeys:    c.MetricLabelKeys,
 		dependencies: c.ExecutorDependencies,
+		orgLimits:    c.OrgLimitEnforcer,
 	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
@@ -206,7 +213,17 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	for _, dep := range c.dependencies {
 		ctx = dep.Inject(ctx)
 	}
-	q, err := c.query(ctx, req.Compiler)
+
+	release := func() {}
+	if c.orgLimits != nil {
+		r, err := c.orgLimits.AcquireQuery(ctx, req.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		release = r
+	}
+
+	q, err := c.query(ctx, req.Compiler, req.MemoryBytesLimit, release)
 	if err != nil {
 		return q, err
 	}
@@ -215,12 +232,17 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 }
 
 // query submits a query for execution returning immediately.
-// Done must be called on any returned Query objects.
-func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
+// Done must be called on any returned Query objects. The release
+// function is called once the query is done or has failed to start.
+// A positive memoryBytesLimit lowers the memory quota of the query.
+func (c *Controller) query(ctx context.Context, compiler flux.Compiler, memoryBytesLimit int64, release func()) (flux.Query, error) {
 	q, err := c.createQuery(ctx, compiler.CompilerType())
 	if err != nil {
+		release()
 		return nil, handleFluxError(err)
 	}
+	q.release = release
+	q.memoryBytesLimit = memoryBytesLimit
 
 	if err := c.compileQuery(q, compiler); err != nil {
 		q.setErr(err)
@@ -443,6 +465,10 @@ func (c *Controller) finish(q *Query) {
 		close(c.done)
 	}
 	c.queriesMu.Unlock()
+
+	if q.release != nil {
+		q.release()
+	}
 }
 
 // Queries reports the active queries.
@@ -534,6 +560,13 @@ type Query struct {
 
 	memoryManager *queryMemoryManager
 	alloc         *memory.Allocator
+
+	// memoryBytesLimit, if positive, lowers the memory quota of the query.
+	memoryBytesLimit int64
+
+	// release gives back the concurrent query reserved for the
+	// organization of the query.
+	release func()
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -20,10 +20,13 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb"
+	platformmock "github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/quota"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"go.uber.org/zap/zaptest"
@@ -661,6 +664,55 @@ func TestController_PerQueryMemoryLimit(t *testing.T) {
 	}
 }
 
+func TestController_RequestMemoryLimit(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	const limit = 256
+	run := func(size int) error {
+		compiler := &mock.Compiler{
+			CompileFn: func(ctx context.Context) (flux.Program, error) {
+				return &mock.Program{
+					ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+						defer func() {
+							if err, ok := recover().(error); ok && err != nil {
+								q.SetErr(err)
+							}
+						}()
+
+						mem := arrow.NewAllocator(alloc)
+						b := mem.Allocate(size)
+						mem.Free(b)
+					},
+				}, nil
+			},
+		}
+
+		req := makeRequest(compiler)
+		req.MemoryBytesLimit = limit
+		q, err := ctrl.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+		return q.Err()
+	}
+
+	if err := run(limit); err != nil {
+		t.Fatalf("expected the query to allocate up to its limit, got %v", err)
+	}
+	// The limit is below the quota of the controller, but the query exceeds it.
+	if err := run(limit + 1); err == nil {
+		t.Fatal("expected error about memory limit exceeded")
+	}
+}
+
 func TestController_ConcurrencyQuota(t *testing.T) {
 	const (
 		numQueries       = 3
@@ -727,6 +779,59 @@ func TestController_ConcurrencyQuota(t *testing.T) {
 	}
 }
 
+func TestController_OrgConcurrencyLimit(t *testing.T) {
+	limits := platformmock.NewOrgLimitsService()
+	limits.FindOrgLimitsFn = func(ctx context.Context, orgID platform.ID) (*platform.OrgLimits, error) {
+		return &platform.OrgLimits{OrgID: orgID, ConcurrentQueries: 1}, nil
+	}
+
+	config := config
+	config.ConcurrencyQuota = 2
+	config.QueueSize = 2
+	config.OrgLimitEnforcer = quota.NewEnforcer(limits, nil)
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	request := func(orgID platform.ID) *query.Request {
+		req := makeRequest(mockCompiler)
+		req.OrganizationID = orgID
+		return req
+	}
+	done := func(q flux.Query) {
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+	}
+
+	q, err := ctrl.Query(context.Background(), request(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ctrl.Query(context.Background(), request(1)); platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Fatalf("expected too many requests error, got %v", err)
+	}
+
+	// Other organizations are not affected by the limit.
+	other, err := ctrl.Query(context.Background(), request(2))
+	if err != nil {
+		t.Fatalf("unexpected error for other organization: %s", err)
+	}
+	done(other)
+
+	// The query is given back to the organization once it is done.
+	done(q)
+	q, err = ctrl.Query(context.Background(), request(1))
+	if err != nil {
+		t.Fatalf("unexpected error after query was done: %s", err)
+	}
+	done(q)
+}
+
 func TestController_QueueSize(t *testing.T) {
 	const (
 		concurrencyQuota = 2
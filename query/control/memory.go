@@ -31,9 +31,18 @@ type memoryManager struct {
 // createAllocator will construct an allocator and memory manager
 // for the given query.
 func (c *Controller) createAllocator(q *Query) {
+	quota := c.memory.memoryBytesQuotaPerQuery
+	if q.memoryBytesLimit > 0 && q.memoryBytesLimit < quota {
+		quota = q.memoryBytesLimit
+	}
+	limit := c.memory.initialBytesQuotaPerQuery
+	if limit > quota {
+		limit = quota
+	}
 	q.memoryManager = &queryMemoryManager{
 		m:     c.memory,
-		limit: c.memory.initialBytesQuotaPerQuery,
+		quota: quota,
+		limit: limit,
 	}
 	q.alloc = &memory.Allocator{
 		// Use an anonymous function to ensure the value is copied.
@@ -44,7 +53,11 @@ func (c *Controller) createAllocator(q *Query) {
 
 // queryMemoryManager is a memory manager for a specific query.
 type queryMemoryManager struct {
-	m     *memoryManager
+	m *memoryManager
+
+	// quota is the maximum amount of memory that may be
+	// allocated to this query.
+	quota int64
 	limit int64
 	given int64
 }
@@ -60,8 +73,8 @@ type queryMemoryManager struct {
 // too much about the specific message or structure.
 func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 	// It can be determined statically if we are going to violate
-	// the memoryBytesQuotaPerQuery.
-	if q.limit+want > q.m.memoryBytesQuotaPerQuery {
+	// the quota of the query.
+	if q.limit+want > q.quota {
 		return 0, errors.New("query hit hard limit")
 	}
 
@@ -106,12 +119,12 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 func (q *queryMemoryManager) giveMemory(want, unused int64) int64 {
 	// If we can safely double the limit, then just do that.
 	if q.limit > want && q.limit < unused {
-		if q.limit*2 <= q.m.memoryBytesQuotaPerQuery {
+		if q.limit*2 <= q.quota {
 			return q.limit
 		}
 		// Doubling the limit sends us over the quota.
 		// Determine what would be our maximum amount.
-		max := q.m.memoryBytesQuotaPerQuery - q.limit
+		max := q.quota - q.limit
 		if max > want {
 			return max
 		}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -49,6 +50,17 @@ func (b *BucketLookup) LookupName(ctx context.Context, orgID platform.ID, id pla
 	return bucket.Name
 }
 
+// LookupReadBucket returns the ID of the bucket that holds the data of the bucket
+// with the given id starting at start, which is either the bucket itself or one
+// of its downsampling tiers.
+func (b *BucketLookup) LookupReadBucket(ctx context.Context, orgID platform.ID, id platform.ID, start time.Time) platform.ID {
+	bucket, err := b.BucketService.FindBucketByID(ctx, id)
+	if err != nil || bucket == nil || bucket.OrgID != orgID {
+		return id
+	}
+	return bucket.ReadBucketID(start, time.Now())
+}
+
 func (b *BucketLookup) FindAllBuckets(ctx context.Context, orgID platform.ID) ([]*platform.Bucket, int) {
 	oid := platform.ID(orgID)
 	filter := platform.BucketFilter{
@@ -37,7 +37,7 @@ func (d *Dialect) SetHeaders(w http.ResponseWriter) {
 func (d *Dialect) Encoder() flux.MultiResultEncoder {
 	switch d.Encoding {
 	case JSON, JSONPretty:
-		return new(MultiResultEncoder)
+		return &MultiResultEncoder{TimeFormat: d.TimeFormat}
 	default:
 		panic("not implemented")
 	}
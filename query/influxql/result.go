@@ -13,7 +13,10 @@ import (
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format used to encode time values; defaults to RFC3339Nano.
+	TimeFormat TimeFormat
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -154,7 +157,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = e.formatTime(execute.Time(vs.Value(i)).Time())
 							}
 						}
 					default:
@@ -188,3 +191,24 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
 }
+
+// formatTime converts t into the representation selected by the encoder's TimeFormat.
+// Epoch formats are encoded as integers, matching the influxdb 1.X epoch query parameter.
+func (e *MultiResultEncoder) formatTime(t time.Time) interface{} {
+	switch e.TimeFormat {
+	case Hour:
+		return t.UnixNano() / int64(time.Hour)
+	case Minute:
+		return t.UnixNano() / int64(time.Minute)
+	case Second:
+		return t.UnixNano() / int64(time.Second)
+	case Millisecond:
+		return t.UnixNano() / int64(time.Millisecond)
+	case Microsecond:
+		return t.UnixNano() / int64(time.Microsecond)
+	case Nanosecond:
+		return t.UnixNano()
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
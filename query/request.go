@@ -23,6 +23,10 @@ type Request struct {
 	// Source represents the ultimate source of the request.
 	Source string `json:"source"`
 
+	// MemoryBytesLimit, if positive, is the maximum number of bytes the query
+	// is allowed to use when it is lower than the quota of the controller.
+	MemoryBytesLimit int64 `json:"memory_bytes_limit,omitempty"`
+
 	// compilerMappings maps compiler types to creation methods
 	compilerMappings flux.CompilerMappings
 }
@@ -16,13 +16,18 @@ const FromKind = "influxDBFrom"
 type FromOpSpec struct {
 	Bucket   string `json:"bucket,omitempty"`
 	BucketID string `json:"bucketID,omitempty"`
+
+	// SkipDownsampleTiers reads old data from the bucket itself rather than
+	// from its downsampling tiers.
+	SkipDownsampleTiers bool `json:"skipDownsampleTiers,omitempty"`
 }
 
 func init() {
 	fromSignature := semantic.FunctionPolySignature{
 		Parameters: map[string]semantic.PolyType{
-			"bucket":   semantic.String,
-			"bucketID": semantic.String,
+			"bucket":          semantic.String,
+			"bucketID":        semantic.String,
+			"downsampleTiers": semantic.Bool,
 		},
 		Required: nil,
 		Return:   flux.TableObjectType,
@@ -48,6 +53,12 @@ func createFromOpSpec(args flux.Arguments, a *flux.Administration) (flux.Operati
 		spec.BucketID = bucketID
 	}
 
+	if downsampleTiers, ok, err := args.GetBool("downsampleTiers"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.SkipDownsampleTiers = !downsampleTiers
+	}
+
 	if spec.Bucket == "" && spec.BucketID == "" {
 		return nil, &flux.Error{
 			Code: codes.Invalid,
@@ -99,6 +110,8 @@ func (s *FromOpSpec) BucketsAccessed(orgID *platform.ID) (readBuckets, writeBuck
 type FromProcedureSpec struct {
 	Bucket   string
 	BucketID string
+
+	SkipDownsampleTiers bool
 }
 
 func newFromProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -111,8 +124,9 @@ func newFromProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Proce
 	}
 
 	return &FromProcedureSpec{
-		Bucket:   spec.Bucket,
-		BucketID: spec.BucketID,
+		Bucket:              spec.Bucket,
+		BucketID:            spec.BucketID,
+		SkipDownsampleTiers: spec.SkipDownsampleTiers,
 	}, nil
 }
 
@@ -125,6 +139,7 @@ func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
 
 	ns.Bucket = s.Bucket
 	ns.BucketID = s.BucketID
+	ns.SkipDownsampleTiers = s.SkipDownsampleTiers
 
 	return ns
 }
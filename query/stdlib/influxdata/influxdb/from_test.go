@@ -58,6 +58,21 @@ func TestFrom_NewQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "from bucket ID without downsample tiers",
+			Raw:  `from(bucketID:"aaaabbbbccccdddd", downsampleTiers: false)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							BucketID:            "aaaabbbbccccdddd",
+							SkipDownsampleTiers: true,
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "from with database",
 			Raw:  `from(bucket:"mybucket") |> range(start:-4h, stop:-2h) |> sum()`,
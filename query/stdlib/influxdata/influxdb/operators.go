@@ -17,6 +17,8 @@ const (
 	ReadGroupPhysKind     = "ReadGroupPhysKind"
 	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
 	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+
+	ReadWindowAggregatePhysKind = "ReadWindowAggregatePhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -44,12 +46,45 @@ func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+type ReadWindowAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	WindowEvery int64
+	Aggregate   plan.ProcedureKind
+	CreateEmpty bool
+
+	// TimeColumn is set when the windows are merged back
+	// into a single table per series, see ReadWindowAggregateSpec.
+	TimeColumn string
+}
+
+func (s *ReadWindowAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadWindowAggregatePhysKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadWindowAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.WindowEvery = s.WindowEvery
+	ns.Aggregate = s.Aggregate
+	ns.CreateEmpty = s.CreateEmpty
+
+	ns.TimeColumn = s.TimeColumn
+	return ns
+}
+
 type ReadRangePhysSpec struct {
 	plan.DefaultCost
 
 	Bucket   string
 	BucketID string
 
+	// SkipDownsampleTiers reads old data from the bucket itself rather than
+	// from its downsampling tiers.
+	SkipDownsampleTiers bool
+
 	// FilterSet is set to true if there is a filter.
 	FilterSet bool
 	// Filter is the filter to use when calling into
@@ -68,6 +103,7 @@ func (s *ReadRangePhysSpec) Copy() plan.ProcedureSpec {
 
 	ns.Bucket = s.Bucket
 	ns.BucketID = s.BucketID
+	ns.SkipDownsampleTiers = s.SkipDownsampleTiers
 
 	ns.FilterSet = s.FilterSet
 	if ns.FilterSet {
@@ -1,6 +1,8 @@
 package influxdb
 
 import (
+	"math"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -17,7 +19,11 @@ func init() {
 		PushDownReadTagKeysRule{},
 		PushDownReadTagValuesRule{},
 		SortedPivotRule{},
+		PushDownAggregateWindowRule{},
 	)
+	for _, kind := range windowAggregateKinds {
+		plan.RegisterPhysicalRules(PushDownWindowAggregateRule{Kind: kind})
+	}
 }
 
 // PushDownGroupRule pushes down a group operation to storage
@@ -58,6 +64,147 @@ func (rule PushDownGroupRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
 	}), true, nil
 }
 
+// windowAggregateKinds are the kinds of the aggregates
+// that storage can compute for each window.
+var windowAggregateKinds = []plan.ProcedureKind{
+	universe.FirstKind,
+	universe.LastKind,
+	universe.MinKind,
+	universe.MaxKind,
+	universe.MeanKind,
+	universe.SumKind,
+	universe.CountKind,
+}
+
+// PushDownWindowAggregateRule pushes down a windowed aggregate to storage.
+// It matches 'ReadRange |> window() |> agg()' for the aggregate of Kind.
+type PushDownWindowAggregateRule struct {
+	Kind plan.ProcedureKind
+}
+
+func (rule PushDownWindowAggregateRule) Name() string {
+	return "PushDownWindowAggregateRule(" + string(rule.Kind) + ")"
+}
+
+func (rule PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Pat(rule.Kind, plan.Pat(universe.WindowKind, plan.Pat(ReadRangePhysKind)))
+}
+
+func (rule PushDownWindowAggregateRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	windowNode := pn.Predecessors()[0]
+	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	fromSpec := windowNode.Predecessors()[0].ProcedureSpec().(*ReadRangePhysSpec)
+
+	if !isPushableWindow(windowSpec) || !isPushableAggregate(pn.ProcedureSpec()) {
+		return pn, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadWindowAggregate", &ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: *fromSpec.Copy().(*ReadRangePhysSpec),
+		WindowEvery:       windowSpec.Window.Every.Nanoseconds(),
+		Aggregate:         rule.Kind,
+		CreateEmpty:       windowSpec.CreateEmpty,
+	}), true, nil
+}
+
+// isPushableWindow returns true if the windows can be computed by storage,
+// which only supports fixed windows of a whole number of nanoseconds that
+// do not overlap and are aligned to the Unix epoch.
+func isPushableWindow(spec *universe.WindowProcedureSpec) bool {
+	w := spec.Window
+	switch {
+	case w.Every.Months() != 0 || !w.Every.IsPositive():
+		return false
+	case w.Every.Nanoseconds() == math.MaxInt64:
+		// A window of infinite duration does not window the data.
+		return false
+	case !w.Period.Equal(w.Every) || !w.Offset.IsZero():
+		return false
+	}
+	return spec.TimeColumn == execute.DefaultTimeColLabel &&
+		spec.StartColumn == execute.DefaultStartColLabel &&
+		spec.StopColumn == execute.DefaultStopColLabel
+}
+
+// isPushableAggregate returns true if the aggregate is only computed
+// on the _value column, which is the only column storage aggregates.
+func isPushableAggregate(spec plan.ProcedureSpec) bool {
+	var columns []string
+	switch s := spec.(type) {
+	case *universe.FirstProcedureSpec:
+		columns = []string{s.Column}
+	case *universe.LastProcedureSpec:
+		columns = []string{s.Column}
+	case *universe.MinProcedureSpec:
+		columns = []string{s.Column}
+	case *universe.MaxProcedureSpec:
+		columns = []string{s.Column}
+	case *universe.MeanProcedureSpec:
+		columns = s.Columns
+	case *universe.SumProcedureSpec:
+		columns = s.Columns
+	case *universe.CountProcedureSpec:
+		columns = s.Columns
+	}
+	return len(columns) == 1 && columns[0] == execute.DefaultValueColLabel
+}
+
+// PushDownAggregateWindowRule merges the windows of a windowed aggregate
+// pushed down to storage back into a single table for each series.
+// It matches 'ReadWindowAggregate |> duplicate(column: "_stop", as: "_time") |> window(every: inf)',
+// which is what remains of aggregateWindow() once PushDownWindowAggregateRule
+// has been applied.
+type PushDownAggregateWindowRule struct{}
+
+func (PushDownAggregateWindowRule) Name() string {
+	return "PushDownAggregateWindowRule"
+}
+
+func (PushDownAggregateWindowRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.WindowKind,
+		plan.Pat(universe.SchemaMutationKind,
+			plan.Pat(ReadWindowAggregatePhysKind)))
+}
+
+func (PushDownAggregateWindowRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	windowSpec := pn.ProcedureSpec().(*universe.WindowProcedureSpec)
+	duplicateNode := pn.Predecessors()[0]
+	duplicateSpec := duplicateNode.ProcedureSpec().(*universe.SchemaMutationProcedureSpec)
+	fromSpec := duplicateNode.Predecessors()[0].ProcedureSpec().(*ReadWindowAggregatePhysSpec)
+
+	// The windows have already been merged.
+	if fromSpec.TimeColumn != "" {
+		return pn, false, nil
+	}
+
+	// The window needs to merge all of the windows of a series.
+	if w := windowSpec.Window; w.Every.Nanoseconds() != math.MaxInt64 || w.Every.Months() != 0 {
+		return pn, false, nil
+	} else if windowSpec.TimeColumn != execute.DefaultTimeColLabel ||
+		windowSpec.StartColumn != execute.DefaultStartColLabel ||
+		windowSpec.StopColumn != execute.DefaultStopColLabel {
+		return pn, false, nil
+	}
+
+	// The schema mutator needs to correspond to a duplicate of
+	// one of the window bounds as the time column.
+	if len(duplicateSpec.Mutations) != 1 {
+		return pn, false, nil
+	}
+	m, ok := duplicateSpec.Mutations[0].(*universe.DuplicateOpSpec)
+	if !ok {
+		return pn, false, nil
+	} else if m.Column != execute.DefaultStartColLabel && m.Column != execute.DefaultStopColLabel {
+		return pn, false, nil
+	} else if m.As != execute.DefaultTimeColLabel {
+		return pn, false, nil
+	}
+
+	newFromSpec := fromSpec.Copy().(*ReadWindowAggregatePhysSpec)
+	newFromSpec.TimeColumn = m.Column
+	return plan.CreatePhysicalNode("ReadWindowAggregate", newFromSpec), true, nil
+}
+
 // PushDownRangeRule pushes down a range filter to storage
 type PushDownRangeRule struct{}
 
@@ -77,9 +224,10 @@ func (rule PushDownRangeRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
 
 	rangeSpec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
 	return plan.CreatePhysicalNode("ReadRange", &ReadRangePhysSpec{
-		Bucket:   fromSpec.Bucket,
-		BucketID: fromSpec.BucketID,
-		Bounds:   rangeSpec.Bounds,
+		Bucket:              fromSpec.Bucket,
+		BucketID:            fromSpec.BucketID,
+		SkipDownsampleTiers: fromSpec.SkipDownsampleTiers,
+		Bounds:              rangeSpec.Bounds,
 	}), true, nil
 }
 
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 )
 
@@ -59,6 +61,32 @@ func TestPushDownRangeRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "without downsample tiers",
+			// from -> range  =>  ReadRange
+			Rules: []plan.Rule{
+				influxdb.PushDownRangeRule{},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("from", &influxdb.FromProcedureSpec{
+						Bucket:              "my-bucket",
+						SkipDownsampleTiers: true,
+					}),
+					plan.CreateLogicalNode("range", &rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &influxdb.ReadRangePhysSpec{
+						Bucket:              "my-bucket",
+						SkipDownsampleTiers: true,
+						Bounds:              readRangeSpec.Bounds,
+					}),
+				},
+			},
+		},
 		{
 			Name: "with successor",
 			// from -> range -> count  =>  ReadRange -> count
@@ -853,6 +881,242 @@ func TestPushDownGroupRule(t *testing.T) {
 	}
 }
 
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	newWindow := func(every, period, offset time.Duration) *universe.WindowProcedureSpec {
+		return &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  values.ConvertDuration(every),
+				Period: values.ConvertDuration(period),
+				Offset: values.ConvertDuration(offset),
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+			CreateEmpty: true,
+		}
+	}
+	// windowAggregate returns the plan 'ReadRange -> window -> agg'.
+	// The window procedure spec does not copy all of its fields,
+	// so the plans that do not change are created twice rather
+	// than with NoChange.
+	windowAggregate := func(window *universe.WindowProcedureSpec, id plan.NodeID, agg plan.PhysicalProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadRange", &readRange),
+				plan.CreatePhysicalNode("window", window),
+				plan.CreatePhysicalNode(id, agg),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+	readWindowAggregate := func(kind plan.ProcedureKind) *influxdb.ReadWindowAggregatePhysSpec {
+		return &influxdb.ReadWindowAggregatePhysSpec{
+			ReadRangePhysSpec: readRange,
+			WindowEvery:       int64(time.Minute),
+			Aggregate:         kind,
+			CreateEmpty:       true,
+		}
+	}
+
+	minSpec := &universe.MinProcedureSpec{
+		SelectorConfig: execute.DefaultSelectorConfig,
+	}
+	maxSpec := &universe.MaxProcedureSpec{
+		SelectorConfig: execute.DefaultSelectorConfig,
+	}
+	firstSpec := &universe.FirstProcedureSpec{
+		SelectorConfig: execute.DefaultSelectorConfig,
+	}
+	sumSpec := &universe.SumProcedureSpec{
+		AggregateConfig: execute.AggregateConfig{Columns: []string{"x"}},
+	}
+	window := newWindow(time.Minute, time.Minute, 0)
+	overlapping := newWindow(time.Minute, 2*time.Minute, 0)
+	offset := newWindow(time.Minute, time.Minute, 30*time.Second)
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "min",
+			// ReadRange -> window -> min  =>  ReadWindowAggregate
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{Kind: universe.MinKind},
+			},
+			Before: windowAggregate(window, "min", minSpec),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", readWindowAggregate(universe.MinKind)),
+				},
+			},
+		},
+		{
+			Name: "mean with successor",
+			// ReadRange -> window -> mean -> count  =>  ReadWindowAggregate -> count
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{Kind: universe.MeanKind},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", readWindowAggregate(universe.MeanKind)),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name: "overlapping windows",
+			// ReadRange -> window(period: 2m) -> max  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{Kind: universe.MaxKind},
+			},
+			Before: windowAggregate(overlapping, "max", maxSpec),
+			After:  windowAggregate(overlapping, "max", maxSpec),
+		},
+		{
+			Name: "offset",
+			// ReadRange -> window(offset: 30s) -> first  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{Kind: universe.FirstKind},
+			},
+			Before: windowAggregate(offset, "first", firstSpec),
+			After:  windowAggregate(offset, "first", firstSpec),
+		},
+		{
+			Name: "other column",
+			// ReadRange -> window -> sum(columns: ["x"])  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{Kind: universe.SumKind},
+			},
+			Before: windowAggregate(window, "sum", sumSpec),
+			After:  windowAggregate(window, "sum", sumSpec),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestPushDownAggregateWindowRule(t *testing.T) {
+	readWindowAggregate := influxdb.ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: influxdb.ReadRangePhysSpec{
+			Bucket: "my-bucket",
+			Bounds: flux.Bounds{
+				Start: fluxTime(5),
+				Stop:  fluxTime(10),
+			},
+		},
+		WindowEvery: int64(time.Minute),
+		Aggregate:   universe.MeanKind,
+		CreateEmpty: true,
+	}
+	merged := readWindowAggregate
+	merged.TimeColumn = execute.DefaultStopColLabel
+
+	inf := values.ConvertDuration(math.MaxInt64)
+	windowInf := &universe.WindowProcedureSpec{
+		Window: plan.WindowSpec{
+			Every:  inf,
+			Period: inf,
+		},
+		TimeColumn:  execute.DefaultTimeColLabel,
+		StartColumn: execute.DefaultStartColLabel,
+		StopColumn:  execute.DefaultStopColLabel,
+	}
+	// aggregateWindow returns the plan 'ReadWindowAggregate -> duplicate -> window(every: inf)'.
+	// The schema mutation and window procedure specs cannot be copied,
+	// so the plans that do not change are created twice rather than
+	// with NoChange.
+	aggregateWindow := func(spec *influxdb.ReadWindowAggregatePhysSpec, column string) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadWindowAggregate", spec),
+				plan.CreatePhysicalNode("duplicate", &universe.SchemaMutationProcedureSpec{
+					Mutations: []universe.SchemaMutation{
+						&universe.DuplicateOpSpec{Column: column, As: execute.DefaultTimeColLabel},
+					},
+				}),
+				plan.CreatePhysicalNode("window", windowInf),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "aggregateWindow",
+			// ReadWindowAggregate -> duplicate -> window(every: inf)  =>  ReadWindowAggregate
+			Rules: []plan.Rule{
+				influxdb.PushDownAggregateWindowRule{},
+			},
+			Before: aggregateWindow(&readWindowAggregate, execute.DefaultStopColLabel),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &merged),
+				},
+			},
+		},
+		{
+			Name: "duplicate other column",
+			// ReadWindowAggregate -> duplicate(column: "_value") -> window(every: inf)  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownAggregateWindowRule{},
+			},
+			Before: aggregateWindow(&readWindowAggregate, execute.DefaultValueColLabel),
+			After:  aggregateWindow(&readWindowAggregate, execute.DefaultValueColLabel),
+		},
+		{
+			Name: "already merged",
+			// ReadWindowAggregate -> duplicate -> window(every: inf)  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownAggregateWindowRule{},
+			},
+			Before: aggregateWindow(&merged, execute.DefaultStopColLabel),
+			After:  aggregateWindow(&merged, execute.DefaultStopColLabel),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
 func TestReadTagKeysRule(t *testing.T) {
 	fromSpec := influxdb.FromProcedureSpec{
 		Bucket: "my-bucket",
@@ -0,0 +1,246 @@
+// Package smtp is the Flux package sending the notifications of the SMTP
+// notification endpoint as emails.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/smtp"
+
+// TLS modes of the connection to the SMTP server.
+const (
+	TLSModeNone     = "none"     // plain text
+	TLSModeSTARTTLS = "starttls" // plain text upgraded with STARTTLS
+	TLSModeTLS      = "tls"      // TLS from the start
+)
+
+// dialTimeout bounds the time to connect to the SMTP server.
+const dialTimeout = 10 * time.Second
+
+const source = `package smtp
+
+import "experimental"
+
+// send sends an email through an SMTP server, returning true once the server accepted it.
+builtin send
+
+// endpoint creates the endpoint for an SMTP server.
+// The returned factory function accepts a mapFn parameter, which must return
+// an object with the subject and body fields of the email.
+endpoint = (host, port=25, tls="starttls", username="", password="", from, to) =>
+    (mapFn) =>
+        (tables=<-) => tables
+            |> map(fn: (r) => {
+                obj = mapFn(r: r)
+                return {r with _sent: string(v: send(
+                    host: host,
+                    port: port,
+                    tls: tls,
+                    username: username,
+                    password: password,
+                    from: from,
+                    to: to,
+                    subject: obj.subject,
+                    body: obj.body,
+                ))}
+            })
+            |> experimental.group(mode: "extend", columns: ["_sent"])
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Package = "smtp"
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+	flux.RegisterPackageValue(PackagePath, "send", sendFunc)
+}
+
+var sendFunc = values.NewFunction(
+	"send",
+	semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Parameters: map[string]semantic.PolyType{
+			"host":     semantic.String,
+			"port":     semantic.Int,
+			"tls":      semantic.String,
+			"username": semantic.String,
+			"password": semantic.String,
+			"from":     semantic.String,
+			"to":       semantic.NewArrayPolyType(semantic.String),
+			"subject":  semantic.String,
+			"body":     semantic.String,
+		},
+		Required: semantic.LabelSet{"host", "port", "tls", "from", "to", "subject", "body"},
+		Return:   semantic.Bool,
+	}),
+	func(ctx context.Context, args values.Object) (values.Value, error) {
+		m, err := readMessage(interpreter.NewArguments(args))
+		if err != nil {
+			return nil, err
+		}
+		if err := Send(ctx, m); err != nil {
+			return nil, &flux.Error{
+				Code: codes.Unavailable,
+				Msg:  "could not send email",
+				Err:  err,
+			}
+		}
+		return values.NewBool(true), nil
+	},
+	true,
+)
+
+// Message is an email and the SMTP server to send it through.
+type Message struct {
+	Host     string
+	Port     int
+	TLSMode  string
+	Username string
+	Password string
+	From     string
+	To       []string
+	Subject  string
+	Body     string
+}
+
+func readMessage(args interpreter.Arguments) (Message, error) {
+	var (
+		m   Message
+		err error
+	)
+	if m.Host, err = args.GetRequiredString("host"); err != nil {
+		return m, err
+	}
+	port, err := args.GetRequiredInt("port")
+	if err != nil {
+		return m, err
+	}
+	m.Port = int(port)
+	if m.TLSMode, err = args.GetRequiredString("tls"); err != nil {
+		return m, err
+	}
+	if m.Username, _, err = args.GetString("username"); err != nil {
+		return m, err
+	}
+	if m.Password, _, err = args.GetString("password"); err != nil {
+		return m, err
+	}
+	if m.From, err = args.GetRequiredString("from"); err != nil {
+		return m, err
+	}
+	to, err := args.GetRequiredArray("to", semantic.String)
+	if err != nil {
+		return m, err
+	}
+	to.Range(func(i int, v values.Value) {
+		m.To = append(m.To, v.Str())
+	})
+	if m.Subject, err = args.GetRequiredString("subject"); err != nil {
+		return m, err
+	}
+	if m.Body, err = args.GetRequiredString("body"); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// Send sends the email of the message through its SMTP server.
+func Send(ctx context.Context, m Message) error {
+	if len(m.To) == 0 {
+		return fmt.Errorf("no recipient")
+	}
+
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	tlsConfig := &tls.Config{ServerName: m.Host}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	switch m.TLSMode {
+	case TLSModeNone, TLSModeSTARTTLS:
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	case TLSModeTLS:
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	default:
+		return fmt.Errorf("invalid tls mode %q", m.TLSMode)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if m.TLSMode == TLSModeSTARTTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.From); err != nil {
+		return err
+	}
+	for _, to := range m.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.bytes(time.Now())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// bytes returns the headers and body of the email.
+func (m Message) bytes(date time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	body := strings.Replace(m.Body, "\r\n", "\n", -1)
+	b.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
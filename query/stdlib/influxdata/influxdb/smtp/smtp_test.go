@@ -0,0 +1,114 @@
+package smtp_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/smtp"
+)
+
+// serveSMTP accepts a single connection on l, answering as an SMTP server
+// that accepts any email, and sends the commands and data it received on ch.
+func serveSMTP(t *testing.T, l net.Listener, ch chan<- []string) {
+	conn, err := l.Accept()
+	if err != nil {
+		t.Error(err)
+		close(ch)
+		return
+	}
+	defer conn.Close()
+
+	var received []string
+	r := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost")
+	data := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		received = append(received, line)
+		switch {
+		case data:
+			if line == "." {
+				data = false
+				reply("250 OK")
+			}
+		case strings.HasPrefix(line, "DATA"):
+			data = true
+			reply("354 go ahead")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 bye")
+			ch <- received
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+	ch <- received
+}
+
+func TestSend(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	ch := make(chan []string, 1)
+	go serveSMTP(t, l, ch)
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	err = smtp.Send(context.Background(), smtp.Message{
+		Host:    host,
+		Port:    p,
+		TLSMode: smtp.TLSModeNone,
+		From:    "influxdb@example.com",
+		To:      []string{"oncall@example.com", "ops@example.com"},
+		Subject: "cpu is crit",
+		Body:    "cpu usage\nis 99%",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := strings.Join(<-ch, "\n")
+	for _, exp := range []string{
+		"MAIL FROM:<influxdb@example.com>",
+		"RCPT TO:<oncall@example.com>",
+		"RCPT TO:<ops@example.com>",
+		"To: oncall@example.com, ops@example.com",
+		"Subject: cpu is crit",
+		"cpu usage\nis 99%",
+	} {
+		if !strings.Contains(received, exp) {
+			t.Errorf("expected the server to receive %q, got:\n%s", exp, received)
+		}
+	}
+}
+
+func TestSend_invalidTLSMode(t *testing.T) {
+	err := smtp.Send(context.Background(), smtp.Message{Host: "localhost", Port: 25, TLSMode: "ssl", To: []string{"a@example.com"}})
+	if err == nil {
+		t.Fatal("expected an invalid tls mode to fail")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	script := `
+import "influxdata/influxdb/smtp"
+
+e = smtp.endpoint(host: "localhost", from: "influxdb@example.com", to: ["oncall@example.com"])
+`
+	if _, _, err := flux.Eval(context.Background(), script); err != nil {
+		t.Fatal(err)
+	}
+}
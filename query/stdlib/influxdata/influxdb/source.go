@@ -20,6 +20,7 @@ import (
 func init() {
 	execute.RegisterSource(ReadRangePhysKind, createReadFilterSource)
 	execute.RegisterSource(ReadGroupPhysKind, createReadGroupSource)
+	execute.RegisterSource(ReadWindowAggregatePhysKind, createReadWindowAggregateSource)
 	execute.RegisterSource(ReadTagKeysPhysKind, createReadTagKeysSource)
 	execute.RegisterSource(ReadTagValuesPhysKind, createReadTagValuesSource)
 }
@@ -180,6 +181,9 @@ func createReadFilterSource(s plan.ProcedureSpec, id execute.DatasetID, a execut
 	if err != nil {
 		return nil, err
 	}
+	if !spec.SkipDownsampleTiers {
+		bucketID = lookupReadBucketID(ctx, deps.BucketLookup, orgID, bucketID, *bounds)
+	}
 
 	var filter *semantic.FunctionExpression
 	if spec.FilterSet {
@@ -198,6 +202,15 @@ func createReadFilterSource(s plan.ProcedureSpec, id execute.DatasetID, a execut
 	), nil
 }
 
+// lookupReadBucketID returns the ID of the bucket to read the data of bucketID
+// within bounds from, which may be one of its downsampling tiers.
+func lookupReadBucketID(ctx context.Context, lookup BucketLookup, orgID, bucketID platform.ID, bounds execute.Bounds) platform.ID {
+	if l, ok := lookup.(ReadBucketLookup); ok {
+		return l.LookupReadBucket(ctx, orgID, bucketID, bounds.Start.Time())
+	}
+	return bucketID
+}
+
 type readGroupSource struct {
 	Source
 	reader   Reader
@@ -257,6 +270,9 @@ func createReadGroupSource(s plan.ProcedureSpec, id execute.DatasetID, a execute
 	if err != nil {
 		return nil, err
 	}
+	if !spec.SkipDownsampleTiers {
+		bucketID = lookupReadBucketID(ctx, deps.BucketLookup, orgID, bucketID, *bounds)
+	}
 
 	var filter *semantic.FunctionExpression
 	if spec.FilterSet {
@@ -280,6 +296,92 @@ func createReadGroupSource(s plan.ProcedureSpec, id execute.DatasetID, a execute
 	), nil
 }
 
+type readWindowAggregateSource struct {
+	Source
+	reader   Reader
+	readSpec ReadWindowAggregateSpec
+}
+
+func ReadWindowAggregateSource(id execute.DatasetID, r Reader, readSpec ReadWindowAggregateSpec, a execute.Administration) execute.Source {
+	src := new(readWindowAggregateSource)
+
+	src.id = id
+	src.alloc = a.Allocator()
+
+	src.reader = r
+	src.readSpec = readSpec
+
+	src.m = GetStorageDependencies(a.Context()).FromDeps.Metrics
+	src.orgID = readSpec.OrganizationID
+	src.op = "readWindowAggregate"
+
+	src.runner = src
+	return src
+}
+
+func (s *readWindowAggregateSource) run(ctx context.Context) error {
+	stop := s.readSpec.Bounds.Stop
+	tables, err := s.reader.ReadWindowAggregate(
+		ctx,
+		s.readSpec,
+		s.alloc,
+	)
+	if err != nil {
+		return err
+	}
+	return s.processTables(ctx, tables, stop)
+}
+
+func createReadWindowAggregateSource(s plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	span, ctx := tracing.StartSpanFromContext(a.Context())
+	defer span.Finish()
+
+	spec := s.(*ReadWindowAggregatePhysSpec)
+
+	bounds := a.StreamContext().Bounds()
+	if bounds == nil {
+		return nil, errors.New("nil bounds passed to from")
+	}
+
+	deps := GetStorageDependencies(a.Context()).FromDeps
+
+	req := query.RequestFromContext(a.Context())
+	if req == nil {
+		return nil, errors.New("missing request on context")
+	}
+
+	orgID := req.OrganizationID
+	bucketID, err := spec.LookupBucketID(ctx, orgID, deps.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+	if !spec.SkipDownsampleTiers {
+		bucketID = lookupReadBucketID(ctx, deps.BucketLookup, orgID, bucketID, *bounds)
+	}
+
+	var filter *semantic.FunctionExpression
+	if spec.FilterSet {
+		filter = spec.Filter
+	}
+	return ReadWindowAggregateSource(
+		id,
+		deps.Reader,
+		ReadWindowAggregateSpec{
+			ReadFilterSpec: ReadFilterSpec{
+				OrganizationID: orgID,
+				BucketID:       bucketID,
+				Bounds:         *bounds,
+				Predicate:      filter,
+			},
+			WindowEvery: spec.WindowEvery,
+			Aggregate:   spec.Aggregate,
+			CreateEmpty: spec.CreateEmpty,
+			TimeColumn:  spec.TimeColumn,
+		},
+		a,
+	), nil
+}
+
 func createReadTagKeysSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	span, ctx := tracing.StartSpanFromContext(a.Context())
 	defer span.Finish()
@@ -3,10 +3,12 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/semantic"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/prom"
@@ -25,6 +27,12 @@ type BucketLookup interface {
 	LookupName(ctx context.Context, orgID platform.ID, id platform.ID) string
 }
 
+// ReadBucketLookup is implemented by bucket lookups that can direct reads of the
+// data of a bucket starting at start to one of its downsampling tiers.
+type ReadBucketLookup interface {
+	LookupReadBucket(ctx context.Context, orgID platform.ID, id platform.ID, start time.Time) platform.ID
+}
+
 type OrganizationLookup interface {
 	Lookup(ctx context.Context, name string) (platform.ID, bool)
 	LookupName(ctx context.Context, id platform.ID) string
@@ -119,6 +127,21 @@ type ReadGroupSpec struct {
 	AggregateMethod string
 }
 
+type ReadWindowAggregateSpec struct {
+	ReadFilterSpec
+
+	// WindowEvery is the duration of each window in nanoseconds.
+	WindowEvery int64
+	// Aggregate is the kind of the aggregate computed for each window.
+	Aggregate plan.ProcedureKind
+	// CreateEmpty produces results for the windows without data.
+	CreateEmpty bool
+	// TimeColumn is the window bound, _start or _stop, used as the _time
+	// of each aggregate when the windows of a series are merged into a
+	// single table. When it is empty, a table is produced for each window.
+	TimeColumn string
+}
+
 type ReadTagKeysSpec struct {
 	ReadFilterSpec
 }
@@ -131,6 +154,7 @@ type ReadTagValuesSpec struct {
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadWindowAggregate(ctx context.Context, spec ReadWindowAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
@@ -0,0 +1,53 @@
+// Package types is the Flux package testing the types of values.
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// PackagePath is the import path of the package in Flux.
+const PackagePath = "influxdata/influxdb/types"
+
+const source = `package types
+
+// isNumeric returns true if v is an integer, an unsigned integer or a float.
+builtin isNumeric
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Package = "types"
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+	flux.RegisterPackageValue(PackagePath, "isNumeric", isNumericFunc)
+}
+
+var isNumericFunc = values.NewFunction(
+	"isNumeric",
+	semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Parameters: map[string]semantic.PolyType{
+			"v": semantic.Tvar(1),
+		},
+		Required: semantic.LabelSet{"v"},
+		Return:   semantic.Bool,
+	}),
+	func(ctx context.Context, args values.Object) (values.Value, error) {
+		v, ok := args.Get("v")
+		if !ok {
+			return nil, fmt.Errorf("missing argument %q", "v")
+		}
+		switch v.Type().Nature() {
+		case semantic.Int, semantic.UInt, semantic.Float:
+			return values.NewBool(true), nil
+		default:
+			return values.NewBool(false), nil
+		}
+	},
+	false,
+)
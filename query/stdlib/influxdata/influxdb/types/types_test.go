@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func TestIsNumeric(t *testing.T) {
+	script := `
+import "influxdata/influxdb/types"
+
+int = types.isNumeric(v: 1)
+uint = types.isNumeric(v: uint(v: 1))
+float = types.isNumeric(v: 1.5)
+string = types.isNumeric(v: "1")
+bool = types.isNumeric(v: true)
+`
+	_, scope, err := flux.Eval(context.Background(), script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{
+		"int":    true,
+		"uint":   true,
+		"float":  true,
+		"string": false,
+		"bool":   false,
+	} {
+		v, ok := scope.Lookup(name)
+		if !ok {
+			t.Fatalf("%s is not defined", name)
+		}
+		if got := v.Bool(); got != want {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
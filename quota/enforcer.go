@@ -0,0 +1,279 @@
+// Package quota enforces the per-organization limits stored by an
+// influxdb.OrgLimitsService.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// DefaultRefreshInterval is how long the limits and series cardinality of an
+// organization are cached before they are read again.
+const DefaultRefreshInterval = 10 * time.Second
+
+// SeriesCardinalityFunc returns the number of series stored by each organization.
+type SeriesCardinalityFunc func() (map[influxdb.ID]int64, error)
+
+var (
+	_ influxdb.OrgLimitEnforcer = (*Enforcer)(nil)
+	_ influxdb.OrgLimitsService = (*Enforcer)(nil)
+	_ influxdb.UsageService     = (*Enforcer)(nil)
+)
+
+// Enforcer enforces the limits of organizations. It wraps the service the
+// limits are stored in, so that limits updated through it apply immediately;
+// limits updated elsewhere apply once the cached limits are refreshed.
+type Enforcer struct {
+	OrgLimitsService influxdb.OrgLimitsService
+
+	// SeriesCardinality is used to enforce the series cardinality limit.
+	// If it is nil, the series cardinality is not limited.
+	SeriesCardinality SeriesCardinalityFunc
+
+	// RefreshInterval is how long limits and series cardinality are cached.
+	RefreshInterval time.Duration
+
+	mu   sync.Mutex
+	orgs map[influxdb.ID]*orgState
+
+	cardinalityMu   sync.Mutex
+	cardinality     map[influxdb.ID]int64
+	cardinalityTime time.Time
+
+	now func() time.Time
+}
+
+// NewEnforcer returns an Enforcer for the limits stored in s.
+func NewEnforcer(s influxdb.OrgLimitsService, cardinality SeriesCardinalityFunc) *Enforcer {
+	return &Enforcer{
+		OrgLimitsService:  s,
+		SeriesCardinality: cardinality,
+		RefreshInterval:   DefaultRefreshInterval,
+		orgs:              make(map[influxdb.ID]*orgState),
+		now:               time.Now,
+	}
+}
+
+type orgState struct {
+	limits influxdb.OrgLimits
+	loaded time.Time
+
+	requests tokenBucket
+	bytes    tokenBucket
+	queries  int
+}
+
+func (s *orgState) setLimits(l influxdb.OrgLimits, now time.Time) {
+	s.limits = l
+	s.loaded = now
+	s.requests.setRate(l.WriteRequestsPerSecond, now)
+	s.bytes.setRate(l.WriteBytesPerSecond, now)
+}
+
+// state returns the state of the organization orgID, reading its limits if
+// they have not been read within the refresh interval. e.mu must be held.
+func (e *Enforcer) state(ctx context.Context, orgID influxdb.ID) (*orgState, error) {
+	now := e.now()
+	s, ok := e.orgs[orgID]
+	if ok && now.Sub(s.loaded) < e.RefreshInterval {
+		return s, nil
+	}
+
+	l, err := e.OrgLimitsService.FindOrgLimits(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s = &orgState{}
+		e.orgs[orgID] = s
+	}
+	s.setLimits(*l, now)
+	return s, nil
+}
+
+// FindOrgLimits returns the limits of the organization orgID.
+func (e *Enforcer) FindOrgLimits(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+	return e.OrgLimitsService.FindOrgLimits(ctx, orgID)
+}
+
+// UpdateOrgLimits replaces the limits of the organization orgID and starts
+// enforcing them.
+func (e *Enforcer) UpdateOrgLimits(ctx context.Context, orgID influxdb.ID, l influxdb.OrgLimits) (*influxdb.OrgLimits, error) {
+	updated, err := e.OrgLimitsService.UpdateOrgLimits(ctx, orgID, l)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if s, ok := e.orgs[orgID]; ok {
+		s.setLimits(*updated, e.now())
+	}
+	return updated, nil
+}
+
+// AllowWriteRequest returns an error if the organization has exceeded its write
+// request rate or has reached its series cardinality limit.
+func (e *Enforcer) AllowWriteRequest(ctx context.Context, orgID influxdb.ID) error {
+	const op = "quota/AllowWriteRequest"
+
+	e.mu.Lock()
+	s, err := e.state(ctx, orgID)
+	if err != nil {
+		e.mu.Unlock()
+		return err
+	}
+	limits := s.limits
+	if limits.WriteRequestsPerSecond > 0 && !s.requests.take(1, e.now()) {
+		e.mu.Unlock()
+		return &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Op:   op,
+			Msg:  fmt.Sprintf("organization has exceeded its limit of %d write requests per second", limits.WriteRequestsPerSecond),
+		}
+	}
+	e.mu.Unlock()
+
+	if limits.MaxSeriesCardinality > 0 && e.SeriesCardinality != nil {
+		n, err := e.seriesCardinality(orgID)
+		if err != nil {
+			return err
+		}
+		if n >= limits.MaxSeriesCardinality {
+			return &influxdb.Error{
+				Code: influxdb.ETooManyRequests,
+				Op:   op,
+				Msg:  fmt.Sprintf("organization has reached its limit of %d series", limits.MaxSeriesCardinality),
+			}
+		}
+	}
+	return nil
+}
+
+// AllowWriteBytes returns an error if writing n bytes would exceed the write
+// byte rate of the organization.
+func (e *Enforcer) AllowWriteBytes(ctx context.Context, orgID influxdb.ID, n int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, err := e.state(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if s.limits.WriteBytesPerSecond > 0 && !s.bytes.take(n, e.now()) {
+		return &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Op:   "quota/AllowWriteBytes",
+			Msg:  fmt.Sprintf("organization has exceeded its limit of %d write bytes per second", s.limits.WriteBytesPerSecond),
+		}
+	}
+	return nil
+}
+
+// AcquireQuery reserves one of the concurrent queries of the organization.
+func (e *Enforcer) AcquireQuery(ctx context.Context, orgID influxdb.ID) (func(), error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, err := e.state(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if s.limits.ConcurrentQueries > 0 && s.queries >= s.limits.ConcurrentQueries {
+		return nil, &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Op:   "quota/AcquireQuery",
+			Msg:  fmt.Sprintf("organization has reached its limit of %d concurrent queries", s.limits.ConcurrentQueries),
+		}
+	}
+	s.queries++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Lock()
+			s.queries--
+			e.mu.Unlock()
+		})
+	}, nil
+}
+
+// seriesCardinality returns the series cardinality of the organization orgID,
+// reading the cardinality of all organizations if it has not been read within
+// the refresh interval.
+func (e *Enforcer) seriesCardinality(orgID influxdb.ID) (int64, error) {
+	e.cardinalityMu.Lock()
+	defer e.cardinalityMu.Unlock()
+
+	if now := e.now(); e.cardinality == nil || now.Sub(e.cardinalityTime) >= e.RefreshInterval {
+		card, err := e.SeriesCardinality()
+		if err != nil {
+			return 0, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "quota/seriesCardinality",
+				Msg:  "unable to determine series cardinality",
+				Err:  err,
+			}
+		}
+		e.cardinality = card
+		e.cardinalityTime = now
+	}
+	return e.cardinality[orgID], nil
+}
+
+// GetUsage returns the remaining budget of each limit set for the organization
+// of filter. The bucket and range of filter are ignored.
+func (e *Enforcer) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.OrgID == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "quota/GetUsage",
+			Msg:  "organization id is required",
+		}
+	}
+	orgID := *filter.OrgID
+
+	usage := make(map[influxdb.UsageMetric]*influxdb.Usage)
+	add := func(m influxdb.UsageMetric, v float64) {
+		if v < 0 {
+			v = 0
+		}
+		usage[m] = &influxdb.Usage{
+			OrganizationID: &orgID,
+			Type:           m,
+			Value:          v,
+		}
+	}
+
+	e.mu.Lock()
+	s, err := e.state(ctx, orgID)
+	if err != nil {
+		e.mu.Unlock()
+		return nil, err
+	}
+	now := e.now()
+	limits := s.limits
+	if limits.WriteRequestsPerSecond > 0 {
+		add(influxdb.UsageWriteRequestsRemaining, s.requests.remaining(now))
+	}
+	if limits.WriteBytesPerSecond > 0 {
+		add(influxdb.UsageWriteBytesRemaining, s.bytes.remaining(now))
+	}
+	if limits.ConcurrentQueries > 0 {
+		add(influxdb.UsageConcurrentQueriesRemaining, float64(limits.ConcurrentQueries-s.queries))
+	}
+	e.mu.Unlock()
+
+	if limits.MaxSeriesCardinality > 0 && e.SeriesCardinality != nil {
+		n, err := e.seriesCardinality(orgID)
+		if err != nil {
+			return nil, err
+		}
+		add(influxdb.UsageSeriesRemaining, float64(limits.MaxSeriesCardinality-n))
+	}
+	return usage, nil
+}
@@ -0,0 +1,168 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func newTestEnforcer(limits influxdb.OrgLimits, card map[influxdb.ID]int64) (*Enforcer, *time.Time) {
+	svc := mock.NewOrgLimitsService()
+	svc.FindOrgLimitsFn = func(ctx context.Context, orgID influxdb.ID) (*influxdb.OrgLimits, error) {
+		l := limits
+		l.OrgID = orgID
+		return &l, nil
+	}
+
+	e := NewEnforcer(svc, func() (map[influxdb.ID]int64, error) {
+		return card, nil
+	})
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+	return e, &now
+}
+
+func TestEnforcer_AllowWriteRequest(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEnforcer(influxdb.OrgLimits{WriteRequestsPerSecond: 2}, nil)
+
+	for i := 0; i < 2; i++ {
+		if err := e.AllowWriteRequest(ctx, 1); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+	if err := e.AllowWriteRequest(ctx, 1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+	// Other organizations have their own budget.
+	if err := e.AllowWriteRequest(ctx, 2); err != nil {
+		t.Fatalf("unexpected error for other organization: %v", err)
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if err := e.AllowWriteRequest(ctx, 1); err != nil {
+		t.Fatalf("unexpected error after refill: %v", err)
+	}
+}
+
+func TestEnforcer_AllowWriteBytes(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEnforcer(influxdb.OrgLimits{WriteBytesPerSecond: 100}, nil)
+
+	// A request larger than the limit is allowed while the budget is full,
+	// but must be paid back before the next request.
+	if err := e.AllowWriteBytes(ctx, 1, 150); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := e.AllowWriteBytes(ctx, 1, 1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+
+	*now = now.Add(time.Second)
+	if err := e.AllowWriteBytes(ctx, 1, 40); err != nil {
+		t.Fatalf("unexpected error after refill: %v", err)
+	}
+	if err := e.AllowWriteBytes(ctx, 1, 20); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+}
+
+func TestEnforcer_SeriesCardinality(t *testing.T) {
+	ctx := context.Background()
+	card := map[influxdb.ID]int64{1: 10, 2: 9}
+	e, _ := newTestEnforcer(influxdb.OrgLimits{MaxSeriesCardinality: 10}, card)
+
+	if err := e.AllowWriteRequest(ctx, 1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+	if err := e.AllowWriteRequest(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEnforcer_AcquireQuery(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newTestEnforcer(influxdb.OrgLimits{ConcurrentQueries: 1}, nil)
+
+	release, err := e.AcquireQuery(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AcquireQuery(ctx, 1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+
+	// Releasing more than once must not free additional queries.
+	release()
+	release()
+	if _, err := e.AcquireQuery(ctx, 1); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+	if _, err := e.AcquireQuery(ctx, 1); influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+		t.Fatalf("expected too many requests, got %v", err)
+	}
+}
+
+func TestEnforcer_UpdateOrgLimits(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newTestEnforcer(influxdb.OrgLimits{ConcurrentQueries: 1}, nil)
+
+	if _, err := e.AcquireQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.UpdateOrgLimits(ctx, 1, influxdb.OrgLimits{ConcurrentQueries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AcquireQuery(ctx, 1); err != nil {
+		t.Fatalf("updated limits were not applied: %v", err)
+	}
+}
+
+func TestEnforcer_GetUsage(t *testing.T) {
+	ctx := context.Background()
+	limits := influxdb.OrgLimits{
+		WriteRequestsPerSecond: 5,
+		ConcurrentQueries:      3,
+		MaxSeriesCardinality:   100,
+	}
+	e, _ := newTestEnforcer(limits, map[influxdb.ID]int64{1: 40})
+
+	if err := e.AllowWriteRequest(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AcquireQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID := influxdb.ID(1)
+	usage, err := e.GetUsage(ctx, influxdb.UsageFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[influxdb.UsageMetric]float64{
+		influxdb.UsageWriteRequestsRemaining:     4,
+		influxdb.UsageConcurrentQueriesRemaining: 2,
+		influxdb.UsageSeriesRemaining:            60,
+	}
+	if len(usage) != len(exp) {
+		t.Fatalf("got %d usage metrics, expected %d", len(usage), len(exp))
+	}
+	for m, v := range exp {
+		u, ok := usage[m]
+		if !ok {
+			t.Errorf("missing usage metric %s", m)
+			continue
+		}
+		if u.Value != v {
+			t.Errorf("got %s %v, expected %v", m, u.Value, v)
+		}
+	}
+
+	if _, err := e.GetUsage(ctx, influxdb.UsageFilter{}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid error without organization, got %v", err)
+	}
+}
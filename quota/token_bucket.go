@@ -0,0 +1,55 @@
+package quota
+
+import (
+	"math"
+	"time"
+)
+
+// tokenBucket refills at rate tokens per second up to a capacity of rate
+// tokens. A request larger than the capacity is allowed once the bucket is
+// full and leaves the bucket in debt, so that following requests have to wait
+// for it to refill.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// setRate changes the rate of the bucket. A bucket that has not been used yet
+// starts out full.
+func (b *tokenBucket) setRate(rate int, now time.Time) {
+	b.refill(now)
+	b.rate = float64(rate)
+	if b.last.IsZero() || b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.last.IsZero() {
+		return
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.rate, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// take removes n tokens from the bucket, returning false if there are not
+// enough tokens left.
+func (b *tokenBucket) take(n int, now time.Time) bool {
+	b.refill(now)
+	need := math.Min(float64(n), b.rate)
+	if b.tokens < need {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// remaining returns the number of whole tokens left in the bucket.
+func (b *tokenBucket) remaining(now time.Time) float64 {
+	b.refill(now)
+	return math.Max(0, math.Floor(b.tokens))
+}
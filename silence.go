@@ -0,0 +1,140 @@
+package influxdb
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// ErrSilenceNotFound is the error msg for a missing silence.
+const ErrSilenceNotFound = "silence not found"
+
+// ops for silence error.
+const (
+	OpFindSilenceByID = "FindSilenceByID"
+	OpFindSilences    = "FindSilences"
+	OpCreateSilence   = "CreateSilence"
+	OpUpdateSilence   = "UpdateSilence"
+	OpDeleteSilence   = "DeleteSilence"
+)
+
+// Silence suppresses the notifications of the notification rules matching
+// its tags between StartTime and EndTime. A rule matches a silence the same
+// way it matches a tag filter, see NotificationRule.MatchesTags; a silence
+// without tags matches every rule of the organization. Only the statuses
+// having all of the tags of the silence are suppressed.
+type Silence struct {
+	ID          ID        `json:"id,omitempty"`
+	OrgID       ID        `json:"orgID"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Tags        []Tag     `json:"tags,omitempty"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	CRUDLog
+}
+
+// Valid returns an error if the silence is not valid.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence orgID is invalid",
+		}
+	}
+	if s.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence name is empty",
+		}
+	}
+	if !s.EndTime.After(s.StartTime) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence end time must be after its start time",
+		}
+	}
+	for _, t := range s.Tags {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the silence ended before now.
+func (s *Silence) Expired(now time.Time) bool {
+	return !s.EndTime.After(now)
+}
+
+// Silences returns true if the silence applies to the notification rule r.
+func (s *Silence) Silences(r NotificationRule) bool {
+	return s.OrgID == r.GetOrgID() && r.MatchesTags(s.Tags)
+}
+
+// SilenceFilter represents a set of filter that restrict the returned results.
+type SilenceFilter struct {
+	ID    *ID
+	OrgID *ID
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts SilenceFilter fields to url query params.
+func (f SilenceFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.ID != nil {
+		qp.Add("id", f.ID.String())
+	}
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+	return qp
+}
+
+// SilenceUpdate is the set of updates to apply to a silence.
+type SilenceUpdate struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Tags        *[]Tag     `json:"tags,omitempty"`
+	StartTime   *time.Time `json:"startTime,omitempty"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+}
+
+// Apply applies the update to the silence.
+func (u *SilenceUpdate) Apply(s *Silence) {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Description != nil {
+		s.Description = *u.Description
+	}
+	if u.Tags != nil {
+		s.Tags = *u.Tags
+	}
+	if u.StartTime != nil {
+		s.StartTime = *u.StartTime
+	}
+	if u.EndTime != nil {
+		s.EndTime = *u.EndTime
+	}
+}
+
+// SilenceService manages the silences of notification rules.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns a list of silences that match the filter and the
+	// total count of matching silences.
+	FindSilences(ctx context.Context, filter SilenceFilter, opt ...FindOptions) ([]*Silence, int, error)
+
+	// CreateSilence creates a new silence and sets s.ID with the new identifier.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a single silence with the changeset.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence by ID.
+	DeleteSilence(ctx context.Context, id ID) error
+}
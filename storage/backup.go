@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+var _ influxdb.BackupService = (*Engine)(nil)
+
+const (
+	// backupSnapshotRetryInterval is how often a backup retries snapshotting the
+	// cache while another snapshot is in progress.
+	backupSnapshotRetryInterval = 100 * time.Millisecond
+
+	// backupSnapshotTimeout is how long a backup waits for another snapshot to
+	// complete before giving up.
+	backupSnapshotTimeout = 30 * time.Second
+)
+
+// CreateBackup creates a consistent copy of the TSM data, series file and index
+// in a new directory within the engine's backup directory.
+//
+// The cache is first snapshotted so the backup includes all data written before
+// the call, waiting for any snapshot already in progress to complete. Index and
+// series file compactions are paused so their files are not rewritten. Then, under the engine lock, hard links are created to all TSM
+// and tombstone files and the series file and index are copied. Writes are only
+// blocked while the series file and index are copied.
+func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	closed := e.closing == nil
+	e.mu.RUnlock()
+	if closed {
+		return 0, nil, ErrEngineClosed
+	}
+
+	if err := e.writeBackupSnapshot(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	e.index.DisableCompactions()
+	defer e.index.EnableCompactions()
+	e.index.Wait()
+
+	e.sfile.DisableCompactions()
+	defer e.sfile.EnableCompactions()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closing == nil {
+		return 0, nil, ErrEngineClosed
+	}
+
+	id, path, err := e.createBackupDir()
+	if err != nil {
+		return 0, nil, err
+	}
+	span.LogKV("backup_id", id, "path", path)
+
+	files, err := e.createBackupLocked(ctx, path)
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return 0, nil, err
+	}
+
+	return id, files, nil
+}
+
+// writeBackupSnapshot snapshots the cache. If another snapshot is in progress,
+// its data is not yet in a TSM file, so the snapshot is retried until that one
+// completes rather than taking a backup that is missing it.
+func (e *Engine) writeBackupSnapshot(ctx context.Context) error {
+	timeout := time.NewTimer(backupSnapshotTimeout)
+	defer timeout.Stop()
+
+	for {
+		err := e.engine.WriteSnapshot(ctx, tsm1.CacheStatusBackup)
+		if err != tsm1.ErrSnapshotInProgress {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return &influxdb.Error{
+				Code: influxdb.EUnavailable,
+				Msg:  "cache snapshot still in progress; try the backup again later",
+				Err:  err,
+			}
+		case <-time.After(backupSnapshotRetryInterval):
+		}
+	}
+}
+
+func (e *Engine) createBackupLocked(ctx context.Context, path string) ([]string, error) {
+	snapshotPath, err := e.engine.FileStore.CreateSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(snapshotPath, filepath.Join(path, DefaultEngineDirectoryName)); err != nil {
+		_ = os.RemoveAll(snapshotPath)
+		return nil, err
+	}
+
+	// Partially written compaction output is replaced by a rename once complete, so
+	// it never needs to be part of the backup.
+	skip := func(path string, fi os.FileInfo) bool {
+		return strings.HasSuffix(fi.Name(), ".compacting")
+	}
+
+	if err := fs.CopyDir(e.config.GetSeriesFilePath(e.path), filepath.Join(path, DefaultSeriesFileDirectoryName), skip); err != nil {
+		return nil, err
+	}
+
+	if err := fs.CopyDir(e.config.GetIndexPath(e.path), filepath.Join(path, DefaultIndexDirectoryName), skip); err != nil {
+		return nil, err
+	}
+
+	return backupFiles(path)
+}
+
+// createBackupDir creates the directory for the next backup and returns its id and path.
+// It must be called under the engine lock.
+func (e *Engine) createBackupDir() (int, string, error) {
+	if err := os.MkdirAll(filepath.Join(e.path, DefaultBackupDirectoryName), 0777); err != nil {
+		return 0, "", err
+	}
+
+	for {
+		e.lastBackupID++
+		path := e.InternalBackupPath(e.lastBackupID)
+		if err := os.Mkdir(path, 0777); os.IsExist(err) {
+			// Left over from a previous process; never overwrite it.
+			continue
+		} else if err != nil {
+			return 0, "", err
+		}
+		return e.lastBackupID, path, nil
+	}
+}
+
+// backupFiles returns the paths, relative to root, of all files in the backup at root.
+func backupFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// FetchBackupFile writes a given backup file to the provided writer.
+// After a successful write, the internal copy is left in place until the backup is deleted.
+func (e *Engine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+	span.LogKV("backup_id", backupID, "backup_file", backupFile)
+
+	name := filepath.Clean(filepath.FromSlash(backupFile))
+	if name == "." || filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup file %q", backupFile),
+		}
+	}
+
+	f, err := os.Open(filepath.Join(e.InternalBackupPath(backupID), name))
+	if os.IsNotExist(err) {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "backup file not found",
+		}
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// DeleteBackup removes the internal copy of a backup.
+func (e *Engine) DeleteBackup(ctx context.Context, backupID int) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+	span.LogKV("backup_id", backupID)
+
+	path := e.InternalBackupPath(backupID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "backup not found",
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
+
+// InternalBackupPath provides the internal, full path directory name of the backup.
+// This should not be exposed via API.
+func (e *Engine) InternalBackupPath(backupID int) string {
+	return filepath.Join(e.path, DefaultBackupDirectoryName, strconv.Itoa(backupID))
+}
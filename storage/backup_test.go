@@ -0,0 +1,129 @@
+package storage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+)
+
+func TestEngine_Backup(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	pt := models.MustNewPoint(
+		"cpu",
+		models.Tags{
+			{Key: models.MeasurementTagKeyBytes, Value: []byte("cpu")},
+			{Key: []byte("host"), Value: []byte("server")},
+			{Key: models.FieldKeyTagKeyBytes, Value: []byte("value")},
+		},
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Engine.WritePoints(ctx, []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	id, files, err := engine.CreateBackup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hasTSM, hasSeries, hasIndex bool
+	for _, f := range files {
+		switch {
+		case strings.HasPrefix(f, storage.DefaultEngineDirectoryName+"/") && strings.HasSuffix(f, ".tsm"):
+			hasTSM = true
+		case strings.HasPrefix(f, storage.DefaultSeriesFileDirectoryName+"/"):
+			hasSeries = true
+		case strings.HasPrefix(f, storage.DefaultIndexDirectoryName+"/"):
+			hasIndex = true
+		}
+	}
+	if !hasTSM || !hasSeries || !hasIndex {
+		t.Fatalf("backup is missing files: tsm=%v series=%v index=%v files=%v", hasTSM, hasSeries, hasIndex, files)
+	}
+
+	// Writes continue after the backup and must not show up in it.
+	pt2 := models.MustNewPoint(
+		"cpu",
+		models.Tags{
+			{Key: models.MeasurementTagKeyBytes, Value: []byte("cpu")},
+			{Key: []byte("host"), Value: []byte("server2")},
+			{Key: models.FieldKeyTagKeyBytes, Value: []byte("value")},
+		},
+		map[string]interface{}{"value": 1.0},
+		time.Unix(2, 3),
+	)
+	if err := engine.Engine.WritePoints(ctx, []models.Point{pt2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restore the backup into a fresh engine directory.
+	restored := NewDefaultEngine()
+	defer restored.Close()
+
+	for _, f := range files {
+		target := filepath.Join(restored.path, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			t.Fatal(err)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.FetchBackupFile(ctx, id, f, out); err != nil {
+			t.Fatal(err)
+		}
+		if err := out.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored.MustOpen()
+
+	if got, exp := restored.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %v series, exp %v series in restored index", got, exp)
+	}
+
+	if err := engine.DeleteBackup(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(engine.InternalBackupPath(id)); !os.IsNotExist(err) {
+		t.Fatalf("expected backup to be removed, got %v", err)
+	}
+}
+
+func TestEngine_FetchBackupFile_Invalid(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	id, _, err := engine.CreateBackup(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", "../secret", "/etc/passwd"} {
+		err := engine.FetchBackupFile(ctx, id, name, ioutil.Discard)
+		if got, exp := influxdb.ErrorCode(err), influxdb.EInvalid; got != exp {
+			t.Errorf("fetching %q: got error code %q, expected %q", name, got, exp)
+		}
+	}
+
+	err = engine.FetchBackupFile(ctx, id, "missing", ioutil.Discard)
+	if got, exp := influxdb.ErrorCode(err), influxdb.ENotFound; got != exp {
+		t.Errorf("got error code %q, expected %q", got, exp)
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// BucketSchemaFinder provides the schemas of buckets.
+type BucketSchemaFinder interface {
+	FindBucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error)
+}
+
+// bucketSchemas returns the schemas of the buckets written to by the collection,
+// keyed by the encoded org and bucket name. Buckets without a schema are not
+// included.
+func (e *Engine) bucketSchemas(ctx context.Context, collection *tsdb.SeriesCollection) (map[string]*influxdb.BucketSchema, error) {
+	var schemas map[string]*influxdb.BucketSchema
+	seen := make(map[string]struct{})
+	for iter := collection.Iterator(); iter.Next(); {
+		name := iter.Name()
+		if _, ok := seen[string(name)]; ok {
+			continue
+		}
+		seen[string(name)] = struct{}{}
+
+		// Measurements are named by the encoded org and bucket IDs.
+		if len(name) != 16 {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+
+		s, err := e.bucketSchema(ctx, bucketID)
+		if err != nil {
+			return nil, err
+		} else if s == nil || len(s.Measurements) == 0 {
+			continue
+		}
+
+		if schemas == nil {
+			schemas = make(map[string]*influxdb.BucketSchema)
+		}
+		schemas[string(name)] = s
+	}
+	return schemas, nil
+}
+
+// bucketSchema returns the schema of the bucket, or nil if the bucket does not
+// exist. Schemas are cached until InvalidateBucket is called for the bucket.
+func (e *Engine) bucketSchema(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+	e.schemasMu.RLock()
+	s, ok := e.schemas[bucketID]
+	gen := e.schemasGen
+	e.schemasMu.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	// The lookup is made without the lock so that a slow lookup does not block
+	// writes to other buckets.
+	s, err := e.schemaFinder.FindBucketSchema(ctx, bucketID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A schema found before an invalidation may be stale, so it is only cached
+	// if no bucket was invalidated during the lookup.
+	e.schemasMu.Lock()
+	defer e.schemasMu.Unlock()
+	if e.schemasGen == gen {
+		if e.schemas == nil {
+			e.schemas = make(map[influxdb.ID]*influxdb.BucketSchema)
+		}
+		e.schemas[bucketID] = s
+	}
+	return s, nil
+}
+
+// enforceBucketSchemas drops the points in the collection that are not allowed by
+// the schema of their bucket: points of undeclared measurements or fields, points
+// with tag keys that are not allowed, and points with fields of the wrong type.
+func enforceBucketSchemas(collection *tsdb.SeriesCollection, schemas map[string]*influxdb.BucketSchema) {
+	j := 0
+	for iter := collection.Iterator(); iter.Next(); {
+		s, ok := schemas[string(iter.Name())]
+		if !ok {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		if reason := checkBucketSchema(s, iter.Tags(), iter.Type()); reason != "" {
+			_, bucketID := tsdb.DecodeNameSlice(iter.Name())
+			collection.Drop(iter.Index(), fmt.Sprintf("%s by the schema of bucket %s", reason, bucketID))
+			continue
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+}
+
+// checkBucketSchema returns why a point with the tags and field type is not
+// allowed by the schema s, or the empty string if it is.
+func checkBucketSchema(s *influxdb.BucketSchema, tags models.Tags, typ models.FieldType) string {
+	measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+	m := s.Measurement(measurement)
+	if m == nil {
+		return fmt.Sprintf("measurement %q is not declared", measurement)
+	}
+
+	for _, t := range tags {
+		switch string(t.Key) {
+		case models.MeasurementTagKey, models.FieldKeyTagKey:
+			continue
+		}
+		if !m.HasTag(string(t.Key)) {
+			return fmt.Sprintf("tag key %q is not allowed for measurement %q", t.Key, measurement)
+		}
+	}
+
+	field := string(tags.Get(models.FieldKeyTagKeyBytes))
+	f := m.Field(field)
+	if f == nil {
+		return fmt.Sprintf("field %q is not declared for measurement %q", field, measurement)
+	}
+	if got := schemaFieldType(typ); got != f.Type {
+		return fmt.Sprintf("field %q of measurement %q must be of type %s but got %s", field, measurement, f.Type, got)
+	}
+	return ""
+}
+
+// schemaFieldType returns the bucket schema field type of typ.
+func schemaFieldType(typ models.FieldType) influxdb.SchemaFieldType {
+	switch typ {
+	case models.Float:
+		return influxdb.SchemaFieldTypeFloat
+	case models.Integer:
+		return influxdb.SchemaFieldTypeInteger
+	case models.Unsigned:
+		return influxdb.SchemaFieldTypeUnsigned
+	case models.String:
+		return influxdb.SchemaFieldTypeString
+	case models.Boolean:
+		return influxdb.SchemaFieldTypeBoolean
+	default:
+		return influxdb.SchemaFieldType(typ.String())
+	}
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ platform.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService wraps an existing platform.BucketSchemaService
+// implementation.
+//
+// BucketSchemaService ensures that when the schema of a bucket is updated, the
+// engine no longer enforces the schema it cached for the bucket.
+type BucketSchemaService struct {
+	inner  platform.BucketSchemaService
+	engine BucketInvalidator
+}
+
+// NewBucketSchemaService returns a new BucketSchemaService for the provided
+// BucketInvalidator, which typically will be an Engine.
+func NewBucketSchemaService(s platform.BucketSchemaService, engine BucketInvalidator) *BucketSchemaService {
+	return &BucketSchemaService{
+		inner:  s,
+		engine: engine,
+	}
+}
+
+// FindBucketSchema returns the schema of a bucket.
+func (s *BucketSchemaService) FindBucketSchema(ctx context.Context, bucketID platform.ID) (*platform.BucketSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.inner.FindBucketSchema(ctx, bucketID)
+}
+
+// UpdateBucketSchema replaces the schema of a bucket.
+func (s *BucketSchemaService) UpdateBucketSchema(ctx context.Context, bucketID platform.ID, schema platform.BucketSchema) (*platform.BucketSchema, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	bs, err := s.inner.UpdateBucketSchema(ctx, bucketID, schema)
+	if err != nil {
+		return nil, err
+	}
+	s.engine.InvalidateBucket(bucketID)
+	return bs, nil
+}
@@ -0,0 +1,25 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/storage"
+)
+
+func TestBucketSchemaService_UpdateBucketSchema(t *testing.T) {
+	bucketID := platform.ID(2)
+
+	// Test updating a schema invalidates the engine's cached state.
+	deleter := &MockDeleter{}
+	service := storage.NewBucketSchemaService(mock.NewBucketSchemaService(), deleter)
+
+	if _, err := service.UpdateBucketSchema(context.TODO(), bucketID, platform.BucketSchema{}); err != nil {
+		t.Fatal(err)
+	}
+	if deleter.invalidated != bucketID {
+		t.Errorf("got invalidated bucket ID: %s, expected %s", deleter.invalidated, bucketID)
+	}
+}
@@ -13,11 +13,18 @@ type BucketDeleter interface {
 	DeleteBucket(context.Context, platform.ID, platform.ID) error
 }
 
+// BucketInvalidator defines the behaviour of dropping the state cached for a
+// bucket when it changes.
+type BucketInvalidator interface {
+	InvalidateBucket(platform.ID)
+}
+
 // BucketService wraps an existing platform.BucketService implementation.
 //
 // BucketService ensures that when a bucket is deleted, all stored data
 // associated with the bucket is either removed, or marked to be removed via a
-// future compaction.
+// future compaction. When a bucket is updated, BucketService invalidates the
+// state the engine cached for it, if the engine is a BucketInvalidator.
 type BucketService struct {
 	inner  platform.BucketService
 	engine BucketDeleter
@@ -97,7 +104,15 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd pl
 	if s.inner == nil || s.engine == nil {
 		return nil, errors.New("nil inner BucketService or Engine")
 	}
-	return s.inner.UpdateBucket(ctx, id, upd)
+
+	b, err := s.inner.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := s.engine.(BucketInvalidator); ok {
+		e.InvalidateBucket(id)
+	}
+	return b, nil
 }
 
 // DeleteBucket removes a bucket by ID.
@@ -55,8 +55,35 @@ func TestBucketService(t *testing.T) {
 	}
 }
 
+func TestBucketService_UpdateBucket(t *testing.T) {
+	inmemService := newInMemKVSVC(t)
+
+	org := &platform.Organization{Name: "org1"}
+	if err := inmemService.CreateOrganization(context.TODO(), org); err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := &platform.Bucket{OrgID: org.ID, Name: "bucket1"}
+	if err := inmemService.CreateBucket(context.TODO(), bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test updating a bucket invalidates the engine's cached state.
+	deleter := &MockDeleter{}
+	service := storage.NewBucketService(inmemService, deleter)
+
+	limit := int64(10)
+	if _, err := service.UpdateBucket(context.TODO(), bucket.ID, platform.BucketUpdate{MaxSeriesCardinality: &limit}); err != nil {
+		t.Fatal(err)
+	}
+	if deleter.invalidated != bucket.ID {
+		t.Errorf("got invalidated bucket ID: %s, expected %s", deleter.invalidated, bucket.ID)
+	}
+}
+
 type MockDeleter struct {
 	orgID, bucketID platform.ID
+	invalidated     platform.ID
 }
 
 func (m *MockDeleter) DeleteBucket(_ context.Context, orgID, bucketID platform.ID) error {
@@ -64,6 +91,10 @@ func (m *MockDeleter) DeleteBucket(_ context.Context, orgID, bucketID platform.I
 	return nil
 }
 
+func (m *MockDeleter) InvalidateBucket(bucketID platform.ID) {
+	m.invalidated = bucketID
+}
+
 func newInMemKVSVC(t *testing.T) *kv.Service {
 	t.Helper()
 
@@ -17,6 +17,7 @@ const (
 	DefaultIndexDirectoryName      = "index"
 	DefaultWALDirectoryName        = "wal"
 	DefaultEngineDirectoryName     = "data"
+	DefaultBackupDirectoryName     = "backup"
 )
 
 // Config holds the configuration for an Engine.
@@ -38,6 +39,11 @@ type Config struct {
 	Engine     tsm1.Config `toml:"engine"`
 	EnginePath string      `toml:"engine-path"` // Overrides the default path.
 
+	// ColdEnginePath is the directory cold TSM files are moved to according to
+	// the cold tier policy of the engine. It is typically on a larger, slower
+	// device than the engine path. The cold tier is disabled if it is not set.
+	ColdEnginePath string `toml:"cold-engine-path"`
+
 	// Index config.
 	Index     tsi1.Config `toml:"index"`
 	IndexPath string      `toml:"index-path"` // Overrides the default path.
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/encryption"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// SecretLoader loads secrets from the secret store.
+type SecretLoader interface {
+	LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error)
+}
+
+// loadKeyring returns the keyring configured by c, or nil if c does not configure
+// any keys.
+func (e *Engine) loadKeyring(ctx context.Context, c tsm1.EncryptionConfig) (*encryption.Keyring, error) {
+	switch {
+	case c.KeyFile != "":
+		return encryption.LoadKeyringFile(c.KeyFile)
+
+	case c.KeySecret != "":
+		if e.secrets == nil {
+			return nil, errors.New("encryption keys configured in the secret store, but no secret store is available")
+		}
+
+		orgID, err := influxdb.IDFromString(c.KeySecretOrgID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization of encryption key secret %q: %v", c.KeySecret, err)
+		}
+
+		s, err := e.secrets.LoadSecret(ctx, *orgID, c.KeySecret)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load encryption key secret %q: %v", c.KeySecret, err)
+		}
+
+		k, err := encryption.ParseKeyring(s)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key secret %q: %v", c.KeySecret, err)
+		}
+		return k, nil
+	}
+	return nil, nil
+}
+
+// configureWAL sets the format new WAL segments are written in, and the keys
+// existing segments are decrypted with.
+func (e *Engine) configureWAL(ctx context.Context) error {
+	compression, err := wal.ParseCompression(e.config.WAL.Compression)
+	if err != nil {
+		return err
+	}
+
+	keyring, err := e.loadKeyring(ctx, e.config.WAL.Encryption)
+	if err != nil {
+		return err
+	}
+
+	e.wal.WithCompression(compression)
+	e.wal.WithKeyring(keyring)
+	e.walKeyring = keyring
+	return nil
+}
+
+// configureEncryption sets the keys TSM and TSI index files are encrypted and
+// decrypted with.
+func (e *Engine) configureEncryption(ctx context.Context) error {
+	keyring, err := e.loadKeyring(ctx, e.config.Engine.Encryption)
+	if err != nil {
+		return err
+	}
+
+	e.engine.WithKeyring(keyring)
+	e.index.WithKeyring(keyring)
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
@@ -53,9 +54,33 @@ type Engine struct {
 	engine  *tsm1.Engine
 	wal     *wal.WAL
 
+	// lastBackupID is the id of the most recently created backup.
+	lastBackupID int
+
 	retentionEnforcer        runner
 	retentionEnforcerLimiter runnable
 
+	// bucketFinder provides the series cardinality limits of buckets, which are
+	// cached in seriesLimits until the bucket is invalidated.
+	bucketFinder    BucketFinder
+	seriesLimitsMu  sync.RWMutex
+	seriesLimits    map[platform.ID]int64
+	seriesLimitsGen uint64     // Incremented by every invalidation.
+	seriesLimitMu   sync.Mutex // Serializes writes to buckets with series limits.
+
+	// schemaFinder provides the schemas of buckets, which are cached in schemas
+	// until the bucket is invalidated.
+	schemaFinder BucketSchemaFinder
+	schemasMu    sync.RWMutex
+	schemas      map[platform.ID]*platform.BucketSchema
+	schemasGen   uint64 // Incremented by every invalidation.
+
+	// secrets provides the encryption keys stored in the secret store.
+	secrets SecretLoader
+
+	// walKeyring holds the keys WAL segments are encrypted with, if any.
+	walKeyring *encryption.Keyring
+
 	defaultMetricLabels prometheus.Labels
 
 	// Tracks all goroutines started by the Engine.
@@ -109,6 +134,22 @@ func WithRetentionEnforcer(finder BucketFinder) Option {
 	}
 }
 
+// WithBucketSeriesLimits makes the engine enforce the MaxSeriesCardinality of
+// the buckets provided by finder when writing points.
+func WithBucketSeriesLimits(finder BucketFinder) Option {
+	return func(e *Engine) {
+		e.bucketFinder = finder
+	}
+}
+
+// WithBucketSchemas makes the engine enforce the schemas of buckets provided by
+// finder when writing points.
+func WithBucketSchemas(finder BucketSchemaFinder) Option {
+	return func(e *Engine) {
+		e.schemaFinder = finder
+	}
+}
+
 // WithRetentionEnforcerLimiter sets a limiter used to control when the
 // retention enforcer can proceed. If this option is not used then the default
 // limiter (or the absence of one) is a no-op, and no limitations will be put
@@ -119,6 +160,14 @@ func WithRetentionEnforcerLimiter(f runnable) Option {
 	}
 }
 
+// WithSecretService makes the engine load the encryption keys configured to be
+// stored in the secret store from svc.
+func WithSecretService(svc SecretLoader) Option {
+	return func(e *Engine) {
+		e.secrets = svc
+	}
+}
+
 // WithFileStoreObserver makes the engine have the provided file store observer.
 func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
 	return func(e *Engine) {
@@ -174,7 +223,9 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e.wal.SetEnabled(c.WAL.Enabled)
 
 	// Initialise Engine
-	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine, tsm1.WithSnapshotter(e))
+	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine,
+		tsm1.WithSnapshotter(e),
+		tsm1.WithColdTierPath(c.ColdEnginePath))
 
 	// Apply options.
 	for _, option := range options {
@@ -240,6 +291,14 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	if err := e.configureWAL(ctx); err != nil {
+		return err
+	}
+
+	if err := e.configureEncryption(ctx); err != nil {
+		return err
+	}
+
 	// Open the services in order and clean up if any fail.
 	var oh openHelper
 	oh.Open(ctx, e.sfile)
@@ -289,6 +348,7 @@ func (e *Engine) replayWAL() error {
 	// Execute all the entries in the WAL again
 	reader := wal.NewWALReader(walPaths)
 	reader.WithLogger(e.logger)
+	reader.WithKeyring(e.walKeyring)
 	err = reader.Read(func(entry wal.WALEntry) error {
 		switch en := entry.(type) {
 		case *wal.WriteWALEntry:
@@ -451,28 +511,18 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 
 	collection, j := tsdb.NewSeriesCollection(points), 0
 
-	// dropPoint should be called whenever there is reason to drop a point from
-	// the batch.
-	dropPoint := func(key []byte, reason string) {
-		if collection.Reason == "" {
-			collection.Reason = reason
-		}
-		collection.Dropped++
-		collection.DroppedKeys = append(collection.DroppedKeys, key)
-	}
-
 	for iter := collection.Iterator(); iter.Next(); {
 		tags := iter.Tags()
 
 		// Not enough tags present.
 		if tags.Len() < 2 {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
 			continue
 		}
 
 		// First tag key is not measurement tag.
 		if !bytes.Equal(tags[0].Key, models.MeasurementTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
 			continue
 		}
 
@@ -480,26 +530,26 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 
 		// Last tag key is not field tag.
 		if !bytes.Equal(fkey, models.FieldKeyTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
+			collection.Drop(iter.Index(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
 			continue
 		}
 
 		// The value representing the underlying field key is invalid if it's "time".
 		if bytes.Equal(fval, timeBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
+			collection.Drop(iter.Index(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
 			continue
 		}
 
 		// Filter out any tags with key equal to "time": they are invalid.
 		if tags.Get(timeBytes) != nil {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
+			collection.Drop(iter.Index(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
 			continue
 		}
 
 		// Drop any point with invalid unicode characters in any of the tag keys or values.
 		// This will also cover validating the value used to represent the field key.
 		if !models.ValidTagTokens(tags) {
-			dropPoint(iter.Key(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
+			collection.Drop(iter.Index(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
 			continue
 		}
 
@@ -508,6 +558,25 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	}
 	collection.Truncate(j)
 
+	// Drop any points that are not allowed by the schema of their bucket.
+	if e.schemaFinder != nil {
+		schemas, err := e.bucketSchemas(ctx, collection)
+		if err != nil {
+			return err
+		}
+		if len(schemas) > 0 {
+			enforceBucketSchemas(collection, schemas)
+		}
+	}
+
+	var limits map[string]int64
+	if e.bucketFinder != nil {
+		var err error
+		if limits, err = e.bucketSeriesLimits(ctx, collection); err != nil {
+			return err
+		}
+	}
+
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
@@ -515,6 +584,16 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop any points that would create series beyond the limit of their bucket.
+	if len(limits) > 0 {
+		e.seriesLimitMu.Lock()
+		defer e.seriesLimitMu.Unlock()
+
+		if err := e.limitSeriesCardinality(collection, limits); err != nil {
+			return err
+		}
+	}
+
 	// Convert the collection to values for adding to the WAL/Cache.
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
@@ -595,10 +674,26 @@ func (e *Engine) CommitSegments(ctx context.Context, segs []string, fn func() er
 	return e.wal.Remove(ctx, segs)
 }
 
+// InvalidateBucket drops the series cardinality limit and schema cached for the
+// bucket, so that the next write to it finds them again. It must be called when
+// a bucket or its schema is updated.
+func (e *Engine) InvalidateBucket(bucketID platform.ID) {
+	e.seriesLimitsMu.Lock()
+	delete(e.seriesLimits, bucketID)
+	e.seriesLimitsGen++
+	e.seriesLimitsMu.Unlock()
+
+	e.schemasMu.Lock()
+	delete(e.schemas, bucketID)
+	e.schemasGen++
+	e.schemasMu.Unlock()
+}
+
 // DeleteBucket deletes an entire bucket from the storage engine.
 func (e *Engine) DeleteBucket(ctx context.Context, orgID, bucketID platform.ID) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
+	e.InvalidateBucket(bucketID)
 	return e.DeleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64)
 }
 
@@ -693,6 +788,25 @@ func (e *Engine) MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats
 	return e.index.MeasurementCardinalityStats()
 }
 
+// OrgSeriesCardinality returns the number of series stored by each organization.
+func (e *Engine) OrgSeriesCardinality() (map[influxdb.ID]int64, error) {
+	stats, err := e.MeasurementCardinalityStats()
+	if err != nil {
+		return nil, err
+	}
+
+	card := make(map[influxdb.ID]int64)
+	for name, n := range stats {
+		// Measurements are named by the encoded org and bucket IDs.
+		if len(name) != 16 {
+			continue
+		}
+		org, _ := tsdb.DecodeNameSlice([]byte(name))
+		card[org] += int64(n)
+	}
+	return card, nil
+}
+
 // MeasurementStats returns the current measurement stats for the engine.
 func (e *Engine) MeasurementStats() (tsm1.MeasurementStats, error) {
 	return e.engine.MeasurementStats()
@@ -1,7 +1,9 @@
 package storage_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -12,9 +14,13 @@ import (
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/prom/promtest"
+	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
+	"github.com/influxdata/influxdb/predicate"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
@@ -352,6 +358,60 @@ func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 
 }
 
+func TestEngine_DeleteBucket_RegexOrPredicate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	p := func(m, host string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{
+				models.FieldKeyTagKey:    "value",
+				models.MeasurementTagKey: m,
+				"host":                   host,
+			}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("cpu", "a"),
+		p("cpu", "web-1"),
+		p("cpu", "db-1"),
+		p("mem", "a"),
+		p("mem", "web-1"),
+		p("disk", "web-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(6); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Removes the a and web-* series of cpu and mem.
+	n, err := predicate.Parse(`(host="a" or host=~/^web-\d+$/) and _measurement!~/^disk$/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pred, err := predicate.New(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.DeleteBucketRangePredicate(context.Background(), engine.org, engine.bucket,
+		math.MinInt64, math.MaxInt64, pred); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
@@ -464,6 +524,74 @@ func TestEngine_WALDisabled(t *testing.T) {
 	}
 }
 
+func TestEngine_WALEncryption(t *testing.T) {
+	key := hex.EncodeToString(bytes.Repeat([]byte{1}, encryption.KeySize))
+
+	config := storage.NewConfig()
+	config.WAL.Encryption.KeySecret = "wal-keys"
+	config.WAL.Encryption.KeySecretOrgID = "3131313131313131"
+
+	secrets := mock.NewSecretService()
+	secrets.LoadSecretFn = func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+		if orgID.String() != config.WAL.Encryption.KeySecretOrgID || k != config.WAL.Encryption.KeySecret {
+			return "", fmt.Errorf("secret not found: %s/%s", orgID, k)
+		}
+		return key, nil
+	}
+
+	engine := NewEngine(config, rand.Int(), rand.Int(), storage.WithSecretService(secrets))
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.Tags{
+			{Key: models.MeasurementTagKeyBytes, Value: []byte("cpu")},
+			{Key: []byte("host"), Value: []byte("server")},
+			{Key: models.FieldKeyTagKeyBytes, Value: []byte("value")},
+		},
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+	engine.Engine.Close() // Don't remove the data
+
+	segments, err := wal.SegmentFileNames(config.GetWALPath(engine.path))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(segments) != 1 {
+		t.Fatalf("unexpected segments: %v", segments)
+	}
+
+	b, err := ioutil.ReadFile(segments[0])
+	if err != nil {
+		t.Fatal(err)
+	} else if bytes.Contains(b, []byte("server")) {
+		t.Fatal("unexpected plaintext in wal segment")
+	}
+
+	// The WAL is replayed with the key from the secret store.
+	engine.MustOpen()
+	engine.Engine.Close()
+
+	// The engine fails to open without the key, leaving the WAL intact.
+	reopened := storage.NewEngine(engine.path, storage.NewConfig(),
+		storage.WithEngineID(engine.engineID), storage.WithNodeID(engine.nodeID))
+	if err := reopened.Open(context.Background()); !encryption.IsKeyNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reopened.Close()
+
+	if fi, err := os.Stat(segments[0]); err != nil {
+		t.Fatal(err)
+	} else if got, exp := fi.Size(), int64(len(b)); got != exp {
+		t.Fatalf("wal segment size changed: got %d, exp %d", got, exp)
+	}
+}
+
 func TestEngine_WriteConflictingBatch(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -471,7 +599,7 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 
 	name := tsdb.EncodeNameString(engine.org, engine.bucket)
 
-	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+	points := []models.Point{
 		models.MustNewPoint(
 			name,
 			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
@@ -484,10 +612,263 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 			map[string]interface{}{"value": 2},
 			time.Unix(1, 2),
 		),
+	}
+	err := engine.Engine.WritePoints(context.TODO(), points)
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+
+	// The point conflicting with the first one is reported as dropped.
+	if got, exp := len(pwe.DroppedPoints), 1; got != exp {
+		t.Fatalf("got %d dropped points, exp %d", got, exp)
+	}
+	if pwe.DroppedPoints[0].Point != points[1] {
+		t.Errorf("got dropped point %v, exp %v", pwe.DroppedPoints[0].Point, points[1])
+	}
+	if pwe.DroppedPoints[0].Reason == "" {
+		t.Error("expected a reason for the dropped point")
+	}
+
+	// Points conflicting with the type of an existing series are reported too.
+	err = engine.Engine.WritePoints(context.TODO(), points[1:])
+	if pwe, ok = err.(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+	if got, exp := len(pwe.DroppedPoints), 1; got != exp {
+		t.Fatalf("got %d dropped points, exp %d", got, exp)
+	}
+	if got, exp := pwe.DroppedPoints[0].Reason, "series type mismatch: already Float but got Integer"; got != exp {
+		t.Errorf("got reason %q, exp %q", got, exp)
+	}
+}
+
+func TestEngine_MaxSeriesCardinality(t *testing.T) {
+	path, err := ioutil.TempDir("", "storage_engine_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	org, bucket := influxdb.ID(1), influxdb.ID(2)
+	limit, finds := int64(2), 0
+	var onFind func()
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(ctx context.Context, filter influxdb.BucketFilter, opts ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		if filter.ID == nil || *filter.ID != bucket {
+			return nil, 0, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		finds++
+		if onFind != nil {
+			onFind()
+		}
+		return []*influxdb.Bucket{{ID: bucket, OrgID: org, MaxSeriesCardinality: limit}}, 1, nil
+	}
+
+	engine := storage.NewEngine(path, storage.NewConfig(),
+		storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()), storage.WithBucketSeriesLimits(buckets))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	point := func(bucket influxdb.ID, host string, ts int64) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(org, bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(ts, 0),
+		)
+	}
+
+	// The first two series fit within the limit, the third is dropped.
+	err = engine.WritePoints(context.Background(), []models.Point{
+		point(bucket, "a", 1),
+		point(bucket, "b", 1),
+		point(bucket, "a", 2),
+		point(bucket, "c", 1),
 	})
-	if _, ok := err.(tsdb.PartialWriteError); !ok {
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
 		t.Fatal("expected partial write error. got:", err)
 	}
+	if got, exp := pwe.Dropped, 1; got != exp {
+		t.Errorf("got %d dropped series, exp %d", got, exp)
+	}
+	if got, exp := pwe.Reason, "max series cardinality of 2 exceeded for bucket 0000000000000002: dropped new series cpu,host=c value"; got != exp {
+		t.Errorf("got reason %q, exp %q", got, exp)
+	}
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %v series, exp %v series in index", got, exp)
+	}
+
+	// Existing series still accept writes.
+	if err := engine.WritePoints(context.Background(), []models.Point{point(bucket, "b", 3)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Buckets without a limit are not affected.
+	if err := engine.WritePoints(context.Background(), []models.Point{point(3, "c", 1), point(3, "d", 1)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := engine.SeriesCardinality(), int64(4); got != exp {
+		t.Fatalf("got %v series, exp %v series in index", got, exp)
+	}
+
+	// The limit is cached until the bucket is invalidated.
+	if got, exp := finds, 1; got != exp {
+		t.Fatalf("got %d bucket lookups, exp %d", got, exp)
+	}
+	limit = 3
+	if err := engine.WritePoints(context.Background(), []models.Point{point(bucket, "c", 1)}); err == nil {
+		t.Fatal("expected partial write error")
+	}
+	engine.InvalidateBucket(bucket)
+	if err := engine.WritePoints(context.Background(), []models.Point{point(bucket, "c", 1)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := finds, 2; got != exp {
+		t.Fatalf("got %d bucket lookups, exp %d", got, exp)
+	}
+
+	// A limit found while the bucket is invalidated may be stale, so it is not
+	// cached.
+	onFind = func() { engine.InvalidateBucket(bucket) }
+	engine.InvalidateBucket(bucket)
+	if err := engine.WritePoints(context.Background(), []models.Point{point(bucket, "a", 3)}); err != nil {
+		t.Fatal(err)
+	}
+	onFind = nil
+	for i := 0; i < 2; i++ {
+		if err := engine.WritePoints(context.Background(), []models.Point{point(bucket, "a", 4)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, exp := finds, 4; got != exp {
+		t.Fatalf("got %d bucket lookups, exp %d", got, exp)
+	}
+}
+
+func TestEngine_BucketSchema(t *testing.T) {
+	path, err := ioutil.TempDir("", "storage_engine_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	org, bucket := influxdb.ID(1), influxdb.ID(2)
+	tags, finds := []string{"host"}, 0
+	var onFind func()
+	schemas := mock.NewBucketSchemaService()
+	schemas.FindBucketSchemaFn = func(ctx context.Context, bucketID influxdb.ID) (*influxdb.BucketSchema, error) {
+		if bucketID != bucket {
+			return &influxdb.BucketSchema{BucketID: bucketID}, nil
+		}
+		finds++
+		if onFind != nil {
+			onFind()
+		}
+		return &influxdb.BucketSchema{
+			BucketID: bucket,
+			Measurements: []influxdb.MeasurementSchema{
+				{
+					Name:   "cpu",
+					Tags:   tags,
+					Fields: []influxdb.FieldSchema{{Name: "usage", Type: influxdb.SchemaFieldTypeFloat}},
+				},
+			},
+		}, nil
+	}
+
+	engine := storage.NewEngine(path, storage.NewConfig(),
+		storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()), storage.WithBucketSchemas(schemas))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	point := func(bucket influxdb.ID, measurement string, tags map[string]string, field string, value interface{}) models.Point {
+		tags[models.MeasurementTagKey] = measurement
+		tags[models.FieldKeyTagKey] = field
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(org, bucket),
+			models.NewTags(tags),
+			map[string]interface{}{field: value},
+			time.Unix(1, 0),
+		)
+	}
+
+	err = engine.WritePoints(context.Background(), []models.Point{
+		point(bucket, "cpu", map[string]string{"host": "a"}, "usage", 1.0),
+		point(bucket, "mem", map[string]string{"host": "a"}, "usage", 1.0),
+		point(bucket, "cpu", map[string]string{"region": "west"}, "usage", 1.0),
+		point(bucket, "cpu", map[string]string{"host": "a"}, "idle", 1.0),
+		point(bucket, "cpu", map[string]string{"host": "b"}, "usage", int64(1)),
+	})
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatal("expected partial write error. got:", err)
+	}
+
+	exp := []string{
+		`measurement "mem" is not declared by the schema of bucket 0000000000000002`,
+		`tag key "region" is not allowed for measurement "cpu" by the schema of bucket 0000000000000002`,
+		`field "idle" is not declared for measurement "cpu" by the schema of bucket 0000000000000002`,
+		`field "usage" of measurement "cpu" must be of type float but got integer by the schema of bucket 0000000000000002`,
+	}
+	if got := len(pwe.DroppedPoints); got != len(exp) {
+		t.Fatalf("got %d dropped points, exp %d", got, len(exp))
+	}
+	for i, p := range pwe.DroppedPoints {
+		if p.Reason != exp[i] {
+			t.Errorf("dropped point %d: got reason %q, exp %q", i, p.Reason, exp[i])
+		}
+	}
+	if got, exp := engine.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %v series, exp %v series in index", got, exp)
+	}
+
+	// Buckets without a schema accept any point.
+	if err := engine.WritePoints(context.Background(), []models.Point{
+		point(3, "mem", map[string]string{"region": "west"}, "free", int64(1)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The schema is cached until the bucket is invalidated.
+	tags = []string{"host", "region"}
+	if err := engine.WritePoints(context.Background(), []models.Point{
+		point(bucket, "cpu", map[string]string{"region": "west"}, "usage", 1.0),
+	}); err == nil {
+		t.Fatal("expected partial write error")
+	}
+	engine.InvalidateBucket(bucket)
+	if err := engine.WritePoints(context.Background(), []models.Point{
+		point(bucket, "cpu", map[string]string{"region": "west"}, "usage", 1.0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := finds, 2; got != exp {
+		t.Fatalf("got %d schema lookups, exp %d", got, exp)
+	}
+
+	// A schema found while the bucket is invalidated may be stale, so it is not
+	// cached.
+	onFind = func() { engine.InvalidateBucket(bucket) }
+	engine.InvalidateBucket(bucket)
+	for i := 0; i < 3; i++ {
+		if i == 1 {
+			onFind = nil
+		}
+		if err := engine.WritePoints(context.Background(), []models.Point{
+			point(bucket, "cpu", map[string]string{"host": "a"}, "usage", 1.0),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, exp := finds, 4; got != exp {
+		t.Fatalf("got %d schema lookups, exp %d", got, exp)
+	}
 }
 
 func BenchmarkDeleteBucket(b *testing.B) {
@@ -543,10 +924,11 @@ type Engine struct {
 }
 
 // NewEngine create a new wrapper around a storage engine.
-func NewEngine(c storage.Config, engineID, nodeID int) *Engine {
+func NewEngine(c storage.Config, engineID, nodeID int, options ...storage.Option) *Engine {
 	path, _ := ioutil.TempDir("", "storage_engine_test")
 
-	engine := storage.NewEngine(path, c, storage.WithEngineID(engineID), storage.WithNodeID(nodeID))
+	options = append([]storage.Option{storage.WithEngineID(engineID), storage.WithNodeID(nodeID)}, options...)
+	engine := storage.NewEngine(path, c, options...)
 
 	org, err := influxdb.IDFromString("3131313131313131")
 	if err != nil {
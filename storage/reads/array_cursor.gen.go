@@ -7,8 +7,10 @@
 package reads
 
 import (
+	"context"
 	"errors"
 
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
 
@@ -237,6 +239,406 @@ func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// floatWindowSelectorArrayCursor selects a single point of each window.
+type floatWindowSelectorArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   window
+	typ datatypes.Aggregate_AggregateType
+	a   *cursors.FloatArray
+	i   int
+	n   int
+	ws  int64
+	t   int64
+	v   float64
+	res *cursors.FloatArray
+}
+
+func newFloatWindowSelectorArrayCursor(cur cursors.FloatArrayCursor, w window, typ datatypes.Aggregate_AggregateType) *floatWindowSelectorArrayCursor {
+	return &floatWindowSelectorArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		typ:              typ,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowSelectorArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.FloatArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.t, c.v = ws, t, v
+		} else if c.selects(v) {
+			c.t, c.v = t, v
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *floatWindowSelectorArrayCursor) selects(v float64) bool {
+	switch c.typ {
+	case datatypes.AggregateTypeLast:
+		return true
+
+	case datatypes.AggregateTypeMin:
+		return v < c.v
+	case datatypes.AggregateTypeMax:
+		return v > c.v
+
+	}
+	return false
+}
+
+func (c *floatWindowSelectorArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.t)
+	c.res.Values = append(c.res.Values, c.v)
+	c.n = 0
+}
+
+// floatWindowSumArrayCursor sums the points of each window.
+type floatWindowSumArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   window
+	a   *cursors.FloatArray
+	i   int
+	n   int
+	ws  int64
+	acc float64
+	res *cursors.FloatArray
+}
+
+func newFloatWindowSumArrayCursor(cur cursors.FloatArrayCursor, w window) *floatWindowSumArrayCursor {
+	return &floatWindowSumArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowSumArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowSumArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.FloatArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += v
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *floatWindowSumArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc)
+	c.n = 0
+}
+
+// floatFloatWindowMeanArrayCursor computes the mean of the points of each window.
+type floatFloatWindowMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+	w   window
+	a   *cursors.FloatArray
+	i   int
+	n   int
+	ws  int64
+	acc float64
+	res *cursors.FloatArray
+}
+
+func newFloatFloatWindowMeanArrayCursor(cur cursors.FloatArrayCursor, w window) *floatFloatWindowMeanArrayCursor {
+	return &floatFloatWindowMeanArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		a:                &cursors.FloatArray{},
+		res:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatFloatWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatFloatWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.FloatArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += float64(v)
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *floatFloatWindowMeanArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc/float64(c.n))
+	c.n = 0
+}
+
+// floatWindowStatsArrayCursor produces the sum, minimum or maximum of each
+// window of a series. The windows whose statistics are recorded in the index are
+// answered from the statistics, while the values of all other windows are read.
+type floatWindowStatsArrayCursor struct {
+	ctx     context.Context
+	itr     cursors.CursorIterator
+	req     cursors.CursorRequest
+	w       window
+	typ     datatypes.Aggregate_AggregateType
+	windows []cursors.WindowStats
+	cur     cursors.FloatArrayCursor
+	a       *cursors.FloatArray
+	i       int
+	next    int64
+	err     error
+	res     *cursors.FloatArray
+}
+
+func newFloatWindowStatsArrayCursor(ctx context.Context, itr cursors.CursorIterator, req *cursors.CursorRequest, w window, typ datatypes.Aggregate_AggregateType, windows []cursors.WindowStats) *floatWindowStatsArrayCursor {
+	return &floatWindowStatsArrayCursor{
+		ctx:     ctx,
+		itr:     itr,
+		req:     *req,
+		w:       w,
+		typ:     typ,
+		windows: windows,
+		a:       &cursors.FloatArray{},
+		next:    w.windowStart(w.start),
+		res:     &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowStatsArrayCursor) Err() error { return c.err }
+
+func (c *floatWindowStatsArrayCursor) Close() {
+	if c.cur != nil {
+		c.cur.Close()
+		c.cur = nil
+	}
+}
+
+func (c *floatWindowStatsArrayCursor) Stats() cursors.CursorStats { return c.itr.Stats() }
+
+func (c *floatWindowStatsArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock && c.err == nil {
+		if c.i < len(c.a.Timestamps) {
+			n := len(c.a.Timestamps) - c.i
+			if m := MaxPointsPerBlock - len(c.res.Timestamps); n > m {
+				n = m
+			}
+			c.res.Timestamps = append(c.res.Timestamps, c.a.Timestamps[c.i:c.i+n]...)
+			c.res.Values = append(c.res.Values, c.a.Values[c.i:c.i+n]...)
+			c.i += n
+			continue
+		}
+
+		if c.cur != nil {
+			if c.a, c.i = c.cur.Next(), 0; len(c.a.Timestamps) > 0 {
+				continue
+			}
+			c.err = c.cur.Err()
+			c.Close()
+			continue
+		}
+
+		if len(c.windows) == 0 {
+			break
+		}
+
+		s := &c.windows[0]
+		c.windows = c.windows[1:]
+		if !s.Decode {
+			t, v := c.value(s)
+			c.res.Timestamps = append(c.res.Timestamps, t)
+			c.res.Values = append(c.res.Values, v)
+		} else if c.openCursor(s.Start, s.End); c.cur == nil {
+			// There are no values to read within the windows.
+			continue
+		}
+		c.next = s.End
+	}
+	return c.res
+}
+
+// value returns the timestamp and the aggregate of the window of s.
+func (c *floatWindowStatsArrayCursor) value(s *cursors.WindowStats) (int64, float64) {
+	min, max, sum := s.FloatStats()
+	switch c.typ {
+	case datatypes.AggregateTypeMin:
+		return s.MinTime, min
+	case datatypes.AggregateTypeMax:
+		return s.MaxTime, max
+	default:
+		return c.w.windowTime(s.Start), sum
+	}
+}
+
+// openCursor opens a cursor producing the aggregate of the windows of the
+// time range [start, end) from their values.
+func (c *floatWindowStatsArrayCursor) openCursor(start, end int64) {
+	req := c.req
+	req.StartTime, req.EndTime = start, end
+	cur, err := c.itr.Next(c.ctx, &req)
+	if err != nil || cur == nil {
+		c.err = err
+		return
+	}
+
+	w := window{every: c.w.every, start: start, end: end}
+	switch c.typ {
+	case datatypes.AggregateTypeSum:
+		c.cur, _ = newWindowSumArrayCursor(cur, w).(cursors.FloatArrayCursor)
+	default:
+		c.cur, _ = newWindowSelectorArrayCursor(cur, w, c.typ).(cursors.FloatArrayCursor)
+	}
+	if c.cur == nil {
+		cur.Close()
+	}
+}
+
+// integerFloatWindowCountArrayCursor counts the points of each window. When createEmpty
+// is set, a count of zero is produced for the windows without points.
+type integerFloatWindowCountArrayCursor struct {
+	cursors.FloatArrayCursor
+	w           window
+	createEmpty bool
+	a           *cursors.FloatArray
+	i           int
+	n           int64
+	ws          int64
+	next        int64
+	res         *cursors.IntegerArray
+}
+
+func newIntegerFloatWindowCountArrayCursor(cur cursors.FloatArrayCursor, w window, createEmpty bool) *integerFloatWindowCountArrayCursor {
+	return &integerFloatWindowCountArrayCursor{
+		FloatArrayCursor: cur,
+		w:                w,
+		createEmpty:      createEmpty,
+		a:                &cursors.FloatArray{},
+		next:             w.windowStart(w.start),
+		res:              &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerFloatWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *integerFloatWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.FloatArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				c.emitEmpty(c.w.end)
+				break
+			}
+		}
+
+		if ws := c.w.windowStart(c.a.Timestamps[c.i]); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.emitEmpty(ws)
+			c.ws = ws
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerFloatWindowCountArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.n)
+	c.n = 0
+	c.next = c.ws + c.w.every
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerFloatWindowCountArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
 type floatEmptyArrayCursor struct {
 	res cursors.FloatArray
 }
@@ -466,6 +868,429 @@ func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// integerWindowSelectorArrayCursor selects a single point of each window.
+type integerWindowSelectorArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   window
+	typ datatypes.Aggregate_AggregateType
+	a   *cursors.IntegerArray
+	i   int
+	n   int
+	ws  int64
+	t   int64
+	v   int64
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowSelectorArrayCursor(cur cursors.IntegerArrayCursor, w window, typ datatypes.Aggregate_AggregateType) *integerWindowSelectorArrayCursor {
+	return &integerWindowSelectorArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		typ:                typ,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSelectorArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.IntegerArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.t, c.v = ws, t, v
+		} else if c.selects(v) {
+			c.t, c.v = t, v
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerWindowSelectorArrayCursor) selects(v int64) bool {
+	switch c.typ {
+	case datatypes.AggregateTypeLast:
+		return true
+
+	case datatypes.AggregateTypeMin:
+		return v < c.v
+	case datatypes.AggregateTypeMax:
+		return v > c.v
+
+	}
+	return false
+}
+
+func (c *integerWindowSelectorArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.t)
+	c.res.Values = append(c.res.Values, c.v)
+	c.n = 0
+}
+
+// integerWindowSumArrayCursor sums the points of each window.
+type integerWindowSumArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   window
+	a   *cursors.IntegerArray
+	i   int
+	n   int
+	ws  int64
+	acc int64
+	res *cursors.IntegerArray
+}
+
+func newIntegerWindowSumArrayCursor(cur cursors.IntegerArrayCursor, w window) *integerWindowSumArrayCursor {
+	return &integerWindowSumArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowSumArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.IntegerArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += v
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerWindowSumArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc)
+	c.n = 0
+}
+
+// floatIntegerWindowMeanArrayCursor computes the mean of the points of each window.
+type floatIntegerWindowMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w   window
+	a   *cursors.IntegerArray
+	i   int
+	n   int
+	ws  int64
+	acc float64
+	res *cursors.FloatArray
+}
+
+func newFloatIntegerWindowMeanArrayCursor(cur cursors.IntegerArrayCursor, w window) *floatIntegerWindowMeanArrayCursor {
+	return &floatIntegerWindowMeanArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		a:                  &cursors.IntegerArray{},
+		res:                &cursors.FloatArray{},
+	}
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.IntegerArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += float64(v)
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc/float64(c.n))
+	c.n = 0
+}
+
+// integerWindowStatsArrayCursor produces the count, sum, minimum or maximum of each
+// window of a series. The windows whose statistics are recorded in the index are
+// answered from the statistics, while the values of all other windows are read.
+type integerWindowStatsArrayCursor struct {
+	ctx         context.Context
+	itr         cursors.CursorIterator
+	req         cursors.CursorRequest
+	w           window
+	typ         datatypes.Aggregate_AggregateType
+	createEmpty bool
+	windows     []cursors.WindowStats
+	cur         cursors.IntegerArrayCursor
+	a           *cursors.IntegerArray
+	i           int
+	next        int64
+	err         error
+	res         *cursors.IntegerArray
+}
+
+func newIntegerWindowStatsArrayCursor(ctx context.Context, itr cursors.CursorIterator, req *cursors.CursorRequest, w window, typ datatypes.Aggregate_AggregateType, createEmpty bool, windows []cursors.WindowStats) *integerWindowStatsArrayCursor {
+	return &integerWindowStatsArrayCursor{
+		ctx:         ctx,
+		itr:         itr,
+		req:         *req,
+		w:           w,
+		typ:         typ,
+		createEmpty: createEmpty,
+		windows:     windows,
+		a:           &cursors.IntegerArray{},
+		next:        w.windowStart(w.start),
+		res:         &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowStatsArrayCursor) Err() error { return c.err }
+
+func (c *integerWindowStatsArrayCursor) Close() {
+	if c.cur != nil {
+		c.cur.Close()
+		c.cur = nil
+	}
+}
+
+func (c *integerWindowStatsArrayCursor) Stats() cursors.CursorStats { return c.itr.Stats() }
+
+func (c *integerWindowStatsArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock && c.err == nil {
+		if c.i < len(c.a.Timestamps) {
+			n := len(c.a.Timestamps) - c.i
+			if m := MaxPointsPerBlock - len(c.res.Timestamps); n > m {
+				n = m
+			}
+			c.res.Timestamps = append(c.res.Timestamps, c.a.Timestamps[c.i:c.i+n]...)
+			c.res.Values = append(c.res.Values, c.a.Values[c.i:c.i+n]...)
+			c.i += n
+			continue
+		}
+
+		if c.cur != nil {
+			if c.a, c.i = c.cur.Next(), 0; len(c.a.Timestamps) > 0 {
+				continue
+			}
+			c.err = c.cur.Err()
+			c.Close()
+			continue
+		}
+
+		if len(c.windows) == 0 {
+			c.emitEmpty(c.w.end)
+			break
+		}
+
+		s := &c.windows[0]
+		c.windows = c.windows[1:]
+		c.emitEmpty(c.w.windowStart(s.Start))
+		if !s.Decode {
+			t, v := c.value(s)
+			c.res.Timestamps = append(c.res.Timestamps, t)
+			c.res.Values = append(c.res.Values, v)
+		} else if c.openCursor(s.Start, s.End); c.cur == nil {
+			// There are no values to read within the windows.
+			continue
+		}
+		c.next = s.End
+	}
+	return c.res
+}
+
+// value returns the timestamp and the aggregate of the window of s.
+func (c *integerWindowStatsArrayCursor) value(s *cursors.WindowStats) (int64, int64) {
+	if c.typ == datatypes.AggregateTypeCount {
+		return c.w.windowTime(s.Start), s.Count
+	}
+	min, max, sum := s.IntegerStats()
+	switch c.typ {
+	case datatypes.AggregateTypeMin:
+		return s.MinTime, min
+	case datatypes.AggregateTypeMax:
+		return s.MaxTime, max
+	default:
+		return c.w.windowTime(s.Start), sum
+	}
+}
+
+// openCursor opens a cursor producing the aggregate of the windows of the
+// time range [start, end) from their values.
+func (c *integerWindowStatsArrayCursor) openCursor(start, end int64) {
+	req := c.req
+	req.StartTime, req.EndTime = start, end
+	cur, err := c.itr.Next(c.ctx, &req)
+	if err != nil || cur == nil {
+		c.err = err
+		return
+	}
+
+	w := window{every: c.w.every, start: start, end: end}
+	switch c.typ {
+	case datatypes.AggregateTypeCount:
+		c.cur, _ = newWindowCountArrayCursor(cur, w, c.createEmpty).(cursors.IntegerArrayCursor)
+	case datatypes.AggregateTypeSum:
+		c.cur, _ = newWindowSumArrayCursor(cur, w).(cursors.IntegerArrayCursor)
+	default:
+		c.cur, _ = newWindowSelectorArrayCursor(cur, w, c.typ).(cursors.IntegerArrayCursor)
+	}
+	if c.cur == nil {
+		cur.Close()
+	}
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerWindowStatsArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty || c.typ != datatypes.AggregateTypeCount {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
+// integerIntegerWindowCountArrayCursor counts the points of each window. When createEmpty
+// is set, a count of zero is produced for the windows without points.
+type integerIntegerWindowCountArrayCursor struct {
+	cursors.IntegerArrayCursor
+	w           window
+	createEmpty bool
+	a           *cursors.IntegerArray
+	i           int
+	n           int64
+	ws          int64
+	next        int64
+	res         *cursors.IntegerArray
+}
+
+func newIntegerIntegerWindowCountArrayCursor(cur cursors.IntegerArrayCursor, w window, createEmpty bool) *integerIntegerWindowCountArrayCursor {
+	return &integerIntegerWindowCountArrayCursor{
+		IntegerArrayCursor: cur,
+		w:                  w,
+		createEmpty:        createEmpty,
+		a:                  &cursors.IntegerArray{},
+		next:               w.windowStart(w.start),
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerIntegerWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerIntegerWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.IntegerArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				c.emitEmpty(c.w.end)
+				break
+			}
+		}
+
+		if ws := c.w.windowStart(c.a.Timestamps[c.i]); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.emitEmpty(ws)
+			c.ws = ws
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerIntegerWindowCountArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.n)
+	c.n = 0
+	c.next = c.ws + c.w.every
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerIntegerWindowCountArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
 type integerEmptyArrayCursor struct {
 	res cursors.IntegerArray
 }
@@ -695,6 +1520,408 @@ func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// unsignedWindowSelectorArrayCursor selects a single point of each window.
+type unsignedWindowSelectorArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   window
+	typ datatypes.Aggregate_AggregateType
+	a   *cursors.UnsignedArray
+	i   int
+	n   int
+	ws  int64
+	t   int64
+	v   uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedWindowSelectorArrayCursor(cur cursors.UnsignedArrayCursor, w window, typ datatypes.Aggregate_AggregateType) *unsignedWindowSelectorArrayCursor {
+	return &unsignedWindowSelectorArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		typ:                 typ,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowSelectorArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.UnsignedArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.t, c.v = ws, t, v
+		} else if c.selects(v) {
+			c.t, c.v = t, v
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *unsignedWindowSelectorArrayCursor) selects(v uint64) bool {
+	switch c.typ {
+	case datatypes.AggregateTypeLast:
+		return true
+
+	case datatypes.AggregateTypeMin:
+		return v < c.v
+	case datatypes.AggregateTypeMax:
+		return v > c.v
+
+	}
+	return false
+}
+
+func (c *unsignedWindowSelectorArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.t)
+	c.res.Values = append(c.res.Values, c.v)
+	c.n = 0
+}
+
+// unsignedWindowSumArrayCursor sums the points of each window.
+type unsignedWindowSumArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   window
+	a   *cursors.UnsignedArray
+	i   int
+	n   int
+	ws  int64
+	acc uint64
+	res *cursors.UnsignedArray
+}
+
+func newUnsignedWindowSumArrayCursor(cur cursors.UnsignedArrayCursor, w window) *unsignedWindowSumArrayCursor {
+	return &unsignedWindowSumArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowSumArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowSumArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.UnsignedArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += v
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *unsignedWindowSumArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc)
+	c.n = 0
+}
+
+// floatUnsignedWindowMeanArrayCursor computes the mean of the points of each window.
+type floatUnsignedWindowMeanArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w   window
+	a   *cursors.UnsignedArray
+	i   int
+	n   int
+	ws  int64
+	acc float64
+	res *cursors.FloatArray
+}
+
+func newFloatUnsignedWindowMeanArrayCursor(cur cursors.UnsignedArrayCursor, w window) *floatUnsignedWindowMeanArrayCursor {
+	return &floatUnsignedWindowMeanArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		a:                   &cursors.UnsignedArray{},
+		res:                 &cursors.FloatArray{},
+	}
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.UnsignedArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.acc = ws, 0
+		}
+		c.acc += float64(v)
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.acc/float64(c.n))
+	c.n = 0
+}
+
+// unsignedWindowStatsArrayCursor produces the sum, minimum or maximum of each
+// window of a series. The windows whose statistics are recorded in the index are
+// answered from the statistics, while the values of all other windows are read.
+type unsignedWindowStatsArrayCursor struct {
+	ctx     context.Context
+	itr     cursors.CursorIterator
+	req     cursors.CursorRequest
+	w       window
+	typ     datatypes.Aggregate_AggregateType
+	windows []cursors.WindowStats
+	cur     cursors.UnsignedArrayCursor
+	a       *cursors.UnsignedArray
+	i       int
+	next    int64
+	err     error
+	res     *cursors.UnsignedArray
+}
+
+func newUnsignedWindowStatsArrayCursor(ctx context.Context, itr cursors.CursorIterator, req *cursors.CursorRequest, w window, typ datatypes.Aggregate_AggregateType, windows []cursors.WindowStats) *unsignedWindowStatsArrayCursor {
+	return &unsignedWindowStatsArrayCursor{
+		ctx:     ctx,
+		itr:     itr,
+		req:     *req,
+		w:       w,
+		typ:     typ,
+		windows: windows,
+		a:       &cursors.UnsignedArray{},
+		next:    w.windowStart(w.start),
+		res:     &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowStatsArrayCursor) Err() error { return c.err }
+
+func (c *unsignedWindowStatsArrayCursor) Close() {
+	if c.cur != nil {
+		c.cur.Close()
+		c.cur = nil
+	}
+}
+
+func (c *unsignedWindowStatsArrayCursor) Stats() cursors.CursorStats { return c.itr.Stats() }
+
+func (c *unsignedWindowStatsArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock && c.err == nil {
+		if c.i < len(c.a.Timestamps) {
+			n := len(c.a.Timestamps) - c.i
+			if m := MaxPointsPerBlock - len(c.res.Timestamps); n > m {
+				n = m
+			}
+			c.res.Timestamps = append(c.res.Timestamps, c.a.Timestamps[c.i:c.i+n]...)
+			c.res.Values = append(c.res.Values, c.a.Values[c.i:c.i+n]...)
+			c.i += n
+			continue
+		}
+
+		if c.cur != nil {
+			if c.a, c.i = c.cur.Next(), 0; len(c.a.Timestamps) > 0 {
+				continue
+			}
+			c.err = c.cur.Err()
+			c.Close()
+			continue
+		}
+
+		if len(c.windows) == 0 {
+			break
+		}
+
+		s := &c.windows[0]
+		c.windows = c.windows[1:]
+		if !s.Decode {
+			t, v := c.value(s)
+			c.res.Timestamps = append(c.res.Timestamps, t)
+			c.res.Values = append(c.res.Values, v)
+		} else if c.openCursor(s.Start, s.End); c.cur == nil {
+			// There are no values to read within the windows.
+			continue
+		}
+		c.next = s.End
+	}
+	return c.res
+}
+
+// value returns the timestamp and the aggregate of the window of s.
+func (c *unsignedWindowStatsArrayCursor) value(s *cursors.WindowStats) (int64, uint64) {
+	min, max, sum := s.UnsignedStats()
+	switch c.typ {
+	case datatypes.AggregateTypeMin:
+		return s.MinTime, min
+	case datatypes.AggregateTypeMax:
+		return s.MaxTime, max
+	default:
+		return c.w.windowTime(s.Start), sum
+	}
+}
+
+// openCursor opens a cursor producing the aggregate of the windows of the
+// time range [start, end) from their values.
+func (c *unsignedWindowStatsArrayCursor) openCursor(start, end int64) {
+	req := c.req
+	req.StartTime, req.EndTime = start, end
+	cur, err := c.itr.Next(c.ctx, &req)
+	if err != nil || cur == nil {
+		c.err = err
+		return
+	}
+
+	w := window{every: c.w.every, start: start, end: end}
+	switch c.typ {
+	case datatypes.AggregateTypeSum:
+		c.cur, _ = newWindowSumArrayCursor(cur, w).(cursors.UnsignedArrayCursor)
+	default:
+		c.cur, _ = newWindowSelectorArrayCursor(cur, w, c.typ).(cursors.UnsignedArrayCursor)
+	}
+	if c.cur == nil {
+		cur.Close()
+	}
+}
+
+// integerUnsignedWindowCountArrayCursor counts the points of each window. When createEmpty
+// is set, a count of zero is produced for the windows without points.
+type integerUnsignedWindowCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	w           window
+	createEmpty bool
+	a           *cursors.UnsignedArray
+	i           int
+	n           int64
+	ws          int64
+	next        int64
+	res         *cursors.IntegerArray
+}
+
+func newIntegerUnsignedWindowCountArrayCursor(cur cursors.UnsignedArrayCursor, w window, createEmpty bool) *integerUnsignedWindowCountArrayCursor {
+	return &integerUnsignedWindowCountArrayCursor{
+		UnsignedArrayCursor: cur,
+		w:                   w,
+		createEmpty:         createEmpty,
+		a:                   &cursors.UnsignedArray{},
+		next:                w.windowStart(w.start),
+		res:                 &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.UnsignedArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				c.emitEmpty(c.w.end)
+				break
+			}
+		}
+
+		if ws := c.w.windowStart(c.a.Timestamps[c.i]); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.emitEmpty(ws)
+			c.ws = ws
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.n)
+	c.n = 0
+	c.next = c.ws + c.w.every
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerUnsignedWindowCountArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
 type unsignedEmptyArrayCursor struct {
 	res cursors.UnsignedArray
 }
@@ -884,6 +2111,158 @@ func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// stringWindowSelectorArrayCursor selects a single point of each window.
+type stringWindowSelectorArrayCursor struct {
+	cursors.StringArrayCursor
+	w   window
+	typ datatypes.Aggregate_AggregateType
+	a   *cursors.StringArray
+	i   int
+	n   int
+	ws  int64
+	t   int64
+	v   string
+	res *cursors.StringArray
+}
+
+func newStringWindowSelectorArrayCursor(cur cursors.StringArrayCursor, w window, typ datatypes.Aggregate_AggregateType) *stringWindowSelectorArrayCursor {
+	return &stringWindowSelectorArrayCursor{
+		StringArrayCursor: cur,
+		w:                 w,
+		typ:               typ,
+		a:                 &cursors.StringArray{},
+		res:               &cursors.StringArray{},
+	}
+}
+
+func (c *stringWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowSelectorArrayCursor) Next() *cursors.StringArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.StringArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.t, c.v = ws, t, v
+		} else if c.selects(v) {
+			c.t, c.v = t, v
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *stringWindowSelectorArrayCursor) selects(v string) bool {
+	switch c.typ {
+	case datatypes.AggregateTypeLast:
+		return true
+
+	}
+	return false
+}
+
+func (c *stringWindowSelectorArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.t)
+	c.res.Values = append(c.res.Values, c.v)
+	c.n = 0
+}
+
+// integerStringWindowCountArrayCursor counts the points of each window. When createEmpty
+// is set, a count of zero is produced for the windows without points.
+type integerStringWindowCountArrayCursor struct {
+	cursors.StringArrayCursor
+	w           window
+	createEmpty bool
+	a           *cursors.StringArray
+	i           int
+	n           int64
+	ws          int64
+	next        int64
+	res         *cursors.IntegerArray
+}
+
+func newIntegerStringWindowCountArrayCursor(cur cursors.StringArrayCursor, w window, createEmpty bool) *integerStringWindowCountArrayCursor {
+	return &integerStringWindowCountArrayCursor{
+		StringArrayCursor: cur,
+		w:                 w,
+		createEmpty:       createEmpty,
+		a:                 &cursors.StringArray{},
+		next:              w.windowStart(w.start),
+		res:               &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerStringWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *integerStringWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.StringArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				c.emitEmpty(c.w.end)
+				break
+			}
+		}
+
+		if ws := c.w.windowStart(c.a.Timestamps[c.i]); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.emitEmpty(ws)
+			c.ws = ws
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerStringWindowCountArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.n)
+	c.n = 0
+	c.next = c.ws + c.w.every
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerStringWindowCountArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
 type stringEmptyArrayCursor struct {
 	res cursors.StringArray
 }
@@ -1073,6 +2452,158 @@ func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// booleanWindowSelectorArrayCursor selects a single point of each window.
+type booleanWindowSelectorArrayCursor struct {
+	cursors.BooleanArrayCursor
+	w   window
+	typ datatypes.Aggregate_AggregateType
+	a   *cursors.BooleanArray
+	i   int
+	n   int
+	ws  int64
+	t   int64
+	v   bool
+	res *cursors.BooleanArray
+}
+
+func newBooleanWindowSelectorArrayCursor(cur cursors.BooleanArrayCursor, w window, typ datatypes.Aggregate_AggregateType) *booleanWindowSelectorArrayCursor {
+	return &booleanWindowSelectorArrayCursor{
+		BooleanArrayCursor: cur,
+		w:                  w,
+		typ:                typ,
+		a:                  &cursors.BooleanArray{},
+		res:                &cursors.BooleanArray{},
+	}
+}
+
+func (c *booleanWindowSelectorArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowSelectorArrayCursor) Next() *cursors.BooleanArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.BooleanArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				break
+			}
+		}
+
+		t, v := c.a.Timestamps[c.i], c.a.Values[c.i]
+		if ws := c.w.windowStart(t); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.ws, c.t, c.v = ws, t, v
+		} else if c.selects(v) {
+			c.t, c.v = t, v
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *booleanWindowSelectorArrayCursor) selects(v bool) bool {
+	switch c.typ {
+	case datatypes.AggregateTypeLast:
+		return true
+
+	}
+	return false
+}
+
+func (c *booleanWindowSelectorArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.t)
+	c.res.Values = append(c.res.Values, c.v)
+	c.n = 0
+}
+
+// integerBooleanWindowCountArrayCursor counts the points of each window. When createEmpty
+// is set, a count of zero is produced for the windows without points.
+type integerBooleanWindowCountArrayCursor struct {
+	cursors.BooleanArrayCursor
+	w           window
+	createEmpty bool
+	a           *cursors.BooleanArray
+	i           int
+	n           int64
+	ws          int64
+	next        int64
+	res         *cursors.IntegerArray
+}
+
+func newIntegerBooleanWindowCountArrayCursor(cur cursors.BooleanArrayCursor, w window, createEmpty bool) *integerBooleanWindowCountArrayCursor {
+	return &integerBooleanWindowCountArrayCursor{
+		BooleanArrayCursor: cur,
+		w:                  w,
+		createEmpty:        createEmpty,
+		a:                  &cursors.BooleanArray{},
+		next:               w.windowStart(w.start),
+		res:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerBooleanWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *integerBooleanWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	for len(c.res.Timestamps) < MaxPointsPerBlock {
+		if c.i == len(c.a.Timestamps) {
+			c.a = c.BooleanArrayCursor.Next()
+			c.i = 0
+			if len(c.a.Timestamps) == 0 {
+				if c.n > 0 {
+					c.emit()
+				}
+				c.emitEmpty(c.w.end)
+				break
+			}
+		}
+
+		if ws := c.w.windowStart(c.a.Timestamps[c.i]); c.n == 0 || ws != c.ws {
+			if c.n > 0 {
+				c.emit()
+			}
+			c.emitEmpty(ws)
+			c.ws = ws
+		}
+		c.n++
+		c.i++
+	}
+	return c.res
+}
+
+func (c *integerBooleanWindowCountArrayCursor) emit() {
+	c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.ws))
+	c.res.Values = append(c.res.Values, c.n)
+	c.n = 0
+	c.next = c.ws + c.w.every
+}
+
+// emitEmpty produces a count of zero for the windows without points
+// that start before end.
+func (c *integerBooleanWindowCountArrayCursor) emitEmpty(end int64) {
+	if !c.createEmpty {
+		return
+	}
+	for ; c.next < end; c.next += c.w.every {
+		c.res.Timestamps = append(c.res.Timestamps, c.w.windowTime(c.next))
+		c.res.Values = append(c.res.Values, 0)
+	}
+}
+
 type booleanEmptyArrayCursor struct {
 	res cursors.BooleanArray
 }
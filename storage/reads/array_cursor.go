@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
@@ -63,6 +64,125 @@ func newCountArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	}
 }
 
+// window describes the fixed windows of a window aggregate, which are
+// aligned to the Unix epoch and truncated to the range [start, end).
+type window struct {
+	every int64
+	start int64
+	end   int64
+}
+
+// windowStart returns the start of the window that contains t.
+func (w window) windowStart(t int64) int64 {
+	r := t % w.every
+	if r < 0 {
+		r += w.every
+	}
+	return t - r
+}
+
+// windowTime returns the timestamp of the aggregate for the window
+// starting at ws, which is the start of the window truncated to the range.
+func (w window) windowTime(ws int64) int64 {
+	if ws < w.start {
+		return w.start
+	}
+	return ws
+}
+
+func newWindowAggregateArrayCursor(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, cursor cursors.Cursor) cursors.Cursor {
+	if cursor == nil {
+		return nil
+	}
+
+	w := window{
+		every: req.WindowEvery,
+		start: req.Range.Start,
+		end:   req.Range.End,
+	}
+	switch typ := req.Aggregate.Type; typ {
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		return newWindowSelectorArrayCursor(cursor, w, typ)
+	case datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		switch cursor.(type) {
+		case cursors.StringArrayCursor, cursors.BooleanArrayCursor:
+			// min and max are only defined for numeric values
+			return nil
+		}
+		return newWindowSelectorArrayCursor(cursor, w, typ)
+	case datatypes.AggregateTypeSum:
+		return newWindowSumArrayCursor(cursor, w)
+	case datatypes.AggregateTypeCount:
+		return newWindowCountArrayCursor(cursor, w, req.CreateEmpty)
+	case datatypes.AggregateTypeMean:
+		return newWindowMeanArrayCursor(cursor, w)
+	default:
+		panic(fmt.Sprintf("invalid window aggregate: %s", typ))
+	}
+}
+
+func newWindowSelectorArrayCursor(cur cursors.Cursor, w window, typ datatypes.Aggregate_AggregateType) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowSelectorArrayCursor(cur, w, typ)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowSelectorArrayCursor(cur, w, typ)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowSelectorArrayCursor(cur, w, typ)
+	case cursors.StringArrayCursor:
+		return newStringWindowSelectorArrayCursor(cur, w, typ)
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowSelectorArrayCursor(cur, w, typ)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowSumArrayCursor(cur cursors.Cursor, w window) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowSumArrayCursor(cur, w)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowSumArrayCursor(cur, w)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowSumArrayCursor(cur, w)
+	default:
+		// sum is only defined for numeric values
+		return nil
+	}
+}
+
+func newWindowCountArrayCursor(cur cursors.Cursor, w window, createEmpty bool) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newIntegerFloatWindowCountArrayCursor(cur, w, createEmpty)
+	case cursors.IntegerArrayCursor:
+		return newIntegerIntegerWindowCountArrayCursor(cur, w, createEmpty)
+	case cursors.UnsignedArrayCursor:
+		return newIntegerUnsignedWindowCountArrayCursor(cur, w, createEmpty)
+	case cursors.StringArrayCursor:
+		return newIntegerStringWindowCountArrayCursor(cur, w, createEmpty)
+	case cursors.BooleanArrayCursor:
+		return newIntegerBooleanWindowCountArrayCursor(cur, w, createEmpty)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowMeanArrayCursor(cur cursors.Cursor, w window) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatFloatWindowMeanArrayCursor(cur, w)
+	case cursors.IntegerArrayCursor:
+		return newFloatIntegerWindowMeanArrayCursor(cur, w)
+	case cursors.UnsignedArrayCursor:
+		return newFloatUnsignedWindowMeanArrayCursor(cur, w)
+	default:
+		// mean is only defined for numeric values
+		return nil
+	}
+}
+
 type cursorContext struct {
 	ctx   context.Context
 	req   *cursors.CursorRequest
@@ -161,3 +281,62 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 func (m *multiShardArrayCursors) newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor {
 	return newAggregateArrayCursor(ctx, agg, cursor)
 }
+
+// newWindowAggregateCursor returns a cursor producing the window aggregate of
+// req for the series of row.
+func (m *multiShardArrayCursors) newWindowAggregateCursor(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, row SeriesRow) cursors.Cursor {
+	if cur := m.newWindowStatsCursor(ctx, req, row); cur != nil {
+		return cur
+	}
+	return newWindowAggregateArrayCursor(ctx, req, m.createCursor(row))
+}
+
+// newWindowStatsCursor returns a cursor producing the count, sum, minimum or
+// maximum of each window of the series of row, which answers the windows
+// whose statistics are recorded in the index without reading their values.
+// It returns nil if there are no such windows.
+func (m *multiShardArrayCursors) newWindowStatsCursor(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, row SeriesRow) cursors.Cursor {
+	agg := req.Aggregate.Type
+	switch agg {
+	case datatypes.AggregateTypeCount, datatypes.AggregateTypeSum, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+	default:
+		return nil
+	}
+
+	// The statistics cover all values of a series in a single shard.
+	if row.ValueCond != nil || len(row.Query) != 1 {
+		return nil
+	}
+	itr, ok := row.Query[0].(cursors.WindowStatsCursorIterator)
+	if !ok {
+		return nil
+	}
+
+	m.req.Name = row.Name
+	m.req.Tags = row.SeriesTags
+	m.req.Field = row.Field
+
+	typ, windows, ok := itr.WindowStats(ctx, &m.req, req.WindowEvery)
+	if !ok {
+		return nil
+	}
+
+	w := window{
+		every: req.WindowEvery,
+		start: req.Range.Start,
+		end:   req.Range.End,
+	}
+	if agg == datatypes.AggregateTypeCount {
+		return newIntegerWindowStatsArrayCursor(ctx, itr, &m.req, w, agg, req.CreateEmpty, windows)
+	}
+	switch typ {
+	case models.Float:
+		return newFloatWindowStatsArrayCursor(ctx, itr, &m.req, w, agg, windows)
+	case models.Integer:
+		return newIntegerWindowStatsArrayCursor(ctx, itr, &m.req, w, agg, false, windows)
+	case models.Unsigned:
+		return newUnsignedWindowStatsArrayCursor(ctx, itr, &m.req, w, agg, windows)
+	default:
+		return nil
+	}
+}
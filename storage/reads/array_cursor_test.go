@@ -0,0 +1,155 @@
+package reads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+type floatArrayCursor struct {
+	arrays []*cursors.FloatArray
+}
+
+func (c *floatArrayCursor) Close()                     {}
+func (c *floatArrayCursor) Err() error                 { return nil }
+func (c *floatArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *floatArrayCursor) Next() *cursors.FloatArray {
+	if len(c.arrays) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+func TestNewWindowAggregateArrayCursor(t *testing.T) {
+	// The points span two arrays, such that the window [20, 30)
+	// is split across them, and there are no points in [30, 40).
+	newCursor := func() cursors.Cursor {
+		return &floatArrayCursor{
+			arrays: []*cursors.FloatArray{
+				{
+					Timestamps: []int64{5, 8, 12, 15, 21},
+					Values:     []float64{3, 1, 4, 1, 5},
+				},
+				{
+					Timestamps: []int64{25, 28, 41},
+					Values:     []float64{9, 2, 6},
+				},
+			},
+		}
+	}
+
+	type result struct {
+		Timestamps []int64
+		Floats     []float64
+		Integers   []int64
+	}
+
+	tests := []struct {
+		name        string
+		typ         datatypes.Aggregate_AggregateType
+		createEmpty bool
+		exp         result
+	}{
+		{
+			name: "first",
+			typ:  datatypes.AggregateTypeFirst,
+			exp: result{
+				Timestamps: []int64{5, 12, 21, 41},
+				Floats:     []float64{3, 4, 5, 6},
+			},
+		},
+		{
+			name: "last",
+			typ:  datatypes.AggregateTypeLast,
+			exp: result{
+				Timestamps: []int64{8, 15, 28, 41},
+				Floats:     []float64{1, 1, 2, 6},
+			},
+		},
+		{
+			name: "min",
+			typ:  datatypes.AggregateTypeMin,
+			exp: result{
+				Timestamps: []int64{8, 15, 28, 41},
+				Floats:     []float64{1, 1, 2, 6},
+			},
+		},
+		{
+			name: "max",
+			typ:  datatypes.AggregateTypeMax,
+			exp: result{
+				Timestamps: []int64{5, 12, 25, 41},
+				Floats:     []float64{3, 4, 9, 6},
+			},
+		},
+		{
+			name: "sum",
+			typ:  datatypes.AggregateTypeSum,
+			exp: result{
+				Timestamps: []int64{2, 10, 20, 40},
+				Floats:     []float64{4, 5, 16, 6},
+			},
+		},
+		{
+			name: "mean",
+			typ:  datatypes.AggregateTypeMean,
+			exp: result{
+				Timestamps: []int64{2, 10, 20, 40},
+				Floats:     []float64{2, 2.5, 16.0 / 3, 6},
+			},
+		},
+		{
+			name: "count",
+			typ:  datatypes.AggregateTypeCount,
+			exp: result{
+				Timestamps: []int64{2, 10, 20, 40},
+				Integers:   []int64{2, 2, 3, 1},
+			},
+		},
+		{
+			name:        "count create empty",
+			typ:         datatypes.AggregateTypeCount,
+			createEmpty: true,
+			exp: result{
+				Timestamps: []int64{2, 10, 20, 30, 40, 50},
+				Integers:   []int64{2, 2, 3, 0, 1, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &datatypes.ReadWindowAggregateRequest{
+				Range:       datatypes.TimestampRange{Start: 2, End: 55},
+				WindowEvery: 10,
+				Aggregate:   &datatypes.Aggregate{Type: tt.typ},
+				CreateEmpty: tt.createEmpty,
+			}
+
+			var got result
+			switch cur := newWindowAggregateArrayCursor(context.Background(), req, newCursor()).(type) {
+			case cursors.FloatArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					got.Timestamps = append(got.Timestamps, a.Timestamps...)
+					got.Floats = append(got.Floats, a.Values...)
+				}
+			case cursors.IntegerArrayCursor:
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					got.Timestamps = append(got.Timestamps, a.Timestamps...)
+					got.Integers = append(got.Integers, a.Values...)
+				}
+			default:
+				t.Fatalf("unexpected cursor type %T", cur)
+			}
+
+			if !cmp.Equal(got, tt.exp) {
+				t.Errorf("unexpected window aggregates; -got/+exp\n%s", cmp.Diff(got, tt.exp))
+			}
+		})
+	}
+}
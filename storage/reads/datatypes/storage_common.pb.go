@@ -96,18 +96,33 @@ const (
 	AggregateTypeNone  Aggregate_AggregateType = 0
 	AggregateTypeSum   Aggregate_AggregateType = 1
 	AggregateTypeCount Aggregate_AggregateType = 2
+	AggregateTypeFirst Aggregate_AggregateType = 3
+	AggregateTypeLast  Aggregate_AggregateType = 4
+	AggregateTypeMin   Aggregate_AggregateType = 5
+	AggregateTypeMax   Aggregate_AggregateType = 6
+	AggregateTypeMean  Aggregate_AggregateType = 7
 )
 
 var Aggregate_AggregateType_name = map[int32]string{
 	0: "NONE",
 	1: "SUM",
 	2: "COUNT",
+	3: "FIRST",
+	4: "LAST",
+	5: "MIN",
+	6: "MAX",
+	7: "MEAN",
 }
 
 var Aggregate_AggregateType_value = map[string]int32{
 	"NONE":  0,
 	"SUM":   1,
 	"COUNT": 2,
+	"FIRST": 3,
+	"LAST":  4,
+	"MIN":   5,
+	"MAX":   6,
+	"MEAN":  7,
 }
 
 func (x Aggregate_AggregateType) String() string {
@@ -115,7 +130,7 @@ func (x Aggregate_AggregateType) String() string {
 }
 
 func (Aggregate_AggregateType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{2, 0}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{3, 0}
 }
 
 type ReadResponse_FrameType int32
@@ -140,7 +155,7 @@ func (x ReadResponse_FrameType) String() string {
 }
 
 func (ReadResponse_FrameType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 0}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 0}
 }
 
 type ReadResponse_DataType int32
@@ -174,7 +189,7 @@ func (x ReadResponse_DataType) String() string {
 }
 
 func (ReadResponse_DataType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 1}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 1}
 }
 
 type ReadFilterRequest struct {
@@ -261,6 +276,54 @@ func (m *ReadGroupRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ReadGroupRequest proto.InternalMessageInfo
 
+// ReadWindowAggregateRequest is the request message for Storage.ReadWindowAggregate.
+type ReadWindowAggregateRequest struct {
+	ReadSource *types.Any     `protobuf:"bytes,1,opt,name=read_source,json=readSource,proto3" json:"read_source,omitempty"`
+	Range      TimestampRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range"`
+	Predicate  *Predicate     `protobuf:"bytes,3,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	// WindowEvery is the duration of each window in nanoseconds.
+	// Windows are aligned to the Unix epoch and truncated to Range.
+	WindowEvery int64 `protobuf:"varint,4,opt,name=window_every,json=windowEvery,proto3" json:"window_every,omitempty"`
+	// Aggregate is computed over the points of each series in each window.
+	Aggregate *Aggregate `protobuf:"bytes,5,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// CreateEmpty produces a point for windows with no points
+	// for the aggregates that have a value for an empty window.
+	CreateEmpty bool `protobuf:"varint,6,opt,name=create_empty,json=createEmpty,proto3" json:"create_empty,omitempty"`
+}
+
+func (m *ReadWindowAggregateRequest) Reset()         { *m = ReadWindowAggregateRequest{} }
+func (m *ReadWindowAggregateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadWindowAggregateRequest) ProtoMessage()    {}
+func (*ReadWindowAggregateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_715e4bf4cdf1f73d, []int{2}
+}
+func (m *ReadWindowAggregateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReadWindowAggregateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReadWindowAggregateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReadWindowAggregateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadWindowAggregateRequest.Merge(m, src)
+}
+func (m *ReadWindowAggregateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReadWindowAggregateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadWindowAggregateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadWindowAggregateRequest proto.InternalMessageInfo
+
 type Aggregate struct {
 	Type Aggregate_AggregateType `protobuf:"varint,1,opt,name=type,proto3,enum=influxdata.platform.storage.Aggregate_AggregateType" json:"type,omitempty"`
 }
@@ -269,7 +332,7 @@ func (m *Aggregate) Reset()         { *m = Aggregate{} }
 func (m *Aggregate) String() string { return proto.CompactTextString(m) }
 func (*Aggregate) ProtoMessage()    {}
 func (*Aggregate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{2}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{3}
 }
 func (m *Aggregate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -307,7 +370,7 @@ func (m *Tag) Reset()         { *m = Tag{} }
 func (m *Tag) String() string { return proto.CompactTextString(m) }
 func (*Tag) ProtoMessage()    {}
 func (*Tag) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{3}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{4}
 }
 func (m *Tag) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -345,7 +408,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5}
 }
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -390,7 +453,7 @@ func (m *ReadResponse_Frame) Reset()         { *m = ReadResponse_Frame{} }
 func (m *ReadResponse_Frame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_Frame) ProtoMessage()    {}
 func (*ReadResponse_Frame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 0}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 0}
 }
 func (m *ReadResponse_Frame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -691,7 +754,7 @@ func (m *ReadResponse_GroupFrame) Reset()         { *m = ReadResponse_GroupFrame
 func (m *ReadResponse_GroupFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_GroupFrame) ProtoMessage()    {}
 func (*ReadResponse_GroupFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 1}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 1}
 }
 func (m *ReadResponse_GroupFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -729,7 +792,7 @@ func (m *ReadResponse_SeriesFrame) Reset()         { *m = ReadResponse_SeriesFra
 func (m *ReadResponse_SeriesFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_SeriesFrame) ProtoMessage()    {}
 func (*ReadResponse_SeriesFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 2}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 2}
 }
 func (m *ReadResponse_SeriesFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -767,7 +830,7 @@ func (m *ReadResponse_FloatPointsFrame) Reset()         { *m = ReadResponse_Floa
 func (m *ReadResponse_FloatPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_FloatPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_FloatPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 3}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 3}
 }
 func (m *ReadResponse_FloatPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -805,7 +868,7 @@ func (m *ReadResponse_IntegerPointsFrame) Reset()         { *m = ReadResponse_In
 func (m *ReadResponse_IntegerPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_IntegerPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_IntegerPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 4}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 4}
 }
 func (m *ReadResponse_IntegerPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -843,7 +906,7 @@ func (m *ReadResponse_UnsignedPointsFrame) Reset()         { *m = ReadResponse_U
 func (m *ReadResponse_UnsignedPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_UnsignedPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_UnsignedPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 5}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 5}
 }
 func (m *ReadResponse_UnsignedPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -881,7 +944,7 @@ func (m *ReadResponse_BooleanPointsFrame) Reset()         { *m = ReadResponse_Bo
 func (m *ReadResponse_BooleanPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_BooleanPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_BooleanPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 6}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 6}
 }
 func (m *ReadResponse_BooleanPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -919,7 +982,7 @@ func (m *ReadResponse_StringPointsFrame) Reset()         { *m = ReadResponse_Str
 func (m *ReadResponse_StringPointsFrame) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse_StringPointsFrame) ProtoMessage()    {}
 func (*ReadResponse_StringPointsFrame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{4, 7}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{5, 7}
 }
 func (m *ReadResponse_StringPointsFrame) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -956,7 +1019,7 @@ func (m *CapabilitiesResponse) Reset()         { *m = CapabilitiesResponse{} }
 func (m *CapabilitiesResponse) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesResponse) ProtoMessage()    {}
 func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{5}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{6}
 }
 func (m *CapabilitiesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -997,7 +1060,7 @@ func (m *TimestampRange) Reset()         { *m = TimestampRange{} }
 func (m *TimestampRange) String() string { return proto.CompactTextString(m) }
 func (*TimestampRange) ProtoMessage()    {}
 func (*TimestampRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{6}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{7}
 }
 func (m *TimestampRange) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1037,7 +1100,7 @@ func (m *TagKeysRequest) Reset()         { *m = TagKeysRequest{} }
 func (m *TagKeysRequest) String() string { return proto.CompactTextString(m) }
 func (*TagKeysRequest) ProtoMessage()    {}
 func (*TagKeysRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{7}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{8}
 }
 func (m *TagKeysRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1078,7 +1141,7 @@ func (m *TagValuesRequest) Reset()         { *m = TagValuesRequest{} }
 func (m *TagValuesRequest) String() string { return proto.CompactTextString(m) }
 func (*TagValuesRequest) ProtoMessage()    {}
 func (*TagValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{8}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{9}
 }
 func (m *TagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1116,7 +1179,7 @@ func (m *StringValuesResponse) Reset()         { *m = StringValuesResponse{} }
 func (m *StringValuesResponse) String() string { return proto.CompactTextString(m) }
 func (*StringValuesResponse) ProtoMessage()    {}
 func (*StringValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_715e4bf4cdf1f73d, []int{9}
+	return fileDescriptor_715e4bf4cdf1f73d, []int{10}
 }
 func (m *StringValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1153,6 +1216,7 @@ func init() {
 	proto.RegisterEnum("influxdata.platform.storage.ReadResponse_DataType", ReadResponse_DataType_name, ReadResponse_DataType_value)
 	proto.RegisterType((*ReadFilterRequest)(nil), "influxdata.platform.storage.ReadFilterRequest")
 	proto.RegisterType((*ReadGroupRequest)(nil), "influxdata.platform.storage.ReadGroupRequest")
+	proto.RegisterType((*ReadWindowAggregateRequest)(nil), "influxdata.platform.storage.ReadWindowAggregateRequest")
 	proto.RegisterType((*Aggregate)(nil), "influxdata.platform.storage.Aggregate")
 	proto.RegisterType((*Tag)(nil), "influxdata.platform.storage.Tag")
 	proto.RegisterType((*ReadResponse)(nil), "influxdata.platform.storage.ReadResponse")
@@ -1175,101 +1239,110 @@ func init() {
 func init() { proto.RegisterFile("storage_common.proto", fileDescriptor_715e4bf4cdf1f73d) }
 
 var fileDescriptor_715e4bf4cdf1f73d = []byte{
-	// 1498 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x57, 0xcb, 0x6f, 0xdb, 0xc8,
-	0x19, 0x17, 0xf5, 0x34, 0x3f, 0xc9, 0x32, 0x3d, 0x51, 0x5d, 0x87, 0x69, 0x24, 0x56, 0x28, 0x52,
-	0x17, 0x49, 0xe4, 0xd4, 0x49, 0xd1, 0x20, 0x6d, 0x0f, 0x96, 0x23, 0x5b, 0x6a, 0x6c, 0xc9, 0xa0,
-	0xe4, 0x00, 0xe9, 0x45, 0x18, 0xdb, 0x63, 0x86, 0x88, 0x44, 0xb2, 0x24, 0x15, 0x58, 0x40, 0x2f,
-	0xbd, 0x05, 0x3a, 0xb5, 0x97, 0x1e, 0x0a, 0x08, 0x58, 0x60, 0x8f, 0x7b, 0xdf, 0xbf, 0x21, 0x87,
-	0x05, 0x36, 0xc7, 0x3d, 0x09, 0xbb, 0x0a, 0xb0, 0xc0, 0xfe, 0x0b, 0x7b, 0x5a, 0xcc, 0x0c, 0x47,
-	0xa2, 0x6c, 0xc3, 0x96, 0xf6, 0xb4, 0xc8, 0x6d, 0xe6, 0x7b, 0xfc, 0xbe, 0x07, 0xbf, 0xc7, 0x10,
-	0x72, 0x9e, 0x6f, 0xbb, 0xd8, 0x20, 0xed, 0x13, 0xbb, 0xdb, 0xb5, 0xad, 0x92, 0xe3, 0xda, 0xbe,
-	0x8d, 0xee, 0x98, 0xd6, 0x59, 0xa7, 0x77, 0x7e, 0x8a, 0x7d, 0x5c, 0x72, 0x3a, 0xd8, 0x3f, 0xb3,
-	0xdd, 0x6e, 0x29, 0x90, 0x54, 0x73, 0x86, 0x6d, 0xd8, 0x4c, 0x6e, 0x93, 0x9e, 0xb8, 0x8a, 0x7a,
-	0xc7, 0xb0, 0x6d, 0xa3, 0x43, 0x36, 0xd9, 0xed, 0xb8, 0x77, 0xb6, 0x49, 0xba, 0x8e, 0xdf, 0x0f,
-	0x98, 0xb7, 0x2f, 0x32, 0xb1, 0x25, 0x58, 0x2b, 0x8e, 0x4b, 0x4e, 0xcd, 0x13, 0xec, 0x13, 0x4e,
-	0x28, 0xfe, 0x20, 0xc1, 0xaa, 0x4e, 0xf0, 0xe9, 0xae, 0xd9, 0xf1, 0x89, 0xab, 0x93, 0x7f, 0xf6,
-	0x88, 0xe7, 0xa3, 0x0a, 0xa4, 0x5d, 0x82, 0x4f, 0xdb, 0x9e, 0xdd, 0x73, 0x4f, 0xc8, 0xba, 0xa4,
-	0x49, 0x1b, 0xe9, 0xad, 0x5c, 0x89, 0xe3, 0x96, 0x04, 0x6e, 0x69, 0xdb, 0xea, 0x97, 0xb3, 0xe3,
-	0x51, 0x01, 0x28, 0x42, 0x93, 0xc9, 0xea, 0xe0, 0x4e, 0xce, 0x68, 0x0f, 0x12, 0x2e, 0xb6, 0x0c,
-	0xb2, 0x1e, 0x65, 0x00, 0xf7, 0x4b, 0xd7, 0x04, 0x5a, 0x6a, 0x99, 0x5d, 0xe2, 0xf9, 0xb8, 0xeb,
-	0xe8, 0x54, 0xa5, 0x1c, 0x7f, 0x3f, 0x2a, 0x44, 0x74, 0xae, 0x8f, 0x9e, 0x83, 0x3c, 0x71, 0x7c,
-	0x3d, 0xc6, 0xc0, 0xee, 0x5d, 0x0b, 0x76, 0x28, 0xa4, 0xf5, 0xa9, 0x62, 0xf1, 0xab, 0x04, 0x28,
-	0xd4, 0xd3, 0x3d, 0xd7, 0xee, 0x39, 0x9f, 0x74, 0xa8, 0xe8, 0x01, 0x80, 0x41, 0xa3, 0x6c, 0xbf,
-	0x21, 0x7d, 0x6f, 0x3d, 0xae, 0xc5, 0x36, 0xe4, 0xf2, 0xf2, 0x78, 0x54, 0x90, 0x59, 0xec, 0x2f,
-	0x48, 0xdf, 0xd3, 0x65, 0x43, 0x1c, 0x51, 0x0d, 0x12, 0xec, 0xb2, 0x9e, 0xd0, 0xa4, 0x8d, 0xec,
-	0xd6, 0xe3, 0x6b, 0xed, 0x5d, 0xcc, 0x60, 0x89, 0x5f, 0x38, 0x02, 0x75, 0x1f, 0x1b, 0x86, 0x4b,
-	0x0c, 0xea, 0x7e, 0x72, 0x0e, 0xf7, 0xb7, 0x85, 0xb4, 0x3e, 0x55, 0x44, 0x0f, 0x20, 0xf1, 0xda,
-	0xb4, 0x7c, 0x6f, 0x3d, 0xa5, 0x49, 0x1b, 0xa9, 0xf2, 0xda, 0x78, 0x54, 0x48, 0x54, 0x29, 0xe1,
-	0xc7, 0x51, 0x41, 0xa6, 0x87, 0xdd, 0x0e, 0x36, 0x3c, 0x9d, 0x0b, 0x15, 0xf7, 0x20, 0xc1, 0x7c,
-	0x40, 0x77, 0x01, 0xf6, 0xf4, 0xc6, 0xd1, 0x61, 0xbb, 0xde, 0xa8, 0x57, 0x94, 0x88, 0xba, 0x3c,
-	0x18, 0x6a, 0x3c, 0xe2, 0xba, 0x6d, 0x11, 0x74, 0x1b, 0x96, 0x38, 0xbb, 0xfc, 0x4a, 0x89, 0xaa,
-	0xe9, 0xc1, 0x50, 0x4b, 0x31, 0x66, 0xb9, 0xaf, 0xc6, 0xdf, 0x7d, 0x9e, 0x8f, 0x14, 0xbf, 0x90,
-	0x60, 0x8a, 0x8e, 0xee, 0x80, 0x5c, 0xad, 0xd5, 0x5b, 0x02, 0x2c, 0x33, 0x18, 0x6a, 0x4b, 0x94,
-	0xcb, 0xb0, 0x7e, 0x07, 0xd9, 0x80, 0xd9, 0x3e, 0x6c, 0xd4, 0xea, 0xad, 0xa6, 0x22, 0xa9, 0xca,
-	0x60, 0xa8, 0x65, 0xb8, 0xc4, 0xa1, 0x4d, 0x3d, 0x0b, 0x4b, 0x35, 0x2b, 0x7a, 0xad, 0xd2, 0x54,
-	0xa2, 0x61, 0xa9, 0x26, 0x71, 0x4d, 0xe2, 0xa1, 0x4d, 0xc8, 0x31, 0xa9, 0xe6, 0x4e, 0xb5, 0x72,
-	0xb0, 0xdd, 0xde, 0xde, 0xdf, 0x6f, 0xb7, 0x6a, 0x07, 0x15, 0x25, 0xae, 0xfe, 0x6a, 0x30, 0xd4,
-	0x56, 0xa9, 0x6c, 0xf3, 0xe4, 0x35, 0xe9, 0xe2, 0xed, 0x4e, 0x87, 0x96, 0x4e, 0xe0, 0xed, 0xd7,
-	0x12, 0xc8, 0x93, 0xec, 0xa1, 0x2a, 0xc4, 0xfd, 0xbe, 0xc3, 0x0b, 0x38, 0xbb, 0xf5, 0x64, 0xbe,
-	0x9c, 0x4f, 0x4f, 0xad, 0xbe, 0x43, 0x74, 0x86, 0x50, 0x3c, 0x87, 0xe5, 0x19, 0x32, 0x2a, 0x40,
-	0x3c, 0xc8, 0x01, 0xf3, 0x67, 0x86, 0xc9, 0x92, 0x71, 0x17, 0x62, 0xcd, 0xa3, 0x03, 0x45, 0x52,
-	0x73, 0x83, 0xa1, 0xa6, 0xcc, 0xf0, 0x9b, 0xbd, 0x2e, 0xfa, 0x2d, 0x24, 0x76, 0x1a, 0x47, 0xf5,
-	0x96, 0x12, 0x55, 0xd7, 0x06, 0x43, 0x0d, 0xcd, 0x08, 0xec, 0xd8, 0x3d, 0xcb, 0x0f, 0x22, 0x7a,
-	0x08, 0xb1, 0x16, 0x36, 0x90, 0x02, 0xb1, 0x37, 0xa4, 0xcf, 0x22, 0xc9, 0xe8, 0xf4, 0x88, 0x72,
-	0x90, 0x78, 0x8b, 0x3b, 0x3d, 0xde, 0x5d, 0x19, 0x9d, 0x5f, 0x8a, 0xff, 0xcd, 0x42, 0x86, 0x56,
-	0xa3, 0x4e, 0x3c, 0xc7, 0xb6, 0x3c, 0x82, 0x0e, 0x20, 0x79, 0xe6, 0xe2, 0x2e, 0xf1, 0xd6, 0x25,
-	0x2d, 0xb6, 0x91, 0xde, 0xda, 0xbc, 0xb1, 0x90, 0x85, 0x6a, 0x69, 0x97, 0xea, 0x05, 0x9d, 0x18,
-	0x80, 0xa8, 0xef, 0x92, 0x90, 0x60, 0x74, 0xb4, 0x2f, 0x1a, 0x24, 0xc5, 0x2a, 0xfa, 0xc9, 0xfc,
-	0xb8, 0xac, 0xc0, 0x18, 0x48, 0x35, 0x22, 0x7a, 0xa4, 0x01, 0x49, 0x8f, 0x7d, 0xf9, 0x60, 0xda,
-	0xfc, 0x69, 0x7e, 0x38, 0x5e, 0x31, 0x02, 0x2f, 0x80, 0x41, 0x0e, 0x64, 0xce, 0x3a, 0x36, 0xf6,
-	0xdb, 0x0e, 0x2b, 0xbb, 0x60, 0x06, 0x3d, 0x5b, 0x20, 0x7a, 0xaa, 0xcd, 0x6b, 0x96, 0x27, 0x62,
-	0x65, 0x3c, 0x2a, 0xa4, 0x43, 0xd4, 0x6a, 0x44, 0x4f, 0x9f, 0x4d, 0xaf, 0xe8, 0x1c, 0xb2, 0xa6,
-	0xe5, 0x13, 0x83, 0xb8, 0xc2, 0x26, 0x1f, 0x55, 0x7f, 0x9d, 0xdf, 0x66, 0x8d, 0xeb, 0x87, 0xad,
-	0xae, 0x8e, 0x47, 0x85, 0xe5, 0x19, 0x7a, 0x35, 0xa2, 0x2f, 0x9b, 0x61, 0x02, 0xfa, 0x17, 0xac,
-	0xf4, 0x2c, 0xcf, 0x34, 0x2c, 0x72, 0x2a, 0x4c, 0xc7, 0x99, 0xe9, 0xbf, 0xcd, 0x6f, 0xfa, 0x28,
-	0x00, 0x08, 0xdb, 0x46, 0xe3, 0x51, 0x21, 0x3b, 0xcb, 0xa8, 0x46, 0xf4, 0x6c, 0x6f, 0x86, 0x42,
-	0xe3, 0x3e, 0xb6, 0xed, 0x0e, 0xc1, 0x96, 0x30, 0x9e, 0x58, 0x34, 0xee, 0x32, 0xd7, 0xbf, 0x14,
-	0xf7, 0x0c, 0x9d, 0xc6, 0x7d, 0x1c, 0x26, 0x20, 0x1f, 0x96, 0x3d, 0xdf, 0x35, 0x2d, 0x43, 0x18,
-	0xe6, 0xc3, 0xf5, 0x2f, 0x0b, 0xd4, 0x0e, 0x53, 0x0f, 0xdb, 0x55, 0xc6, 0xa3, 0x42, 0x26, 0x4c,
-	0xae, 0x46, 0xf4, 0x8c, 0x17, 0xba, 0x97, 0x93, 0x10, 0xa7, 0xc8, 0xea, 0x39, 0xc0, 0xb4, 0x92,
-	0xd1, 0x3d, 0x58, 0xf2, 0xb1, 0xc1, 0x77, 0x0b, 0xed, 0xb4, 0x4c, 0x39, 0x3d, 0x1e, 0x15, 0x52,
-	0x2d, 0x6c, 0xb0, 0xcd, 0x92, 0xf2, 0xf9, 0x01, 0x95, 0x01, 0x39, 0xd8, 0xf5, 0x4d, 0xdf, 0xb4,
-	0x2d, 0x2a, 0xdd, 0x7e, 0x8b, 0x3b, 0xb4, 0x3a, 0xa9, 0x46, 0x6e, 0x3c, 0x2a, 0x28, 0x87, 0x82,
-	0xfb, 0x82, 0xf4, 0x5f, 0xe2, 0x8e, 0xa7, 0x2b, 0xce, 0x05, 0x8a, 0xfa, 0x7f, 0x09, 0xd2, 0xa1,
-	0xaa, 0x47, 0xcf, 0x20, 0xee, 0x63, 0x43, 0x74, 0xb8, 0x76, 0xfd, 0x9e, 0xc5, 0x46, 0xd0, 0xd2,
-	0x4c, 0x07, 0x35, 0x40, 0xa6, 0x82, 0x6d, 0x36, 0x28, 0xa3, 0x6c, 0x50, 0x6e, 0xcd, 0x9f, 0xbf,
-	0xe7, 0xd8, 0xc7, 0x6c, 0x4c, 0x2e, 0x9d, 0x06, 0x27, 0xf5, 0xef, 0xa0, 0x5c, 0x6c, 0x1d, 0x94,
-	0x07, 0xf0, 0xc5, 0x7e, 0xe7, 0x6e, 0x2a, 0x7a, 0x88, 0x82, 0xd6, 0x20, 0xc9, 0xc6, 0x17, 0x4f,
-	0x84, 0xa4, 0x07, 0x37, 0x75, 0x1f, 0xd0, 0xe5, 0x96, 0x58, 0x10, 0x2d, 0x36, 0x41, 0x3b, 0x80,
-	0x5b, 0x57, 0x54, 0xf9, 0x82, 0x70, 0xf1, 0xb0, 0x73, 0x97, 0xeb, 0x76, 0x41, 0xb4, 0xa5, 0x09,
-	0xda, 0x0b, 0x58, 0xbd, 0x54, 0x8c, 0x0b, 0x82, 0xc9, 0x02, 0xac, 0xd8, 0x04, 0x99, 0x01, 0x04,
-	0xab, 0x2a, 0x19, 0x2c, 0xda, 0x88, 0x7a, 0x6b, 0x30, 0xd4, 0x56, 0x26, 0xac, 0x60, 0xd7, 0x16,
-	0x20, 0x39, 0xd9, 0xd7, 0xb3, 0x02, 0xdc, 0x97, 0x60, 0x13, 0x7d, 0x29, 0xc1, 0x92, 0xf8, 0xde,
-	0xe8, 0x37, 0x90, 0xd8, 0xdd, 0x6f, 0x6c, 0xb7, 0x94, 0x88, 0xba, 0x3a, 0x18, 0x6a, 0xcb, 0x82,
-	0xc1, 0x3e, 0x3d, 0xd2, 0x20, 0x55, 0xab, 0xb7, 0x2a, 0x7b, 0x15, 0x5d, 0x40, 0x0a, 0x7e, 0xf0,
-	0x39, 0x51, 0x11, 0x96, 0x8e, 0xea, 0xcd, 0xda, 0x5e, 0xbd, 0xf2, 0x5c, 0x89, 0xf2, 0x1d, 0x29,
-	0x44, 0xc4, 0x37, 0xa2, 0x28, 0xe5, 0x46, 0x63, 0xbf, 0xb2, 0x5d, 0x57, 0x62, 0xb3, 0x28, 0x41,
-	0xde, 0x51, 0x1e, 0x92, 0xcd, 0x96, 0x5e, 0xab, 0xef, 0x29, 0x71, 0x15, 0x0d, 0x86, 0x5a, 0x56,
-	0x08, 0xf0, 0x54, 0x06, 0x8e, 0x7f, 0x26, 0x41, 0x6e, 0x07, 0x3b, 0xf8, 0xd8, 0xec, 0x98, 0xbe,
-	0x49, 0xbc, 0xc9, 0x6e, 0x6c, 0x40, 0xfc, 0x04, 0x3b, 0xa2, 0x6f, 0xae, 0x1f, 0x1b, 0x57, 0x01,
-	0x50, 0xa2, 0x57, 0xb1, 0x7c, 0xb7, 0xaf, 0x33, 0x20, 0xf5, 0xcf, 0x20, 0x4f, 0x48, 0xe1, 0x95,
-	0x2d, 0x5f, 0xb1, 0xb2, 0xe5, 0x60, 0x65, 0x3f, 0x8b, 0x3e, 0x95, 0x8a, 0x4f, 0x21, 0x3b, 0xfb,
-	0x00, 0xa6, 0xb2, 0x9e, 0x8f, 0x5d, 0x9f, 0xe9, 0xc7, 0x74, 0x7e, 0xa1, 0x98, 0xc4, 0x3a, 0x65,
-	0xfa, 0x31, 0x9d, 0x1e, 0x8b, 0xdf, 0x4b, 0x90, 0x15, 0x43, 0x66, 0xfa, 0x7c, 0xa7, 0xad, 0x3d,
-	0xf7, 0xf3, 0xbd, 0x85, 0x0d, 0x4f, 0x3c, 0xdf, 0xfd, 0xc9, 0xf9, 0x97, 0xf6, 0xa7, 0xf2, 0xef,
-	0x28, 0x28, 0x2d, 0x6c, 0xbc, 0x64, 0x15, 0xfe, 0x49, 0x87, 0x8a, 0x7e, 0x0d, 0xa9, 0x60, 0x97,
-	0xb0, 0x3d, 0x2e, 0xeb, 0x49, 0xbe, 0x3d, 0x8a, 0x25, 0xc8, 0xf1, 0xca, 0x16, 0x59, 0x08, 0x0a,
-	0x79, 0x3a, 0x07, 0xd8, 0xea, 0x11, 0x73, 0x60, 0xeb, 0x7f, 0x71, 0x48, 0x35, 0xb9, 0x25, 0x64,
-	0x02, 0x4c, 0x7f, 0x6a, 0x51, 0xe9, 0xc6, 0x19, 0x3f, 0xf3, 0xf7, 0xab, 0xfe, 0x61, 0xee, 0x9d,
-	0xf0, 0x48, 0x42, 0x06, 0xc8, 0x93, 0x3f, 0x22, 0xf4, 0x70, 0xa1, 0x3f, 0xa7, 0xc5, 0x0c, 0xbd,
-	0x01, 0xb1, 0x60, 0xd1, 0xfd, 0x9b, 0xb6, 0x5e, 0xa8, 0x43, 0xd4, 0x3f, 0x5e, 0x2b, 0x7c, 0x55,
-	0x8a, 0x1f, 0x49, 0xc8, 0x06, 0x79, 0x52, 0x7f, 0x37, 0x44, 0x75, 0xb1, 0x4e, 0x7f, 0x9e, 0xc1,
-	0x57, 0x90, 0x09, 0x4f, 0x1d, 0xb4, 0x76, 0xa9, 0xae, 0x2b, 0x5d, 0xc7, 0xef, 0xdf, 0x00, 0x7e,
-	0xd5, 0xe0, 0x2a, 0xff, 0xfe, 0xfd, 0x77, 0xf9, 0xc8, 0xfb, 0x71, 0x5e, 0xfa, 0x30, 0xce, 0x4b,
-	0xdf, 0x8e, 0xf3, 0xd2, 0x7f, 0x3e, 0xe6, 0x23, 0x1f, 0x3e, 0xe6, 0x23, 0xdf, 0x7c, 0xcc, 0x47,
-	0xfe, 0xc1, 0x5e, 0x04, 0xf4, 0x41, 0xe0, 0x1d, 0x27, 0x99, 0xad, 0xc7, 0x3f, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0x7f, 0x0e, 0xbb, 0xbb, 0xa6, 0x11, 0x00, 0x00,
+	// 1647 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcd, 0x6f, 0x23, 0x49,
+	0x15, 0x77, 0xfb, 0x33, 0xfd, 0xec, 0x78, 0x3a, 0x35, 0x66, 0xf0, 0xf6, 0xb0, 0x76, 0x63, 0xa1,
+	0x25, 0x68, 0x77, 0x9d, 0xc5, 0xbb, 0x68, 0x57, 0x03, 0x1c, 0xec, 0x8c, 0x13, 0x9b, 0xf1, 0x47,
+	0xd4, 0x76, 0x16, 0x96, 0x8b, 0x55, 0x89, 0x2b, 0xbd, 0xad, 0xb1, 0xbb, 0x4d, 0x77, 0x7b, 0x26,
+	0x16, 0x5c, 0xb8, 0xad, 0x7c, 0x02, 0x71, 0x03, 0x59, 0x42, 0xe2, 0xc8, 0x9d, 0xbf, 0x61, 0x0e,
+	0x1c, 0xf6, 0xc8, 0xc9, 0x02, 0x8f, 0x84, 0xc4, 0x99, 0x1b, 0x27, 0x54, 0x55, 0x5d, 0x76, 0x3b,
+	0xb1, 0x12, 0x7b, 0x4e, 0xab, 0xb9, 0x55, 0xbd, 0x8f, 0xdf, 0x7b, 0x55, 0xf5, 0xbe, 0xba, 0x21,
+	0xe3, 0x7a, 0xb6, 0x83, 0x0d, 0xd2, 0xbb, 0xb4, 0x87, 0x43, 0xdb, 0x2a, 0x8e, 0x1c, 0xdb, 0xb3,
+	0xd1, 0x63, 0xd3, 0xba, 0x1a, 0x8c, 0xaf, 0xfb, 0xd8, 0xc3, 0xc5, 0xd1, 0x00, 0x7b, 0x57, 0xb6,
+	0x33, 0x2c, 0xfa, 0x92, 0x6a, 0xc6, 0xb0, 0x0d, 0x9b, 0xc9, 0x1d, 0xd1, 0x15, 0x57, 0x51, 0x1f,
+	0x1b, 0xb6, 0x6d, 0x0c, 0xc8, 0x11, 0xdb, 0x5d, 0x8c, 0xaf, 0x8e, 0xc8, 0x70, 0xe4, 0x4d, 0x7c,
+	0xe6, 0x3b, 0x37, 0x99, 0xd8, 0x12, 0xac, 0x07, 0x23, 0x87, 0xf4, 0xcd, 0x4b, 0xec, 0x11, 0x4e,
+	0x28, 0xfc, 0x47, 0x82, 0x03, 0x9d, 0xe0, 0xfe, 0x89, 0x39, 0xf0, 0x88, 0xa3, 0x93, 0x5f, 0x8d,
+	0x89, 0xeb, 0xa1, 0x2a, 0x24, 0x1d, 0x82, 0xfb, 0x3d, 0xd7, 0x1e, 0x3b, 0x97, 0x24, 0x2b, 0x69,
+	0xd2, 0x61, 0xb2, 0x94, 0x29, 0x72, 0xdc, 0xa2, 0xc0, 0x2d, 0x96, 0xad, 0x49, 0x25, 0xbd, 0x98,
+	0xe7, 0x81, 0x22, 0x74, 0x98, 0xac, 0x0e, 0xce, 0x72, 0x8d, 0x4e, 0x21, 0xe6, 0x60, 0xcb, 0x20,
+	0xd9, 0x30, 0x03, 0x78, 0xbf, 0x78, 0xc7, 0x41, 0x8b, 0x5d, 0x73, 0x48, 0x5c, 0x0f, 0x0f, 0x47,
+	0x3a, 0x55, 0xa9, 0x44, 0x5f, 0xcd, 0xf3, 0x21, 0x9d, 0xeb, 0xa3, 0xa7, 0x20, 0x2f, 0x1d, 0xcf,
+	0x46, 0x18, 0xd8, 0x7b, 0x77, 0x82, 0x9d, 0x09, 0x69, 0x7d, 0xa5, 0x58, 0xf8, 0x7b, 0x0c, 0x14,
+	0xea, 0xe9, 0xa9, 0x63, 0x8f, 0x47, 0x6f, 0xf5, 0x51, 0xd1, 0x07, 0x00, 0x06, 0x3d, 0x65, 0xef,
+	0x39, 0x99, 0xb8, 0xd9, 0xa8, 0x16, 0x39, 0x94, 0x2b, 0xfb, 0x8b, 0x79, 0x5e, 0x66, 0x67, 0x7f,
+	0x46, 0x26, 0xae, 0x2e, 0x1b, 0x62, 0x89, 0xea, 0x10, 0x63, 0x9b, 0x6c, 0x4c, 0x93, 0x0e, 0xd3,
+	0xa5, 0x8f, 0xef, 0xb4, 0x77, 0xf3, 0x06, 0x8b, 0x7c, 0xc3, 0x11, 0xa8, 0xfb, 0xd8, 0x30, 0x1c,
+	0x62, 0x50, 0xf7, 0xe3, 0x5b, 0xb8, 0x5f, 0x16, 0xd2, 0xfa, 0x4a, 0x11, 0x7d, 0x00, 0xb1, 0x2f,
+	0x4d, 0xcb, 0x73, 0xb3, 0x09, 0x4d, 0x3a, 0x4c, 0x54, 0x1e, 0x2d, 0xe6, 0xf9, 0x58, 0x8d, 0x12,
+	0xfe, 0x37, 0xcf, 0xcb, 0x74, 0x71, 0x32, 0xc0, 0x86, 0xab, 0x73, 0xa1, 0xc2, 0x29, 0xc4, 0x98,
+	0x0f, 0xe8, 0x5d, 0x80, 0x53, 0xbd, 0x7d, 0x7e, 0xd6, 0x6b, 0xb5, 0x5b, 0x55, 0x25, 0xa4, 0xee,
+	0x4f, 0x67, 0x1a, 0x3f, 0x71, 0xcb, 0xb6, 0x08, 0x7a, 0x07, 0xf6, 0x38, 0xbb, 0xf2, 0x85, 0x12,
+	0x56, 0x93, 0xd3, 0x99, 0x96, 0x60, 0xcc, 0xca, 0x44, 0x8d, 0x7e, 0xf5, 0x97, 0x5c, 0xa8, 0xf0,
+	0x57, 0x09, 0x56, 0xe8, 0xe8, 0x31, 0xc8, 0xb5, 0x7a, 0xab, 0x2b, 0xc0, 0x52, 0xd3, 0x99, 0xb6,
+	0x47, 0xb9, 0x0c, 0xeb, 0x7b, 0x90, 0xf6, 0x99, 0xbd, 0xb3, 0x76, 0xbd, 0xd5, 0xed, 0x28, 0x92,
+	0xaa, 0x4c, 0x67, 0x5a, 0x8a, 0x4b, 0x9c, 0xd9, 0xd4, 0xb3, 0xa0, 0x54, 0xa7, 0xaa, 0xd7, 0xab,
+	0x1d, 0x25, 0x1c, 0x94, 0xea, 0x10, 0xc7, 0x24, 0x2e, 0x3a, 0x82, 0x0c, 0x93, 0xea, 0x1c, 0xd7,
+	0xaa, 0xcd, 0x72, 0xaf, 0xdc, 0x68, 0xf4, 0xba, 0xf5, 0x66, 0x55, 0x89, 0xaa, 0xdf, 0x9a, 0xce,
+	0xb4, 0x03, 0x2a, 0xdb, 0xb9, 0xfc, 0x92, 0x0c, 0x71, 0x79, 0x30, 0xa0, 0xa1, 0xe3, 0x7b, 0xfb,
+	0xe7, 0x08, 0xa8, 0xf4, 0x31, 0x7e, 0x6e, 0x5a, 0x7d, 0xfb, 0xe5, 0xea, 0x1e, 0xdf, 0xea, 0xc0,
+	0x2e, 0x41, 0xea, 0x25, 0x3b, 0x6f, 0x8f, 0xbc, 0x20, 0xce, 0x24, 0x1b, 0xd5, 0xa4, 0xc3, 0x48,
+	0xe5, 0xc1, 0x62, 0x9e, 0x4f, 0xf2, 0x7b, 0xa8, 0x52, 0xb2, 0x9e, 0x7c, 0xb9, 0xda, 0xac, 0xc7,
+	0x64, 0xec, 0x4d, 0x63, 0xb2, 0x04, 0xa9, 0x4b, 0x87, 0x60, 0x8f, 0xf4, 0x58, 0xad, 0x65, 0xc1,
+	0xbd, 0xc7, 0x2d, 0x1f, 0x33, 0x7a, 0x95, 0x92, 0xf5, 0xe4, 0xe5, 0x6a, 0x53, 0xf8, 0x6f, 0x18,
+	0xe4, 0x25, 0x18, 0xaa, 0x41, 0xd4, 0x9b, 0x8c, 0xf8, 0x53, 0xa4, 0x4b, 0x9f, 0x6c, 0xe7, 0xc2,
+	0x6a, 0xd5, 0x9d, 0x8c, 0x88, 0xce, 0x10, 0x0a, 0x7f, 0x0a, 0xc3, 0xfe, 0x1a, 0x1d, 0xe5, 0x21,
+	0xea, 0xc7, 0x29, 0x8b, 0x99, 0x35, 0x26, 0x0b, 0xd8, 0x77, 0x21, 0xd2, 0x39, 0x6f, 0x2a, 0x92,
+	0x9a, 0x99, 0xce, 0x34, 0x65, 0x8d, 0xdf, 0x19, 0x0f, 0xd1, 0x77, 0x21, 0x76, 0xdc, 0x3e, 0x6f,
+	0x75, 0x95, 0xb0, 0xfa, 0x68, 0x3a, 0xd3, 0xd0, 0x9a, 0xc0, 0xb1, 0x3d, 0xb6, 0x3c, 0x2a, 0x72,
+	0x52, 0xd7, 0x3b, 0x5d, 0x25, 0xb2, 0x41, 0xe4, 0xc4, 0x74, 0x5c, 0x8f, 0x7a, 0xd1, 0x28, 0x77,
+	0xba, 0x22, 0x72, 0xd7, 0x24, 0x1a, 0xd8, 0xf5, 0xa8, 0x17, 0xcd, 0x7a, 0x4b, 0x89, 0x6d, 0xf0,
+	0xa2, 0x69, 0x5a, 0x8c, 0x5d, 0xfe, 0x85, 0x12, 0xdf, 0xc4, 0xc6, 0xd7, 0x14, 0xbe, 0x59, 0x2d,
+	0xb7, 0x94, 0xc4, 0x06, 0xf8, 0x26, 0xc1, 0x96, 0x9f, 0x18, 0x1f, 0x42, 0xa4, 0x8b, 0x0d, 0xa4,
+	0x40, 0xe4, 0x39, 0x99, 0xb0, 0xdb, 0x4e, 0xe9, 0x74, 0x89, 0x32, 0x10, 0x7b, 0x81, 0x07, 0x63,
+	0x1e, 0xcb, 0x29, 0x9d, 0x6f, 0x0a, 0xbf, 0x4f, 0x43, 0x8a, 0x06, 0xbf, 0x4e, 0xdc, 0x91, 0x6d,
+	0xb9, 0x04, 0x35, 0x21, 0x7e, 0xe5, 0xe0, 0x21, 0x71, 0xb3, 0x92, 0x16, 0x39, 0x4c, 0x96, 0x8e,
+	0xee, 0xad, 0x87, 0x42, 0xb5, 0x78, 0x42, 0xf5, 0xfc, 0xb8, 0xf7, 0x41, 0xd4, 0xaf, 0xe2, 0x10,
+	0x63, 0x74, 0xd4, 0x10, 0x75, 0x36, 0xc1, 0x82, 0xf0, 0x93, 0xed, 0x71, 0x59, 0x9d, 0x62, 0x20,
+	0xb5, 0x90, 0x28, 0xb5, 0x6d, 0x88, 0xbb, 0xac, 0x80, 0xf8, 0xb9, 0xfd, 0xa3, 0xed, 0xe1, 0x78,
+	0xe1, 0x11, 0x78, 0x3e, 0x0c, 0x1a, 0x41, 0xea, 0x6a, 0x60, 0x63, 0xaf, 0x37, 0x62, 0xd5, 0xcb,
+	0xcf, 0xf8, 0x27, 0x3b, 0x9c, 0x9e, 0x6a, 0xf3, 0xd2, 0xc7, 0x2f, 0x82, 0x65, 0x47, 0x80, 0x5a,
+	0x0b, 0xe9, 0xc9, 0xab, 0xd5, 0x16, 0x5d, 0x43, 0xda, 0xb4, 0x3c, 0x62, 0x10, 0x47, 0xd8, 0xe4,
+	0x85, 0xe1, 0x27, 0xdb, 0xdb, 0xac, 0x73, 0xfd, 0xa0, 0xd5, 0x83, 0xc5, 0x3c, 0xbf, 0xbf, 0x46,
+	0xaf, 0x85, 0xf4, 0x7d, 0x33, 0x48, 0x40, 0xbf, 0x81, 0x07, 0x63, 0xcb, 0x35, 0x0d, 0x8b, 0xf4,
+	0x85, 0xe9, 0x28, 0x33, 0xfd, 0xd3, 0xed, 0x4d, 0x9f, 0xfb, 0x00, 0x41, 0xdb, 0x68, 0x31, 0xcf,
+	0xa7, 0xd7, 0x19, 0xb5, 0x90, 0x9e, 0x1e, 0xaf, 0x51, 0xe8, 0xb9, 0x2f, 0x6c, 0x7b, 0x40, 0xb0,
+	0x25, 0x8c, 0xc7, 0x76, 0x3d, 0x77, 0x85, 0xeb, 0xdf, 0x3a, 0xf7, 0x1a, 0x9d, 0x9e, 0xfb, 0x22,
+	0x48, 0x40, 0x1e, 0xec, 0xbb, 0x9e, 0x63, 0x5a, 0x86, 0x30, 0xcc, 0x7b, 0xf4, 0x8f, 0x77, 0x88,
+	0x1d, 0xa6, 0x1e, 0xb4, 0xab, 0x2c, 0xe6, 0xf9, 0x54, 0x90, 0x5c, 0x0b, 0xe9, 0x29, 0x37, 0xb0,
+	0xaf, 0xc4, 0x21, 0x4a, 0x91, 0xd5, 0x6b, 0x80, 0x55, 0x24, 0xa3, 0xf7, 0x60, 0xcf, 0xc3, 0x06,
+	0x1f, 0x51, 0x68, 0xa6, 0xa5, 0x2a, 0xc9, 0xc5, 0x3c, 0x9f, 0xe8, 0x62, 0x83, 0x0d, 0x28, 0x09,
+	0x8f, 0x2f, 0x50, 0x05, 0xd0, 0x08, 0x3b, 0x9e, 0xe9, 0x99, 0xb6, 0x45, 0xa5, 0x7b, 0x2f, 0xf0,
+	0x80, 0x46, 0x27, 0xd5, 0xc8, 0x2c, 0xe6, 0x79, 0xe5, 0x4c, 0x70, 0x9f, 0x91, 0xc9, 0xe7, 0x78,
+	0xe0, 0xea, 0xca, 0xe8, 0x06, 0x45, 0xfd, 0xa3, 0x04, 0xc9, 0x40, 0xd4, 0xa3, 0x27, 0x10, 0xf5,
+	0xb0, 0x21, 0x32, 0x5c, 0xbb, 0xbb, 0xab, 0x61, 0xc3, 0x4f, 0x69, 0xa6, 0x83, 0xda, 0x20, 0x53,
+	0xc1, 0x1e, 0x2b, 0xe6, 0x61, 0x56, 0xcc, 0x4b, 0xdb, 0xdf, 0xdf, 0x53, 0xec, 0x61, 0x56, 0xca,
+	0xf7, 0xfa, 0xfe, 0x4a, 0xfd, 0x19, 0x28, 0x37, 0x53, 0x07, 0xe5, 0x00, 0x3c, 0xd1, 0x4d, 0xb9,
+	0x9b, 0x8a, 0x1e, 0xa0, 0xa0, 0x47, 0x10, 0x67, 0xe5, 0x8b, 0x5f, 0x84, 0xa4, 0xfb, 0x3b, 0xb5,
+	0x01, 0xe8, 0x76, 0x4a, 0xec, 0x88, 0x16, 0x59, 0xa2, 0x35, 0xe1, 0xe1, 0x86, 0x28, 0xdf, 0x11,
+	0x2e, 0x1a, 0x74, 0xee, 0x76, 0xdc, 0xee, 0x88, 0xb6, 0xb7, 0x44, 0x7b, 0x06, 0x07, 0xb7, 0x82,
+	0x71, 0x47, 0x30, 0x59, 0x80, 0x15, 0x3a, 0x20, 0x33, 0x00, 0xbf, 0x9b, 0xc6, 0xfd, 0x79, 0x2d,
+	0xa4, 0x3e, 0x9c, 0xce, 0xb4, 0x07, 0x4b, 0x96, 0x3f, 0xb2, 0xe5, 0x21, 0xbe, 0x1c, 0xfb, 0xd6,
+	0x05, 0xb8, 0x2f, 0x7e, 0x27, 0xfa, 0x9b, 0x04, 0x7b, 0xe2, 0xbd, 0xd1, 0x77, 0x20, 0x76, 0xd2,
+	0x68, 0x97, 0xbb, 0x4a, 0x48, 0x3d, 0x98, 0xce, 0xb4, 0x7d, 0xc1, 0x60, 0x4f, 0x8f, 0x34, 0x48,
+	0xd4, 0x5b, 0xdd, 0xea, 0x69, 0x55, 0x17, 0x90, 0x82, 0xef, 0x3f, 0x27, 0x2a, 0xc0, 0xde, 0x79,
+	0xab, 0x53, 0x3f, 0x6d, 0x55, 0x9f, 0x2a, 0x61, 0xde, 0x21, 0x85, 0x88, 0x78, 0x23, 0x8a, 0x52,
+	0x69, 0xb7, 0x1b, 0xb4, 0x49, 0x46, 0xd6, 0x51, 0xfc, 0x7b, 0x47, 0x39, 0x88, 0x77, 0xba, 0x7a,
+	0xbd, 0x75, 0xaa, 0x44, 0x55, 0x34, 0x9d, 0x69, 0x69, 0x21, 0xc0, 0xaf, 0x52, 0xcc, 0x96, 0x12,
+	0x64, 0x8e, 0xf1, 0x08, 0x5f, 0x98, 0x03, 0xd3, 0x33, 0x89, 0xbb, 0xec, 0x8d, 0x6d, 0x88, 0x5e,
+	0xe2, 0x91, 0xc8, 0x9b, 0xbb, 0xcb, 0xc6, 0x26, 0x00, 0x4a, 0x74, 0xab, 0x96, 0xe7, 0x4c, 0x74,
+	0x06, 0xa4, 0x7e, 0x0a, 0xf2, 0x92, 0x14, 0x6c, 0xd9, 0xf2, 0x86, 0x96, 0x2d, 0xfb, 0x2d, 0xfb,
+	0x49, 0xf8, 0x33, 0xa9, 0xf0, 0x19, 0xa4, 0xd7, 0xc7, 0x4d, 0x2a, 0xeb, 0x7a, 0xd8, 0xf1, 0x98,
+	0x7e, 0x44, 0xe7, 0x1b, 0x8a, 0x49, 0xac, 0x3e, 0xd3, 0x8f, 0xe8, 0x74, 0x59, 0xf8, 0xb7, 0x04,
+	0x69, 0x51, 0x64, 0x56, 0xc3, 0x32, 0x4d, 0xed, 0xad, 0x87, 0xe5, 0x2e, 0x36, 0x5c, 0x31, 0x2c,
+	0x7b, 0xcb, 0xf5, 0x37, 0xed, 0x83, 0xf7, 0xb7, 0x61, 0x50, 0xba, 0xd8, 0xf8, 0x9c, 0x45, 0xf8,
+	0x5b, 0x7d, 0x54, 0xf4, 0x6d, 0x48, 0xf8, 0xbd, 0x84, 0xf5, 0x71, 0x59, 0x8f, 0xf3, 0xee, 0x51,
+	0x28, 0x42, 0x86, 0x47, 0xb6, 0xb8, 0x05, 0x3f, 0x90, 0x57, 0x75, 0x80, 0xb5, 0x1e, 0x51, 0x07,
+	0x4a, 0x7f, 0x88, 0x41, 0xa2, 0xc3, 0x2d, 0x21, 0x13, 0x60, 0xf5, 0x6f, 0x04, 0x15, 0xef, 0xad,
+	0xf1, 0x6b, 0x3f, 0x51, 0xd4, 0x1f, 0x6c, 0xdd, 0x13, 0x3e, 0x92, 0x90, 0x01, 0xf2, 0xf2, 0xc3,
+	0x1a, 0x7d, 0xb8, 0xd3, 0x07, 0xf8, 0x6e, 0x86, 0x7e, 0x0d, 0x0f, 0x37, 0x7c, 0x34, 0xa2, 0x4f,
+	0xef, 0xc5, 0xd8, 0xfc, 0x99, 0xb9, 0x9b, 0xf1, 0xe7, 0x20, 0xba, 0x3b, 0x7a, 0xff, 0xbe, 0x96,
+	0x1b, 0x48, 0x4f, 0xf5, 0x87, 0x77, 0x0a, 0x6f, 0x7a, 0xdf, 0x8f, 0x24, 0x64, 0x83, 0xbc, 0x0c,
+	0xfe, 0x7b, 0xae, 0xf4, 0x66, 0x92, 0xbc, 0x99, 0xc1, 0x2f, 0x20, 0x15, 0x2c, 0x79, 0xe8, 0xd1,
+	0xad, 0xa4, 0x62, 0x5f, 0x85, 0xf7, 0x80, 0x6f, 0xaa, 0x9a, 0x95, 0xef, 0xbf, 0xfa, 0x57, 0x2e,
+	0xf4, 0x6a, 0x91, 0x93, 0xbe, 0x5e, 0xe4, 0xa4, 0x7f, 0x2e, 0x72, 0xd2, 0xef, 0x5e, 0xe7, 0x42,
+	0x5f, 0xbf, 0xce, 0x85, 0xfe, 0xf1, 0x3a, 0x17, 0xfa, 0x25, 0x1b, 0x47, 0xe8, 0x34, 0xe2, 0x5e,
+	0xc4, 0x99, 0xad, 0x8f, 0xff, 0x3f, 0x00, 0x59, 0xf0, 0xc4, 0x3e, 0x6a, 0x14, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1288,6 +1361,8 @@ type StorageClient interface {
 	ReadFilter(ctx context.Context, in *ReadFilterRequest, opts ...grpc.CallOption) (Storage_ReadFilterClient, error)
 	// ReadGroup performs a group operation at storage
 	ReadGroup(ctx context.Context, in *ReadGroupRequest, opts ...grpc.CallOption) (Storage_ReadGroupClient, error)
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error)
 	// TagKeys performs a read operation for tag keys
 	TagKeys(ctx context.Context, in *TagKeysRequest, opts ...grpc.CallOption) (Storage_TagKeysClient, error)
 	// TagValues performs a read operation for tag values
@@ -1368,8 +1443,40 @@ func (x *storageReadGroupClient) Recv() (*ReadResponse, error) {
 	return m, nil
 }
 
+func (c *storageClient) ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/influxdata.platform.storage.Storage/ReadWindowAggregate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReadWindowAggregateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReadWindowAggregateClient interface {
+	Recv() (*ReadResponse, error)
+	grpc.ClientStream
+}
+
+type storageReadWindowAggregateClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReadWindowAggregateClient) Recv() (*ReadResponse, error) {
+	m := new(ReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *storageClient) TagKeys(ctx context.Context, in *TagKeysRequest, opts ...grpc.CallOption) (Storage_TagKeysClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/influxdata.platform.storage.Storage/TagKeys", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/influxdata.platform.storage.Storage/TagKeys", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1401,7 +1508,7 @@ func (x *storageTagKeysClient) Recv() (*StringValuesResponse, error) {
 }
 
 func (c *storageClient) TagValues(ctx context.Context, in *TagValuesRequest, opts ...grpc.CallOption) (Storage_TagValuesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[3], "/influxdata.platform.storage.Storage/TagValues", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[4], "/influxdata.platform.storage.Storage/TagValues", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1447,6 +1554,8 @@ type StorageServer interface {
 	ReadFilter(*ReadFilterRequest, Storage_ReadFilterServer) error
 	// ReadGroup performs a group operation at storage
 	ReadGroup(*ReadGroupRequest, Storage_ReadGroupServer) error
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(*ReadWindowAggregateRequest, Storage_ReadWindowAggregateServer) error
 	// TagKeys performs a read operation for tag keys
 	TagKeys(*TagKeysRequest, Storage_TagKeysServer) error
 	// TagValues performs a read operation for tag values
@@ -1501,6 +1610,27 @@ func (x *storageReadGroupServer) Send(m *ReadResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Storage_ReadWindowAggregate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadWindowAggregateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ReadWindowAggregate(m, &storageReadWindowAggregateServer{stream})
+}
+
+type Storage_ReadWindowAggregateServer interface {
+	Send(*ReadResponse) error
+	grpc.ServerStream
+}
+
+type storageReadWindowAggregateServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageReadWindowAggregateServer) Send(m *ReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Storage_TagKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TagKeysRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1581,6 +1711,11 @@ var _Storage_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Storage_ReadGroup_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReadWindowAggregate",
+			Handler:       _Storage_ReadWindowAggregate_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TagKeys",
 			Handler:       _Storage_TagKeys_Handler,
@@ -1723,6 +1858,77 @@ func (m *ReadGroupRequest) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *ReadWindowAggregateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadWindowAggregateRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.ReadSource.Size()))
+		n8, err := m.ReadSource.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
+	n9, err := m.Range.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n9
+	if m.Predicate != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
+		n10, err := m.Predicate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.WindowEvery != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowEvery))
+	}
+	if m.Aggregate != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Aggregate.Size()))
+		n11, err := m.Aggregate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.CreateEmpty {
+		dAtA[i] = 0x30
+		i++
+		if m.CreateEmpty {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
 func (m *Aggregate) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1822,11 +2028,11 @@ func (m *ReadResponse_Frame) MarshalTo(dAtA []byte) (int, error) {
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn8, err := m.Data.MarshalTo(dAtA[i:])
+		nn12, err := m.Data.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += nn8
+		i += nn12
 	}
 	return i, nil
 }
@@ -1837,11 +2043,11 @@ func (m *ReadResponse_Frame_Series) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Series.Size()))
-		n9, err := m.Series.MarshalTo(dAtA[i:])
+		n13, err := m.Series.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n13
 	}
 	return i, nil
 }
@@ -1851,11 +2057,11 @@ func (m *ReadResponse_Frame_FloatPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.FloatPoints.Size()))
-		n10, err := m.FloatPoints.MarshalTo(dAtA[i:])
+		n14, err := m.FloatPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n10
+		i += n14
 	}
 	return i, nil
 }
@@ -1865,11 +2071,11 @@ func (m *ReadResponse_Frame_IntegerPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.IntegerPoints.Size()))
-		n11, err := m.IntegerPoints.MarshalTo(dAtA[i:])
+		n15, err := m.IntegerPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n11
+		i += n15
 	}
 	return i, nil
 }
@@ -1879,11 +2085,11 @@ func (m *ReadResponse_Frame_UnsignedPoints) MarshalTo(dAtA []byte) (int, error)
 		dAtA[i] = 0x22
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.UnsignedPoints.Size()))
-		n12, err := m.UnsignedPoints.MarshalTo(dAtA[i:])
+		n16, err := m.UnsignedPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n16
 	}
 	return i, nil
 }
@@ -1893,11 +2099,11 @@ func (m *ReadResponse_Frame_BooleanPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x2a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.BooleanPoints.Size()))
-		n13, err := m.BooleanPoints.MarshalTo(dAtA[i:])
+		n17, err := m.BooleanPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n13
+		i += n17
 	}
 	return i, nil
 }
@@ -1907,11 +2113,11 @@ func (m *ReadResponse_Frame_StringPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x32
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.StringPoints.Size()))
-		n14, err := m.StringPoints.MarshalTo(dAtA[i:])
+		n18, err := m.StringPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n14
+		i += n18
 	}
 	return i, nil
 }
@@ -1921,11 +2127,11 @@ func (m *ReadResponse_Frame_Group) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x3a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Group.Size()))
-		n15, err := m.Group.MarshalTo(dAtA[i:])
+		n19, err := m.Group.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n15
+		i += n19
 	}
 	return i, nil
 }
@@ -2027,8 +2233,8 @@ func (m *ReadResponse_FloatPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(len(m.Values)*8))
 		for _, num := range m.Values {
-			f16 := math.Float64bits(float64(num))
-			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f16))
+			f20 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f20))
 			i += 8
 		}
 	}
@@ -2060,22 +2266,22 @@ func (m *ReadResponse_IntegerPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		}
 	}
 	if len(m.Values) > 0 {
-		dAtA18 := make([]byte, len(m.Values)*10)
-		var j17 int
+		dAtA22 := make([]byte, len(m.Values)*10)
+		var j21 int
 		for _, num1 := range m.Values {
 			num := uint64(num1)
 			for num >= 1<<7 {
-				dAtA18[j17] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA22[j21] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j17++
+				j21++
 			}
-			dAtA18[j17] = uint8(num)
-			j17++
+			dAtA22[j21] = uint8(num)
+			j21++
 		}
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintStorageCommon(dAtA, i, uint64(j17))
-		i += copy(dAtA[i:], dAtA18[:j17])
+		i = encodeVarintStorageCommon(dAtA, i, uint64(j21))
+		i += copy(dAtA[i:], dAtA22[:j21])
 	}
 	return i, nil
 }
@@ -2105,21 +2311,21 @@ func (m *ReadResponse_UnsignedPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		}
 	}
 	if len(m.Values) > 0 {
-		dAtA20 := make([]byte, len(m.Values)*10)
-		var j19 int
+		dAtA24 := make([]byte, len(m.Values)*10)
+		var j23 int
 		for _, num := range m.Values {
 			for num >= 1<<7 {
-				dAtA20[j19] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA24[j23] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j19++
+				j23++
 			}
-			dAtA20[j19] = uint8(num)
-			j19++
+			dAtA24[j23] = uint8(num)
+			j23++
 		}
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintStorageCommon(dAtA, i, uint64(j19))
-		i += copy(dAtA[i:], dAtA20[:j19])
+		i = encodeVarintStorageCommon(dAtA, i, uint64(j23))
+		i += copy(dAtA[i:], dAtA24[:j23])
 	}
 	return i, nil
 }
@@ -2288,29 +2494,29 @@ func (m *TagKeysRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.TagsSource.Size()))
-		n21, err := m.TagsSource.MarshalTo(dAtA[i:])
+		n25, err := m.TagsSource.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n21
+		i += n25
 	}
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
-	n22, err := m.Range.MarshalTo(dAtA[i:])
+	n26, err := m.Range.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n22
+	i += n26
 	if m.Predicate != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
-		n23, err := m.Predicate.MarshalTo(dAtA[i:])
+		n27, err := m.Predicate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n23
+		i += n27
 	}
 	return i, nil
 }
@@ -2334,29 +2540,29 @@ func (m *TagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.TagsSource.Size()))
-		n24, err := m.TagsSource.MarshalTo(dAtA[i:])
+		n28, err := m.TagsSource.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n24
+		i += n28
 	}
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
-	n25, err := m.Range.MarshalTo(dAtA[i:])
+	n29, err := m.Range.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n25
+	i += n29
 	if m.Predicate != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
-		n26, err := m.Predicate.MarshalTo(dAtA[i:])
+		n30, err := m.Predicate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n26
+		i += n30
 	}
 	if len(m.TagKey) > 0 {
 		dAtA[i] = 0x22
@@ -2456,6 +2662,35 @@ func (m *ReadGroupRequest) Size() (n int) {
 	return n
 }
 
+func (m *ReadWindowAggregateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		l = m.ReadSource.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	l = m.Range.Size()
+	n += 1 + l + sovStorageCommon(uint64(l))
+	if m.Predicate != nil {
+		l = m.Predicate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	if m.WindowEvery != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowEvery))
+	}
+	if m.Aggregate != nil {
+		l = m.Aggregate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	if m.CreateEmpty {
+		n += 2
+	}
+	return n
+}
+
 func (m *Aggregate) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3236,6 +3471,239 @@ func (m *ReadGroupRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ReadWindowAggregateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorageCommon
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadSource", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReadSource == nil {
+				m.ReadSource = &types.Any{}
+			}
+			if err := m.ReadSource.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Range.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Predicate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Predicate == nil {
+				m.Predicate = &Predicate{}
+			}
+			if err := m.Predicate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowEvery", wireType)
+			}
+			m.WindowEvery = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowEvery |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Aggregate == nil {
+				m.Aggregate = &Aggregate{}
+			}
+			if err := m.Aggregate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateEmpty", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CreateEmpty = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorageCommon(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Aggregate) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
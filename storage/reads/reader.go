@@ -2,6 +2,7 @@ package reads
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/stdlib/universe"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
@@ -51,6 +53,15 @@ func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec
 	}, nil
 }
 
+func (r *storeReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &windowAggregateIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if spec.Predicate != nil {
@@ -363,6 +374,279 @@ READ:
 	return rs.Err()
 }
 
+type windowAggregateIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadWindowAggregateSpec
+	stats cursors.CursorStats
+	alloc *memory.Allocator
+}
+
+func (wai *windowAggregateIterator) Statistics() cursors.CursorStats { return wai.stats }
+
+func (wai *windowAggregateIterator) Do(f func(flux.Table) error) error {
+	src := wai.s.GetSource(
+		uint64(wai.spec.OrganizationID),
+		uint64(wai.spec.BucketID),
+	)
+
+	// Setup read request
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return err
+	}
+
+	var predicate *datatypes.Predicate
+	if wai.spec.Predicate != nil {
+		p, err := toStoragePredicate(wai.spec.Predicate)
+		if err != nil {
+			return err
+		}
+		predicate = p
+	}
+
+	var req datatypes.ReadWindowAggregateRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = int64(wai.spec.Bounds.Start)
+	req.Range.End = int64(wai.spec.Bounds.Stop)
+
+	req.WindowEvery = wai.spec.WindowEvery
+	req.CreateEmpty = wai.spec.CreateEmpty
+
+	if agg, err := determineAggregateMethod(string(wai.spec.Aggregate)); err != nil {
+		return err
+	} else if agg == datatypes.AggregateTypeNone {
+		return errors.New("missing window aggregate")
+	} else {
+		req.Aggregate = &datatypes.Aggregate{Type: agg}
+	}
+
+	rs, err := wai.s.ReadWindowAggregate(wai.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+	return wai.handleRead(f, rs)
+}
+
+func (wai *windowAggregateIterator) handleRead(f func(flux.Table) error, rs ResultSet) error {
+	defer rs.Close()
+
+	w := window{
+		every: wai.spec.WindowEvery,
+		start: int64(wai.spec.Bounds.Start),
+		end:   int64(wai.spec.Bounds.Stop),
+	}
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		points, err := readWindowPoints(cur)
+		stats := cur.Stats()
+		wai.stats.ScannedValues += stats.ScannedValues
+		wai.stats.ScannedBytes += stats.ScannedBytes
+		cur.Close()
+		if err != nil {
+			return err
+		}
+
+		if err := wai.produceTables(f, w, rs.Tags(), points); err != nil {
+			return err
+		}
+
+		if err := wai.ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// windowPoints are the aggregates of the windows of a series.
+type windowPoints struct {
+	typ        flux.ColType
+	timestamps []int64
+	values     []values.Value
+}
+
+// readWindowPoints reads all of the aggregates produced by cur.
+func readWindowPoints(cur cursors.Cursor) (*windowPoints, error) {
+	p := &windowPoints{}
+	switch cur := cur.(type) {
+	case cursors.IntegerArrayCursor:
+		p.typ = flux.TInt
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.timestamps = append(p.timestamps, a.Timestamps...)
+			for _, v := range a.Values {
+				p.values = append(p.values, values.NewInt(v))
+			}
+		}
+	case cursors.FloatArrayCursor:
+		p.typ = flux.TFloat
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.timestamps = append(p.timestamps, a.Timestamps...)
+			for _, v := range a.Values {
+				p.values = append(p.values, values.NewFloat(v))
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		p.typ = flux.TUInt
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.timestamps = append(p.timestamps, a.Timestamps...)
+			for _, v := range a.Values {
+				p.values = append(p.values, values.NewUInt(v))
+			}
+		}
+	case cursors.BooleanArrayCursor:
+		p.typ = flux.TBool
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.timestamps = append(p.timestamps, a.Timestamps...)
+			for _, v := range a.Values {
+				p.values = append(p.values, values.NewBool(v))
+			}
+		}
+	case cursors.StringArrayCursor:
+		p.typ = flux.TString
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			p.timestamps = append(p.timestamps, a.Timestamps...)
+			for _, v := range a.Values {
+				p.values = append(p.values, values.NewString(v))
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+	return p, cur.Err()
+}
+
+// produceTables produces the tables of a series from its window aggregates.
+// When the windows are not merged, a table is produced for each window,
+// otherwise a single table is produced using the window bound selected by
+// the TimeColumn of the spec as the _time of each aggregate.
+func (wai *windowAggregateIterator) produceTables(f func(flux.Table) error, w window, tags models.Tags, points *windowPoints) error {
+	// The aggregates, as opposed to the selectors, have a null value
+	// for empty windows and drop the _time column.
+	var isAggregate bool
+	switch wai.spec.Aggregate {
+	case universe.MeanKind, universe.SumKind, universe.CountKind:
+		isAggregate = true
+	}
+
+	var b *execute.ColListTableBuilder
+	newBuilder := func(bnds execute.Bounds) error {
+		b = execute.NewColListTableBuilder(defaultGroupKeyForSeries(tags, bnds), wai.alloc)
+		cols, _ := determineTableColsForSeries(tags, points.typ)
+		for j, col := range cols {
+			if j == timeColIdx && isAggregate && wai.spec.TimeColumn == "" {
+				continue
+			}
+			if _, err := b.AddCol(col); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	appendRow := func(bnds execute.Bounds, ts int64, v values.Value) error {
+		for j, col := range b.Cols() {
+			var err error
+			switch col.Label {
+			case execute.DefaultStartColLabel:
+				err = b.AppendTime(j, bnds.Start)
+			case execute.DefaultStopColLabel:
+				err = b.AppendTime(j, bnds.Stop)
+			case execute.DefaultTimeColLabel:
+				err = b.AppendTime(j, execute.Time(ts))
+			case execute.DefaultValueColLabel:
+				if v == nil {
+					err = b.AppendNil(j)
+				} else {
+					err = b.AppendValue(j, v)
+				}
+			default:
+				err = b.AppendString(j, string(tags.Get([]byte(col.Label))))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	produce := func() error {
+		tbl, err := b.Table()
+		if err != nil {
+			return err
+		}
+		b.ClearData()
+		return f(tbl)
+	}
+
+	windowBounds := func(ws int64) execute.Bounds {
+		bnds := execute.Bounds{
+			Start: execute.Time(ws),
+			Stop:  execute.Time(ws + w.every),
+		}
+		return wai.spec.Bounds.Intersect(bnds)
+	}
+
+	if wai.spec.TimeColumn != "" {
+		if err := newBuilder(wai.spec.Bounds); err != nil {
+			return err
+		}
+	}
+
+	i := 0
+	for ws := w.windowStart(w.start); ws < w.end; ws += w.every {
+		var v values.Value
+		ts := int64(0)
+		if i < len(points.timestamps) && w.windowStart(points.timestamps[i]) == ws {
+			ts, v = points.timestamps[i], points.values[i]
+			i++
+		} else if !wai.spec.CreateEmpty {
+			if i == len(points.timestamps) {
+				break
+			}
+			// Skip to the window of the next aggregate.
+			ws = w.windowStart(points.timestamps[i]) - w.every
+			continue
+		}
+
+		bnds := windowBounds(ws)
+		if wai.spec.TimeColumn == "" {
+			if err := newBuilder(bnds); err != nil {
+				return err
+			}
+		}
+		if v != nil || isAggregate {
+			rowBounds := bnds
+			switch wai.spec.TimeColumn {
+			case execute.DefaultStartColLabel:
+				ts, rowBounds = int64(bnds.Start), wai.spec.Bounds
+			case execute.DefaultStopColLabel:
+				ts, rowBounds = int64(bnds.Stop), wai.spec.Bounds
+			}
+			if err := appendRow(rowBounds, ts, v); err != nil {
+				return err
+			}
+		}
+		if wai.spec.TimeColumn == "" {
+			if err := produce(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if wai.spec.TimeColumn != "" && b.NRows() > 0 {
+		return produce()
+	}
+	return nil
+}
+
 func determineAggregateMethod(agg string) (datatypes.Aggregate_AggregateType, error) {
 	if agg == "" {
 		return datatypes.AggregateTypeNone, nil
@@ -0,0 +1,254 @@
+package reads_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+func TestStoreReader_ReadWindowAggregate(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  influxdb.ReadWindowAggregateSpec
+		array *cursors.FloatArray
+		exp   []*executetest.Table
+	}{
+		{
+			name: "selector",
+			spec: influxdb.ReadWindowAggregateSpec{
+				WindowEvery: 10,
+				Aggregate:   universe.FirstKind,
+			},
+			array: &cursors.FloatArray{
+				Timestamps: []int64{12, 31},
+				Values:     []float64{4, 2},
+			},
+			exp: []*executetest.Table{
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), execute.Time(20), execute.Time(12), 4.0, "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(30), execute.Time(35), execute.Time(31), 2.0, "a"},
+					},
+				},
+			},
+		},
+		{
+			name: "aggregate create empty",
+			spec: influxdb.ReadWindowAggregateSpec{
+				WindowEvery: 10,
+				Aggregate:   universe.MeanKind,
+				CreateEmpty: true,
+			},
+			array: &cursors.FloatArray{
+				Timestamps: []int64{10},
+				Values:     []float64{1.5},
+			},
+			exp: []*executetest.Table{
+				{
+					KeyCols:   []string{"_start", "_stop", "host"},
+					KeyValues: []interface{}{execute.Time(5), execute.Time(10), "a"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), execute.Time(10), nil, "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), execute.Time(20), 1.5, "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(20), execute.Time(30), nil, "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(30), execute.Time(35), nil, "a"},
+					},
+				},
+			},
+		},
+		{
+			name: "aggregate window",
+			spec: influxdb.ReadWindowAggregateSpec{
+				WindowEvery: 10,
+				Aggregate:   universe.MeanKind,
+				CreateEmpty: true,
+				TimeColumn:  execute.DefaultStopColLabel,
+			},
+			array: &cursors.FloatArray{
+				Timestamps: []int64{10, 30},
+				Values:     []float64{1.5, 3},
+			},
+			exp: []*executetest.Table{
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), execute.Time(35), execute.Time(10), nil, "a"},
+						{execute.Time(5), execute.Time(35), execute.Time(20), 1.5, "a"},
+						{execute.Time(5), execute.Time(35), execute.Time(30), nil, "a"},
+						{execute.Time(5), execute.Time(35), execute.Time(35), 3.0, "a"},
+					},
+				},
+			},
+		},
+		{
+			name: "aggregate window selector",
+			spec: influxdb.ReadWindowAggregateSpec{
+				WindowEvery: 10,
+				Aggregate:   universe.MaxKind,
+				CreateEmpty: true,
+				TimeColumn:  execute.DefaultStartColLabel,
+			},
+			array: &cursors.FloatArray{
+				Timestamps: []int64{7, 24},
+				Values:     []float64{8, 6},
+			},
+			exp: []*executetest.Table{
+				{
+					KeyCols: []string{"_start", "_stop", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), execute.Time(35), execute.Time(5), 8.0, "a"},
+						{execute.Time(5), execute.Time(35), execute.Time(20), 6.0, "a"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *datatypes.ReadWindowAggregateRequest
+			store := mock.NewStoreReader()
+			store.ReadWindowAggregateFunc = func(ctx context.Context, r *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+				req = r
+
+				done := false
+				rs := mock.NewResultSet()
+				rs.NextFunc = func() bool {
+					next := !done
+					done = true
+					return next
+				}
+				rs.CursorFunc = func() cursors.Cursor {
+					arrays := []*cursors.FloatArray{tt.array}
+					cur := mock.NewFloatArrayCursor()
+					cur.NextFunc = func() *cursors.FloatArray {
+						if len(arrays) == 0 {
+							return &cursors.FloatArray{}
+						}
+						a := arrays[0]
+						arrays = arrays[1:]
+						return a
+					}
+					return cur
+				}
+				rs.TagsFunc = func() models.Tags {
+					return models.NewTags(map[string]string{"host": "a"})
+				}
+				return rs, nil
+			}
+
+			spec := tt.spec
+			spec.Bounds = execute.Bounds{Start: 5, Stop: 35}
+			tables, err := reads.NewReader(store).ReadWindowAggregate(context.Background(), spec, &memory.Allocator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*executetest.Table
+			if err := tables.Do(func(tbl flux.Table) error {
+				tb, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				got = append(got, tb)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if req.WindowEvery != spec.WindowEvery || req.CreateEmpty != spec.CreateEmpty {
+				t.Errorf("unexpected window in request: %v", req)
+			}
+
+			executetest.NormalizeTables(got)
+			executetest.NormalizeTables(tt.exp)
+			if !cmp.Equal(got, tt.exp) {
+				t.Errorf("unexpected tables; -got/+exp\n%s", cmp.Diff(got, tt.exp))
+			}
+		})
+	}
+}
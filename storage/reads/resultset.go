@@ -12,14 +12,16 @@ import (
 type multiShardCursors interface {
 	createCursor(row SeriesRow) cursors.Cursor
 	newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor
+	newWindowAggregateCursor(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, row SeriesRow) cursors.Cursor
 }
 
 type resultSet struct {
-	ctx context.Context
-	agg *datatypes.Aggregate
-	cur SeriesCursor
-	row SeriesRow
-	mb  multiShardCursors
+	ctx    context.Context
+	agg    *datatypes.Aggregate
+	window *datatypes.ReadWindowAggregateRequest
+	cur    SeriesCursor
+	row    SeriesRow
+	mb     multiShardCursors
 }
 
 func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest, cur SeriesCursor) ResultSet {
@@ -30,6 +32,17 @@ func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest,
 	}
 }
 
+// NewWindowAggregateResultSet returns a ResultSet whose cursors produce the
+// aggregate of req for each window of every series of cur.
+func NewWindowAggregateResultSet(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, cur SeriesCursor) ResultSet {
+	return &resultSet{
+		ctx:    ctx,
+		window: req,
+		cur:    cur,
+		mb:     newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
+	}
+}
+
 func (r *resultSet) Err() error { return nil }
 
 // Close closes the result set. Close is idempotent.
@@ -58,6 +71,9 @@ func (r *resultSet) Next() bool {
 }
 
 func (r *resultSet) Cursor() cursors.Cursor {
+	if r.window != nil {
+		return r.mb.newWindowAggregateCursor(r.ctx, r.window, r.row)
+	}
 	cur := r.mb.createCursor(r.row)
 	if r.agg != nil {
 		cur = r.mb.newAggregateCursor(r.ctx, r.agg, cur)
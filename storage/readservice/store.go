@@ -77,6 +77,34 @@ func (s *store) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest)
 	return reads.NewGroupResultSet(ctx, req, newCursor), nil
 }
 
+func (s *store) ReadWindowAggregate(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+	if req.ReadSource == nil {
+		return nil, errors.New("missing read source")
+	}
+	if req.WindowEvery <= 0 {
+		return nil, errors.New("window every must be greater than zero")
+	}
+	if req.Aggregate == nil {
+		return nil, errors.New("missing aggregate")
+	}
+
+	source, err := getReadSource(*req.ReadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	var cur reads.SeriesCursor
+	if ic, err := newIndexSeriesCursor(ctx, &source, req.Predicate, s.viewer); err != nil {
+		return nil, err
+	} else if ic == nil {
+		return nil, nil
+	} else {
+		cur = ic
+	}
+
+	return reads.NewWindowAggregateResultSet(ctx, req, cur), nil
+}
+
 func (s *store) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// bucketSeriesLimits returns the series cardinality limits of the buckets written
+// to by the collection, keyed by the encoded org and bucket name. Buckets without
+// a limit are not included.
+func (e *Engine) bucketSeriesLimits(ctx context.Context, collection *tsdb.SeriesCollection) (map[string]int64, error) {
+	var limits map[string]int64
+	seen := make(map[string]struct{})
+	for iter := collection.Iterator(); iter.Next(); {
+		name := iter.Name()
+		if _, ok := seen[string(name)]; ok {
+			continue
+		}
+		seen[string(name)] = struct{}{}
+
+		// Measurements are named by the encoded org and bucket IDs.
+		if len(name) != 16 {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+
+		limit, err := e.bucketSeriesLimit(ctx, bucketID)
+		if err != nil {
+			return nil, err
+		} else if limit <= 0 {
+			continue
+		}
+
+		if limits == nil {
+			limits = make(map[string]int64)
+		}
+		limits[string(name)] = limit
+	}
+	return limits, nil
+}
+
+// bucketSeriesLimit returns the series cardinality limit of the bucket, or zero
+// if it has none. Limits are cached until InvalidateBucket is called for the
+// bucket.
+func (e *Engine) bucketSeriesLimit(ctx context.Context, bucketID influxdb.ID) (int64, error) {
+	e.seriesLimitsMu.RLock()
+	limit, ok := e.seriesLimits[bucketID]
+	gen := e.seriesLimitsGen
+	e.seriesLimitsMu.RUnlock()
+	if ok {
+		return limit, nil
+	}
+
+	// The lookup is made without the lock so that a slow lookup does not block
+	// writes to other buckets.
+	buckets, _, err := e.bucketFinder.FindBuckets(ctx, influxdb.BucketFilter{ID: &bucketID})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	} else if len(buckets) == 0 {
+		return 0, nil
+	}
+	limit = buckets[0].MaxSeriesCardinality
+
+	// A limit found before an invalidation may be stale, so it is only cached if
+	// no bucket was invalidated during the lookup.
+	e.seriesLimitsMu.Lock()
+	defer e.seriesLimitsMu.Unlock()
+	if e.seriesLimitsGen == gen {
+		if e.seriesLimits == nil {
+			e.seriesLimits = make(map[influxdb.ID]int64)
+		}
+		e.seriesLimits[bucketID] = limit
+	}
+	return limit, nil
+}
+
+// limitSeriesCardinality drops the points in the collection that would create new
+// series in a bucket that already contains its maximum number of series. Points
+// for existing series are always kept.
+//
+// limitSeriesCardinality must be called under seriesLimitMu, so that concurrent
+// writes cannot together exceed a limit.
+func (e *Engine) limitSeriesCardinality(collection *tsdb.SeriesCollection, limits map[string]int64) error {
+	type bucketSeries struct {
+		ids     *tsdb.SeriesIDSet
+		n       int64
+		created map[string]struct{}
+	}
+	buckets := make(map[string]*bucketSeries, len(limits))
+
+	var buf []byte
+	j := 0
+	for iter := collection.Iterator(); iter.Next(); {
+		name := iter.Name()
+		limit, ok := limits[string(name)]
+		if !ok {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		b := buckets[string(name)]
+		if b == nil {
+			ids, err := e.index.MeasurementSeriesIDSet(name)
+			if err != nil {
+				return err
+			}
+			b = &bucketSeries{
+				ids:     ids,
+				n:       int64(ids.Cardinality()),
+				created: make(map[string]struct{}),
+			}
+			buckets[string(name)] = b
+		}
+
+		key := iter.Key()
+		id := e.sfile.SeriesID(name, iter.Tags(), buf)
+		_, created := b.created[string(key)]
+		switch {
+		case !id.IsZero() && b.ids.Contains(id), created:
+			// The series exists, or will be created by an earlier point.
+		case b.n < limit:
+			b.n++
+			b.created[string(key)] = struct{}{}
+		default:
+			_, bucketID := tsdb.DecodeNameSlice(name)
+			collection.Drop(iter.Index(), fmt.Sprintf("max series cardinality of %d exceeded for bucket %s: dropped new series %s",
+				limit, bucketID, seriesString(iter.Tags())))
+			continue
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+	return nil
+}
+
+// seriesString returns a human readable representation of the series with the
+// provided tags, such as "cpu,host=a value", where value is the field key.
+func seriesString(tags models.Tags) string {
+	var measurement, field []byte
+	other := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case models.MeasurementTagKey:
+			measurement = t.Value
+		case models.FieldKeyTagKey:
+			field = t.Value
+		default:
+			other = append(other, t)
+		}
+	}
+	return fmt.Sprintf("%s %s", models.MakeKey(measurement, other), field)
+}
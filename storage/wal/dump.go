@@ -9,6 +9,7 @@ import (
 	"sort"
 	"text/tabwriter"
 
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/value"
@@ -27,6 +28,9 @@ type Dump struct {
 
 	// Whether or not to check for duplicate/out of order entries
 	FindDuplicates bool
+
+	// The keyring used to decrypt encrypted files, if any
+	Keyring *encryption.Keyring
 }
 
 type DumpReport struct {
@@ -128,7 +132,7 @@ func (w *Dump) process(path string, stdout, stderr io.Writer) (*DumpReport, erro
 		return nil, err
 	}
 	defer f.Close()
-	r := NewWALSegmentReader(f)
+	r := NewWALSegmentReader(f, WithSegmentKeyring(w.Keyring))
 
 	// Iterate over the WAL entries
 	for r.Next() {
@@ -1,17 +1,20 @@
 package wal
 
 import (
+	"fmt"
 	"os"
 	"sort"
 
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"go.uber.org/zap"
 )
 
 // WALReader helps one read out the WAL into entries.
 type WALReader struct {
-	files  []string
-	logger *zap.Logger
-	r      *WALSegmentReader
+	files   []string
+	logger  *zap.Logger
+	keyring *encryption.Keyring
+	r       *WALSegmentReader
 }
 
 // NewWALReader constructs a WALReader over the given set of files.
@@ -27,10 +30,14 @@ func NewWALReader(files []string) *WALReader {
 // WithLogger sets the logger for the WALReader.
 func (r *WALReader) WithLogger(logger *zap.Logger) { r.logger = logger }
 
+// WithKeyring sets the keyring used to decrypt encrypted segment files.
+func (r *WALReader) WithKeyring(k *encryption.Keyring) { r.keyring = k }
+
 // Read calls the callback with every entry in the WAL files. If, during
 // reading of a segment file, corruption is encountered, that segment file
 // is truncated up to and including the last valid byte, and processing
-// continues with the next segment file.
+// continues with the next segment file. A segment file encrypted with a key
+// that is not in the keyring is not truncated, and an error is returned.
 func (r *WALReader) Read(cb func(WALEntry) error) error {
 	for _, file := range r.files {
 		if err := r.readFile(file, cb); err != nil {
@@ -60,7 +67,7 @@ func (r *WALReader) readFile(file string, cb func(WALEntry) error) error {
 	}
 
 	if r.r == nil {
-		r.r = NewWALSegmentReader(f)
+		r.r = NewWALSegmentReader(f, WithSegmentKeyring(r.keyring))
 	} else {
 		r.r.Reset(f)
 	}
@@ -68,7 +75,11 @@ func (r *WALReader) readFile(file string, cb func(WALEntry) error) error {
 
 	for r.r.Next() {
 		entry, err := r.r.Read()
-		if err != nil {
+		if isSegmentHeaderError(err) {
+			// The segment is intact but cannot be read, as its key is missing
+			// or its format is not supported by this build.
+			return fmt.Errorf("%s: %w", file, err)
+		} else if err != nil {
 			n := r.r.Count()
 			r.logger.Info("File corrupt", zap.Error(err), zap.String("path", file), zap.Int64("pos", n))
 			if err := f.Truncate(n); err != nil {
@@ -0,0 +1,231 @@
+package wal
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/pkg/encryption"
+)
+
+// Compression is the compression of the entries of a WAL segment.
+type Compression byte
+
+const (
+	// CompressionSnappy compresses entries with snappy.
+	CompressionSnappy Compression = 0
+
+	// CompressionZstd compresses entries with zstd.
+	CompressionZstd Compression = 1
+)
+
+// String returns the name of the compression.
+func (c Compression) String() string {
+	switch c {
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// ParseCompression returns the compression named s. An empty name is snappy, the
+// compression of segments written by earlier versions.
+func ParseCompression(s string) (Compression, error) {
+	switch s {
+	case "", "snappy":
+		return CompressionSnappy, nil
+	case "zstd":
+		return CompressionZstd, nil
+	default:
+		return 0, fmt.Errorf("unknown wal compression %q, expected \"snappy\" or \"zstd\"", s)
+	}
+}
+
+// maxEncodedLen returns the maximum length of the encoding of n bytes.
+func (c Compression) maxEncodedLen(n int) int {
+	if c == CompressionZstd {
+		return zstdMaxEncodedLen(n)
+	}
+	return snappy.MaxEncodedLen(n)
+}
+
+// encode returns the encoding of src, using dst if it is large enough.
+func (c Compression) encode(dst, src []byte) ([]byte, error) {
+	switch c {
+	case CompressionSnappy:
+		return snappy.Encode(dst, src), nil
+	case CompressionZstd:
+		return zstdEncode(dst, src)
+	default:
+		return nil, fmt.Errorf("unknown wal compression: %v", c)
+	}
+}
+
+// Segments are written in their original format, a sequence of snappy compressed
+// entries, unless they are compressed with zstd or encrypted. These segments
+// start with a header recording how their entries are written:
+//
+//	┌───────────┬─────────┬─────────────┬─────────────┬─────────────┐
+//	│   Magic   │ Version │ Compression │ Key ID Len  │   Key ID    │
+//	│  4 bytes  │ 1 byte  │   1 byte    │   1 byte    │   N bytes   │
+//	└───────────┴─────────┴─────────────┴─────────────┴─────────────┘
+//
+// The entries of an encrypted segment, one with a key ID, are sealed with AES-GCM
+// using the key with that ID. Each entry is prefixed with its random nonce, and its
+// type is authenticated along with its compressed data.
+//
+// The original format starts with an entry type, which is never zero, so it is
+// told apart from the header by the first byte of the magic.
+var segmentMagic = [4]byte{0x00, 'W', 'A', 'L'}
+
+const (
+	segmentVersion = 1
+
+	// segmentHeaderSize is the size of the header, excluding the key ID.
+	segmentHeaderSize = len(segmentMagic) + 3
+)
+
+// segmentFormat describes how the entries of a segment are written.
+type segmentFormat struct {
+	compression Compression
+	keyID       string // empty when the segment is not encrypted
+}
+
+// hasHeader returns true if segments of the format start with a header.
+func (f segmentFormat) hasHeader() bool {
+	return f.compression != CompressionSnappy || f.keyID != ""
+}
+
+// appendHeader appends the header of segments of the format to b.
+func (f segmentFormat) appendHeader(b []byte) []byte {
+	b = append(b, segmentMagic[:]...)
+	b = append(b, segmentVersion, byte(f.compression), byte(len(f.keyID)))
+	return append(b, f.keyID...)
+}
+
+// readSegmentHeader reads the header of the segment read by r, returning the format
+// of the segment and the size of its header. Segments in the original format have
+// no header.
+func readSegmentHeader(r *bufio.Reader) (segmentFormat, int, error) {
+	magic, err := r.Peek(len(segmentMagic))
+	if err == io.EOF || (err == nil && string(magic) != string(segmentMagic[:])) {
+		return segmentFormat{}, 0, nil
+	} else if err != nil {
+		return segmentFormat{}, 0, err
+	}
+
+	var hdr [segmentHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return segmentFormat{}, 0, ErrWALCorrupt
+	}
+
+	version, compression, keyLen := hdr[4], Compression(hdr[5]), int(hdr[6])
+	if version != segmentVersion {
+		return segmentFormat{}, 0, fmt.Errorf("unsupported wal segment version: %d", version)
+	}
+
+	switch compression {
+	case CompressionSnappy, CompressionZstd:
+	default:
+		return segmentFormat{}, 0, fmt.Errorf("unknown wal compression: %v", compression)
+	}
+
+	keyID := make([]byte, keyLen)
+	if _, err := io.ReadFull(r, keyID); err != nil {
+		return segmentFormat{}, 0, ErrWALCorrupt
+	}
+
+	return segmentFormat{compression: compression, keyID: string(keyID)}, segmentHeaderSize + keyLen, nil
+}
+
+// segmentHeaderError is returned for a segment whose header cannot be used by the
+// reader, such as the header of a newer version, or of a segment encrypted with a
+// missing key. Unlike a corrupt entry, the segment is intact and must not be
+// truncated.
+type segmentHeaderError struct {
+	err error
+}
+
+func (e *segmentHeaderError) Error() string { return e.err.Error() }
+
+func (e *segmentHeaderError) Unwrap() error { return e.err }
+
+// isSegmentHeaderError returns true if err is a segmentHeaderError.
+func isSegmentHeaderError(err error) bool {
+	var e *segmentHeaderError
+	return errors.As(err, &e)
+}
+
+// SegmentOption configures a WALSegmentWriter or a WALSegmentReader.
+type SegmentOption func(*segmentOptions)
+
+type segmentOptions struct {
+	compression Compression
+	keyring     *encryption.Keyring
+}
+
+// WithSegmentCompression sets the compression of the entries written to a segment.
+// Readers use the compression recorded in the segment.
+func WithSegmentCompression(c Compression) SegmentOption {
+	return func(o *segmentOptions) {
+		o.compression = c
+	}
+}
+
+// WithSegmentKeyring sets the keyring of encrypted segments. Writers encrypt
+// entries with the active key of the keyring, and readers decrypt them with the
+// key recorded in the segment. A nil keyring disables encryption.
+func WithSegmentKeyring(k *encryption.Keyring) SegmentOption {
+	return func(o *segmentOptions) {
+		o.keyring = k
+	}
+}
+
+func newSegmentOptions(opts []SegmentOption) segmentOptions {
+	var o segmentOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// format returns the format of segments written with the options.
+func (o segmentOptions) format() segmentFormat {
+	f := segmentFormat{compression: o.compression}
+	if o.keyring != nil {
+		f.keyID = o.keyring.ActiveID()
+	}
+	return f
+}
+
+// sealEntry encrypts the data of an entry of type entryType, using buf if it is
+// large enough, and returns it prefixed with its nonce.
+func sealEntry(aead cipher.AEAD, buf []byte, entryType WalEntryType, data []byte) ([]byte, error) {
+	n := aead.NonceSize() + len(data) + aead.Overhead()
+	if cap(buf) < n {
+		buf = make([]byte, n)
+	}
+
+	nonce := buf[:aead.NonceSize()]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte{byte(entryType)}), nil
+}
+
+// openEntry decrypts, in place, the data of an entry of type entryType sealed by
+// sealEntry.
+func openEntry(aead cipher.AEAD, entryType byte, b []byte) ([]byte, error) {
+	ns := aead.NonceSize()
+	if len(b) < ns+aead.Overhead() {
+		return nil, ErrWALCorrupt
+	}
+	return aead.Open(b[ns:ns], b[:ns], b[ns:], []byte{entryType})
+}
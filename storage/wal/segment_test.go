@@ -0,0 +1,315 @@
+package wal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/encryption"
+	"github.com/influxdata/influxdb/tsdb/value"
+)
+
+func mustNewKeyring(keys ...byte) *encryption.Keyring {
+	var b [][]byte
+	for _, k := range keys {
+		b = append(b, bytes.Repeat([]byte{k}, encryption.KeySize))
+	}
+
+	k, err := encryption.NewKeyring(b...)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func mustMarshalEntryWith(entry WALEntry, c Compression) (WalEntryType, []byte) {
+	b, err := entry.Encode(make([]byte, 1024<<2))
+	if err != nil {
+		panic(err)
+	}
+
+	compressed, err := c.encode(nil, b)
+	if err != nil {
+		panic(err)
+	}
+	return entry.Type(), compressed
+}
+
+func TestWALSegment_Formats(t *testing.T) {
+	keyring := mustNewKeyring(1)
+	values := map[string][]value.Value{
+		"cpu,host=A#!~#value": []value.Value{value.NewValue(1, 1.1), value.NewValue(2, 2.2)},
+		"mem,host=B#!~#value": []value.Value{value.NewValue(1, int64(1))},
+	}
+
+	tests := []struct {
+		name string
+		opts []SegmentOption
+	}{
+		{name: "snappy"},
+		{name: "zstd", opts: []SegmentOption{WithSegmentCompression(CompressionZstd)}},
+		{name: "snappy encrypted", opts: []SegmentOption{WithSegmentKeyring(keyring)}},
+		{name: "zstd encrypted", opts: []SegmentOption{WithSegmentCompression(CompressionZstd), WithSegmentKeyring(keyring)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+			f := MustTempFile(dir)
+
+			w := NewWALSegmentWriter(f, tt.opts...)
+			for i := 0; i < 2; i++ {
+				if err := w.Write(mustMarshalEntryWith(&WriteWALEntry{Values: values}, w.Compression())); err != nil {
+					fatal(t, "write points", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				fatal(t, "flush", err)
+			}
+
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				fatal(t, "seek", err)
+			}
+
+			// Keys must not be stored as plaintext in encrypted segments.
+			b, err := ioutil.ReadAll(f)
+			if err != nil {
+				fatal(t, "read file", err)
+			}
+			if newSegmentOptions(tt.opts).keyring != nil && bytes.Contains(b, []byte("cpu,host=A")) {
+				t.Fatalf("unexpected plaintext in encrypted segment")
+			}
+
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				fatal(t, "seek", err)
+			}
+
+			r := NewWALSegmentReader(f, WithSegmentKeyring(keyring))
+			var n int
+			for r.Next() {
+				we, err := r.Read()
+				if err != nil {
+					fatal(t, "read entry", err)
+				}
+
+				e, ok := we.(*WriteWALEntry)
+				if !ok {
+					t.Fatalf("expected WriteWALEntry: got %#v", we)
+				}
+				for k, v := range e.Values {
+					for i, vv := range v {
+						if got, exp := vv.String(), values[k][i].String(); got != exp {
+							t.Fatalf("points mismatch: got %v, exp %v", got, exp)
+						}
+					}
+				}
+				n++
+			}
+
+			if got, exp := n, 2; got != exp {
+				t.Fatalf("entry count mismatch: got %d, exp %d", got, exp)
+			} else if got, exp := r.Count(), MustReadFileSize(f); got != exp {
+				t.Fatalf("wrong count of bytes read, got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
+
+func TestWALSegmentReader_MissingKey(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	w := NewWALSegmentWriter(f, WithSegmentKeyring(mustNewKeyring(1)))
+	entry := &WriteWALEntry{Values: map[string][]value.Value{"cpu": []value.Value{value.NewValue(1, 1.1)}}}
+	if err := w.Write(mustMarshalEntry(entry)); err != nil {
+		fatal(t, "write points", err)
+	} else if err := w.Flush(); err != nil {
+		fatal(t, "flush", err)
+	}
+
+	for _, keyring := range []*encryption.Keyring{nil, mustNewKeyring(2)} {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			fatal(t, "seek", err)
+		}
+
+		r := NewWALSegmentReader(f, WithSegmentKeyring(keyring))
+		if !r.Next() {
+			t.Fatalf("expected next, got false")
+		}
+		if _, err := r.Read(); !encryption.IsKeyNotFound(err) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestWALReader_Encrypted(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	keyring := mustNewKeyring(1)
+	path := filepath.Join(dir, "_00001.wal")
+	f, err := os.Create(path)
+	if err != nil {
+		fatal(t, "create segment", err)
+	}
+
+	w := NewWALSegmentWriter(f, WithSegmentKeyring(keyring))
+	for i := 0; i < 2; i++ {
+		entry := &WriteWALEntry{Values: map[string][]value.Value{"cpu": []value.Value{value.NewValue(int64(i), 1.1)}}}
+		if err := w.Write(mustMarshalEntry(entry)); err != nil {
+			fatal(t, "write points", err)
+		}
+	}
+	if err := w.close(); err != nil {
+		fatal(t, "close", err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		fatal(t, "stat", err)
+	}
+
+	// A segment that cannot be decrypted must not be mistaken for a corrupt one.
+	if err := NewWALReader([]string{path}).Read(func(WALEntry) error { return nil }); !encryption.IsKeyNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if fi, err := os.Stat(path); err != nil {
+		fatal(t, "stat", err)
+	} else if fi.Size() != stat.Size() {
+		t.Fatalf("segment truncated: got %d bytes, exp %d", fi.Size(), stat.Size())
+	}
+
+	// Corrupt the last entry, which should be truncated.
+	if err := os.Truncate(path, stat.Size()-1); err != nil {
+		fatal(t, "truncate", err)
+	}
+
+	reader := NewWALReader([]string{path})
+	reader.WithKeyring(keyring)
+	var n int
+	if err := reader.Read(func(WALEntry) error { n++; return nil }); err != nil {
+		fatal(t, "read", err)
+	} else if got, exp := n, 1; got != exp {
+		t.Fatalf("entry count mismatch: got %d, exp %d", got, exp)
+	}
+}
+
+func TestWALReader_UnsupportedHeader(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	entry := &WriteWALEntry{Values: map[string][]value.Value{"cpu": []value.Value{value.NewValue(1, 1.1)}}}
+	typ, data := mustMarshalEntry(entry)
+
+	var segment bytes.Buffer
+	segment.Write(segmentMagic[:])
+	segment.Write([]byte{segmentVersion + 1, byte(CompressionSnappy), 0})
+	var lv [5]byte
+	lv[0] = byte(typ)
+	binary.BigEndian.PutUint32(lv[1:], uint32(len(data)))
+	segment.Write(lv[:])
+	segment.Write(data)
+
+	path := filepath.Join(dir, "_00001.wal")
+	if err := ioutil.WriteFile(path, segment.Bytes(), 0666); err != nil {
+		fatal(t, "write segment", err)
+	}
+
+	// A segment of a newer version must be left intact.
+	if err := NewWALReader([]string{path}).Read(func(WALEntry) error { return nil }); err == nil {
+		t.Fatal("expected error reading segment of unsupported version")
+	} else if b, err := ioutil.ReadFile(path); err != nil {
+		fatal(t, "read segment", err)
+	} else if !bytes.Equal(b, segment.Bytes()) {
+		t.Fatalf("segment modified: got %d bytes, exp %d", len(b), segment.Len())
+	}
+}
+
+func TestWAL_Open_FormatChange(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	keyring := mustNewKeyring(1, 2)
+	write := func(l *WAL) {
+		t.Helper()
+		if err := l.Open(context.Background()); err != nil {
+			fatal(t, "open", err)
+		}
+		if _, err := l.WriteMulti(context.Background(), map[string][]value.Value{
+			"cpu,host=A#!~#value": []value.Value{value.NewValue(1, 1.1)},
+		}); err != nil {
+			fatal(t, "write points", err)
+		}
+		if err := l.Close(); err != nil {
+			fatal(t, "close", err)
+		}
+	}
+
+	// Segments are appended to while the format is unchanged.
+	write(NewWAL(dir))
+	write(NewWAL(dir))
+
+	l := NewWAL(dir)
+	l.WithKeyring(keyring)
+	write(l)
+
+	// Rotating the key starts a new segment.
+	l = NewWAL(dir)
+	l.WithKeyring(mustNewKeyring(2, 1))
+	write(l)
+
+	segments, err := SegmentFileNames(dir)
+	if err != nil {
+		fatal(t, "segment file names", err)
+	} else if got, exp := len(segments), 3; got != exp {
+		t.Fatalf("segment count mismatch: got %d, exp %d", got, exp)
+	}
+
+	reader := NewWALReader(segments)
+	reader.WithKeyring(keyring)
+	var n int
+	if err := reader.Read(func(WALEntry) error { n++; return nil }); err != nil {
+		fatal(t, "read", err)
+	} else if got, exp := n, 4; got != exp {
+		t.Fatalf("entry count mismatch: got %d, exp %d", got, exp)
+	}
+}
+
+func TestVerifier_MissingKey(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	l := NewWAL(dir)
+	l.WithKeyring(mustNewKeyring(1))
+	if err := l.Open(context.Background()); err != nil {
+		fatal(t, "open", err)
+	}
+	writeRandomEntry(l, t)
+	if err := l.Close(); err != nil {
+		fatal(t, "close", err)
+	}
+
+	summary, err := (&Verifier{Dir: dir}).Run(false)
+	if err != nil {
+		fatal(t, "verify", err)
+	} else if got, exp := len(summary.MissingKeyFiles), 1; got != exp {
+		t.Fatalf("unexpected files missing keys: %v", summary.MissingKeyFiles)
+	} else if len(summary.CorruptFiles) != 0 {
+		t.Fatalf("unexpected corrupt files: %v", summary.CorruptFiles)
+	}
+
+	summary, err = (&Verifier{Dir: dir, Keyring: mustNewKeyring(1)}).Run(false)
+	if err != nil {
+		fatal(t, "verify", err)
+	} else if len(summary.MissingKeyFiles) != 0 || len(summary.CorruptFiles) != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	} else if got, exp := summary.EntryCount, 1; got != exp {
+		t.Fatalf("entry count mismatch: got %d, exp %d", got, exp)
+	}
+}
@@ -10,18 +10,25 @@ import (
 	"path/filepath"
 	"text/tabwriter"
 	"time"
+
+	"github.com/influxdata/influxdb/pkg/encryption"
 )
 
 type Verifier struct {
 	Stderr io.Writer
 	Stdout io.Writer
 	Dir    string
+
+	// Keyring is used to decrypt encrypted files. Files encrypted with a key that
+	// is not in the keyring cannot be verified.
+	Keyring *encryption.Keyring
 }
 
 type VerificationSummary struct {
-	EntryCount   int
-	FileCount    int
-	CorruptFiles []string
+	EntryCount      int
+	FileCount       int
+	CorruptFiles    []string
+	MissingKeyFiles []string
 }
 
 func (v *Verifier) Run(print bool) (*VerificationSummary, error) {
@@ -53,7 +60,7 @@ func (v *Verifier) Run(print bool) (*VerificationSummary, error) {
 	start := time.Now()
 	tw := tabwriter.NewWriter(v.Stdout, 8, 2, 1, ' ', 0)
 
-	var corruptFiles []string
+	var corruptFiles, missingKeyFiles []string
 	var entriesScanned int
 
 	for _, fpath := range files {
@@ -63,11 +70,17 @@ func (v *Verifier) Run(print bool) (*VerificationSummary, error) {
 		}
 
 		clean := true
-		reader := NewWALSegmentReader(f)
+		reader := NewWALSegmentReader(f, WithSegmentKeyring(v.Keyring))
 		for reader.Next() {
 			entriesScanned++
 			_, err := reader.Read()
-			if err != nil {
+			if encryption.IsKeyNotFound(err) {
+				clean = false
+				entriesScanned--
+				fmt.Fprintf(tw, "%s: not verified: %v\n", fpath, err)
+				missingKeyFiles = append(missingKeyFiles, fpath)
+				break
+			} else if err != nil {
 				clean = false
 				fmt.Fprintf(tw, "%s: corrupt entry found at position %d\n", fpath, reader.Count())
 				corruptFiles = append(corruptFiles, fpath)
@@ -93,12 +106,20 @@ func (v *Verifier) Run(print bool) (*VerificationSummary, error) {
 		}
 	}
 
+	if len(missingKeyFiles) > 0 {
+		fmt.Fprintf(tw, "\n  Files not verified, missing encryption key: ")
+		for _, name := range missingKeyFiles {
+			fmt.Fprintf(tw, "\n    %s", name)
+		}
+	}
+
 	fmt.Fprintf(tw, "\nCompleted in %v\n", time.Since(start))
 
 	summary := &VerificationSummary{
-		EntryCount:   entriesScanned,
-		CorruptFiles: corruptFiles,
-		FileCount:    len(files),
+		EntryCount:      entriesScanned,
+		CorruptFiles:    corruptFiles,
+		MissingKeyFiles: missingKeyFiles,
+		FileCount:       len(files),
 	}
 
 	return summary, nil
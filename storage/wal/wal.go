@@ -3,6 +3,7 @@ package wal
 import (
 	"bufio"
 	"context"
+	"crypto/cipher"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/pool"
 	"github.com/influxdata/influxdb/tsdb/value"
@@ -103,6 +105,11 @@ type WAL struct {
 	// is opened if a non-default value is required.
 	syncDelay time.Duration
 
+	// compression and keyring set the format of new segments. Entries are compressed
+	// with compression, and encrypted with the active key of keyring if it is set.
+	compression Compression
+	keyring     *encryption.Keyring
+
 	// WALOutput is the writer used by the logger.
 	logger *zap.Logger // Logger to be used for important messages
 
@@ -136,6 +143,24 @@ func (l *WAL) WithFsyncDelay(delay time.Duration) {
 	l.syncDelay = delay
 }
 
+// WithCompression sets the compression of new segments and should be called before
+// the WAL is opened.
+func (l *WAL) WithCompression(c Compression) {
+	l.compression = c
+}
+
+// WithKeyring sets the keyring used to encrypt new segments, and to decrypt existing
+// ones, and should be called before the WAL is opened. New segments are encrypted
+// with the active key of the keyring.
+func (l *WAL) WithKeyring(k *encryption.Keyring) {
+	l.keyring = k
+}
+
+// segmentOptions returns the options of the segments written by the WAL.
+func (l *WAL) segmentOptions() []SegmentOption {
+	return []SegmentOption{WithSegmentCompression(l.compression), WithSegmentKeyring(l.keyring)}
+}
+
 // SetEnabled sets if the WAL is enabled and should be called before the WAL is opened.
 func (l *WAL) SetEnabled(enabled bool) {
 	l.enabled = enabled
@@ -219,13 +244,22 @@ func (l *WAL) Open(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
-			if _, err := fd.Seek(0, io.SeekEnd); err != nil {
-				return err
-			}
-			l.currentSegmentWriter = NewWALSegmentWriter(fd)
 
-			// Reset the current segment size stat
-			l.tracker.SetCurrentSegmentSize(uint64(stat.Size()))
+			// Only append to the last segment if it was written in the format of
+			// new segments. Otherwise, the next write starts a new segment.
+			format, _, err := readSegmentHeader(bufio.NewReader(fd))
+			if err != nil || format != newSegmentOptions(l.segmentOptions()).format() {
+				fd.Close()
+			} else {
+				if _, err := fd.Seek(0, io.SeekEnd); err != nil {
+					return err
+				}
+				l.currentSegmentWriter = NewWALSegmentWriter(fd, l.segmentOptions()...)
+				l.currentSegmentWriter.header = nil
+
+				// Reset the current segment size stat
+				l.tracker.SetCurrentSegmentSize(uint64(stat.Size()))
+			}
 		}
 	}
 
@@ -429,10 +463,14 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 		return -1, err
 	}
 
-	encBuf := bytesPool.Get(snappy.MaxEncodedLen(len(b)))
+	encBuf := bytesPool.Get(l.compression.maxEncodedLen(len(b)))
 
-	compressed := snappy.Encode(encBuf, b)
+	compressed, err := l.compression.encode(encBuf, b)
 	bytesPool.Put(bytes)
+	if err != nil {
+		bytesPool.Put(encBuf)
+		return -1, err
+	}
 
 	syncErr := make(chan error)
 
@@ -594,7 +632,7 @@ func (l *WAL) newSegmentFile() error {
 	if err != nil {
 		return err
 	}
-	l.currentSegmentWriter = NewWALSegmentWriter(fd)
+	l.currentSegmentWriter = NewWALSegmentWriter(fd, l.segmentOptions()...)
 	l.tracker.IncSegments()
 
 	// Reset the current segment size stat
@@ -1070,14 +1108,33 @@ type WALSegmentWriter struct {
 	bw   *bufio.Writer
 	w    io.WriteCloser
 	size int
+
+	compression Compression
+	header      []byte // written before the first entry of the segment
+	aead        cipher.AEAD
+	sealBuf     []byte
+	err         error
 }
 
-// NewWALSegmentWriter returns a new WALSegmentWriter writing to w.
-func NewWALSegmentWriter(w io.WriteCloser) *WALSegmentWriter {
-	return &WALSegmentWriter{
-		bw: bufio.NewWriterSize(w, 16*1024),
-		w:  w,
+// NewWALSegmentWriter returns a new WALSegmentWriter writing to w. By default,
+// segments are written in their original format, with snappy compressed entries.
+func NewWALSegmentWriter(w io.WriteCloser, opts ...SegmentOption) *WALSegmentWriter {
+	o := newSegmentOptions(opts)
+	format := o.format()
+
+	sw := &WALSegmentWriter{
+		bw:          bufio.NewWriterSize(w, 16*1024),
+		w:           w,
+		compression: format.compression,
+	}
+
+	if format.hasHeader() {
+		sw.header = format.appendHeader(nil)
 	}
+	if format.keyID != "" {
+		sw.aead, sw.err = o.keyring.AEAD(format.keyID)
+	}
+	return sw
 }
 
 func (w *WALSegmentWriter) path() string {
@@ -1087,8 +1144,34 @@ func (w *WALSegmentWriter) path() string {
 	return ""
 }
 
+// Compression returns the compression the entries written by w are expected to
+// be compressed with.
+func (w *WALSegmentWriter) Compression() Compression {
+	return w.compression
+}
+
 // Write writes entryType and the buffer containing compressed entry data.
 func (w *WALSegmentWriter) Write(entryType WalEntryType, compressed []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.header) > 0 {
+		if _, err := w.bw.Write(w.header); err != nil {
+			return err
+		}
+		w.size += len(w.header)
+		w.header = nil
+	}
+
+	if w.aead != nil {
+		sealed, err := sealEntry(w.aead, w.sealBuf, entryType, compressed)
+		if err != nil {
+			return err
+		}
+		compressed, w.sealBuf = sealed, sealed
+	}
+
 	var buf [5]byte
 	buf[0] = byte(entryType)
 	binary.BigEndian.PutUint32(buf[1:5], uint32(len(compressed)))
@@ -1137,13 +1220,22 @@ type WALSegmentReader struct {
 	entry WALEntry
 	n     int64
 	err   error
+
+	keyring     *encryption.Keyring
+	headerRead  bool
+	compression Compression
+	aead        cipher.AEAD
 }
 
-// NewWALSegmentReader returns a new WALSegmentReader reading from r.
-func NewWALSegmentReader(r io.ReadCloser) *WALSegmentReader {
+// NewWALSegmentReader returns a new WALSegmentReader reading from r. Segments are
+// read in the format they were written in; encrypted segments require a keyring
+// holding their key.
+func NewWALSegmentReader(r io.ReadCloser, opts ...SegmentOption) *WALSegmentReader {
+	o := newSegmentOptions(opts)
 	return &WALSegmentReader{
-		rc: r,
-		r:  bufio.NewReader(r),
+		rc:      r,
+		r:       bufio.NewReader(r),
+		keyring: o.keyring,
 	}
 }
 
@@ -1153,10 +1245,44 @@ func (r *WALSegmentReader) Reset(rc io.ReadCloser) {
 	r.entry = nil
 	r.n = 0
 	r.err = nil
+	r.headerRead = false
+	r.compression = CompressionSnappy
+	r.aead = nil
+}
+
+// readHeader reads the header of the segment, if any, and prepares the reader to
+// read entries in the format of the segment.
+func (r *WALSegmentReader) readHeader() error {
+	format, n, err := readSegmentHeader(r.r)
+	if err == ErrWALCorrupt {
+		return err
+	} else if err != nil {
+		return &segmentHeaderError{err: err}
+	}
+
+	if format.keyID != "" {
+		aead, err := r.keyring.AEAD(format.keyID)
+		if err != nil {
+			return &segmentHeaderError{err: fmt.Errorf("cannot decrypt wal segment: %w", err)}
+		}
+		r.aead = aead
+	}
+
+	r.compression = format.compression
+	r.n += int64(n)
+	return nil
 }
 
 // Next indicates if there is a value to read.
 func (r *WALSegmentReader) Next() bool {
+	if !r.headerRead {
+		r.headerRead = true
+		if err := r.readHeader(); err != nil {
+			r.err = err
+			return true
+		}
+	}
+
 	var nReadOK int
 
 	// read the type and the length of the entry
@@ -1188,18 +1314,33 @@ func (r *WALSegmentReader) Next() bool {
 	}
 	nReadOK += n
 
-	decLen, err := snappy.DecodedLen(b[:length])
-	if err != nil {
-		r.err = err
-		return true
+	compressed := b[:length]
+	if r.aead != nil {
+		if compressed, err = openEntry(r.aead, entryType, compressed); err != nil {
+			r.err = err
+			return true
+		}
 	}
-	decBuf := *(getBuf(decLen))
-	defer putBuf(&decBuf)
 
-	data, err := snappy.Decode(decBuf, b[:length])
-	if err != nil {
-		r.err = err
-		return true
+	var data []byte
+	if r.compression == CompressionZstd {
+		if data, err = zstdDecode(nil, compressed); err != nil {
+			r.err = err
+			return true
+		}
+	} else {
+		decLen, err := snappy.DecodedLen(compressed)
+		if err != nil {
+			r.err = err
+			return true
+		}
+		decBuf := *(getBuf(decLen))
+		defer putBuf(&decBuf)
+
+		if data, err = snappy.Decode(decBuf, compressed); err != nil {
+			r.err = err
+			return true
+		}
 	}
 
 	// and marshal it and send it to the cache
@@ -0,0 +1,18 @@
+package wal
+
+import "github.com/klauspost/compress/zstd"
+
+// zstdEncoder favours speed, as entries are compressed on the write path. Both
+// the encoder and decoder are safe for concurrent use.
+var zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
+func zstdMaxEncodedLen(n int) int { return zstdEncoder.MaxEncodedSize(n) }
+
+func zstdEncode(dst, src []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(src, dst[:0]), nil
+}
+
+func zstdDecode(dst, src []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(src, dst[:0])
+}
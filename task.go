@@ -64,6 +64,43 @@ type Task struct {
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// DependsOn are the IDs of the upstream tasks of the task. A task with
+	// upstream tasks is not run on its own schedule, but for every time of its
+	// schedule that all of its upstream tasks have succeeded a run scheduled
+	// for. Upstream runs that succeeded before a restart of the scheduler are
+	// forgotten, so a time only triggers if all of its runs succeed after it.
+	// A task cannot be deleted while other tasks depend on it.
+	DependsOn []ID `json:"dependsOn,omitempty"`
+
+	// OnWrite additionally runs the task soon after points are written to a
+	// bucket, rather than only on its schedule.
+	OnWrite *TaskWriteTrigger `json:"onWrite,omitempty"`
+}
+
+// DefaultTaskWriteDebounce is the debounce of a TaskWriteTrigger that does not set one.
+const DefaultTaskWriteDebounce = 5 * time.Second
+
+// TaskWriteTrigger runs a task when points matching it are written to its bucket.
+// The task runs once per debounce window, starting with the first matching write,
+// no matter how many matching writes the window holds.
+type TaskWriteTrigger struct {
+	BucketID ID `json:"bucketID"`
+	// Measurement, when set, only matches the points of the measurement.
+	Measurement string `json:"measurement,omitempty"`
+	// Tags, when set, only matches the points with all of the tag values.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Debounce is a duration string, i.e.: "10s" is 10 seconds.
+	Debounce string `json:"debounce,omitempty"`
+}
+
+// DebounceDuration returns the debounce of the trigger, or DefaultTaskWriteDebounce
+// if it has none.
+func (t *TaskWriteTrigger) DebounceDuration() (time.Duration, error) {
+	if t.Debounce == "" {
+		return DefaultTaskWriteDebounce, nil
+	}
+	return time.ParseDuration(t.Debounce)
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -154,6 +191,8 @@ type TaskCreate struct {
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
+	DependsOn      []ID                   `json:"dependsOn,omitempty"`
+	OnWrite        *TaskWriteTrigger      `json:"onWrite,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -173,6 +212,9 @@ type TaskUpdate struct {
 	Flux        *string `json:"flux,omitempty"`
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
+	DependsOn   *[]ID   `json:"dependsOn,omitempty"`
+	// OnWrite replaces the write trigger of the task. A trigger without a bucket removes it.
+	OnWrite *TaskWriteTrigger `json:"onWrite,omitempty"`
 
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
@@ -188,10 +230,12 @@ type TaskUpdate struct {
 func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	// this is a type so we can marshal string into durations nicely
 	jo := struct {
-		Flux        *string `json:"flux,omitempty"`
-		Status      *string `json:"status,omitempty"`
-		Name        string  `json:"name,omitempty"`
-		Description *string `json:"description,omitempty"`
+		Flux        *string           `json:"flux,omitempty"`
+		Status      *string           `json:"status,omitempty"`
+		Name        string            `json:"name,omitempty"`
+		Description *string           `json:"description,omitempty"`
+		DependsOn   *[]ID             `json:"dependsOn,omitempty"`
+		OnWrite     *TaskWriteTrigger `json:"onWrite,omitempty"`
 
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
@@ -214,6 +258,8 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
+	t.DependsOn = jo.DependsOn
+	t.OnWrite = jo.OnWrite
 	t.Options.Cron = jo.Cron
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
@@ -229,10 +275,12 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 
 func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo := struct {
-		Flux        *string `json:"flux,omitempty"`
-		Status      *string `json:"status,omitempty"`
-		Name        string  `json:"name,omitempty"`
-		Description *string `json:"description,omitempty"`
+		Flux        *string           `json:"flux,omitempty"`
+		Status      *string           `json:"status,omitempty"`
+		Name        string            `json:"name,omitempty"`
+		Description *string           `json:"description,omitempty"`
+		DependsOn   *[]ID             `json:"dependsOn,omitempty"`
+		OnWrite     *TaskWriteTrigger `json:"onWrite,omitempty"`
 
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
@@ -251,6 +299,8 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Cron = t.Options.Cron
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
+	jo.DependsOn = t.DependsOn
+	jo.OnWrite = t.OnWrite
 	if t.Options.Offset != nil {
 		offset := *t.Options.Offset
 		jo.Offset = &offset
@@ -274,7 +324,7 @@ func (t TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.DependsOn == nil && t.OnWrite == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
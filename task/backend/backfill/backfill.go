@@ -0,0 +1,270 @@
+// Package backfill runs tasks at every time they were scheduled for in a
+// historical time range.
+package backfill
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/options"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.TaskBackfillService = (*Backfiller)(nil)
+
+// Executor executes the runs of a backfill.
+type Executor interface {
+	PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error)
+}
+
+// Backfiller enqueues the runs of backfills with an executor. A backfill keeps at
+// most as many runs in progress as the concurrency option of its task allows.
+// While a task is backfilled, ConcurrencyLimit limits its scheduled runs too.
+type Backfiller struct {
+	log *zap.Logger
+	ts  influxdb.TaskService
+	ex  Executor
+
+	mu        sync.Mutex
+	backfills map[influxdb.ID]*backfill
+	wg        sync.WaitGroup
+}
+
+// NewBackfiller returns a Backfiller running the tasks of ts with ex.
+func NewBackfiller(log *zap.Logger, ts influxdb.TaskService, ex Executor) *Backfiller {
+	return &Backfiller{
+		log:       log,
+		ts:        ts,
+		ex:        ex,
+		backfills: make(map[influxdb.ID]*backfill),
+	}
+}
+
+type backfill struct {
+	mu     sync.Mutex
+	state  influxdb.TaskBackfill
+	cancel context.CancelFunc
+}
+
+// progress returns a copy of the state of the backfill.
+func (b *backfill) progress() *influxdb.TaskBackfill {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.state
+	return &s
+}
+
+// BackfillTask starts a backfill of the task over the range.
+func (b *Backfiller) BackfillTask(ctx context.Context, taskID influxdb.ID, start, stop time.Time) (*influxdb.TaskBackfill, error) {
+	start, stop = start.UTC().Truncate(time.Second), stop.UTC().Truncate(time.Second)
+	if err := influxdb.ValidTaskBackfillRange(start, stop, time.Now()); err != nil {
+		return nil, err
+	}
+
+	t, err := b.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := ScheduledTimes(t, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := 1
+	if o, err := options.FromScript(t.Flux); err == nil && o.Concurrency != nil && *o.Concurrency > 0 {
+		concurrency = int(*o.Concurrency)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cur, ok := b.backfills[taskID]; ok && cur.progress().Status == influxdb.TaskBackfillRunning {
+		return nil, influxdb.ErrTaskBackfillRunning
+	}
+
+	// The runs outlive the request that started the backfill.
+	rctx, cancel := context.WithCancel(context.Background())
+	bf := &backfill{
+		state: influxdb.TaskBackfill{
+			TaskID:    taskID,
+			Start:     start,
+			Stop:      stop,
+			Status:    influxdb.TaskBackfillRunning,
+			Total:     len(times),
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	b.backfills[taskID] = bf
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.run(rctx, bf, t, times, concurrency)
+	}()
+
+	return bf.progress(), nil
+}
+
+// run executes a run of the task for each of the times, with at most concurrency
+// runs in progress.
+func (b *Backfiller) run(ctx context.Context, bf *backfill, t *influxdb.Task, times []time.Time, concurrency int) {
+	log := b.log.With(zap.String("taskID", t.ID.String()))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	finish := func(err error) {
+		bf.mu.Lock()
+		defer bf.mu.Unlock()
+		if err != nil {
+			bf.state.Failed++
+		} else {
+			bf.state.Succeeded++
+		}
+	}
+
+loop:
+	for _, sf := range times {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+
+		p, err := b.ex.PromisedExecute(ctx, scheduler.ID(t.ID), sf, sf.Add(t.Offset))
+		if err != nil {
+			log.Info("Failed to execute backfill run", zap.Time("scheduledFor", sf), zap.Error(err))
+			finish(err)
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Canceling ctx cancels the promise too.
+			finish(p.Error())
+			<-sem
+		}()
+	}
+	wg.Wait()
+
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	if ctx.Err() != nil {
+		bf.state.Status = influxdb.TaskBackfillCanceled
+	} else {
+		bf.state.Status = influxdb.TaskBackfillCompleted
+	}
+	bf.state.FinishedAt = time.Now().UTC()
+	bf.cancel()
+	log.Info("Backfill finished", zap.String("status", bf.state.Status), zap.Int("succeeded", bf.state.Succeeded), zap.Int("failed", bf.state.Failed))
+}
+
+// ConcurrencyLimit returns a limit func for ex that applies executor.ConcurrencyLimit
+// to the runs of the tasks being backfilled, so that the scheduled and backfilled
+// runs of a task share its concurrency option. The runs of other tasks are not limited.
+func (b *Backfiller) ConcurrencyLimit(ex *executor.Executor) executor.LimitFunc {
+	limit := executor.ConcurrencyLimit(ex)
+	return func(t *influxdb.Task, r *influxdb.Run) error {
+		if !b.backfilling(t.ID) {
+			return nil
+		}
+		return limit(t, r)
+	}
+}
+
+// backfilling returns true if the task has a backfill running.
+func (b *Backfiller) backfilling(taskID influxdb.ID) bool {
+	b.mu.Lock()
+	bf, ok := b.backfills[taskID]
+	b.mu.Unlock()
+	return ok && bf.progress().Status == influxdb.TaskBackfillRunning
+}
+
+// FindTaskBackfill returns the progress of the latest backfill of the task.
+func (b *Backfiller) FindTaskBackfill(ctx context.Context, taskID influxdb.ID) (*influxdb.TaskBackfill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bf, ok := b.backfills[taskID]
+	if !ok {
+		return nil, influxdb.ErrTaskBackfillNotFound
+	}
+	return bf.progress(), nil
+}
+
+// CancelTaskBackfill cancels the running backfill of the task and waits for its
+// runs in progress to be canceled.
+func (b *Backfiller) CancelTaskBackfill(ctx context.Context, taskID influxdb.ID) error {
+	b.mu.Lock()
+	bf, ok := b.backfills[taskID]
+	b.mu.Unlock()
+
+	if !ok || bf.progress().Status != influxdb.TaskBackfillRunning {
+		return influxdb.ErrTaskBackfillNotFound
+	}
+	bf.cancel()
+
+	// Wait for the backfill to record its cancellation.
+	for {
+		if bf.progress().Status != influxdb.TaskBackfillRunning {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Close cancels all running backfills and waits for them to finish.
+func (b *Backfiller) Close() error {
+	b.mu.Lock()
+	for _, bf := range b.backfills {
+		bf.cancel()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// ScheduledTimes returns the times from start, inclusive, to stop, exclusive, that
+// the task is scheduled for by its every or cron option.
+func ScheduledTimes(t *influxdb.Task, start, stop time.Time) ([]time.Time, error) {
+	// The schedule returns the times after the one it is created with.
+	sch, last, err := scheduler.NewSchedule(t.EffectiveCron(), start.Add(-time.Second))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task has no valid schedule",
+			Err:  err,
+		}
+	}
+
+	var times []time.Time
+	for {
+		next, err := sch.Next(last)
+		if err != nil {
+			return nil, err
+		}
+		if !next.Before(stop) {
+			return times, nil
+		}
+		if !next.Before(start) {
+			if len(times) == influxdb.MaxTaskBackfillRuns {
+				return nil, influxdb.ErrTaskBackfillTooLarge(start, stop)
+			}
+			times = append(times, next)
+		}
+		last = next
+	}
+}
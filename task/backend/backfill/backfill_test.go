@@ -0,0 +1,292 @@
+package backfill_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/task/backend/backfill"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestScheduledTimes(t *testing.T) {
+	start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		task  *influxdb.Task
+		start time.Time
+		stop  time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "every",
+			task:  &influxdb.Task{Every: "1h"},
+			start: start,
+			stop:  start.Add(3 * time.Hour),
+			want:  []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		{
+			name:  "every with unaligned start",
+			task:  &influxdb.Task{Every: "1h"},
+			start: start.Add(30 * time.Minute),
+			stop:  start.Add(3 * time.Hour),
+			want:  []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		{
+			name:  "cron",
+			task:  &influxdb.Task{Cron: "0 12 * * *"},
+			start: start,
+			stop:  start.Add(48 * time.Hour),
+			want:  []time.Time{start.Add(12 * time.Hour), start.Add(36 * time.Hour)},
+		},
+		{
+			name:  "no scheduled times",
+			task:  &influxdb.Task{Every: "24h"},
+			start: start.Add(time.Hour),
+			stop:  start.Add(2 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backfill.ScheduledTimes(tt.task, tt.start, tt.stop)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("time %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("too many runs", func(t *testing.T) {
+		_, err := backfill.ScheduledTimes(&influxdb.Task{Every: "1s"}, start, start.Add(365*24*time.Hour))
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("got error %v, want code %q", err, influxdb.EInvalid)
+		}
+	})
+}
+
+// fakeExecutor runs every promise to completion in the background, failing the
+// runs scheduled for the times in fail.
+type fakeExecutor struct {
+	mu       sync.Mutex
+	running  int
+	max      int
+	executed []time.Time
+	fail     map[time.Time]bool
+	block    chan struct{}
+}
+
+func (e *fakeExecutor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.executed = append(e.executed, scheduledFor)
+	e.running++
+	if e.running > e.max {
+		e.max = e.running
+	}
+
+	p := &fakePromise{done: make(chan struct{})}
+	go func() {
+		if e.block != nil {
+			select {
+			case <-e.block:
+			case <-ctx.Done():
+				p.err = influxdb.ErrRunCanceled
+			}
+		}
+		if e.fail[scheduledFor] {
+			p.err = errors.New("run failed")
+		}
+
+		e.mu.Lock()
+		e.running--
+		e.mu.Unlock()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+type fakePromise struct {
+	done chan struct{}
+	err  error
+}
+
+func (p *fakePromise) ID() influxdb.ID            { return 1 }
+func (p *fakePromise) Cancel(ctx context.Context) {}
+func (p *fakePromise) Done() <-chan struct{}      { return p.done }
+func (p *fakePromise) Error() error {
+	<-p.done
+	return p.err
+}
+
+func newTaskService(flux string) *mock.TaskService {
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, Every: "1h", Flux: flux}, nil
+	}
+	return ts
+}
+
+// waitForStatus polls the backfill of the task until it is no longer running.
+func waitForStatus(t *testing.T, b *backfill.Backfiller, taskID influxdb.ID) *influxdb.TaskBackfill {
+	t.Helper()
+
+	for i := 0; i < 500; i++ {
+		bf, err := b.FindTaskBackfill(context.Background(), taskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bf.Status != influxdb.TaskBackfillRunning {
+			return bf
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("backfill did not finish")
+	return nil
+}
+
+func TestBackfiller_BackfillTask(t *testing.T) {
+	start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ex := &fakeExecutor{fail: map[time.Time]bool{start.Add(time.Hour): true}}
+	b := backfill.NewBackfiller(zaptest.NewLogger(t), newTaskService(`option task = {name: "backfill", every: 1h, concurrency: 2}`), ex)
+	defer b.Close()
+
+	bf, err := b.BackfillTask(context.Background(), 1, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bf.Total != 24 {
+		t.Fatalf("got %d runs, want 24", bf.Total)
+	}
+
+	bf = waitForStatus(t, b, 1)
+	if bf.Status != influxdb.TaskBackfillCompleted {
+		t.Errorf("got status %q, want %q", bf.Status, influxdb.TaskBackfillCompleted)
+	}
+	if bf.Succeeded != 23 || bf.Failed != 1 {
+		t.Errorf("got %d succeeded and %d failed runs, want 23 and 1", bf.Succeeded, bf.Failed)
+	}
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if len(ex.executed) != 24 {
+		t.Errorf("got %d executed runs, want 24", len(ex.executed))
+	}
+	if ex.max > 2 {
+		t.Errorf("got %d concurrent runs, want at most 2", ex.max)
+	}
+}
+
+func TestBackfiller_CancelTaskBackfill(t *testing.T) {
+	start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ex := &fakeExecutor{block: make(chan struct{})}
+	b := backfill.NewBackfiller(zaptest.NewLogger(t), newTaskService(`option task = {name: "backfill", every: 1h}`), ex)
+	defer b.Close()
+
+	if _, err := b.BackfillTask(context.Background(), 1, start, start.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one backfill of a task runs at a time.
+	if _, err := b.BackfillTask(context.Background(), 1, start, start.Add(time.Hour)); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("got error %v, want code %q", err, influxdb.EConflict)
+	}
+
+	if err := b.CancelTaskBackfill(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	bf, err := b.FindTaskBackfill(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bf.Status != influxdb.TaskBackfillCanceled {
+		t.Errorf("got status %q, want %q", bf.Status, influxdb.TaskBackfillCanceled)
+	}
+	if bf.Succeeded+bf.Failed >= bf.Total {
+		t.Errorf("expected the canceled backfill not to run all of its %d runs", bf.Total)
+	}
+
+	if err := b.CancelTaskBackfill(context.Background(), 1); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("got error %v, want code %q", err, influxdb.ENotFound)
+	}
+}
+
+func TestBackfiller_InvalidRange(t *testing.T) {
+	b := backfill.NewBackfiller(zaptest.NewLogger(t), newTaskService(`option task = {name: "backfill", every: 1h}`), &fakeExecutor{})
+	defer b.Close()
+
+	start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := b.BackfillTask(context.Background(), 1, start, start); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v, want code %q", err, influxdb.EInvalid)
+	}
+}
+
+func TestBackfiller_ConcurrencyLimit(t *testing.T) {
+	start := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	task := &influxdb.Task{ID: 1, Every: "1h", Flux: `option task = {name: "backfill", every: 1h, concurrency: 1}`}
+
+	// A backfilled run is in progress along with a scheduled run.
+	backfilled := &influxdb.Run{ID: 1, ScheduledFor: start}
+	scheduled := &influxdb.Run{ID: 2, ScheduledFor: time.Now()}
+	tcs := &mock.TaskControlService{
+		CurrentlyRunningFn: func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error) {
+			return []*influxdb.Run{scheduled, backfilled}, nil
+		},
+	}
+	ex, _ := executor.NewExecutor(zaptest.NewLogger(t), nil, nil, nil, tcs)
+
+	b := backfill.NewBackfiller(zaptest.NewLogger(t), newTaskService(task.Flux), &fakeExecutor{block: make(chan struct{})})
+	defer b.Close()
+	limit := b.ConcurrencyLimit(ex)
+
+	// The runs of a task that is not backfilled are not limited.
+	if err := limit(task, scheduled); err != nil {
+		t.Fatalf("unexpected error before backfill: %v", err)
+	}
+
+	if _, err := b.BackfillTask(context.Background(), task.ID, start, start.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The older backfilled run goes first.
+	if err := limit(task, backfilled); err != nil {
+		t.Fatalf("unexpected error for backfilled run: %v", err)
+	}
+	if err := limit(task, scheduled); err == nil {
+		t.Fatal("expected scheduled run to be limited during backfill")
+	}
+
+	if err := b.CancelTaskBackfill(context.Background(), task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := limit(task, scheduled); err != nil {
+		t.Fatalf("unexpected error after backfill: %v", err)
+	}
+}
+
+func TestBackfiller_FutureStop(t *testing.T) {
+	b := backfill.NewBackfiller(zaptest.NewLogger(t), newTaskService(`option task = {name: "backfill", every: 1h}`), &fakeExecutor{})
+	defer b.Close()
+
+	start := time.Now().Add(-time.Hour)
+	if _, err := b.BackfillTask(context.Background(), 1, start, start.Add(2*time.Hour)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v, want code %q", err, influxdb.EInvalid)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 var _ middleware.Coordinator = (*Coordinator)(nil)
 var _ Executor = (*executor.Executor)(nil)
+var _ scheduler.Dependent = SchedulableTask{}
 
 // DefaultLimit is the maximum number of tasks that a given taskd server can own
 const DefaultLimit = 1000
@@ -25,11 +26,18 @@ type Executor interface {
 	Cancel(ctx context.Context, runID influxdb.ID) error
 }
 
+// WriteWatcher is an abstraction of the write trigger with only the functions needed by the coordinator
+type WriteWatcher interface {
+	Watch(task *influxdb.Task)
+	Unwatch(id influxdb.ID)
+}
+
 // Coordinator is the intermediary between the scheduling/executing system and the rest of the task system
 type Coordinator struct {
 	log *zap.Logger
 	sch scheduler.Scheduler
 	ex  Executor
+	ww  WriteWatcher
 
 	limit int
 }
@@ -62,12 +70,28 @@ func (t SchedulableTask) LastScheduled() time.Time {
 	return t.lsc
 }
 
+// DependsOn returns the IDs of the Task's upstream tasks
+func (t SchedulableTask) DependsOn() []scheduler.ID {
+	ids := make([]scheduler.ID, len(t.Task.DependsOn))
+	for i, id := range t.Task.DependsOn {
+		ids[i] = scheduler.ID(id)
+	}
+	return ids
+}
+
 func WithLimitOpt(i int) CoordinatorOption {
 	return func(c *Coordinator) {
 		c.limit = i
 	}
 }
 
+// WithWriteWatcherOpt has the coordinator tell ww about the write triggers of the tasks
+func WithWriteWatcherOpt(ww WriteWatcher) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.ww = ww
+	}
+}
+
 // NewSchedulableTask transforms an influxdb task to a schedulable task type
 func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 
@@ -119,6 +143,10 @@ func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) erro
 		return err
 	}
 
+	if c.ww != nil {
+		c.ww.Watch(task)
+	}
+
 	return nil
 }
 
@@ -141,6 +169,11 @@ func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Task)
 		}
 	}
 
+	// the write watcher leaves inactive tasks out by itself.
+	if c.ww != nil {
+		c.ww.Watch(to)
+	}
+
 	return nil
 }
 
@@ -151,6 +184,10 @@ func (c *Coordinator) TaskDeleted(ctx context.Context, id influxdb.ID) error {
 		return err
 	}
 
+	if c.ww != nil {
+		c.ww.Unwatch(id)
+	}
+
 	return nil
 }
 
@@ -234,3 +234,33 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 		})
 	}
 }
+
+func Test_Coordinator_WriteWatcher(t *testing.T) {
+	var (
+		now      = time.Now().UTC()
+		task     = &influxdb.Task{ID: 1, Status: "active", CreatedAt: now, Cron: "* * * * *"}
+		inactive = &influxdb.Task{ID: 1, Status: "inactive", CreatedAt: now, Cron: "* * * * *"}
+		ww       = &writeWatcherW{}
+		coord    = NewCoordinator(zaptest.NewLogger(t), &schedulerC{}, &executorE{}, WithWriteWatcherOpt(ww))
+		ctx      = context.Background()
+	)
+
+	if err := coord.TaskCreated(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := coord.TaskUpdated(ctx, task, inactive); err != nil {
+		t.Fatal(err)
+	}
+	if err := coord.TaskDeleted(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []interface{}{
+		watchCall{task.ID},
+		watchCall{task.ID},
+		unwatchCall{task.ID},
+	}
+	if diff := cmp.Diff(exp, ww.calls); diff != "" {
+		t.Errorf("unexpected write watcher calls %s", diff)
+	}
+}
@@ -41,6 +41,20 @@ type (
 	}
 )
 
+type (
+	writeWatcherW struct {
+		calls []interface{}
+	}
+
+	watchCall struct {
+		TaskID influxdb.ID
+	}
+
+	unwatchCall struct {
+		TaskID influxdb.ID
+	}
+)
+
 type (
 	promise struct {
 		run *influxdb.Run
@@ -112,3 +126,11 @@ func (e *executorE) Cancel(ctx context.Context, runID influxdb.ID) error {
 	e.calls = append(e.calls, cancelCallC{runID})
 	return nil
 }
+
+func (w *writeWatcherW) Watch(task *influxdb.Task) {
+	w.calls = append(w.calls, watchCall{task.ID})
+}
+
+func (w *writeWatcherW) Unwatch(id influxdb.ID) {
+	w.calls = append(w.calls, unwatchCall{id})
+}
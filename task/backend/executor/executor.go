@@ -7,13 +7,16 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/influxdb"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
@@ -41,8 +44,30 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 // LimitFunc is a function the executor will use to
 type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
+// RunSucceededFunc is called with the task and scheduled time of every run that succeeds.
+type RunSucceededFunc func(taskID influxdb.ID, scheduledFor time.Time)
+
+const (
+	// DefaultRetryBackoff is the delay before the first re-attempt of a failed run.
+	DefaultRetryBackoff = time.Second
+
+	// DefaultMaxRetryBackoff is the longest delay between attempts of a failed run.
+	DefaultMaxRetryBackoff = time.Minute
+)
+
+type executorOptFunc func(e *Executor)
+
+// WithRetryBackoff sets the delay before the first re-attempt of a failed run. The
+// delay doubles with every further attempt, up to max.
+func WithRetryBackoff(initial, max time.Duration) executorOptFunc {
+	return func(e *Executor) {
+		e.retryBackoff = initial
+		e.maxRetryBackoff = max
+	}
+}
+
 // NewExecutor creates a new task executor
-func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService) (*Executor, *ExecutorMetrics) {
+func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService, opts ...executorOptFunc) (*Executor, *ExecutorMetrics) {
 	e := &Executor{
 		log: log,
 		ts:  ts,
@@ -54,6 +79,12 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.Authorizati
 		promiseQueue:    make(chan *promise, 1000),                                //TODO(lh): make this configurable
 		workerLimit:     make(chan struct{}, 100),                                 //TODO(lh): make this configurable
 		limitFunc:       func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
+		retryBackoff:    DefaultRetryBackoff,
+		maxRetryBackoff: DefaultMaxRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(e)
 	}
 
 	e.metrics = NewExecutorMetrics(e)
@@ -85,6 +116,12 @@ type Executor struct {
 
 	limitFunc LimitFunc
 
+	runSucceeded RunSucceededFunc
+
+	// backoff between attempts of failed runs
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+
 	// keep a pool of execution workers.
 	workerPool  sync.Pool
 	workerLimit chan struct{}
@@ -95,6 +132,11 @@ func (e *Executor) SetLimitFunc(l LimitFunc) {
 	e.limitFunc = l
 }
 
+// SetRunSucceededFunc sets the func called when a run of this task executor succeeds
+func (e *Executor) SetRunSucceededFunc(fn RunSucceededFunc) {
+	e.runSucceeded = fn
+}
+
 // Execute is a executor to satisfy the needs of tasks
 func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
 	_, err := e.PromisedExecute(ctx, id, scheduledFor, runAt)
@@ -287,17 +329,21 @@ func (w *worker) work() {
 			}
 		}
 
-		// execute the promise
-		w.executeQuery(prom)
-
-		// close promise done channel and set appropriate error
-		close(prom.done)
+		// execute the promise, a failed attempt to be retried is queued again
+		if !w.executeQuery(prom) {
+			continue
+		}
 
-		// remove promise from registry
-		w.e.currentPromises.Delete(prom.run.ID)
+		w.done(prom)
 	}
 }
 
+// done closes the promise done channel and removes the promise from the registry.
+func (w *worker) done(p *promise) {
+	close(p.done)
+	w.e.currentPromises.Delete(p.run.ID)
+}
+
 func (w *worker) start(p *promise) {
 	// trace
 	span, ctx := tracing.StartSpanFromContext(p.ctx)
@@ -327,12 +373,21 @@ func (w *worker) finish(p *promise, rs backend.RunStatus, err error) {
 	// add to metrics
 	rd := time.Since(p.startedAt)
 	w.e.metrics.FinishRun(p.task, rs, rd)
+	if p.attempts > 0 {
+		w.e.metrics.RunAttempts(p.task, p.attempts)
+	}
 
 	// log error
 	if err != nil {
 		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), err.Error())
 		w.e.log.Debug("Execution failed", zap.Error(err), zap.String("taskID", p.task.ID.String()))
 		w.e.metrics.LogError(p.task.Type, err)
+		switch influxdb.ErrorCode(err) {
+		case influxdb.ETimeout:
+			w.e.metrics.LimitExceeded(p.task, "timeout")
+		case influxdb.EResourceExhausted:
+			w.e.metrics.LimitExceeded(p.task, "memory")
+		}
 
 		if backend.IsUnrecoverable(err) {
 			// TODO (al): once user notification system is put in place, this code should be uncommented
@@ -354,23 +409,93 @@ func (w *worker) finish(p *promise, rs backend.RunStatus, err error) {
 	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
 		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
+
+	if p.err == nil && w.e.runSucceeded != nil {
+		w.e.runSucceeded(p.task.ID, p.run.ScheduledFor)
+	}
 }
 
-func (w *worker) executeQuery(p *promise) {
-	span, ctx := tracing.StartSpanFromContext(p.ctx)
-	defer span.Finish()
+// executeQuery makes an attempt of the run, returning false if the attempt
+// failed and the run is queued again to be re-attempted.
+func (w *worker) executeQuery(p *promise) bool {
+	// A run is attempted at most retry times, so the default of 1 never
+	// re-attempts a failed run.
+	opts, err := options.FromScript(p.task.Flux)
+	if err != nil {
+		opts = options.Options{}
+	}
+	attempts := int64(1)
+	if opts.Retry != nil {
+		attempts = *opts.Retry
+	}
 
-	// start
-	w.start(p)
+	if p.attempts == 0 {
+		// start
+		w.start(p)
+	} else {
+		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Starting attempt %d of %d", p.attempts+1, attempts))
+	}
 
 	pkg, err := flux.Parse(p.task.Flux)
 	if err != nil {
 		w.finish(p, backend.RunFail, influxdb.ErrFluxParseError(err))
-		return
+		return true
+	}
+
+	p.attempts++
+	err = w.attempt(p, pkg, opts)
+	// A run over its limits would be over them again, so it is not re-attempted.
+	if err != nil && int64(p.attempts) < attempts && !backend.IsUnrecoverable(err) && !isLimitExceeded(err) {
+		backoff := w.e.backoff(p.attempts)
+		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Attempt %d of %d failed, retrying in %s: %v", p.attempts, attempts, backoff, err))
+		w.retry(p, backoff, err)
+		return false
 	}
 
+	if err != nil {
+		w.finish(p, backend.RunFail, err)
+		return true
+	}
+	w.finish(p, backend.RunSuccess, nil)
+	return true
+}
+
+// retry queues the run again once the backoff after its failed attempt passed.
+// No worker is held during the backoff.
+func (w *worker) retry(p *promise, backoff time.Duration, err error) {
+	go func() {
+		select {
+		case <-p.ctx.Done():
+			// The run was canceled, so it is not attempted again.
+			w.finish(p, backend.RunFail, err)
+			w.done(p)
+		case <-time.After(backoff):
+			w.e.promiseQueue <- p
+			w.e.startWorker()
+		}
+	}()
+}
+
+// attempt runs the query of the task once within the timeout and memoryLimit
+// options of the task, returning the error of a failed attempt.
+func (w *worker) attempt(p *promise, pkg *ast.Package, opts options.Options) error {
+	span, ctx := tracing.StartSpanFromContext(p.ctx)
+	defer span.Finish()
+
 	sf := p.run.ScheduledFor
 
+	var timeout time.Duration
+	if opts.Timeout != nil {
+		timeout, _ = opts.Timeout.DurationFrom(sf)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// timedOut tells whether the attempt ran out of time, rather than the run being canceled.
+	timedOut := func() bool {
+		return timeout > 0 && ctx.Err() == context.DeadlineExceeded && p.ctx.Err() == nil
+	}
+
 	req := &query.Request{
 		Authorization:  p.auth,
 		OrganizationID: p.task.OrganizationID,
@@ -379,12 +504,17 @@ func (w *worker) executeQuery(p *promise) {
 			Now: sf,
 		},
 	}
+	if opts.MemoryLimit != nil {
+		req.MemoryBytesLimit = *opts.MemoryLimit
+	}
 	ctx = icontext.SetAuthorizer(ctx, p.task.Authorization)
 	it, err := w.e.qs.Query(ctx, req)
 	if err != nil {
+		if timedOut() {
+			return influxdb.ErrRunTimedOut(timeout)
+		}
 		// Assume the error should not be part of the runResult.
-		w.finish(p, backend.RunFail, influxdb.ErrQueryError(err))
-		return
+		return influxdb.ErrQueryError(err)
 	}
 
 	var runErr error
@@ -405,17 +535,66 @@ func (w *worker) executeQuery(p *promise) {
 		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), msg)
 	}
 
+	if timedOut() {
+		return influxdb.ErrRunTimedOut(timeout)
+	}
+	if opts.MemoryLimit != nil {
+		for _, err := range []error{runErr, it.Err()} {
+			if isMemoryLimitError(err) {
+				return influxdb.ErrRunMemoryLimitExceeded(*opts.MemoryLimit, err)
+			}
+		}
+	}
+
 	if runErr != nil {
-		w.finish(p, backend.RunFail, influxdb.ErrRunExecutionError(runErr))
-		return
+		return influxdb.ErrRunExecutionError(runErr)
 	}
 
 	if it.Err() != nil {
-		w.finish(p, backend.RunFail, influxdb.ErrResultIteratorError(it.Err()))
-		return
+		return influxdb.ErrResultIteratorError(it.Err())
 	}
+	return nil
+}
 
-	w.finish(p, backend.RunSuccess, nil)
+// isLimitExceeded tells whether err is the failure of a run over the timeout or
+// memoryLimit options of its task.
+func isLimitExceeded(err error) bool {
+	switch influxdb.ErrorCode(err) {
+	case influxdb.ETimeout, influxdb.EResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// isMemoryLimitError tells whether err is caused by a query allocating more
+// memory than it is allowed to.
+func isMemoryLimitError(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case memory.LimitExceededError:
+			return true
+		case *flux.Error:
+			err = e.Err
+		case *influxdb.Error:
+			err = e.Err
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the attempt following the given attempt of a
+// failed run.
+func (e *Executor) backoff(attempt int) time.Duration {
+	d := e.retryBackoff
+	for i := 1; i < attempt && d < e.maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > e.maxRetryBackoff {
+		d = e.maxRetryBackoff
+	}
+	return d
 }
 
 // RunsActive returns the current number of workers, which is equivalent to
@@ -446,6 +625,9 @@ type promise struct {
 	createdAt time.Time
 	startedAt time.Time
 
+	// attempts is the number of times the run was attempted.
+	attempts int
+
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
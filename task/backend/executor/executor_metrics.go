@@ -16,8 +16,11 @@ type ExecutorMetrics struct {
 	errorsCounter        *prometheus.CounterVec
 	manualRunsCounter    *prometheus.CounterVec
 	resumeRunsCounter    *prometheus.CounterVec
+	retriesCounter       *prometheus.CounterVec
 	unrecoverableCounter *prometheus.CounterVec
 	runLatency           *prometheus.HistogramVec
+	runAttempts          *prometheus.HistogramVec
+	limitsCounter        *prometheus.CounterVec
 }
 
 type runCollector struct {
@@ -85,12 +88,34 @@ func NewExecutorMetrics(ex *Executor) *ExecutorMetrics {
 			Help:      "Total number of runs resumed by task ID",
 		}, []string{"taskID"}),
 
+		retriesCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_counter",
+			Help:      "Total number of re-attempts of failed runs by task ID",
+		}, []string{"taskID"}),
+
 		runLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "run_latency_seconds",
 			Help:      "Records the latency between the time the run was due to run and the time the task started execution, by task type",
 		}, []string{"task_type"}),
+
+		runAttempts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "run_attempts",
+			Help:      "The number of times each completed run was attempted, by task type",
+			Buckets:   prometheus.LinearBuckets(1, 1, 10),
+		}, []string{"task_type"}),
+
+		limitsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "limits_exceeded_counter",
+			Help:      "Total number of runs failed for exceeding the timeout or memory limit of their task, by task type and limit",
+		}, []string{"task_type", "limit"}),
 	}
 }
 
@@ -129,8 +154,11 @@ func (em *ExecutorMetrics) PrometheusCollectors() []prometheus.Collector {
 		em.runDuration,
 		em.manualRunsCounter,
 		em.resumeRunsCounter,
+		em.retriesCounter,
 		em.unrecoverableCounter,
 		em.runLatency,
+		em.runAttempts,
+		em.limitsCounter,
 	}
 }
 
@@ -151,6 +179,19 @@ func (em *ExecutorMetrics) FinishRun(task *influxdb.Task, status backend.RunStat
 	em.runDuration.WithLabelValues("", task.ID.String()).Observe(runDuration.Seconds())
 }
 
+// RunAttempts records the number of times a completed run of the task was attempted.
+func (em *ExecutorMetrics) RunAttempts(task *influxdb.Task, attempts int) {
+	em.runAttempts.WithLabelValues(task.Type).Observe(float64(attempts))
+	if attempts > 1 {
+		em.retriesCounter.WithLabelValues(task.ID.String()).Add(float64(attempts - 1))
+	}
+}
+
+// LimitExceeded increments the count of runs of the task failed for exceeding the limit, "timeout" or "memory".
+func (em *ExecutorMetrics) LimitExceeded(task *influxdb.Task, limit string) {
+	em.limitsCounter.WithLabelValues(task.Type, limit).Inc()
+}
+
 // LogError increments the count of errors by error code.
 func (em *ExecutorMetrics) LogError(taskType string, err error) {
 	switch e := err.(type) {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/influxdb"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
@@ -76,6 +77,12 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("Retry", testRetry)
+	t.Run("RetryExhausted", testRetryExhausted)
+	t.Run("RetryBackoff", testRetryBackoff)
+	t.Run("RunSucceeded", testRunSucceeded)
+	t.Run("Timeout", testTimeout)
+	t.Run("MemoryLimit", testMemoryLimit)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -527,6 +534,237 @@ func testErrorHandling(t *testing.T) {
 	*/
 }
 
+func testRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	tes.ex.retryBackoff, tes.ex.maxRetryBackoff = time.Millisecond, time.Millisecond
+
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(tes.metrics.PrometheusCollectors()...)
+
+	script := fmt.Sprintf(fmtRetryTestScript, t.Name(), 3)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first attempt fails, the second one succeeds
+	tes.svc.FailNextQuery(errors.New("transient storage error"))
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	<-promise.Done()
+
+	if got := promise.Error(); got != nil {
+		t.Fatal(got)
+	}
+
+	var found bool
+	for _, l := range tes.tcs.run.Log {
+		if strings.HasPrefix(l.Message, "Attempt 1 of 3 failed, retrying in 1ms:") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the failed attempt in the run log, got %v", tes.tcs.run.Log)
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "task_executor_retries_counter", map[string]string{"taskID": task.ID.String()})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 retry, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mg, "task_executor_run_attempts", map[string]string{"task_type": ""})
+	if got := *m.Histogram.SampleSum; got != 2 {
+		t.Fatalf("expected 2 attempts, got %v", got)
+	}
+}
+
+func testRetryExhausted(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	tes.ex.retryBackoff, tes.ex.maxRetryBackoff = time.Millisecond, time.Millisecond
+
+	script := fmt.Sprintf(fmtRetryTestScript, t.Name(), 2)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.FailNextQuery(errors.New("transient storage error"))
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("still failing"))
+
+	<-promise.Done()
+
+	if got := promise.Error(); got == nil || !strings.Contains(got.Error(), "still failing") {
+		t.Fatalf("expected the error of the last attempt, got %v", got)
+	}
+}
+
+func testRetryBackoff(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	tes.ex.retryBackoff, tes.ex.maxRetryBackoff = time.Hour, time.Hour
+
+	script := fmt.Sprintf(fmtRetryTestScript, t.Name(), 2)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.FailNextQuery(errors.New("transient storage error"))
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the worker is released while the run waits to be attempted again
+	deadline := time.Now().Add(time.Second)
+	for tes.ex.RunsActive() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected no active worker during the backoff, got %d", tes.ex.RunsActive())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case <-promise.Done():
+		t.Fatal("expected the run to wait for its next attempt")
+	default:
+	}
+
+	// canceling the run during the backoff fails it
+	promise.Cancel(ctx)
+	<-promise.Done()
+
+	if got := promise.Error(); got == nil || !strings.Contains(got.Error(), "transient storage error") {
+		t.Fatalf("expected the error of the failed attempt, got %v", got)
+	}
+	if tes.tcs.run.Status != "failed" {
+		t.Fatalf("expected the run to fail, got %q", tes.tcs.run.Status)
+	}
+}
+
+func testTimeout(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	tes.ex.retryBackoff, tes.ex.maxRetryBackoff = time.Millisecond, time.Millisecond
+
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(tes.metrics.PrometheusCollectors()...)
+
+	script := fmt.Sprintf(fmtLimitsTestScript, t.Name(), "1s", 1024)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// never finish the query, so that the attempt runs out of time
+	tes.svc.WaitForQueryLive(t, script)
+	<-promise.Done()
+
+	if got := influxdb.ErrorCode(promise.Error()); got != influxdb.ETimeout {
+		t.Fatalf("expected a timeout error, got %v", promise.Error())
+	}
+	if tes.tcs.run.Status != "failed" {
+		t.Fatalf("expected the run to fail, got %q", tes.tcs.run.Status)
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "task_executor_limits_exceeded_counter", map[string]string{"task_type": "", "limit": "timeout"})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 timed out run, got %v", got)
+	}
+	// a run over its timeout is not re-attempted, despite its retry option
+	m = promtest.MustFindMetric(t, mg, "task_executor_run_attempts", map[string]string{"task_type": ""})
+	if got := *m.Histogram.SampleSum; got != 1 {
+		t.Fatalf("expected 1 attempt, got %v", got)
+	}
+}
+
+func testMemoryLimit(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	tes.ex.retryBackoff, tes.ex.maxRetryBackoff = time.Millisecond, time.Millisecond
+
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(tes.metrics.PrometheusCollectors()...)
+
+	script := fmt.Sprintf(fmtLimitsTestScript, t.Name(), "1m", 1024)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.mu.Lock()
+	limit := tes.svc.mostRecentReq.MemoryBytesLimit
+	tes.svc.mu.Unlock()
+	if limit != 1024 {
+		t.Fatalf("expected the query to be limited to 1024 bytes, got %d", limit)
+	}
+
+	// the controller reports the allocation failure as an invalid query
+	tes.svc.FailQuery(script, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "memory allocation limit reached",
+		Err:  memory.LimitExceededError{Limit: 1024, Allocated: 1000, Wanted: 100},
+	})
+	<-promise.Done()
+
+	if got := influxdb.ErrorCode(promise.Error()); got != influxdb.EResourceExhausted {
+		t.Fatalf("expected a resource exhausted error, got %v", promise.Error())
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "task_executor_limits_exceeded_counter", map[string]string{"task_type": "", "limit": "memory"})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 run over its memory limit, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mg, "task_executor_run_attempts", map[string]string{"task_type": ""})
+	if got := *m.Histogram.SampleSum; got != 1 {
+		t.Fatalf("expected 1 attempt, got %v", got)
+	}
+}
+
+func TestExecutor_backoff(t *testing.T) {
+	e := &Executor{retryBackoff: time.Second, maxRetryBackoff: 5 * time.Second}
+
+	for attempt, exp := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := e.backoff(attempt + 1); got != exp {
+			t.Errorf("attempt %d: got backoff %v, exp %v", attempt+1, got, exp)
+		}
+	}
+}
+
 type taskControlService struct {
 	backend.TaskControlService
 
@@ -543,3 +781,48 @@ func (t *taskControlService) FinishRun(ctx context.Context, taskID influxdb.ID,
 	t.run, err = t.TaskControlService.FinishRun(ctx, taskID, runID)
 	return t.run, err
 }
+
+func testRunSucceeded(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	var (
+		mu        sync.Mutex
+		succeeded []influxdb.ID
+	)
+	tes.ex.SetRunSucceededFunc(func(taskID influxdb.ID, scheduledFor time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !scheduledFor.Equal(time.Unix(123, 0)) {
+			t.Errorf("got run scheduled for %v, want %v", scheduledFor, time.Unix(123, 0))
+		}
+		succeeded = append(succeeded, taskID)
+	})
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	run := func(name string, finish func(script string)) influxdb.ID {
+		script := fmt.Sprintf(fmtTestScript, t.Name()+name)
+		task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tes.svc.WaitForQueryLive(t, script)
+		finish(script)
+		<-promise.Done()
+		return task.ID
+	}
+
+	run("Failure", func(script string) { tes.svc.FailQuery(script, errors.New("blargyblargblarg")) })
+	id := run("Success", tes.svc.SucceedQuery)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(succeeded) != 1 || succeeded[0] != id {
+		t.Fatalf("expected only the run of task %s to succeed, got %v", id, succeeded)
+	}
+}
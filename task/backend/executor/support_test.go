@@ -26,6 +26,8 @@ type fakeQueryService struct {
 	// The most recent ctx received in the Query method.
 	// Used to validate that the executor applied the correct authorizer.
 	mostRecentCtx context.Context
+	// The most recent request received in the Query method.
+	mostRecentReq *query.Request
 }
 
 var _ query.AsyncQueryService = (*fakeQueryService)(nil)
@@ -61,6 +63,7 @@ func (s *fakeQueryService) Query(ctx context.Context, req *query.Request) (flux.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.mostRecentCtx = ctx
+	s.mostRecentReq = req
 	if s.queryErr != nil {
 		err := s.queryErr
 		s.queryErr = nil
@@ -295,3 +298,23 @@ option task = {
 			every: 1m,
 }
 from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+// fmtRetryTestScript is like fmtTestScript, with a retry option.
+const fmtRetryTestScript = `
+option task = {
+			name: %q,
+			every: 1m,
+			retry: %d,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+// fmtLimitsTestScript is like fmtRetryTestScript, with timeout and memoryLimit options.
+const fmtLimitsTestScript = `
+option task = {
+			name: %q,
+			every: 1m,
+			retry: 2,
+			timeout: %s,
+			memoryLimit: %d,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
@@ -44,6 +44,20 @@ type Schedulable interface {
 	LastScheduled() time.Time
 }
 
+// Dependent is a Schedulable that is triggered by its upstream Schedulables
+// rather than by its Schedule. It is executed for a scheduled time of its
+// Schedule once all of its upstream Schedulables have succeeded for that time.
+//
+// The upstream Schedulables that succeeded are not persisted: a scheduled time
+// for which only some of them succeeded before a restart is never executed.
+type Dependent interface {
+	Schedulable
+
+	// DependsOn returns the IDs of the upstream Schedulables. A Dependent
+	// without any is scheduled like any other Schedulable.
+	DependsOn() []ID
+}
+
 // SchedulableService encapsulates the work necessary to schedule a job
 type SchedulableService interface {
 
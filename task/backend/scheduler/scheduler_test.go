@@ -39,6 +39,15 @@ func (s mockSchedulable) LastScheduled() time.Time {
 	return s.lastScheduled
 }
 
+type mockDependent struct {
+	mockSchedulable
+	dependsOn []ID
+}
+
+func (s mockDependent) DependsOn() []ID {
+	return s.dependsOn
+}
+
 func (e *mockExecutor) Execute(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time) error {
 	done := make(chan struct{}, 1)
 	select {
@@ -666,3 +675,189 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestTreeScheduler_Dependents(t *testing.T) {
+	type execution struct {
+		id           ID
+		scheduledFor time.Time
+	}
+	c := make(chan execution, 100)
+	exe := &mockExecutor{fn: func(l *sync.Mutex, ctx context.Context, id ID, scheduledFor time.Time) {
+		c <- execution{id: id, scheduledFor: scheduledFor}
+	}}
+	mockTime := clock.NewMock()
+	mockTime.Set(time.Now())
+	sch, _, err := NewScheduler(exe, &mockSchedulableService{}, WithTime(mockTime), WithMaxConcurrentWorkers(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sch.Stop()
+
+	schedule, ts, err := NewSchedule("@every 1h", mockTime.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 depends on 1 and 2.
+	if err := sch.Schedule(mockDependent{
+		mockSchedulable: mockSchedulable{id: 3, schedule: schedule, lastScheduled: ts},
+		dependsOn:       []ID{1, 2},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a Dependent is not run on its own schedule.
+	sch.mu.Lock()
+	scheduled := sch.scheduled.Len()
+	sch.mu.Unlock()
+	if scheduled != 0 {
+		t.Fatalf("expected the dependent not to be in the tree, got %d items", scheduled)
+	}
+
+	sf := ts.Add(time.Hour)
+	sch.Succeeded(1, sf)
+	sch.Succeeded(2, sf.Add(time.Hour))
+	select {
+	case e := <-c:
+		t.Fatalf("expected no execution before all upstream succeeded, got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// times that are not on the schedule of the dependent do not trigger it.
+	sch.Succeeded(1, sf.Add(30*time.Minute))
+	sch.Succeeded(2, sf.Add(30*time.Minute))
+	select {
+	case e := <-c:
+		t.Fatalf("expected no execution for a time off the dependent's schedule, got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sch.Succeeded(2, sf)
+	select {
+	case e := <-c:
+		if e.id != 3 || !e.scheduledFor.Equal(sf) {
+			t.Fatalf("got execution of %d for %s, want 3 for %s", e.id, e.scheduledFor, sf)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dependent was not executed once its upstream succeeded")
+	}
+
+	// once released, a Dependent is no longer triggered.
+	if err := sch.Release(3); err != nil {
+		t.Fatal(err)
+	}
+	sch.Succeeded(1, sf.Add(time.Hour))
+	select {
+	case e := <-c:
+		t.Fatalf("expected no execution of a released dependent, got %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTreeScheduler_DependentsRestart(t *testing.T) {
+	c := make(chan time.Time, 100)
+	exe := &mockExecutor{fn: func(l *sync.Mutex, ctx context.Context, id ID, scheduledFor time.Time) {
+		c <- scheduledFor
+	}}
+	mockTime := clock.NewMock()
+	mockTime.Set(time.Now())
+	schedule, ts, err := NewSchedule("@every 1h", mockTime.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := mockDependent{
+		mockSchedulable: mockSchedulable{id: 3, schedule: schedule, lastScheduled: ts},
+		dependsOn:       []ID{1, 2},
+	}
+	sf := ts.Add(time.Hour)
+
+	sch, _, err := NewScheduler(exe, &mockSchedulableService{}, WithTime(mockTime), WithMaxConcurrentWorkers(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Schedule(dep); err != nil {
+		t.Fatal(err)
+	}
+	sch.Succeeded(1, sf)
+	sch.Stop()
+
+	// the success of 1 is pending in memory only, so a new scheduler forgets it.
+	sch, _, err = NewScheduler(exe, &mockSchedulableService{}, WithTime(mockTime), WithMaxConcurrentWorkers(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sch.Stop()
+	if err := sch.Schedule(dep); err != nil {
+		t.Fatal(err)
+	}
+	sch.Succeeded(2, sf)
+	select {
+	case got := <-c:
+		t.Fatalf("expected an upstream success from before the restart to be forgotten, got execution for %s", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sch.Succeeded(1, sf)
+	select {
+	case got := <-c:
+		if !got.Equal(sf) {
+			t.Fatalf("got execution for %s, want %s", got, sf)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dependent was not executed once its upstream succeeded again")
+	}
+}
+
+func TestDependent_succeed(t *testing.T) {
+	schedule, ts, err := NewSchedule("@every 1s", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := newDependent(mockDependent{mockSchedulable: mockSchedulable{id: 3, schedule: schedule, lastScheduled: ts}, dependsOn: []ID{1, 2}})
+
+	if d.succeed(4, 1) {
+		t.Fatal("expected a schedulable that is not upstream to be ignored")
+	}
+	if d.succeed(1, 0) {
+		t.Fatal("expected a scheduled time that is not after the last scheduled time to be ignored")
+	}
+	for i := int64(1); i <= maxPendingTriggers+1; i++ {
+		if d.succeed(1, i) {
+			t.Fatalf("expected %d not to trigger with one upstream succeeded", i)
+		}
+	}
+	if len(d.succeeded) != maxPendingTriggers {
+		t.Fatalf("got %d pending scheduled times, want %d", len(d.succeeded), maxPendingTriggers)
+	}
+	if d.succeed(2, 1) {
+		t.Fatal("expected the oldest pending scheduled time to be dropped")
+	}
+	if !d.succeed(2, maxPendingTriggers+1) {
+		t.Fatal("expected the scheduled time to trigger once all upstream succeeded")
+	}
+	if !d.succeed(2, 2) {
+		t.Fatal("expected an earlier pending scheduled time to still trigger")
+	}
+}
+
+func TestDependent_scheduled(t *testing.T) {
+	schedule, ts, err := NewSchedule("@every 1h", time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := newDependent(mockDependent{mockSchedulable: mockSchedulable{id: 3, schedule: schedule, lastScheduled: ts}, dependsOn: []ID{1}})
+
+	for _, tt := range []struct {
+		sf   time.Time
+		want bool
+	}{
+		{sf: ts, want: false},
+		{sf: ts.Add(30 * time.Minute), want: false},
+		{sf: ts.Add(time.Hour), want: true},
+		{sf: ts.Add(48 * time.Hour), want: true},
+		{sf: ts.Add(48*time.Hour + time.Second), want: false},
+	} {
+		if got := d.scheduled(tt.sf.Unix()); got != tt.want {
+			t.Errorf("scheduled(%s): got %v, want %v", tt.sf, got, tt.want)
+		}
+	}
+}
@@ -19,6 +19,10 @@ const (
 
 	// defaultMaxWorkers is a constant that sets the default number of maximum workers for a TreeScheduler
 	defaultMaxWorkers = 128
+
+	// maxPendingTriggers is the number of scheduled times a Dependent waits on its upstream Schedulables for.
+	// Past it, the oldest scheduled time is dropped, as some upstream Schedulable must have failed for it.
+	maxPendingTriggers = 100
 )
 
 // TreeScheduler is a Scheduler based on a btree.
@@ -60,6 +64,12 @@ const (
 // Removing a task from the scheduler acquires a write lock, deletes the task from the uniqueness index and from the
 // btree, then releases the lock.  We do not have to readjust the time on delete, because, if the minimum task isn't
 // ready yet, the main loop just resets the timer and keeps going.
+//
+// Dependents:
+//
+// A Dependent with upstream Schedulables is kept out of the btree.  Succeeded records which upstream Schedulables
+// succeeded for a scheduled time, and once all of them have, queues the Dependent for that time.  A dispatcher
+// goroutine hands the queued items to the workers in order, so runs of a Dependent are still serial.
 type TreeScheduler struct {
 	mu           sync.RWMutex
 	scheduled    *btree.BTree
@@ -74,6 +84,11 @@ type TreeScheduler struct {
 	wg           sync.WaitGroup
 	checkpointer SchedulableService
 
+	dependents map[ID]*dependent
+	triggered  []Item
+	trigger    chan struct{}
+	dispatcher sync.WaitGroup
+
 	sm *SchedulerMetrics
 }
 
@@ -118,6 +133,8 @@ func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...tr
 		time:         clock.New(),
 		done:         make(chan struct{}, 1),
 		checkpointer: checkpointer,
+		dependents:   map[ID]*dependent{},
+		trigger:      make(chan struct{}, 1),
 	}
 
 	// apply options
@@ -137,6 +154,9 @@ func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...tr
 		go s.work(context.Background(), s.workchans[i])
 	}
 
+	s.dispatcher.Add(1)
+	go s.dispatch()
+
 	s.sm = NewSchedulerMetrics(s)
 	s.when = time.Time{}
 	s.timer = s.time.Timer(0)
@@ -153,6 +173,8 @@ func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...tr
 		for {
 			select {
 			case <-s.done:
+				// the dispatcher sends to the workchans too, so wait for it to stop before closing them.
+				s.dispatcher.Wait()
 				s.mu.Lock()
 				s.timer.Stop()
 				// close workchans
@@ -244,11 +266,8 @@ func (s *TreeScheduler) iterator(ts time.Time) (btree.ItemIterator, *itemList) {
 		}
 		// distribute to the right worker.
 		{
-			buf := [8]byte{}
-			binary.LittleEndian.PutUint64(buf[:], uint64(it.id))
-			wc := xxhash.Sum64(buf[:]) % uint64(len(s.workchans)) // we just hash so that the number is uniformly distributed
 			select {
-			case s.workchans[wc] <- it:
+			case s.workchan(it.id) <- it:
 				itemsToPlace.toDelete = append(itemsToPlace.toDelete, it)
 				if err := it.updateNext(); err != nil {
 					// in this error case we can't schedule next, so we have to drop the task
@@ -270,6 +289,14 @@ func (s *TreeScheduler) iterator(ts time.Time) (btree.ItemIterator, *itemList) {
 	}, itemsToPlace
 }
 
+// workchan returns the channel of the worker for the ID.
+func (s *TreeScheduler) workchan(id ID) chan Item {
+	buf := [8]byte{}
+	binary.LittleEndian.PutUint64(buf[:], uint64(id))
+	wc := xxhash.Sum64(buf[:]) % uint64(len(s.workchans)) // we just hash so that the number is uniformly distributed
+	return s.workchans[wc]
+}
+
 // When gives us the next time the scheduler will run a task.
 func (s *TreeScheduler) When() time.Time {
 	s.mu.RLock()
@@ -289,6 +316,59 @@ func (s *TreeScheduler) release(taskID ID) {
 	delete(s.nextTime, taskID)
 }
 
+// Succeeded tells the scheduler that the execution of the ID for scheduledFor succeeded, triggering the
+// Dependents whose upstream Schedulables have all succeeded for scheduledFor, if it is on their schedule.
+func (s *TreeScheduler) Succeeded(id ID, scheduledFor time.Time) {
+	sf := scheduledFor.UTC().Unix()
+
+	s.mu.Lock()
+	n := len(s.triggered)
+	for _, d := range s.dependents {
+		if d.succeed(id, sf) {
+			s.triggered = append(s.triggered, Item{
+				ordering: ordering{when: sf + d.offset},
+				id:       d.id,
+				next:     sf,
+				Offset:   d.offset,
+			})
+		}
+	}
+	triggered := len(s.triggered) > n
+	s.mu.Unlock()
+
+	if triggered {
+		select {
+		case s.trigger <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// dispatch hands the triggered Dependents to the workers until the scheduler is stopped.
+func (s *TreeScheduler) dispatch() {
+	defer s.dispatcher.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.trigger:
+		}
+
+		s.mu.Lock()
+		items := s.triggered
+		s.triggered = nil
+		s.mu.Unlock()
+
+		for _, it := range items {
+			select {
+			case s.workchan(it.id) <- it:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
 // Release releases a task.
 // Release also cancels the running task.
 // Task deletion would be faster if the tree supported deleting ranges.
@@ -296,6 +376,7 @@ func (s *TreeScheduler) Release(taskID ID) error {
 	s.sm.release(taskID)
 	s.mu.Lock()
 	s.release(taskID)
+	delete(s.dependents, taskID)
 	s.mu.Unlock()
 	return nil
 }
@@ -336,6 +417,15 @@ func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 // Schedule put puts a Schedulable on the TreeScheduler.
 func (s *TreeScheduler) Schedule(sch Schedulable) error {
 	s.sm.schedule(sch.ID())
+	if d, ok := sch.(Dependent); ok && len(d.DependsOn()) > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// a Dependent is triggered by its upstream Schedulables instead of the tree.
+		s.release(d.ID())
+		s.dependents[d.ID()] = newDependent(d)
+		return nil
+	}
+
 	it := Item{
 		cron:   sch.Schedule(),
 		id:     sch.ID(),
@@ -365,6 +455,7 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 			s.timer.Reset(s.when.Sub(s.time.Now()))
 		}
 	}
+	delete(s.dependents, it.id)
 	nextTime, ok := s.nextTime[it.id]
 
 	if ok {
@@ -381,6 +472,97 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 	return nil
 }
 
+// dependent tracks the upstream Schedulables that succeeded for the scheduled times of a Dependent.
+// The succeeded upstream Schedulables are only kept in memory, so scheduled times that are pending
+// when the scheduler stops are not triggered once it restarts.
+type dependent struct {
+	id       ID
+	cron     Schedule
+	last     int64 // a scheduled time of the Dependent before all of the pending ones.
+	offset   int64
+	upstream map[ID]struct{}
+
+	// succeeded are the upstream Schedulables that succeeded, by scheduled time.
+	succeeded map[int64]map[ID]struct{}
+}
+
+func newDependent(d Dependent) *dependent {
+	upstream := make(map[ID]struct{}, len(d.DependsOn()))
+	for _, id := range d.DependsOn() {
+		upstream[id] = struct{}{}
+	}
+	return &dependent{
+		id:        d.ID(),
+		cron:      d.Schedule(),
+		last:      d.LastScheduled().UTC().Unix(),
+		offset:    int64(d.Offset().Seconds()),
+		upstream:  upstream,
+		succeeded: map[int64]map[ID]struct{}{},
+	}
+}
+
+// succeed records that the upstream Schedulable succeeded for the scheduled time, and returns whether all of
+// the upstream Schedulables have. Scheduled times that are not on the schedule of the Dependent are ignored.
+func (d *dependent) succeed(upstream ID, scheduledFor int64) bool {
+	if _, ok := d.upstream[upstream]; !ok {
+		return false
+	}
+	if !d.scheduled(scheduledFor) {
+		return false
+	}
+
+	done, ok := d.succeeded[scheduledFor]
+	if !ok {
+		if len(d.succeeded) >= maxPendingTriggers {
+			oldest := scheduledFor
+			for sf := range d.succeeded {
+				if sf < oldest {
+					oldest = sf
+				}
+			}
+			if oldest == scheduledFor {
+				// older than all the pending scheduled times, so it would be dropped right away.
+				return false
+			}
+			delete(d.succeeded, oldest)
+		}
+		done = map[ID]struct{}{}
+		d.succeeded[scheduledFor] = done
+	}
+
+	done[upstream] = struct{}{}
+	if len(done) < len(d.upstream) {
+		return false
+	}
+	delete(d.succeeded, scheduledFor)
+	return true
+}
+
+// scheduled returns whether scheduledFor is one of the scheduled times of the Dependent after its last
+// scheduled time. As the scheduled times are found by walking the Schedule, last is moved up to the latest
+// one before all of the pending scheduled times.
+func (d *dependent) scheduled(scheduledFor int64) bool {
+	floor := scheduledFor
+	for sf := range d.succeeded {
+		if sf < floor {
+			floor = sf
+		}
+	}
+
+	for t := d.last; ; {
+		next, err := d.cron.Next(time.Unix(t, 0).UTC())
+		if err != nil || next.Unix() > scheduledFor {
+			return false
+		} else if next.Unix() == scheduledFor {
+			return true
+		}
+		t = next.Unix()
+		if t < floor {
+			d.last = t
+		}
+	}
+}
+
 type ordering struct {
 	when  int64
 	nonce int // for retries
@@ -0,0 +1,184 @@
+// Package trigger runs tasks soon after points are written to the buckets they
+// watch, rather than only on their schedule.
+package trigger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+var _ storage.PointsWriter = (*WriteTrigger)(nil)
+
+// Executor executes the runs triggered by writes.
+type Executor interface {
+	PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error)
+}
+
+// WriteTrigger is a storage.PointsWriter that runs the tasks watching the
+// buckets of the points it writes. A task runs at the end of the debounce
+// window opened by the first matching write, for the second the window ends.
+type WriteTrigger struct {
+	storage.PointsWriter
+
+	log  *zap.Logger
+	ex   Executor
+	time clock.Clock
+
+	mu      sync.Mutex
+	watches map[influxdb.ID][]*watch // by bucket ID
+	pending map[influxdb.ID]*clock.Timer
+}
+
+// WriteTriggerOption is a functional option for the WriteTrigger.
+type WriteTriggerOption func(*WriteTrigger)
+
+// WithTime sets the clock the WriteTrigger debounces writes with.
+func WithTime(t clock.Clock) WriteTriggerOption {
+	return func(w *WriteTrigger) {
+		w.time = t
+	}
+}
+
+// NewWriteTrigger returns a WriteTrigger writing points to pw and running the
+// tasks they trigger with ex.
+func NewWriteTrigger(log *zap.Logger, pw storage.PointsWriter, ex Executor, opts ...WriteTriggerOption) *WriteTrigger {
+	w := &WriteTrigger{
+		PointsWriter: pw,
+		log:          log,
+		ex:           ex,
+		time:         clock.New(),
+		watches:      make(map[influxdb.ID][]*watch),
+		pending:      make(map[influxdb.ID]*clock.Timer),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+type watch struct {
+	taskID      influxdb.ID
+	measurement string
+	tags        map[string]string
+	debounce    time.Duration
+}
+
+// match tells whether the point is one of the watched points of its bucket.
+func (w *watch) match(p models.Point) bool {
+	tags := p.Tags()
+	if w.measurement != "" && tags.GetString(models.MeasurementTagKey) != w.measurement {
+		return false
+	}
+	for k, v := range w.tags {
+		if tags.GetString(k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Watch starts running the task on the writes matching its write trigger, in
+// place of any previous trigger of the task. An inactive task or one without
+// a write trigger is not watched.
+func (w *WriteTrigger) Watch(task *influxdb.Task) {
+	w.Unwatch(task.ID)
+	if task.OnWrite == nil || task.Status == string(backend.TaskInactive) {
+		return
+	}
+
+	debounce, err := task.OnWrite.DebounceDuration()
+	if err != nil {
+		w.log.Info("Not watching writes for task with invalid debounce", zap.String("taskID", task.ID.String()), zap.Error(err))
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	bucketID := task.OnWrite.BucketID
+	w.watches[bucketID] = append(w.watches[bucketID], &watch{
+		taskID:      task.ID,
+		measurement: task.OnWrite.Measurement,
+		tags:        task.OnWrite.Tags,
+		debounce:    debounce,
+	})
+}
+
+// Unwatch stops running the task on writes, including a run waiting on its debounce.
+func (w *WriteTrigger) Unwatch(taskID influxdb.ID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for bucketID, watches := range w.watches {
+		for i, wt := range watches {
+			if wt.taskID != taskID {
+				continue
+			}
+			watches = append(watches[:i], watches[i+1:]...)
+			if len(watches) == 0 {
+				delete(w.watches, bucketID)
+			} else {
+				w.watches[bucketID] = watches
+			}
+			break
+		}
+	}
+
+	if t, ok := w.pending[taskID]; ok {
+		t.Stop()
+		delete(w.pending, taskID)
+	}
+}
+
+// WritePoints writes the points to the underlying PointsWriter, then triggers
+// the tasks watching them.
+func (w *WriteTrigger) WritePoints(ctx context.Context, points []models.Point) error {
+	if err := w.PointsWriter.WritePoints(ctx, points); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.watches) == 0 {
+		return nil
+	}
+
+	for _, p := range points {
+		_, bucketID := tsdb.DecodeNameSlice(p.Name())
+		for _, wt := range w.watches[bucketID] {
+			if _, ok := w.pending[wt.taskID]; ok || !wt.match(p) {
+				continue
+			}
+			taskID := wt.taskID
+			w.pending[taskID] = w.time.AfterFunc(wt.debounce, func() { w.run(taskID) })
+		}
+	}
+	return nil
+}
+
+// run starts a run of the task once its debounce window is over.
+func (w *WriteTrigger) run(taskID influxdb.ID) {
+	w.mu.Lock()
+	if _, ok := w.pending[taskID]; !ok {
+		// the task stopped being watched in the meantime.
+		w.mu.Unlock()
+		return
+	}
+	delete(w.pending, taskID)
+	w.mu.Unlock()
+
+	now := w.time.Now().UTC()
+	if _, err := w.ex.PromisedExecute(context.Background(), scheduler.ID(taskID), now.Truncate(time.Second), now); err != nil {
+		w.log.Info("Failed to run task triggered by write", zap.String("taskID", taskID.String()), zap.Error(err))
+	}
+}
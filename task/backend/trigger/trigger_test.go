@@ -0,0 +1,123 @@
+package trigger_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/backend/trigger"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	orgID    = influxdb.ID(1)
+	bucketID = influxdb.ID(2)
+)
+
+type pointsWriter struct {
+	n int
+}
+
+func (w *pointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	w.n += len(points)
+	return nil
+}
+
+type execution struct {
+	id           scheduler.ID
+	scheduledFor time.Time
+}
+
+type fakeExecutor struct {
+	mu         sync.Mutex
+	executions []execution
+}
+
+func (e *fakeExecutor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.executions = append(e.executions, execution{id: id, scheduledFor: scheduledFor})
+	return nil, nil
+}
+
+func (e *fakeExecutor) executed() []execution {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]execution(nil), e.executions...)
+}
+
+func mustWrite(t *testing.T, w *trigger.WriteTrigger, bucketID influxdb.ID, lp string) {
+	t.Helper()
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	points, err := models.ParsePoints([]byte(lp), models.EscapeMeasurement(encoded[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteTrigger(t *testing.T) {
+	mockTime := clock.NewMock()
+	mockTime.Set(time.Unix(1000, 0))
+	pw := &pointsWriter{}
+	ex := &fakeExecutor{}
+	w := trigger.NewWriteTrigger(zaptest.NewLogger(t), pw, ex, trigger.WithTime(mockTime))
+
+	w.Watch(&influxdb.Task{ID: 10, Status: "active", OnWrite: &influxdb.TaskWriteTrigger{BucketID: bucketID}})
+	w.Watch(&influxdb.Task{ID: 11, Status: "active", OnWrite: &influxdb.TaskWriteTrigger{
+		BucketID:    bucketID,
+		Measurement: "cpu",
+		Tags:        map[string]string{"host": "a"},
+		Debounce:    "10s",
+	}})
+	w.Watch(&influxdb.Task{ID: 12, Status: "inactive", OnWrite: &influxdb.TaskWriteTrigger{BucketID: bucketID}})
+	w.Watch(&influxdb.Task{ID: 13, Status: "active"})
+
+	// writes to other buckets or not matching the predicate do not trigger the tasks.
+	mustWrite(t, w, bucketID+1, "cpu,host=a v=1")
+	mustWrite(t, w, bucketID, "mem,host=a v=1\ncpu,host=b v=1")
+	mustWrite(t, w, bucketID, "cpu,host=a v=1")
+	mockTime.Add(time.Second)
+	mustWrite(t, w, bucketID, "cpu,host=a v=2")
+	if pw.n != 5 {
+		t.Fatalf("expected all the points to be written, got %d", pw.n)
+	}
+	if got := ex.executed(); len(got) != 0 {
+		t.Fatalf("expected no run before the end of the debounce, got %v", got)
+	}
+
+	mockTime.Add(influxdb.DefaultTaskWriteDebounce)
+	mockTime.Add(10 * time.Second)
+
+	got := ex.executed()
+	exp := []execution{
+		{id: 10, scheduledFor: time.Unix(1005, 0).UTC()},
+		{id: 11, scheduledFor: time.Unix(1010, 0).UTC()},
+	}
+	if len(got) != len(exp) {
+		t.Fatalf("got runs %v, want %v", got, exp)
+	}
+	for i := range exp {
+		if got[i].id != exp[i].id || !got[i].scheduledFor.Equal(exp[i].scheduledFor) {
+			t.Fatalf("got runs %v, want %v", got, exp)
+		}
+	}
+
+	// a pending run is dropped once the task is no longer watched.
+	mustWrite(t, w, bucketID, "cpu,host=a v=3")
+	w.Unwatch(10)
+	w.Watch(&influxdb.Task{ID: 11, Status: "inactive", OnWrite: &influxdb.TaskWriteTrigger{BucketID: bucketID}})
+	mockTime.Add(time.Minute)
+	if got := ex.executed(); len(got) != len(exp) {
+		t.Fatalf("expected no run of unwatched tasks, got %v", got)
+	}
+}
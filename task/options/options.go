@@ -38,6 +38,12 @@ type Options struct {
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Timeout is the longest an attempt of a run may take before it fails.
+	Timeout *Duration `json:"timeout,omitempty"`
+
+	// MemoryLimit is the number of bytes an attempt of a run may allocate before it fails.
+	MemoryLimit *int64 `json:"memoryLimit,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -127,6 +133,8 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Timeout = nil
+	o.MemoryLimit = nil
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -136,7 +144,9 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Timeout == nil &&
+		o.MemoryLimit == nil
 }
 
 // All the task option names we accept.
@@ -147,6 +157,8 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optTimeout     = "timeout"
+	optMemoryLimit = "memoryLimit"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -210,7 +222,7 @@ func FromScript(script string) (Options, error) {
 	if err != nil {
 		return opt, err
 	}
-	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset)
+	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset, optTimeout)
 	// TODO(desa): should be dependencies.NewEmpty(), but for now we'll hack things together
 	ctx := newDeps().Inject(context.Background())
 	_, scope, err := flux.EvalAST(ctx, fluxAST)
@@ -313,6 +325,28 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if timeoutVal, ok := optObject.Get(optTimeout); ok {
+		if err := checkNature(timeoutVal.PolyType().Nature(), semantic.Duration); err != nil {
+			return opt, err
+		}
+		dur, ok := durTypes[optTimeout]
+		if !ok || dur == nil {
+			return opt, ErrParseTaskOptionField(optTimeout)
+		}
+		durNode, err := parseSignedDuration(dur.Location().Source)
+		if err != nil {
+			return opt, err
+		}
+		opt.Timeout = &Duration{Node: *durNode}
+	}
+
+	if memoryLimitVal, ok := optObject.Get(optMemoryLimit); ok {
+		if err := checkNature(memoryLimitVal.PolyType().Nature(), semantic.Int); err != nil {
+			return opt, err
+		}
+		opt.MemoryLimit = pointer.Int64(memoryLimitVal.Int())
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -373,6 +407,20 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	if o.Timeout != nil {
+		timeout, err := o.Timeout.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if timeout < time.Second {
+			errs = append(errs, "timeout option must be at least 1 second")
+		} else if timeout.Truncate(time.Second) != timeout {
+			errs = append(errs, "timeout option must be expressible as whole seconds")
+		}
+	}
+	if o.MemoryLimit != nil && *o.MemoryLimit < 1 {
+		errs = append(errs, "memoryLimit must be at least 1 byte")
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -414,7 +462,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTimeout, optMemoryLimit:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -423,7 +471,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTimeout, optMemoryLimit}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
@@ -33,6 +33,12 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.Timeout != nil && !(*opt.Timeout).IsZero() {
+		taskData = fmt.Sprintf("%s  timeout: %s,\n", taskData, opt.Timeout.String())
+	}
+	if opt.MemoryLimit != nil && *opt.MemoryLimit != 0 {
+		taskData = fmt.Sprintf("%s  memoryLimit: %d,\n", taskData, *opt.MemoryLimit)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -77,6 +83,10 @@ func TestFromScript(t *testing.T) {
 		{script: scriptGenerator(options.Options{Name: "name7", Retry: pointer.Int64(20), Every: *(options.MustParseDuration("1h"))}, ""), shouldErr: true},
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name12", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("10m"), MemoryLimit: pointer.Int64(1 << 20)}, ""),
+			exp: options.Options{Name: "name12", Every: *(options.MustParseDuration("1h")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Timeout: options.MustParseDuration("10m"), MemoryLimit: pointer.Int64(1 << 20)}},
+		{script: scriptGenerator(options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), Timeout: options.MustParseDuration("500ms")}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name14\",\n  memoryLimit: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
 		{script: `option task = {
 			name: "name10",
@@ -146,7 +156,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry", "timeout", "memoryLimit"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -222,6 +232,18 @@ func TestValidate(t *testing.T) {
 		t.Error("expected error for retry too large")
 	}
 
+	*bad = good
+	bad.Timeout = options.MustParseDuration("0s")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for 0 timeout")
+	}
+
+	*bad = good
+	bad.MemoryLimit = pointer.Int64(-1)
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative memoryLimit")
+	}
+
 	notbad := new(options.Options)
 	*notbad = good
 	notbad.Cron = ""
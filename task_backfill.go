@@ -0,0 +1,97 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxTaskBackfillRuns is the largest number of runs a single backfill may enqueue.
+const MaxTaskBackfillRuns = 100000
+
+// Statuses of a task backfill.
+const (
+	TaskBackfillRunning   = "running"
+	TaskBackfillCompleted = "completed"
+	TaskBackfillCanceled  = "canceled"
+)
+
+var (
+	// ErrTaskBackfillNotFound is returned when a task has no backfill.
+	ErrTaskBackfillNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "backfill not found",
+	}
+
+	// ErrTaskBackfillRunning is returned when backfilling a task that is being backfilled already.
+	ErrTaskBackfillRunning = &Error{
+		Code: EConflict,
+		Msg:  "task is being backfilled already",
+	}
+)
+
+// TaskBackfill is the progress of the runs of a task at every time it was
+// scheduled for in a historical time range.
+type TaskBackfill struct {
+	TaskID ID        `json:"taskID"`
+	Start  time.Time `json:"start"` // Start is the earliest scheduled time, inclusive.
+	Stop   time.Time `json:"stop"`  // Stop is the latest scheduled time, exclusive.
+	Status string    `json:"status"`
+
+	// Total is the number of scheduled times in the range, of which Succeeded
+	// and Failed have been run.
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	CreatedAt  time.Time `json:"createdAt"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// ValidTaskBackfillRange returns an error if the range of a backfill is not valid.
+// The range must be in the past, at now, so that the backfill does not run the
+// task ahead of its schedule.
+func ValidTaskBackfillRange(start, stop, now time.Time) error {
+	if start.IsZero() || stop.IsZero() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "backfill start and stop are required",
+		}
+	}
+	if !start.Before(stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "backfill start must be before stop",
+		}
+	}
+	if stop.After(now) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "backfill stop must not be in the future",
+		}
+	}
+	return nil
+}
+
+// ErrTaskBackfillTooLarge is returned when a backfill range holds more scheduled
+// times than MaxTaskBackfillRuns.
+func ErrTaskBackfillTooLarge(start, stop time.Time) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("backfill from %s to %s exceeds the maximum of %d runs", start.Format(time.RFC3339), stop.Format(time.RFC3339), MaxTaskBackfillRuns),
+	}
+}
+
+// TaskBackfillService runs tasks over historical time ranges.
+type TaskBackfillService interface {
+	// BackfillTask enqueues a run of the task for every time in the range that
+	// the task is scheduled for. A task has at most one backfill running.
+	BackfillTask(ctx context.Context, taskID ID, start, stop time.Time) (*TaskBackfill, error)
+
+	// FindTaskBackfill returns the progress of the latest backfill of the task.
+	FindTaskBackfill(ctx context.Context, taskID ID) (*TaskBackfill, error)
+
+	// CancelTaskBackfill stops the running backfill of the task, canceling its
+	// runs in progress.
+	CancelTaskBackfill(ctx context.Context, taskID ID) error
+}
@@ -0,0 +1,88 @@
+package influxdb
+
+import "sort"
+
+// TaskDAGNode is a task in a TaskDAG.
+type TaskDAGNode struct {
+	ID            ID     `json:"id"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	LastRunStatus string `json:"lastRunStatus,omitempty"`
+}
+
+// TaskDAGEdge links an upstream task to a task that depends on it.
+type TaskDAGEdge struct {
+	From ID `json:"from"` // From is the upstream task.
+	To   ID `json:"to"`   // To is the task depending on From.
+}
+
+// TaskDAG is the graph of the tasks that a task depends on and that depend on
+// the task, transitively.
+type TaskDAG struct {
+	TaskID ID            `json:"taskID"`
+	Nodes  []TaskDAGNode `json:"nodes"`
+	Edges  []TaskDAGEdge `json:"edges"`
+}
+
+// NewTaskDAG returns the DAG of the task with the ID among the tasks. Upstream
+// tasks missing from tasks are left out of the DAG.
+func NewTaskDAG(taskID ID, tasks []*Task) (*TaskDAG, error) {
+	byID := make(map[ID]*Task, len(tasks))
+	downstream := make(map[ID][]ID)
+	for _, t := range tasks {
+		byID[t.ID] = t
+		for _, up := range t.DependsOn {
+			downstream[up] = append(downstream[up], t.ID)
+		}
+	}
+	if _, ok := byID[taskID]; !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	seen := map[ID]bool{taskID: true}
+	visit := func(next func(id ID) []ID) {
+		queue := []ID{taskID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, n := range next(id) {
+				if _, ok := byID[n]; !ok || seen[n] {
+					continue
+				}
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	visit(func(id ID) []ID { return byID[id].DependsOn })
+	visit(func(id ID) []ID { return downstream[id] })
+
+	dag := &TaskDAG{
+		TaskID: taskID,
+		Nodes:  []TaskDAGNode{},
+		Edges:  []TaskDAGEdge{},
+	}
+	for id := range seen {
+		t := byID[id]
+		dag.Nodes = append(dag.Nodes, TaskDAGNode{
+			ID:            t.ID,
+			Name:          t.Name,
+			Status:        t.Status,
+			LastRunStatus: t.LastRunStatus,
+		})
+		for _, up := range t.DependsOn {
+			if seen[up] {
+				dag.Edges = append(dag.Edges, TaskDAGEdge{From: up, To: t.ID})
+			}
+		}
+	}
+
+	sort.Slice(dag.Nodes, func(i, j int) bool { return dag.Nodes[i].ID < dag.Nodes[j].ID })
+	sort.Slice(dag.Edges, func(i, j int) bool {
+		if dag.Edges[i].From != dag.Edges[j].From {
+			return dag.Edges[i].From < dag.Edges[j].From
+		}
+		return dag.Edges[i].To < dag.Edges[j].To
+	})
+	return dag, nil
+}
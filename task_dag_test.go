@@ -0,0 +1,55 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestNewTaskDAG(t *testing.T) {
+	// 1 -> 2 -> 4, 3 -> 4, 1 -> 5, and 6 on its own.
+	tasks := []*influxdb.Task{
+		{ID: 1, Name: "a"},
+		{ID: 2, Name: "b", DependsOn: []influxdb.ID{1}},
+		{ID: 3, Name: "c"},
+		{ID: 4, Name: "d", DependsOn: []influxdb.ID{2, 3}},
+		{ID: 5, Name: "e", DependsOn: []influxdb.ID{1, 7}},
+		{ID: 6, Name: "f"},
+	}
+
+	dag, err := influxdb.NewTaskDAG(2, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The DAG of 2 holds its upstream 1 and its downstream 4, but neither the
+	// other tasks depending on 1 nor the other upstream tasks of 4.
+	want := &influxdb.TaskDAG{
+		TaskID: 2,
+		Nodes: []influxdb.TaskDAGNode{
+			{ID: 1, Name: "a"},
+			{ID: 2, Name: "b"},
+			{ID: 4, Name: "d"},
+		},
+		Edges: []influxdb.TaskDAGEdge{
+			{From: 1, To: 2},
+			{From: 2, To: 4},
+		},
+	}
+	if diff := cmp.Diff(want, dag); diff != "" {
+		t.Errorf("unexpected DAG -want/+got:\n%s", diff)
+	}
+
+	dag, err = influxdb.NewTaskDAG(6, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dag.Nodes) != 1 || len(dag.Edges) != 0 {
+		t.Errorf("expected a task without dependencies to be alone in its DAG, got %+v", dag)
+	}
+
+	if _, err := influxdb.NewTaskDAG(8, tasks); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("got error %v, want code %q", err, influxdb.ENotFound)
+	}
+}
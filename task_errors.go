@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"fmt"
+	"time"
 )
 
 var (
@@ -178,3 +179,54 @@ func ErrTaskConcurrencyLimitReached(runsInFront int) *Error {
 		Op:   "taskExecutor",
 	}
 }
+
+// ErrRunTimedOut is returned when an attempt of a run takes longer than the timeout option of its task.
+func ErrRunTimedOut(timeout time.Duration) *Error {
+	return &Error{
+		Code: ETimeout,
+		Msg:  fmt.Sprintf("could not execute task run, timeout of %s exceeded", timeout),
+		Op:   "taskExecutor",
+	}
+}
+
+// ErrRunMemoryLimitExceeded is returned when an attempt of a run allocates more than the memoryLimit option of its task.
+func ErrRunMemoryLimitExceeded(limit int64, err error) *Error {
+	return &Error{
+		Code: EResourceExhausted,
+		Msg:  fmt.Sprintf("could not execute task run, memory limit of %d bytes exceeded", limit),
+		Op:   "taskExecutor",
+		Err:  err,
+	}
+}
+
+// ErrTaskDependencyCycle is returned when the upstream tasks of a task depend on the task themselves.
+func ErrTaskDependencyCycle(taskID ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("task dependencies form a cycle through task %s", taskID),
+	}
+}
+
+// ErrInvalidTaskDependency is returned when a task depends on a task it cannot depend on.
+func ErrInvalidTaskDependency(upstreamID ID, reason string) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("task cannot depend on task %s: %s", upstreamID, reason),
+	}
+}
+
+// ErrTaskHasDependents is returned when deleting a task that other tasks depend on.
+func ErrTaskHasDependents(taskID, dependentID ID) *Error {
+	return &Error{
+		Code: EConflict,
+		Msg:  fmt.Sprintf("task %s cannot be deleted while task %s depends on it", taskID, dependentID),
+	}
+}
+
+// ErrInvalidTaskWriteTrigger is returned when a task cannot be triggered by writes as requested.
+func ErrInvalidTaskWriteTrigger(reason string) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid write trigger: %s", reason),
+	}
+}
@@ -0,0 +1,231 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+// BucketSchemaFields will include the buckets and the schemas they are
+// populated with.
+type BucketSchemaFields struct {
+	Organizations []*influxdb.Organization
+	Buckets       []*influxdb.Bucket
+	BucketSchemas []influxdb.BucketSchema
+}
+
+// BucketSchemaService tests all the service functions.
+func BucketSchemaService(
+	init func(BucketSchemaFields, *testing.T) (influxdb.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(BucketSchemaFields, *testing.T) (influxdb.BucketSchemaService, func()),
+			t *testing.T)
+	}{
+		{
+			name: "FindBucketSchema",
+			fn:   FindBucketSchema,
+		},
+		{
+			name: "UpdateBucketSchema",
+			fn:   UpdateBucketSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+func cpuSchema(bucketID string) influxdb.BucketSchema {
+	return influxdb.BucketSchema{
+		BucketID: MustIDBase16(bucketID),
+		Measurements: []influxdb.MeasurementSchema{
+			{
+				Name: "cpu",
+				Tags: []string{"host"},
+				Fields: []influxdb.FieldSchema{
+					{Name: "usage", Type: influxdb.SchemaFieldTypeFloat},
+				},
+			},
+		},
+	}
+}
+
+// FindBucketSchema testing
+func FindBucketSchema(
+	init func(BucketSchemaFields, *testing.T) (influxdb.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		schema *influxdb.BucketSchema
+		err    error
+	}
+
+	schema := cpuSchema(bucketTwoID)
+
+	tests := []struct {
+		name     string
+		fields   BucketSchemaFields
+		bucketID influxdb.ID
+		wants    wants
+	}{
+		{
+			name: "bucket without schema has no measurements",
+			fields: BucketSchemaFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				Buckets:       []*influxdb.Bucket{{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "bucket1"}},
+			},
+			bucketID: MustIDBase16(bucketOneID),
+			wants: wants{
+				schema: &influxdb.BucketSchema{BucketID: MustIDBase16(bucketOneID), Measurements: []influxdb.MeasurementSchema{}},
+			},
+		},
+		{
+			name: "find schema of bucket",
+			fields: BucketSchemaFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				Buckets: []*influxdb.Bucket{
+					{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "bucket1"},
+					{ID: MustIDBase16(bucketTwoID), OrgID: MustIDBase16(orgOneID), Name: "bucket2"},
+				},
+				BucketSchemas: []influxdb.BucketSchema{schema},
+			},
+			bucketID: MustIDBase16(bucketTwoID),
+			wants: wants{
+				schema: &schema,
+			},
+		},
+		{
+			name:     "missing bucket returns not found",
+			bucketID: MustIDBase16(bucketOneID),
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "bucket not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			schema, err := s.FindBucketSchema(ctx, tt.bucketID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(schema, tt.wants.schema); diff != "" {
+				t.Errorf("schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateBucketSchema testing
+func UpdateBucketSchema(
+	init func(BucketSchemaFields, *testing.T) (influxdb.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		schema *influxdb.BucketSchema
+		err    error
+	}
+
+	schema := cpuSchema(bucketOneID)
+
+	tests := []struct {
+		name     string
+		fields   BucketSchemaFields
+		bucketID influxdb.ID
+		schema   influxdb.BucketSchema
+		wants    wants
+	}{
+		{
+			name: "set schema of bucket",
+			fields: BucketSchemaFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				Buckets:       []*influxdb.Bucket{{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "bucket1"}},
+			},
+			bucketID: MustIDBase16(bucketOneID),
+			schema:   influxdb.BucketSchema{Measurements: schema.Measurements},
+			wants: wants{
+				schema: &schema,
+			},
+		},
+		{
+			name: "remove schema of bucket",
+			fields: BucketSchemaFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				Buckets:       []*influxdb.Bucket{{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "bucket1"}},
+				BucketSchemas: []influxdb.BucketSchema{schema},
+			},
+			bucketID: MustIDBase16(bucketOneID),
+			schema:   influxdb.BucketSchema{},
+			wants: wants{
+				schema: &influxdb.BucketSchema{BucketID: MustIDBase16(bucketOneID), Measurements: []influxdb.MeasurementSchema{}},
+			},
+		},
+		{
+			name: "invalid schema",
+			fields: BucketSchemaFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				Buckets:       []*influxdb.Bucket{{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "bucket1"}},
+			},
+			bucketID: MustIDBase16(bucketOneID),
+			schema: influxdb.BucketSchema{Measurements: []influxdb.MeasurementSchema{
+				{Name: "cpu"},
+			}},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  `measurement "cpu" must declare at least one field`,
+				},
+			},
+		},
+		{
+			name:     "missing bucket returns not found",
+			bucketID: MustIDBase16(bucketOneID),
+			schema:   influxdb.BucketSchema{Measurements: schema.Measurements},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "bucket not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			schema, err := s.UpdateBucketSchema(ctx, tt.bucketID, tt.schema)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(schema, tt.wants.schema); diff != "" {
+				t.Errorf("schemas are different -got/+want\ndiff %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			found, err := s.FindBucketSchema(ctx, tt.bucketID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(found, tt.wants.schema); diff != "" {
+				t.Errorf("stored schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
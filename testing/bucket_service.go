@@ -1152,6 +1152,8 @@ func UpdateBucket(
 		id          influxdb.ID
 		retention   int
 		description *string
+
+		maxSeriesCardinality *int64
 	}
 	type wants struct {
 		err    error
@@ -1344,6 +1346,40 @@ func UpdateBucket(
 				},
 			},
 		},
+		{
+			name: "update max series cardinality",
+			fields: BucketFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*influxdb.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*influxdb.Bucket{
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "bucket1",
+					},
+				},
+			},
+			args: args{
+				id:                   MustIDBase16(bucketOneID),
+				maxSeriesCardinality: int64Ptr(1000),
+			},
+			wants: wants{
+				bucket: &influxdb.Bucket{
+					ID:                   MustIDBase16(bucketOneID),
+					OrgID:                MustIDBase16(orgOneID),
+					Name:                 "bucket1",
+					MaxSeriesCardinality: 1000,
+					CRUDLog: influxdb.CRUDLog{
+						UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					},
+				},
+			},
+		},
 		{
 			name: "update retention and name",
 			fields: BucketFields{
@@ -1442,6 +1478,7 @@ func UpdateBucket(
 			}
 
 			upd.Description = tt.args.description
+			upd.MaxSeriesCardinality = tt.args.maxSeriesCardinality
 
 			bucket, err := s.UpdateBucket(ctx, tt.args.id, upd)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
@@ -1452,3 +1489,7 @@ func UpdateBucket(
 		})
 	}
 }
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,198 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+// OrgLimitsFields will include the organizations and the limits they are
+// populated with.
+type OrgLimitsFields struct {
+	Organizations []*influxdb.Organization
+	OrgLimits     []influxdb.OrgLimits
+}
+
+// OrgLimitsService tests all the service functions.
+func OrgLimitsService(
+	init func(OrgLimitsFields, *testing.T) (influxdb.OrgLimitsService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(OrgLimitsFields, *testing.T) (influxdb.OrgLimitsService, func()),
+			t *testing.T)
+	}{
+		{
+			name: "FindOrgLimits",
+			fn:   FindOrgLimits,
+		},
+		{
+			name: "UpdateOrgLimits",
+			fn:   UpdateOrgLimits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// FindOrgLimits testing
+func FindOrgLimits(
+	init func(OrgLimitsFields, *testing.T) (influxdb.OrgLimitsService, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		limits *influxdb.OrgLimits
+		err    error
+	}
+
+	tests := []struct {
+		name   string
+		fields OrgLimitsFields
+		orgID  influxdb.ID
+		wants  wants
+	}{
+		{
+			name: "organization without limits is unlimited",
+			fields: OrgLimitsFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+			},
+			orgID: MustIDBase16(orgOneID),
+			wants: wants{
+				limits: &influxdb.OrgLimits{OrgID: MustIDBase16(orgOneID)},
+			},
+		},
+		{
+			name: "find limits of organization",
+			fields: OrgLimitsFields{
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "org1"},
+					{ID: MustIDBase16(orgTwoID), Name: "org2"},
+				},
+				OrgLimits: []influxdb.OrgLimits{
+					{OrgID: MustIDBase16(orgOneID), WriteRequestsPerSecond: 10},
+					{OrgID: MustIDBase16(orgTwoID), ConcurrentQueries: 2, MaxSeriesCardinality: 1000},
+				},
+			},
+			orgID: MustIDBase16(orgTwoID),
+			wants: wants{
+				limits: &influxdb.OrgLimits{OrgID: MustIDBase16(orgTwoID), ConcurrentQueries: 2, MaxSeriesCardinality: 1000},
+			},
+		},
+		{
+			name:  "missing organization returns not found",
+			orgID: MustIDBase16(orgOneID),
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			limits, err := s.FindOrgLimits(ctx, tt.orgID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(limits, tt.wants.limits); diff != "" {
+				t.Errorf("limits are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateOrgLimits testing
+func UpdateOrgLimits(
+	init func(OrgLimitsFields, *testing.T) (influxdb.OrgLimitsService, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		limits *influxdb.OrgLimits
+		err    error
+	}
+
+	tests := []struct {
+		name   string
+		fields OrgLimitsFields
+		orgID  influxdb.ID
+		limits influxdb.OrgLimits
+		wants  wants
+	}{
+		{
+			name: "replace limits of organization",
+			fields: OrgLimitsFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+				OrgLimits: []influxdb.OrgLimits{
+					{OrgID: MustIDBase16(orgOneID), WriteRequestsPerSecond: 10, ConcurrentQueries: 2},
+				},
+			},
+			orgID:  MustIDBase16(orgOneID),
+			limits: influxdb.OrgLimits{WriteBytesPerSecond: 1024},
+			wants: wants{
+				limits: &influxdb.OrgLimits{OrgID: MustIDBase16(orgOneID), WriteBytesPerSecond: 1024},
+			},
+		},
+		{
+			name: "negative limits are invalid",
+			fields: OrgLimitsFields{
+				Organizations: []*influxdb.Organization{{ID: MustIDBase16(orgOneID), Name: "org1"}},
+			},
+			orgID:  MustIDBase16(orgOneID),
+			limits: influxdb.OrgLimits{ConcurrentQueries: -1},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "concurrentQueries must not be negative",
+				},
+			},
+		},
+		{
+			name:   "missing organization returns not found",
+			orgID:  MustIDBase16(orgOneID),
+			limits: influxdb.OrgLimits{ConcurrentQueries: 1},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			limits, err := s.UpdateOrgLimits(ctx, tt.orgID, tt.limits)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(limits, tt.wants.limits); diff != "" {
+				t.Errorf("limits are different -got/+want\ndiff %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			found, err := s.FindOrgLimits(ctx, tt.orgID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(found, tt.wants.limits); diff != "" {
+				t.Errorf("stored limits are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package cursors
 
 import (
 	"context"
+	"math"
 
 	"github.com/influxdata/influxdb/models"
 )
@@ -64,6 +65,48 @@ func (a CursorIterators) Stats() CursorStats {
 	return stats
 }
 
+// WindowStats are the statistics of the values of a series within the windows
+// spanning the time range [Start, End). Unless Decode is set, the range is a
+// single window and Count, Min, Max and Sum hold its statistics. Min, Max and
+// Sum hold the raw 8 byte representation of the field's value type, and MinTime
+// and MaxTime are the timestamps of the first minimum and maximum values.
+// Otherwise, the statistics are unknown and the values must be read.
+type WindowStats struct {
+	Start, End       int64
+	Decode           bool
+	Count            int64
+	Min, Max, Sum    uint64
+	MinTime, MaxTime int64
+}
+
+// FloatStats returns the minimum, maximum and sum of the values of a float field.
+func (s *WindowStats) FloatStats() (min, max, sum float64) {
+	return math.Float64frombits(s.Min), math.Float64frombits(s.Max), math.Float64frombits(s.Sum)
+}
+
+// IntegerStats returns the minimum, maximum and sum of the values of an integer field.
+func (s *WindowStats) IntegerStats() (min, max, sum int64) {
+	return int64(s.Min), int64(s.Max), int64(s.Sum)
+}
+
+// UnsignedStats returns the minimum, maximum and sum of the values of an unsigned field.
+func (s *WindowStats) UnsignedStats() (min, max, sum uint64) {
+	return s.Min, s.Max, s.Sum
+}
+
+// WindowStatsCursorIterator is implemented by a CursorIterator that can answer
+// the statistics of windows of a series without reading its values.
+type WindowStatsCursorIterator interface {
+	CursorIterator
+
+	// WindowStats returns the field type and the statistics of the series of r
+	// within each window of the time range of r. Windows are every nanoseconds
+	// wide and aligned to the Unix epoch, and windows without values are
+	// omitted. It returns false if no window can be answered without reading
+	// its values.
+	WindowStats(ctx context.Context, r *CursorRequest, every int64) (typ models.FieldType, windows []WindowStats, ok bool)
+}
+
 // CursorStats represents stats collected by a cursor.
 type CursorStats struct {
 	ScannedValues int // number of values scanned
@@ -3,6 +3,8 @@ package tsdb
 import (
 	"errors"
 	"fmt"
+
+	"github.com/influxdata/influxdb/models"
 )
 
 var (
@@ -21,8 +23,18 @@ type PartialWriteError struct {
 
 	// A sorted slice of series keys that were dropped.
 	DroppedKeys [][]byte
+
+	// The points that were dropped, each with the reason it was dropped.
+	DroppedPoints []DroppedPoint
 }
 
 func (e PartialWriteError) Error() string {
 	return fmt.Sprintf("partial write: %s dropped=%d", e.Reason, e.Dropped)
 }
+
+// DroppedPoint is a point that was dropped from a write, along with the reason
+// it was dropped.
+type DroppedPoint struct {
+	Point  models.Point
+	Reason string
+}
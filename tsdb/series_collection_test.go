@@ -124,6 +124,26 @@ func TestSeriesCollection(t *testing.T) {
 		})
 	})
 
+	t.Run("Drop", func(t *testing.T) {
+		points := []models.Point{
+			models.MustNewPoint("a", nil, models.Fields{"f": 1.0}, time.Unix(0, 0)),
+			models.MustNewPoint("b", nil, models.Fields{"f": 1.0}, time.Unix(0, 0)),
+		}
+		collection := NewSeriesCollection(points)
+
+		collection.Drop(1, "reason b")
+		collection.Drop(0, "reason a")
+		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
+			Reason:      "reason b",
+			Dropped:     2,
+			DroppedKeys: bs("a", "b"),
+			DroppedPoints: []DroppedPoint{
+				{Point: points[1], Reason: "reason b"},
+				{Point: points[0], Reason: "reason a"},
+			},
+		})
+	})
+
 	t.Run("Invalid", func(t *testing.T) {
 		collection := &SeriesCollection{Keys: bs("ka", "kb", "kc")}
 
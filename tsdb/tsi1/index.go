@@ -20,6 +20,7 @@ import (
 	"github.com/cespare/xxhash"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/pkg/slices"
 	"github.com/influxdata/influxdb/query"
@@ -113,13 +114,14 @@ type Index struct {
 	metricsEnabled   bool
 
 	// The following may be set when initializing an Index.
-	path               string      // Root directory of the index partitions.
-	disableCompactions bool        // Initially disables compactions on the index.
-	maxLogFileSize     int64       // Maximum size of a LogFile before it's compacted.
-	logfileBufferSize  int         // The size of the buffer used by the LogFile.
-	disableFsync       bool        // Disables flushing buffers and fsyning files. Used when working with indexes offline.
-	logger             *zap.Logger // Index's logger.
-	config             Config      // The index configuration
+	path               string              // Root directory of the index partitions.
+	disableCompactions bool                // Initially disables compactions on the index.
+	maxLogFileSize     int64               // Maximum size of a LogFile before it's compacted.
+	logfileBufferSize  int                 // The size of the buffer used by the LogFile.
+	disableFsync       bool                // Disables flushing buffers and fsyning files. Used when working with indexes offline.
+	logger             *zap.Logger         // Index's logger.
+	config             Config              // The index configuration
+	keyring            *encryption.Keyring // Keys encrypting the index files.
 
 	// The following must be set when initializing an Index.
 	sfile *tsdb.SeriesFile // series lookup file
@@ -198,6 +200,12 @@ func (i *Index) WithLogger(l *zap.Logger) {
 	i.logger = l.With(zap.String("index", "tsi"))
 }
 
+// WithKeyring sets the keys used to encrypt and decrypt index files. New files
+// are encrypted with the active key. It must be called before Open.
+func (i *Index) WithKeyring(k *encryption.Keyring) {
+	i.keyring = k
+}
+
 // SeriesFile returns the series file attached to the index.
 func (i *Index) SeriesFile() *tsdb.SeriesFile { return i.sfile }
 
@@ -251,6 +259,7 @@ func (i *Index) Open(ctx context.Context) error {
 		p.StatsTTL = i.StatsTTL
 		p.nosync = i.disableFsync
 		p.logbufferSize = i.logfileBufferSize
+		p.keyring = i.keyring
 		p.logger = i.logger.With(zap.String("tsi1_partition", fmt.Sprint(j+1)))
 
 		// Each of the trackers needs to be given slightly different default
@@ -588,6 +597,27 @@ func (i *Index) measurementSeriesIDIterator(name []byte) (tsdb.SeriesIDIterator,
 	return tsdb.MergeSeriesIDIterators(itrs...), nil
 }
 
+// MeasurementSeriesIDSet returns the set of non-tombstoned series in the
+// provided measurement. Unlike MeasurementSeriesIDIterator, it does not visit
+// each series, so the cardinality of a measurement can be found cheaply.
+func (i *Index) MeasurementSeriesIDSet(name []byte) (*tsdb.SeriesIDSet, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	sets := make([]*tsdb.SeriesIDSet, 0, len(i.partitions))
+	for _, p := range i.partitions {
+		ids, err := p.MeasurementSeriesIDSet(name)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, ids)
+	}
+
+	ids := tsdb.NewSeriesIDSet()
+	ids.Merge(sets...)
+	return ids, nil
+}
+
 // MeasurementNamesByRegex returns measurement names for the provided regex.
 func (i *Index) MeasurementNamesByRegex(re *regexp.Regexp) ([][]byte, error) {
 	return i.fetchByteValues(func(idx int) ([][]byte, error) {
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sync"
 	"unsafe"
 
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/pkg/mmap"
 	"github.com/influxdata/influxdb/tsdb"
@@ -72,8 +74,32 @@ type IndexFile struct {
 
 	// Path to data file.
 	path string
+
+	// Keys used to decrypt the file, and the ID of the key the file is
+	// encrypted with, if any.
+	keyring *encryption.Keyring
+	keyID   string
+
+	// Encrypted files are not mapped into data. Their measurement block and
+	// series sets are decrypted into memory when opened, while their tag blocks
+	// are decrypted as they are used, and the most recently used are cached.
+	file      *os.File
+	enc       *encryption.FileReader
+	tblkMu    sync.Mutex
+	tblkCache []cachedTagBlock // most recently used first
+	tblkSize  int              // size of the cached tag blocks
+}
+
+// cachedTagBlock is a decrypted tag block of an encrypted index file.
+type cachedTagBlock struct {
+	name string
+	blk  *TagBlock
 }
 
+// tagBlockCacheSize is the size of the decrypted tag blocks cached by an
+// encrypted index file. The most recently used tag block is always cached.
+const tagBlockCacheSize = 4 * 1024 * 1024
+
 // NewIndexFile returns a new instance of IndexFile.
 func NewIndexFile(sfile *tsdb.SeriesFile) *IndexFile {
 	return &IndexFile{
@@ -107,7 +133,8 @@ func (f *IndexFile) bytes() int {
 	return b
 }
 
-// Open memory maps the data file at the file's path.
+// Open memory maps the data file at the file's path. Encrypted files are
+// decrypted as they are read instead.
 func (f *IndexFile) Open() (err error) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -125,13 +152,7 @@ func (f *IndexFile) Open() (err error) {
 	// Extract identifier from path name.
 	f.id, f.level = ParseFilename(f.Path())
 
-	data, err := mmap.Map(f.Path(), 0)
-	if err != nil {
-		f.sfileref.Release()
-		return err
-	}
-
-	if err := f.UnmarshalBinary(data); err != nil {
+	if err := f.open(); err != nil {
 		f.sfileref.Release()
 		f.Close()
 		return err
@@ -143,6 +164,41 @@ func (f *IndexFile) Open() (err error) {
 	return nil
 }
 
+// open maps the data file into memory, or prepares it to be decrypted if it is
+// encrypted.
+func (f *IndexFile) open() error {
+	file, err := os.Open(f.Path())
+	if err != nil {
+		return err
+	}
+
+	keyID, err := encryption.FileKeyID(file)
+	if err != nil {
+		file.Close()
+		return err
+	} else if keyID == "" {
+		file.Close()
+		data, err := mmap.Map(f.Path(), 0)
+		if err != nil {
+			return err
+		}
+		f.data = data
+		return f.UnmarshalBinary(data)
+	}
+	f.file = file
+
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if f.enc, err = encryption.NewFileReader(file, fi.Size(), f.keyring); err != nil {
+		return fmt.Errorf("%s: %w", f.Path(), err)
+	}
+	f.keyID = keyID
+	return f.unmarshalEncrypted()
+}
+
 // Close unmaps the data file.
 func (f *IndexFile) Close() error {
 	// Close the resource and wait for any references.
@@ -156,6 +212,17 @@ func (f *IndexFile) Close() error {
 	f.sfile = nil
 	f.tblks = nil
 	f.mblk = MeasurementBlock{}
+
+	if f.file != nil {
+		f.tblkMu.Lock()
+		f.tblkCache, f.tblkSize = nil, 0
+		f.tblkMu.Unlock()
+
+		f.enc = nil
+		err := f.file.Close()
+		f.file = nil
+		return err
+	}
 	return mmap.Unmap(f.data)
 }
 
@@ -177,7 +244,17 @@ func (f *IndexFile) Acquire() (*lifecycle.Reference, error) {
 }
 
 // Size returns the size of the index file, in bytes.
-func (f *IndexFile) Size() int64 { return int64(len(f.data)) }
+func (f *IndexFile) Size() int64 {
+	if f.enc != nil {
+		return f.enc.Size()
+	}
+	return int64(len(f.data))
+}
+
+// staleKey returns true if the file is not encrypted with the active key of k.
+func (f *IndexFile) staleKey(k *encryption.Keyring) bool {
+	return k != nil && f.keyID != k.ActiveID()
+}
 
 // Compacting returns true if the file is being compacted.
 func (f *IndexFile) Compacting() bool {
@@ -237,6 +314,101 @@ func (f *IndexFile) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// unmarshalEncrypted opens an index from the encrypted file read by enc. Only the
+// measurement block and series sets are decrypted, tag blocks are decrypted as
+// they are used.
+func (f *IndexFile) unmarshalEncrypted() error {
+	size := f.enc.Size()
+	read := func(offset, n int64) ([]byte, error) {
+		if offset < 0 || n < 0 || offset+n > size {
+			return nil, io.ErrShortBuffer
+		}
+		b := make([]byte, n)
+		if _, err := f.enc.ReadAt(b, offset); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	// Ensure magic number exists at the beginning.
+	if sig, err := read(0, int64(len(FileSignature))); err != nil {
+		return err
+	} else if !bytes.Equal(sig, []byte(FileSignature)) {
+		return ErrInvalidIndexFile
+	}
+
+	// Read index file trailer.
+	buf, err := read(size-IndexFileTrailerSize, IndexFileTrailerSize)
+	if err != nil {
+		return err
+	}
+	t, err := ReadIndexFileTrailer(buf)
+	if err != nil {
+		return err
+	}
+
+	// Read series set data.
+	if f.seriesIDSetData, err = read(t.SeriesIDSet.Offset, t.SeriesIDSet.Size); err != nil {
+		return err
+	} else if f.tombstoneSeriesIDSetData, err = read(t.TombstoneSeriesIDSet.Offset, t.TombstoneSeriesIDSet.Size); err != nil {
+		return err
+	}
+
+	// Unmarshal measurement block.
+	if buf, err = read(t.MeasurementBlock.Offset, t.MeasurementBlock.Size); err != nil {
+		return err
+	}
+	return f.mblk.UnmarshalBinary(buf)
+}
+
+// tagBlock returns the tag block of a measurement, or nil if the measurement
+// does not exist. The tag blocks of encrypted files are decrypted, and nil is
+// returned if they cannot be.
+func (f *IndexFile) tagBlock(name []byte) *TagBlock {
+	if f.enc == nil {
+		return f.tblks[string(name)]
+	}
+
+	f.tblkMu.Lock()
+	for i, c := range f.tblkCache {
+		if c.name == string(name) {
+			copy(f.tblkCache[1:i+1], f.tblkCache[:i])
+			f.tblkCache[0] = c
+			f.tblkMu.Unlock()
+			return c.blk
+		}
+	}
+	f.tblkMu.Unlock()
+
+	e, ok := f.mblk.Elem(name)
+	if !ok {
+		return nil
+	}
+
+	buf := make([]byte, e.tagBlock.size)
+	if _, err := f.enc.ReadAt(buf, e.tagBlock.offset); err != nil {
+		return nil
+	}
+
+	var tblk TagBlock
+	if err := tblk.UnmarshalBinary(buf); err != nil {
+		return nil
+	}
+
+	// Evict the least recently used tag blocks beyond the cache size. Evicted
+	// tag blocks stay valid for the elements and iterators still using them.
+	f.tblkMu.Lock()
+	defer f.tblkMu.Unlock()
+	f.tblkCache = append([]cachedTagBlock{{name: string(name), blk: &tblk}}, f.tblkCache...)
+	f.tblkSize += len(buf)
+	for len(f.tblkCache) > 1 && f.tblkSize > tagBlockCacheSize {
+		last := f.tblkCache[len(f.tblkCache)-1]
+		f.tblkSize -= len(last.blk.data)
+		f.tblkCache = f.tblkCache[:len(f.tblkCache)-1]
+	}
+	return &tblk
+}
+
 func (f *IndexFile) SeriesIDSet() (*tsdb.SeriesIDSet, error) {
 	ss := tsdb.NewSeriesIDSet()
 	if err := ss.UnmarshalBinary(f.seriesIDSetData); err != nil {
@@ -292,7 +464,7 @@ func (f *IndexFile) MeasurementHasSeries(ss *tsdb.SeriesIDSet, name []byte) (ok
 // TagValueIterator returns a value iterator for a tag key and a flag
 // indicating if a tombstone exists on the measurement or key.
 func (f *IndexFile) TagValueIterator(name, key []byte) TagValueIterator {
-	tblk := f.tblks[string(name)]
+	tblk := f.tagBlock(name)
 	if tblk == nil {
 		return nil
 	}
@@ -310,7 +482,7 @@ func (f *IndexFile) TagValueIterator(name, key []byte) TagValueIterator {
 // TagKeySeriesIDIterator returns a series iterator for a tag key and a flag
 // indicating if a tombstone exists on the measurement or key.
 func (f *IndexFile) TagKeySeriesIDIterator(name, key []byte) (tsdb.SeriesIDIterator, error) {
-	tblk := f.tblks[string(name)]
+	tblk := f.tagBlock(name)
 	if tblk == nil {
 		return nil, nil
 	}
@@ -343,7 +515,7 @@ func (f *IndexFile) TagKeySeriesIDIterator(name, key []byte) (tsdb.SeriesIDItera
 
 // TagValueSeriesIDSet returns a series id set for a tag value.
 func (f *IndexFile) TagValueSeriesIDSet(name, key, value []byte) (*tsdb.SeriesIDSet, error) {
-	tblk := f.tblks[string(name)]
+	tblk := f.tagBlock(name)
 	if tblk == nil {
 		return nil, nil
 	}
@@ -360,7 +532,7 @@ func (f *IndexFile) TagValueSeriesIDSet(name, key, value []byte) (*tsdb.SeriesID
 
 // TagKey returns a tag key.
 func (f *IndexFile) TagKey(name, key []byte) TagKeyElem {
-	tblk := f.tblks[string(name)]
+	tblk := f.tagBlock(name)
 	if tblk == nil {
 		return nil
 	}
@@ -369,7 +541,7 @@ func (f *IndexFile) TagKey(name, key []byte) TagKeyElem {
 
 // TagValue returns a tag value.
 func (f *IndexFile) TagValue(name, key, value []byte) TagValueElem {
-	tblk := f.tblks[string(name)]
+	tblk := f.tagBlock(name)
 	if tblk == nil {
 		return nil
 	}
@@ -383,8 +555,8 @@ func (f *IndexFile) HasSeries(name []byte, tags models.Tags, buf []byte) (exists
 
 // TagValueElem returns an element for a measurement/tag/value.
 func (f *IndexFile) TagValueElem(name, key, value []byte) TagValueElem {
-	tblk, ok := f.tblks[string(name)]
-	if !ok {
+	tblk := f.tagBlock(name)
+	if tblk == nil {
 		return nil
 	}
 	return tblk.TagValueElem(key, value)
@@ -397,7 +569,7 @@ func (f *IndexFile) MeasurementIterator() MeasurementIterator {
 
 // TagKeyIterator returns an iterator over all tag keys for a measurement.
 func (f *IndexFile) TagKeyIterator(name []byte) TagKeyIterator {
-	blk := f.tblks[string(name)]
+	blk := f.tagBlock(name)
 	if blk == nil {
 		return nil
 	}
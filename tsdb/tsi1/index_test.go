@@ -1,6 +1,7 @@
 package tsi1_test
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/tsi1"
 	"go.uber.org/zap"
@@ -331,6 +333,181 @@ func TestIndex_DiskSizeBytes(t *testing.T) {
 	})
 }
 
+func TestIndex_Encrypted(t *testing.T) {
+	keyring, err := encryption.NewKeyring(bytes.Repeat([]byte{1}, encryption.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compact every write to the log file into an index file.
+	config := tsi1.NewConfig()
+	config.MaxIndexLogFileSize = 1
+	idx := NewIndex(1, config)
+	idx.Index.WithKeyring(keyring)
+	if err := idx.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(idx.Path())
+	defer idx.SeriesFile.Close()
+
+	if err := idx.CreateSeriesSliceIfNotExists([]Series{
+		{Name: []byte("cpu"), Tags: models.NewTags(map[string]string{"region": "east"})},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	idx.Wait()
+
+	paths, err := filepath.Glob(filepath.Join(idx.Path(), "*", "*.tsi"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(paths) == 0 {
+		t.Fatal("expected index files")
+	}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, err := encryption.FileKeyID(bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		} else if id != keyring.ActiveID() {
+			t.Fatalf("unexpected key id for %s: %q", path, id)
+		}
+		if bytes.Contains(b, []byte("region")) {
+			t.Fatalf("unexpected plaintext in encrypted index file %s", path)
+		}
+	}
+
+	reopen := func(k *encryption.Keyring) error {
+		if err := idx.Index.Close(); err != nil {
+			return err
+		} else if err := idx.SeriesFile.Reopen(); err != nil {
+			return err
+		}
+		idx.Index = tsi1.NewIndex(idx.SeriesFile.SeriesFile, idx.Config, tsi1.WithPath(idx.Index.Path()))
+		idx.Index.PartitionN = 1
+		idx.Index.WithKeyring(k)
+		return idx.Open()
+	}
+
+	if err := reopen(keyring); err != nil {
+		t.Fatal(err)
+	} else if v, err := idx.MeasurementExists([]byte("cpu")); err != nil {
+		t.Fatal(err)
+	} else if !v {
+		t.Fatal("expected measurement to exist")
+	}
+
+	// Tag blocks are decrypted as they are used.
+	itr, err := idx.TagValueSeriesIDIterator([]byte("cpu"), []byte("region"), []byte("east"))
+	if err != nil {
+		t.Fatal(err)
+	} else if itr == nil {
+		t.Fatal("expected series iterator")
+	}
+	if e, err := itr.Next(); err != nil {
+		t.Fatal(err)
+	} else if e.SeriesID.IsZero() {
+		t.Fatal("expected series")
+	}
+	itr.Close()
+
+	// Rotating the key rewrites the index files with the new key, after which
+	// the retired key is no longer needed.
+	newKey := bytes.Repeat([]byte{2}, encryption.KeySize)
+	rotated, err := encryption.NewKeyring(newKey, bytes.Repeat([]byte{1}, encryption.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	} else if err := reopen(rotated); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		idx.Wait()
+		if stale := staleIndexFiles(idx.Path(), rotated.ActiveID()); stale == 0 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("%d index files not rewritten with the new key", stale)
+		}
+	}
+
+	if k, err := encryption.NewKeyring(newKey); err != nil {
+		t.Fatal(err)
+	} else if err := reopen(k); err != nil {
+		t.Fatal(err)
+	} else if v, err := idx.MeasurementExists([]byte("cpu")); err != nil {
+		t.Fatal(err)
+	} else if !v {
+		t.Fatal("expected measurement to exist")
+	}
+
+	// The index cannot be opened without the key of its files.
+	if err := reopen(nil); !encryption.IsKeyNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// staleIndexFiles returns the number of index files under path not encrypted with
+// the key identified by keyID.
+func staleIndexFiles(path, keyID string) int {
+	paths, err := filepath.Glob(filepath.Join(path, "*", "*.tsi"))
+	if err != nil {
+		panic(err)
+	}
+
+	var n int
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue // removed by a compaction
+		} else if err != nil {
+			panic(err)
+		}
+		if id, err := encryption.FileKeyID(f); err != nil || id != keyID {
+			n++
+		}
+		f.Close()
+	}
+	return n
+}
+
+// Ensure index returns the set of undeleted series in a measurement.
+func TestIndex_MeasurementSeriesIDSet(t *testing.T) {
+	idx := MustOpenIndex(2, tsi1.NewConfig())
+	defer idx.Close()
+
+	if err := idx.CreateSeriesSliceIfNotExists([]Series{
+		{Name: []byte("cpu"), Tags: models.NewTags(map[string]string{"region": "east"})},
+		{Name: []byte("cpu"), Tags: models.NewTags(map[string]string{"region": "west"})},
+		{Name: []byte("cpu"), Tags: models.NewTags(map[string]string{"region": "north"})},
+		{Name: []byte("mem"), Tags: models.NewTags(map[string]string{"region": "east"})},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	west := idx.SeriesFile.SeriesID([]byte("cpu"), models.NewTags(map[string]string{"region": "west"}), nil)
+	east := idx.SeriesFile.SeriesID([]byte("cpu"), models.NewTags(map[string]string{"region": "east"}), nil)
+	if err := idx.DropSeries(west, idx.SeriesFile.SeriesKey(west), true); err != nil {
+		t.Fatal(err)
+	}
+
+	idx.Run(t, func(t *testing.T) {
+		if ids, err := idx.MeasurementSeriesIDSet([]byte("cpu")); err != nil {
+			t.Fatal(err)
+		} else if got, exp := ids.Cardinality(), uint64(2); got != exp {
+			t.Fatalf("got %d series, exp %d", got, exp)
+		} else if !ids.Contains(east) || ids.Contains(west) {
+			t.Fatalf("unexpected series: %s", ids)
+		}
+
+		if ids, err := idx.MeasurementSeriesIDSet([]byte("disk")); err != nil {
+			t.Fatal(err)
+		} else if ids.Cardinality() != 0 {
+			t.Fatalf("unexpected series: %s", ids)
+		}
+	})
+}
+
 // Ensure index can returns measurement cardinality stats.
 func TestIndex_MeasurementCardinalityStats(t *testing.T) {
 	t.Parallel()
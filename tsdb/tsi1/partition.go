@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -18,6 +19,7 @@ import (
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/pkg/bytesutil"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/tsdb"
@@ -85,6 +87,8 @@ type Partition struct {
 	nosync         bool // when true, flushing and syncing of LogFile will be disabled.
 	logbufferSize  int  // the LogFile's buffer is set to this value.
 
+	keyring *encryption.Keyring // encrypts index files, if set.
+
 	logger *zap.Logger
 
 	// Current size of MANIFEST. Used to determine partition size.
@@ -221,7 +225,7 @@ func (p *Partition) Open() (err error) {
 			case LogFileExt:
 				f, err := p.openLogFile(filepath.Join(p.path, filename))
 				if err != nil {
-					return nil, err
+					return files, err
 				}
 				files = append(files, f)
 
@@ -234,7 +238,7 @@ func (p *Partition) Open() (err error) {
 			case IndexFileExt:
 				f, err := p.openIndexFile(filepath.Join(p.path, filename))
 				if err != nil {
-					return nil, err
+					return files, err
 				}
 				files = append(files, f)
 			}
@@ -304,6 +308,7 @@ func (p *Partition) openLogFile(path string) (*LogFile, error) {
 func (p *Partition) openIndexFile(path string) (*IndexFile, error) {
 	f := NewIndexFile(p.sfile)
 	f.SetPath(path)
+	f.keyring = p.keyring
 	if err := f.Open(); err != nil {
 		return nil, err
 	}
@@ -603,6 +608,39 @@ func (p *Partition) MeasurementSeriesIDIterator(name []byte) (tsdb.SeriesIDItera
 	return newFileSetSeriesIDIterator(fs, fs.MeasurementSeriesIDIterator(name)), nil
 }
 
+// MeasurementSeriesIDSet returns the set of series in the measurement.
+func (p *Partition) MeasurementSeriesIDSet(name []byte) (*tsdb.SeriesIDSet, error) {
+	fs, err := p.FileSet()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Release()
+
+	ids := tsdb.NewSeriesIDSet()
+	itr := fs.MeasurementSeriesIDIterator(name)
+	if itr == nil {
+		return ids, nil
+	}
+	defer itr.Close()
+
+	// Intersect with partition set to ensure deleted series are removed.
+	if ssitr, ok := itr.(tsdb.SeriesIDSetIterator); ok {
+		return p.seriesIDSet.And(ssitr.SeriesIDSet()), nil
+	}
+
+	// Legacy 1.x data is not available as a set and must be iterated.
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if elem.SeriesID.IsZero() {
+			return ids, nil
+		} else if p.seriesIDSet.Contains(elem.SeriesID) {
+			ids.Add(elem.SeriesID)
+		}
+	}
+}
+
 // DropMeasurement deletes a measurement from the index. DropMeasurement does
 // not remove any series from the index directly.
 func (p *Partition) DropMeasurement(name []byte) error {
@@ -965,51 +1003,80 @@ func (p *Partition) compact() {
 			files = files[len(files)-MaxIndexMergeCount:]
 		}
 
-		// We intend to do a compaction. Acquire a resource to do so.
-		ref, err := p.res.Acquire()
-		if err != nil {
-			p.logger.Error("Attempt to compact while partition is closing", zap.Error(err))
+		if !p.compactLevel(files, level, level+1) {
 			return
 		}
+	}
 
-		// Acquire references to the files to keep them alive through compaction.
-		frefs, err := IndexFiles(files).Acquire()
-		if err != nil {
-			p.logger.Error("Attempt to compact a file that is closed", zap.Error(err))
+	// Rewrite files not encrypted with the active key, one per level at a time,
+	// so that retired keys are no longer needed. Files of the last level are
+	// otherwise never rewritten.
+	for level := minLevel; p.keyring != nil && level < len(p.levels); level++ {
+		if p.levelCompacting[level] {
 			continue
 		}
 
-		// Mark the level as compacting.
-		p.levelCompacting[level] = true
+		for _, f := range fs.IndexFiles() {
+			if f.Level() == level && f.staleKey(p.keyring) {
+				if !p.compactLevel([]*IndexFile{f}, level, level) {
+					return
+				}
+				break
+			}
+		}
+	}
+}
 
-		// Start compacting in a separate goroutine.
-		p.currentCompactionN++
-		go func(level int) {
-			// Compact to a new level.
-			p.compactToLevel(files, frefs, level+1, ref.Closing())
+// compactLevel starts compacting files of a level into a file of level dst in a
+// separate goroutine. It returns false if the partition is closing.
+func (p *Partition) compactLevel(files []*IndexFile, level, dst int) bool {
+	// We intend to do a compaction. Acquire a resource to do so.
+	ref, err := p.res.Acquire()
+	if err != nil {
+		p.logger.Error("Attempt to compact while partition is closing", zap.Error(err))
+		return false
+	}
 
-			// Ensure references are released.
-			frefs.Release()
-			ref.Release()
+	// Acquire references to the files to keep them alive through compaction.
+	frefs, err := IndexFiles(files).Acquire()
+	if err != nil {
+		p.logger.Error("Attempt to compact a file that is closed", zap.Error(err))
+		ref.Release()
+		return true
+	}
 
-			// Ensure compaction lock for the level is released.
-			p.mu.Lock()
-			p.levelCompacting[level] = false
-			p.currentCompactionN--
-			p.mu.Unlock()
+	// Mark the level as compacting.
+	p.levelCompacting[level] = true
 
-			// Check for new compactions
-			p.Compact()
-		}(level)
-	}
+	// Start compacting in a separate goroutine.
+	p.currentCompactionN++
+	go func() {
+		// Compact to a new level.
+		p.compactToLevel(files, frefs, dst, ref.Closing())
+
+		// Ensure references are released.
+		frefs.Release()
+		ref.Release()
+
+		// Ensure compaction lock for the level is released.
+		p.mu.Lock()
+		p.levelCompacting[level] = false
+		p.currentCompactionN--
+		p.mu.Unlock()
+
+		// Check for new compactions
+		p.Compact()
+	}()
+	return true
 }
 
 // compactToLevel compacts a set of files into a new file. Replaces old files with
-// compacted file on successful completion. This runs in a separate goroutine.
+// compacted file on successful completion. A single file is rewritten to encrypt
+// it with the active key. This runs in a separate goroutine.
 func (p *Partition) compactToLevel(files []*IndexFile, frefs lifecycle.References,
 	level int, interrupt <-chan struct{}) {
 
-	assert(len(files) >= 2, "at least two index files are required for compaction")
+	assert(len(files) > 0, "at least one index file is required for compaction")
 	assert(level > 0, "cannot compact level zero")
 
 	var err error
@@ -1064,7 +1131,9 @@ func (p *Partition) compactToLevel(files []*IndexFile, frefs lifecycle.Reference
 	// Compact all index files to new index file.
 	lvl := p.levels[level]
 	var n int64
-	if n, err = IndexFiles(files).CompactTo(f, p.sfile, lvl.M, lvl.K, interrupt); err != nil {
+	if n, err = p.writeIndexFile(f, func(w io.Writer) (int64, error) {
+		return IndexFiles(files).CompactTo(w, p.sfile, lvl.M, lvl.K, interrupt)
+	}); err != nil {
 		log.Error("Cannot compact index files", zap.Error(err))
 		return
 	}
@@ -1078,6 +1147,7 @@ func (p *Partition) compactToLevel(files []*IndexFile, frefs lifecycle.Reference
 	// Reopen as an index file.
 	file := NewIndexFile(p.sfile)
 	file.SetPath(path)
+	file.keyring = p.keyring
 	if err = file.Open(); err != nil {
 		log.Error("Cannot open new index file", zap.Error(err))
 		return
@@ -1197,6 +1267,21 @@ func (p *Partition) checkLogFile() error {
 	return nil
 }
 
+// writeIndexFile writes an index file to f with write, encrypting it with the
+// active key if the partition has keys.
+func (p *Partition) writeIndexFile(f *os.File, write func(w io.Writer) (int64, error)) (int64, error) {
+	if p.keyring == nil {
+		return write(f)
+	}
+
+	w := encryption.NewFileWriter(f, p.keyring)
+	n, err := write(w)
+	if err != nil {
+		return n, err
+	}
+	return n, w.Close()
+}
+
 // compactLogFile compacts f into a tsi file. The new file will share the
 // same identifier but will have a ".tsi" extension. Once the log file is
 // compacted then the manifest is updated and the log file is discarded.
@@ -1230,7 +1315,9 @@ func (p *Partition) compactLogFile(ctx context.Context, logFile *LogFile, interr
 
 	// Compact log file to new index file.
 	lvl := p.levels[1]
-	n, err := logFile.CompactTo(f, lvl.M, lvl.K, interrupt)
+	n, err := p.writeIndexFile(f, func(w io.Writer) (int64, error) {
+		return logFile.CompactTo(w, lvl.M, lvl.K, interrupt)
+	})
 	if err != nil {
 		log.Error("Cannot compact log file", zap.Error(err), zap.String("path", logFile.Path()))
 		return
@@ -1245,6 +1332,7 @@ func (p *Partition) compactLogFile(ctx context.Context, logFile *LogFile, interr
 	// Reopen as an index file.
 	file := NewIndexFile(p.sfile)
 	file.SetPath(path)
+	file.keyring = p.keyring
 	if err := file.Open(); err != nil {
 		log.Error("Cannot open compacted index file", zap.Error(err), zap.String("path", file.Path()))
 		return
@@ -67,6 +67,51 @@ func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (
 	}
 }
 
+// WindowStats returns the statistics of the windows of the series of r whose
+// blocks are entirely within a window, without decoding them.
+func (q *arrayCursorIterator) WindowStats(ctx context.Context, r *tsdb.CursorRequest, every int64) (models.FieldType, []cursors.WindowStats, bool) {
+	q.key = tsdb.AppendSeriesKey(q.key[:0], r.Name, r.Tags)
+	id := q.e.sfile.SeriesIDTypedBySeriesKey(q.key)
+	if id.IsZero() {
+		return 0, nil, false
+	}
+
+	// Statistics of the values are only recorded for numeric blocks.
+	var blockType byte
+	switch typ := id.Type(); typ {
+	case models.Float:
+		blockType = BlockFloat64
+	case models.Integer:
+		blockType = BlockInteger
+	case models.Unsigned:
+		blockType = BlockUnsigned
+	default:
+		return 0, nil, false
+	}
+
+	key := q.seriesFieldKeyBytes(r.Name, r.Tags, r.Field)
+	typ, windows, ok := q.e.WindowStats(key, r.StartTime, r.EndTime, every)
+	if !ok || typ != blockType {
+		return 0, nil, false
+	}
+
+	a := make([]cursors.WindowStats, len(windows))
+	for i, w := range windows {
+		a[i] = cursors.WindowStats{
+			Start:   w.Start,
+			End:     w.End,
+			Decode:  w.Decode,
+			Count:   int64(w.Stats.Count),
+			Min:     w.Stats.Min,
+			Max:     w.Stats.Max,
+			Sum:     w.Stats.Sum,
+			MinTime: w.Stats.MinAt,
+			MaxTime: w.Stats.MaxAt,
+		}
+	}
+	return id.Type(), a, true
+}
+
 func (q *arrayCursorIterator) seriesFieldKeyBytes(name []byte, tags models.Tags, field string) []byte {
 	q.key = models.AppendMakeKey(q.key[:0], name, tags)
 	q.key = append(q.key, KeyFieldSeparatorBytes...)
@@ -0,0 +1,267 @@
+package tsm1
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// BlockStats are the statistics of the values stored in a block. The count is
+// recorded for all block types, while the minimum, maximum and sum are only
+// recorded for numeric blocks. Min, Max and Sum hold the raw 8 byte
+// representation of the block's value type and are interpreted using the
+// accessor matching the block type. MinAt and MaxAt are the timestamps of the
+// first values equal to the minimum and maximum.
+type BlockStats struct {
+	Count         uint32
+	Min, Max, Sum uint64
+	MinAt, MaxAt  int64
+}
+
+// UnmarshalBinary decodes BlockStats from a byte slice.
+func (s *BlockStats) UnmarshalBinary(b []byte) error {
+	if len(b) < blockStatsSize {
+		return fmt.Errorf("unmarshalBinary: short buf: %v < %v", len(b), blockStatsSize)
+	}
+	s.Count = binary.BigEndian.Uint32(b[:4])
+	s.Min = binary.BigEndian.Uint64(b[4:12])
+	s.Max = binary.BigEndian.Uint64(b[12:20])
+	s.Sum = binary.BigEndian.Uint64(b[20:28])
+	s.MinAt = int64(binary.BigEndian.Uint64(b[28:36]))
+	s.MaxAt = int64(binary.BigEndian.Uint64(b[36:44]))
+	return nil
+}
+
+// AppendTo writes a binary-encoded version of BlockStats to b, allocating
+// and returning a new slice, if necessary.
+func (s *BlockStats) AppendTo(b []byte) []byte {
+	if len(b) < blockStatsSize {
+		if cap(b) < blockStatsSize {
+			b = make([]byte, blockStatsSize)
+		} else {
+			b = b[:blockStatsSize]
+		}
+	}
+
+	binary.BigEndian.PutUint32(b[:4], s.Count)
+	binary.BigEndian.PutUint64(b[4:12], s.Min)
+	binary.BigEndian.PutUint64(b[12:20], s.Max)
+	binary.BigEndian.PutUint64(b[20:28], s.Sum)
+	binary.BigEndian.PutUint64(b[28:36], uint64(s.MinAt))
+	binary.BigEndian.PutUint64(b[36:44], uint64(s.MaxAt))
+
+	return b
+}
+
+// FloatStats returns the minimum, maximum and sum of a float block.
+func (s *BlockStats) FloatStats() (min, max, sum float64) {
+	return math.Float64frombits(s.Min), math.Float64frombits(s.Max), math.Float64frombits(s.Sum)
+}
+
+// IntegerStats returns the minimum, maximum and sum of an integer block.
+func (s *BlockStats) IntegerStats() (min, max, sum int64) {
+	return int64(s.Min), int64(s.Max), int64(s.Sum)
+}
+
+// UnsignedStats returns the minimum, maximum and sum of an unsigned block.
+func (s *BlockStats) UnsignedStats() (min, max, sum uint64) {
+	return s.Min, s.Max, s.Sum
+}
+
+// Merge combines the statistics o of another block of type typ into s. The
+// blocks must be merged in time order, so that MinAt and MaxAt remain the
+// timestamps of the first minimum and maximum.
+func (s *BlockStats) Merge(typ byte, o BlockStats) {
+	if s.Count == 0 {
+		*s = o
+		return
+	} else if o.Count == 0 {
+		return
+	}
+	s.Count += o.Count
+
+	switch typ {
+	case BlockFloat64:
+		min, max, sum := s.FloatStats()
+		omin, omax, osum := o.FloatStats()
+		if omin < min {
+			s.Min, s.MinAt = o.Min, o.MinAt
+		}
+		if omax > max {
+			s.Max, s.MaxAt = o.Max, o.MaxAt
+		}
+		s.Sum = math.Float64bits(sum + osum)
+	case BlockInteger:
+		min, max, sum := s.IntegerStats()
+		omin, omax, osum := o.IntegerStats()
+		if omin < min {
+			s.Min, s.MinAt = o.Min, o.MinAt
+		}
+		if omax > max {
+			s.Max, s.MaxAt = o.Max, o.MaxAt
+		}
+		s.Sum = uint64(sum + osum)
+	case BlockUnsigned:
+		if o.Min < s.Min {
+			s.Min, s.MinAt = o.Min, o.MinAt
+		}
+		if o.Max > s.Max {
+			s.Max, s.MaxAt = o.Max, o.MaxAt
+		}
+		s.Sum += o.Sum
+	}
+}
+
+// newBlockStats returns the statistics of values, which must all be of the same type.
+func newBlockStats(values Values) BlockStats {
+	if len(values) == 0 {
+		return BlockStats{}
+	}
+
+	ts := make([]int64, len(values))
+	for i, v := range values {
+		ts[i] = v.UnixNano()
+	}
+
+	switch values[0].(type) {
+	case FloatValue:
+		a := make([]float64, len(values))
+		for i, v := range values {
+			a[i] = v.(FloatValue).RawValue()
+		}
+		return newFloatBlockStats(ts, a)
+	case IntegerValue:
+		a := make([]int64, len(values))
+		for i, v := range values {
+			a[i] = v.(IntegerValue).RawValue()
+		}
+		return newIntegerBlockStats(ts, a)
+	case UnsignedValue:
+		a := make([]uint64, len(values))
+		for i, v := range values {
+			a[i] = v.(UnsignedValue).RawValue()
+		}
+		return newUnsignedBlockStats(ts, a)
+	default:
+		return BlockStats{Count: uint32(len(values))}
+	}
+}
+
+// DecodeBlockStats returns the statistics of the values stored in block. Only
+// numeric blocks are decoded; the timestamps are counted for other types.
+func DecodeBlockStats(block []byte) (BlockStats, error) {
+	blockType, err := BlockType(block)
+	if err != nil {
+		return BlockStats{}, err
+	}
+
+	tb, vb, err := unpackBlock(block[1:])
+	if err != nil {
+		return BlockStats{}, err
+	}
+
+	if blockType != BlockFloat64 && blockType != BlockInteger && blockType != BlockUnsigned {
+		return BlockStats{Count: uint32(CountTimestamps(tb))}, nil
+	}
+
+	ts, err := TimeArrayDecodeAll(tb, nil)
+	if err != nil {
+		return BlockStats{}, err
+	}
+
+	switch blockType {
+	case BlockFloat64:
+		a, err := FloatArrayDecodeAll(vb, nil)
+		if err != nil {
+			return BlockStats{}, err
+		}
+		return newFloatBlockStats(ts, a), nil
+	case BlockInteger:
+		a, err := IntegerArrayDecodeAll(vb, nil)
+		if err != nil {
+			return BlockStats{}, err
+		}
+		return newIntegerBlockStats(ts, a), nil
+	default:
+		a, err := UnsignedArrayDecodeAll(vb, nil)
+		if err != nil {
+			return BlockStats{}, err
+		}
+		return newUnsignedBlockStats(ts, a), nil
+	}
+}
+
+func newFloatBlockStats(ts []int64, a []float64) BlockStats {
+	if len(a) == 0 {
+		return BlockStats{}
+	}
+	min, max, sum := a[0], a[0], 0.0
+	minAt, maxAt := ts[0], ts[0]
+	for i, v := range a {
+		if v < min {
+			min, minAt = v, ts[i]
+		}
+		if v > max {
+			max, maxAt = v, ts[i]
+		}
+		sum += v
+	}
+	return BlockStats{
+		Count: uint32(len(a)),
+		Min:   math.Float64bits(min),
+		Max:   math.Float64bits(max),
+		Sum:   math.Float64bits(sum),
+		MinAt: minAt,
+		MaxAt: maxAt,
+	}
+}
+
+func newIntegerBlockStats(ts []int64, a []int64) BlockStats {
+	if len(a) == 0 {
+		return BlockStats{}
+	}
+	min, max, sum := a[0], a[0], int64(0)
+	minAt, maxAt := ts[0], ts[0]
+	for i, v := range a {
+		if v < min {
+			min, minAt = v, ts[i]
+		}
+		if v > max {
+			max, maxAt = v, ts[i]
+		}
+		sum += v
+	}
+	return BlockStats{
+		Count: uint32(len(a)),
+		Min:   uint64(min),
+		Max:   uint64(max),
+		Sum:   uint64(sum),
+		MinAt: minAt,
+		MaxAt: maxAt,
+	}
+}
+
+func newUnsignedBlockStats(ts []int64, a []uint64) BlockStats {
+	if len(a) == 0 {
+		return BlockStats{}
+	}
+	min, max, sum := a[0], a[0], uint64(0)
+	minAt, maxAt := ts[0], ts[0]
+	for i, v := range a {
+		if v < min {
+			min, minAt = v, ts[i]
+		}
+		if v > max {
+			max, maxAt = v, ts[i]
+		}
+		sum += v
+	}
+	return BlockStats{
+		Count: uint32(len(a)),
+		Min:   min,
+		Max:   max,
+		Sum:   sum,
+		MinAt: minAt,
+		MaxAt: maxAt,
+	}
+}
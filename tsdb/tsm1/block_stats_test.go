@@ -0,0 +1,87 @@
+package tsm1_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+func TestDecodeBlockStats(t *testing.T) {
+	values := []tsm1.Value{tsm1.NewValue(0, int64(4)), tsm1.NewValue(1, int64(-1)), tsm1.NewValue(2, int64(9)), tsm1.NewValue(3, int64(-1))}
+	block, err := tsm1.Values(values).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+
+	stats, err := tsm1.DecodeBlockStats(block)
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %v", err)
+	}
+	if got, exp := stats.Count, uint32(4); got != exp {
+		t.Fatalf("unexpected count: got %v, exp %v", got, exp)
+	}
+	if min, max, sum := stats.IntegerStats(); min != -1 || max != 9 || sum != 11 {
+		t.Fatalf("unexpected stats: got min=%v max=%v sum=%v", min, max, sum)
+	}
+	if stats.MinAt != 1 || stats.MaxAt != 2 {
+		t.Fatalf("unexpected times: got min=%v max=%v", stats.MinAt, stats.MaxAt)
+	}
+
+	var b [48]byte
+	var got tsm1.BlockStats
+	if err := got.UnmarshalBinary(stats.AppendTo(b[:0])); err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if !cmp.Equal(got, stats) {
+		t.Fatalf("unexpected stats: -got/+exp\n%s", cmp.Diff(got, stats))
+	}
+}
+
+func TestBlockStats_Merge(t *testing.T) {
+	newStats := func(values ...tsm1.Value) tsm1.BlockStats {
+		block, err := tsm1.Values(values).Encode(nil)
+		if err != nil {
+			t.Fatalf("unexpected error encoding: %v", err)
+		}
+		stats, err := tsm1.DecodeBlockStats(block)
+		if err != nil {
+			t.Fatalf("unexpected error decoding: %v", err)
+		}
+		return stats
+	}
+
+	var floats tsm1.BlockStats
+	floats.Merge(tsm1.BlockFloat64, newStats(tsm1.NewValue(0, 2.5), tsm1.NewValue(1, 1.0)))
+	floats.Merge(tsm1.BlockFloat64, newStats(tsm1.NewValue(2, -0.5)))
+	if min, max, sum := floats.FloatStats(); floats.Count != 3 || min != -0.5 || max != 2.5 || sum != 3 {
+		t.Fatalf("unexpected float stats: count=%v min=%v max=%v sum=%v", floats.Count, min, max, sum)
+	} else if floats.MinAt != 2 || floats.MaxAt != 0 {
+		t.Fatalf("unexpected float times: min=%v max=%v", floats.MinAt, floats.MaxAt)
+	}
+
+	var integers tsm1.BlockStats
+	integers.Merge(tsm1.BlockInteger, newStats(tsm1.NewValue(0, int64(-5))))
+	integers.Merge(tsm1.BlockInteger, newStats(tsm1.NewValue(1, int64(3)), tsm1.NewValue(2, int64(-7))))
+	integers.Merge(tsm1.BlockInteger, newStats(tsm1.NewValue(3, int64(3)), tsm1.NewValue(4, int64(-7))))
+	if min, max, sum := integers.IntegerStats(); integers.Count != 5 || min != -7 || max != 3 || sum != -13 {
+		t.Fatalf("unexpected integer stats: count=%v min=%v max=%v sum=%v", integers.Count, min, max, sum)
+	} else if integers.MinAt != 2 || integers.MaxAt != 1 {
+		// Ties keep the time of the earlier block.
+		t.Fatalf("unexpected integer times: min=%v max=%v", integers.MinAt, integers.MaxAt)
+	}
+
+	var unsigned tsm1.BlockStats
+	unsigned.Merge(tsm1.BlockUnsigned, newStats(tsm1.NewValue(0, uint64(6))))
+	unsigned.Merge(tsm1.BlockUnsigned, newStats(tsm1.NewValue(1, uint64(2))))
+	if min, max, sum := unsigned.UnsignedStats(); unsigned.Count != 2 || min != 2 || max != 6 || sum != 8 {
+		t.Fatalf("unexpected unsigned stats: count=%v min=%v max=%v sum=%v", unsigned.Count, min, max, sum)
+	}
+
+	var strs tsm1.BlockStats
+	strs.Merge(tsm1.BlockString, newStats(tsm1.NewValue(0, "a")))
+	strs.Merge(tsm1.BlockString, newStats(tsm1.NewValue(1, "b"), tsm1.NewValue(2, "c")))
+	if !cmp.Equal(strs, tsm1.BlockStats{Count: 3}) {
+		t.Fatalf("unexpected string stats: %v", strs)
+	}
+}
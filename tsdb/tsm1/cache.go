@@ -11,6 +11,7 @@ import (
 
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/storage/wal"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxql"
@@ -515,6 +516,11 @@ func (cl *CacheLoader) Load(cache *Cache) error {
 }
 
 // WithLogger sets the logger on the CacheLoader.
+// WithKeyring sets the keys used to decrypt encrypted segment files.
+func (cl *CacheLoader) WithKeyring(k *encryption.Keyring) {
+	cl.reader.WithKeyring(k)
+}
+
 func (cl *CacheLoader) WithLogger(logger *zap.Logger) {
 	cl.reader.WithLogger(logger.With(zap.String("service", "cacheloader")))
 }
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/tsdb"
 )
@@ -184,10 +185,11 @@ func (t *tsmGeneration) count() int {
 	return len(t.files)
 }
 
-// hasTombstones returns true if there are keys removed for any of the files.
-func (t *tsmGeneration) hasTombstones() bool {
+// needsRewrite returns true if any of the files has tombstones or is encrypted
+// with a stale key, which is only resolved by compacting the generation.
+func (t *tsmGeneration) needsRewrite() bool {
 	for _, f := range t.files {
-		if f.HasTombstone {
+		if f.HasTombstone || f.StaleKey {
 			return true
 		}
 	}
@@ -205,7 +207,7 @@ func (c *DefaultPlanner) ParseFileName(path string) (int, int, error) {
 // FullyCompacted returns true if the shard is fully compacted.
 func (c *DefaultPlanner) FullyCompacted() bool {
 	gens := c.findGenerations(false)
-	return len(gens) <= 1 && !gens.hasTombstones()
+	return len(gens) <= 1 && !gens.needsRewrite()
 }
 
 // ForceFull causes the planner to return a full compaction plan the next time
@@ -235,7 +237,7 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
-	if len(generations) <= 1 && !generations.hasTombstones() {
+	if len(generations) <= 1 && !generations.needsRewrite() {
 		return nil
 	}
 
@@ -286,17 +288,17 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 	for _, group := range levelGroups {
 		for _, chunk := range group.chunk(minGenerations) {
 			var cGroup CompactionGroup
-			var hasTombstones bool
+			var needsRewrite bool
 			for _, gen := range chunk {
-				if gen.hasTombstones() {
-					hasTombstones = true
+				if gen.needsRewrite() {
+					needsRewrite = true
 				}
 				for _, file := range gen.files {
 					cGroup = append(cGroup, file.Path)
 				}
 			}
 
-			if len(chunk) < minGenerations && !hasTombstones {
+			if len(chunk) < minGenerations && !needsRewrite {
 				continue
 			}
 
@@ -331,7 +333,7 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
-	if len(generations) <= 1 && !generations.hasTombstones() {
+	if len(generations) <= 1 && !generations.needsRewrite() {
 		return nil
 	}
 
@@ -343,7 +345,7 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 		cur := generations[i]
 
 		// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
-		if cur.count() > 2 && cur.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(cur.files[0].Path, 1) == MaxPointsPerBlock && !cur.hasTombstones() {
+		if cur.count() > 2 && cur.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(cur.files[0].Path, 1) == MaxPointsPerBlock && !cur.needsRewrite() {
 			continue
 		}
 
@@ -382,7 +384,7 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 	var cGroups []CompactionGroup
 	for _, group := range levelGroups {
 		// Skip the group if it's not worthwhile to optimize it
-		if len(group) < 4 && !group.hasTombstones() {
+		if len(group) < 4 && !group.needsRewrite() {
 			continue
 		}
 
@@ -428,7 +430,7 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 			var skip bool
 
 			// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
-			if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.hasTombstones() {
+			if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.needsRewrite() {
 				skip = true
 			}
 
@@ -466,7 +468,7 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 	}
 
 	// don't plan if nothing has changed in the filestore
-	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !generations.hasTombstones() {
+	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !generations.needsRewrite() {
 		return nil
 	}
 
@@ -474,7 +476,7 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 
 	// If there is only one generation, return early to avoid re-compacting the same file
 	// over and over again.
-	if len(generations) <= 1 && !generations.hasTombstones() {
+	if len(generations) <= 1 && !generations.needsRewrite() {
 		return nil
 	}
 
@@ -491,13 +493,13 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 
 	// As compactions run, the oldest files get bigger.  We don't want to re-compact them during
 	// this planning if they are maxed out so skip over any we see.
-	var hasTombstones bool
+	var needsRewrite bool
 	for i, g := range generations[:end] {
-		if g.hasTombstones() {
-			hasTombstones = true
+		if g.needsRewrite() {
+			needsRewrite = true
 		}
 
-		if hasTombstones {
+		if needsRewrite {
 			continue
 		}
 
@@ -548,7 +550,7 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 			}
 
 			// Skip the file if it's over the max size and it contains a full block
-			if gen.size() >= uint64(maxTSMFileSize) && c.FileStore.BlockCount(gen.files[0].Path, 1) == MaxPointsPerBlock && !gen.hasTombstones() {
+			if gen.size() >= uint64(maxTSMFileSize) && c.FileStore.BlockCount(gen.files[0].Path, 1) == MaxPointsPerBlock && !gen.needsRewrite() {
 				startIndex++
 				continue
 			}
@@ -575,7 +577,7 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 	compactable := []tsmGenerations{}
 	for _, group := range groups {
 		//if we don't have enough generations to compact, skip it
-		if len(group) < 4 && !group.hasTombstones() {
+		if len(group) < 4 && !group.needsRewrite() {
 			continue
 		}
 		compactable = append(compactable, group)
@@ -698,6 +700,13 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// BlockStats enables recording the statistics of the values in each block
+	// in the index of the TSM files written.
+	BlockStats bool
+
+	// Keyring encrypts the TSM files written with its active key, if set.
+	Keyring *encryption.Keyring
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -1111,12 +1120,12 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 	// Use a disk based TSM buffer if it looks like we might create a big index
 	// in memory.
 	if iter.EstimatedIndexSize() > 64*1024*1024 {
-		w, err = NewTSMWriterWithDiskBuffer(limitWriter)
+		w, err = NewTSMWriterWithDiskBuffer(limitWriter, WithBlockStats(c.BlockStats), WithEncryption(c.Keyring))
 		if err != nil {
 			return err
 		}
 	} else {
-		w, err = NewTSMWriter(limitWriter)
+		w, err = NewTSMWriter(limitWriter, WithBlockStats(c.BlockStats), WithEncryption(c.Keyring))
 		if err != nil {
 			return err
 		}
@@ -2035,9 +2044,9 @@ type tsmGenerations []*tsmGeneration
 func (a tsmGenerations) Len() int           { return len(a) }
 func (a tsmGenerations) Less(i, j int) bool { return a[i].id < a[j].id }
 func (a tsmGenerations) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a tsmGenerations) hasTombstones() bool {
+func (a tsmGenerations) needsRewrite() bool {
 	for _, g := range a {
-		if g.hasTombstones() {
+		if g.needsRewrite() {
 			return true
 		}
 	}
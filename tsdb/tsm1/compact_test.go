@@ -148,6 +148,50 @@ func TestCompactor_CompactFullLastTimestamp(t *testing.T) {
 }
 
 // Ensures that a compaction will properly merge multiple TSM files
+func TestCompactor_CompactFull_Encrypted(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{
+		"cpu,host=A#!~#value": {tsm1.NewValue(1, 1.1)},
+	})
+	f2 := MustWriteTSM(dir, 2, map[string][]tsm1.Value{
+		"cpu,host=B#!~#value": {tsm1.NewValue(1, 2.1)},
+	})
+
+	keyring := mustNewKeyring(1)
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.Keyring = keyring
+	compactor.Open()
+
+	files, err := compactor.CompactFull([]string{f1, f2})
+	if err != nil {
+		t.Fatalf("unexpected error compacting: %v", err)
+	} else if got, exp := len(files), 1; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatalf("unexpected error opening tsm: %v", err)
+	}
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderKeyring(keyring))
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	defer r.Close()
+
+	if got, exp := r.KeyID(), keyring.ActiveID(); got != exp {
+		t.Fatalf("key id mismatch: got %v, exp %v", got, exp)
+	} else if got, exp := r.KeyCount(), 2; got != exp {
+		t.Fatalf("key count mismatch: got %v, exp %v", got, exp)
+	}
+}
+
 func TestCompactor_CompactFull(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -2403,6 +2447,46 @@ func TestDefaultPlanner_PlanOptimize_Tombstones(t *testing.T) {
 
 }
 
+func TestDefaultPlanner_PlanOptimize_StaleKey(t *testing.T) {
+	data := []tsm1.FileStat{
+		{
+			Path: "01-04.tsm1",
+			Size: 251 * 1024 * 1024,
+		},
+		{
+			Path:     "01-05.tsm1",
+			Size:     1 * 1024 * 1024,
+			StaleKey: true,
+		},
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+
+	if cp.FullyCompacted() {
+		t.Fatalf("expected generation with stale key not to be fully compacted")
+	}
+
+	expFiles := []tsm1.FileStat{data[0], data[1]}
+	tsm := cp.PlanOptimize()
+	if exp, got := 1, len(tsm); got != exp {
+		t.Fatalf("compaction group length mismatch: got %v, exp %v", got, exp)
+	} else if exp, got := len(expFiles), len(tsm[0]); got != exp {
+		t.Fatalf("tsm file length mismatch: got %v, exp %v", got, exp)
+	}
+
+	for i, p := range expFiles {
+		if got, exp := tsm[0][i], p.Path; got != exp {
+			t.Fatalf("tsm file mismatch: got %v, exp %v", got, exp)
+		}
+	}
+}
+
 // Ensure that the planner will compact all files if no writes
 // have happened in some interval
 func TestDefaultPlanner_Plan_FullOnCold(t *testing.T) {
@@ -12,6 +12,9 @@ var DefaultMaxConcurrentOpens = runtime.GOMAXPROCS(0)
 const (
 	DefaultMADVWillNeed = false
 
+	// DefaultBlockStats is the default for recording block statistics in the TSM index.
+	DefaultBlockStats = false
+
 	// DefaultLargeSeriesWriteThreshold is the number of series per write
 	// that requires the series index be pregrown before insert.
 	DefaultLargeSeriesWriteThreshold = 10000
@@ -33,8 +36,25 @@ type Config struct {
 	// preallocation to improve throughput. Currently used in the series file.
 	LargeSeriesWriteThreshold int `toml:"large-series-write-threshold"`
 
+	// BlockStats controls whether TSM files are written with the count, minimum,
+	// maximum and sum of the values in each block recorded in the index, allowing
+	// aggregates to be answered without decoding blocks. Files written with this
+	// setting cannot be read by versions that predate it.
+	BlockStats bool `toml:"block-stats"`
+
 	Compaction CompactionConfig `toml:"compaction"`
 	Cache      CacheConfig      `toml:"cache"`
+	ColdTier   ColdTierConfig   `toml:"cold-tier"`
+
+	// Encryption configures the keys TSM and TSI index files, and the stats files
+	// of TSM files, are encrypted with. Files are not encrypted if no keys are
+	// configured. Compactions rewrite files encrypted with rotated keys, which
+	// must be kept until no files use them.
+	//
+	// Only TSM and TSI index files are encrypted. TSM tombstone files and TSI log
+	// files hold series keys, and measurement and tag names, until they are
+	// compacted away, and the series file holds every series key.
+	Encryption EncryptionConfig `toml:"encryption"`
 }
 
 // NewConfig constructs a Config with the default values.
@@ -42,9 +62,11 @@ func NewConfig() Config {
 	return Config{
 		MaxConcurrentOpens:        DefaultMaxConcurrentOpens,
 		MADVWillNeed:              DefaultMADVWillNeed,
+		BlockStats:                DefaultBlockStats,
 		LargeSeriesWriteThreshold: DefaultLargeSeriesWriteThreshold,
 
-		Cache: NewCacheConfig(),
+		Cache:    NewCacheConfig(),
+		ColdTier: NewColdTierConfig(),
 		Compaction: CompactionConfig{
 			FullWriteColdDuration: toml.Duration(DefaultCompactFullWriteColdDuration),
 			Throughput:            toml.Size(DefaultCompactThroughput),
@@ -84,6 +106,39 @@ type CompactionConfig struct {
 	MaxConcurrent int `toml:"max-concurrent"`
 }
 
+// Default cold tier configuration values.
+const (
+	DefaultColdTierAge           = toml.Duration(0)                // Defaults to off.
+	DefaultColdTierMaxHotSize    = toml.Size(0)                    // Defaults to off.
+	DefaultColdTierCheckInterval = toml.Duration(10 * time.Minute) // Ten minutes
+)
+
+// ColdTierConfig holds the policy for relocating TSM files to the cold tier. Files
+// are only relocated when the engine is given a cold tier directory.
+type ColdTierConfig struct {
+	// Age is the age of the newest data in a fully compacted generation after which
+	// the generation is moved to the cold tier. A value of 0 disables the age policy.
+	Age toml.Duration `toml:"age"`
+
+	// MaxHotSize is the size the TSM files in the hot tier can reach before the fully
+	// compacted generations holding the oldest data are moved to the cold tier. A
+	// value of 0 disables the size policy.
+	MaxHotSize toml.Size `toml:"max-hot-size"`
+
+	// CheckInterval is the interval at which the engine checks for generations to
+	// move to the cold tier.
+	CheckInterval toml.Duration `toml:"check-interval"`
+}
+
+// NewColdTierConfig initialises a new ColdTierConfig with default values.
+func NewColdTierConfig() ColdTierConfig {
+	return ColdTierConfig{
+		Age:           DefaultColdTierAge,
+		MaxHotSize:    DefaultColdTierMaxHotSize,
+		CheckInterval: DefaultColdTierCheckInterval,
+	}
+}
+
 // Default Cache configuration values.
 const (
 	DefaultCacheMaxMemorySize             = toml.Size(1024 << 20)           // 1GB
@@ -127,8 +182,9 @@ func NewCacheConfig() CacheConfig {
 
 // Default WAL configuration values.
 const (
-	DefaultWALEnabled    = true
-	DefaultWALFsyncDelay = time.Duration(0)
+	DefaultWALEnabled     = true
+	DefaultWALFsyncDelay  = time.Duration(0)
+	DefaultWALCompression = "snappy"
 )
 
 // WALConfig holds all of the configuration about the WAL.
@@ -141,11 +197,40 @@ type WALConfig struct {
 	// useful for slower disks or when WAL write contention is seen.  A value of 0 fsyncs
 	// every write to the WAL.
 	FsyncDelay toml.Duration `toml:"fsync-delay"`
+
+	// Compression is the compression of WAL entries, either "snappy" or "zstd".
+	// Segments are read with the compression they were written with.
+	Compression string `toml:"compression"`
+
+	// Encryption configures the keys WAL segments are encrypted with. Segments are
+	// not encrypted if no keys are configured.
+	Encryption EncryptionConfig `toml:"encryption"`
 }
 
 func NewWALConfig() WALConfig {
 	return WALConfig{
-		Enabled:    DefaultWALEnabled,
-		FsyncDelay: toml.Duration(DefaultWALFsyncDelay),
+		Enabled:     DefaultWALEnabled,
+		FsyncDelay:  toml.Duration(DefaultWALFsyncDelay),
+		Compression: DefaultWALCompression,
 	}
 }
+
+// EncryptionConfig holds the configuration of the keys used to encrypt files at
+// rest. Keys are hex-encoded 256-bit AES keys, one per line. The first key
+// encrypts new files, and the others are kept to decrypt existing files while
+// keys are rotated.
+type EncryptionConfig struct {
+	// KeyFile is the path of a file holding the keys.
+	KeyFile string `toml:"key-file"`
+
+	// KeySecret is the key of a secret holding the keys in the secret store, and
+	// KeySecretOrgID is the ID of the organization owning the secret. It is only
+	// used if KeyFile is not set.
+	KeySecret      string `toml:"key-secret"`
+	KeySecretOrgID string `toml:"key-secret-org-id"`
+}
+
+// Enabled returns true if keys are configured.
+func (c EncryptionConfig) Enabled() bool {
+	return c.KeyFile != "" || c.KeySecret != ""
+}
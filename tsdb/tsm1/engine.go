@@ -19,6 +19,7 @@ import (
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/metrics"
@@ -102,6 +103,14 @@ func WithSnapshotter(snapshotter Snapshotter) EngineOption {
 	}
 }
 
+// WithColdTierPath sets the directory the engine moves cold TSM files to. An empty
+// path disables the cold tier.
+func WithColdTierPath(path string) EngineOption {
+	return func(e *Engine) {
+		e.FileStore.WithColdDir(path)
+	}
+}
+
 // Engine represents a storage engine with compressed blocks.
 type Engine struct {
 	mu sync.RWMutex
@@ -168,6 +177,9 @@ type Engine struct {
 
 	scheduler   *scheduler
 	snapshotter Snapshotter
+
+	coldTier          ColdTierConfig // Policy for moving TSM files to the cold tier.
+	lastColdTierCheck time.Time      // Only accessed by the level compaction goroutine.
 }
 
 // NewEngine returns a new instance of Engine.
@@ -184,6 +196,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	c.RateLimit = limiter.NewRate(
 		int(config.Compaction.Throughput),
 		int(config.Compaction.ThroughputBurst))
+	c.BlockStats = config.BlockStats
 
 	// determine max concurrent compactions informed by the system
 	maxCompactions := config.Compaction.MaxConcurrent
@@ -228,6 +241,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 		fullCompactionSemaphore:        influxdb.NopSemaphore,
 		scheduler:                      newScheduler(maxCompactions),
 		snapshotter:                    new(noSnapshotter),
+		coldTier:                       config.ColdTier,
 	}
 
 	for _, option := range options {
@@ -263,6 +277,14 @@ func (e *Engine) WithCurrentGenerationFunc(fn func() int) {
 	e.Compactor.FileStore.SetCurrentGenerationFunc(fn)
 }
 
+// WithKeyring sets the keys used to encrypt and decrypt TSM files. New files are
+// encrypted with the active key, and compactions rewrite files encrypted with
+// other keys. It must be called before the engine is opened.
+func (e *Engine) WithKeyring(k *encryption.Keyring) {
+	e.FileStore.WithKeyring(k)
+	e.Compactor.Keyring = k
+}
+
 func (e *Engine) WithFileStoreObserver(obs FileStoreObserver) {
 	e.FileStore.WithObserver(obs)
 }
@@ -545,6 +567,12 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		return err
 	}
 
+	if dir := e.FileStore.ColdDir(); dir != "" {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+	}
+
 	if err := e.cleanup(); err != nil {
 		return err
 	}
@@ -932,6 +960,7 @@ const (
 	CacheStatusColdNoWrites                      // The cache has not been written to for long enough that it should be snapshotted.
 	CacheStatusRetention                         // The cache was snapshotted before running retention.
 	CacheStatusFullCompaction                    // The cache was snapshotted as part of a full compaction.
+	CacheStatusBackup                            // The cache was snapshotted before taking a backup.
 )
 
 // ShouldCompactCache returns a status indicating if the Cache should be
@@ -1049,7 +1078,38 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 			if runnable {
 				span.Finish()
 			}
+
+			// Files are only moved to the cold tier when no compactions are running,
+			// and none can start until they have been moved.
+			if interval := time.Duration(e.coldTier.CheckInterval); e.FileStore.ColdDir() != "" &&
+				time.Since(e.lastColdTierCheck) >= interval && e.compactionTracker.AllActive() == 0 {
+				e.lastColdTierCheck = time.Now()
+				e.moveToColdTier(quit)
+			}
+		}
+	}
+}
+
+// moveToColdTier moves the TSM files selected by the cold tier policy to the cold
+// tier. It must only be called from the level compaction goroutine, which ensures
+// the files are not compacted or deleted from while they are moved.
+func (e *Engine) moveToColdTier(quit <-chan struct{}) {
+	paths := e.FileStore.PlanColdTier(time.Duration(e.coldTier.Age), uint64(e.coldTier.MaxHotSize), time.Now())
+	for _, path := range paths {
+		select {
+		case <-quit:
+			return
+		default:
 		}
+
+		start := time.Now()
+		if err := e.FileStore.MoveToColdTier(path); err != nil {
+			e.logger.Warn("Error moving tsm file to cold tier", zap.String("path", path), zap.Error(err))
+			return
+		}
+		e.logger.Info("Moved tsm file to cold tier",
+			zap.String("path", path),
+			zap.Duration("duration", time.Since(start)))
 	}
 }
 
@@ -1343,6 +1403,15 @@ func (e *Engine) cleanupTempTSMFiles() error {
 		return fmt.Errorf("error getting compaction temp files: %s", err.Error())
 	}
 
+	// Files being moved to the cold tier are copied to temp files first.
+	if dir := e.FileStore.ColdDir(); dir != "" {
+		cold, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("*.%s", TmpTSMFileExtension)))
+		if err != nil {
+			return fmt.Errorf("error getting cold tier temp files: %s", err.Error())
+		}
+		files = append(files, cold...)
+	}
+
 	for _, f := range files {
 		if err := os.Remove(f); err != nil {
 			return fmt.Errorf("error removing temp compaction files: %v", err)
@@ -1356,6 +1425,28 @@ func (e *Engine) KeyCursor(ctx context.Context, key []byte, t int64, ascending b
 	return e.FileStore.KeyCursor(ctx, key, t, ascending)
 }
 
+// ReadStats returns the block type and the combined statistics of the values for key
+// within the time range [min, max], answered from the TSM index without decoding any
+// blocks. It returns false if any values within the time range are still in the cache
+// or the statistics cannot be determined from the index alone.
+func (e *Engine) ReadStats(key []byte, min, max int64) (typ byte, stats BlockStats, ok bool) {
+	if len(e.Cache.Values(key).Include(min, max)) > 0 {
+		return 0, BlockStats{}, false
+	}
+	return e.FileStore.ReadStats(key, min, max)
+}
+
+// WindowStats returns the block type and the statistics of the values for key
+// within each window of the time range [start, end), as FileStore.WindowStats.
+// The windows containing values still in the cache must be decoded. It returns
+// false if the engine does not record block statistics.
+func (e *Engine) WindowStats(key []byte, start, end, every int64) (typ byte, windows []WindowStats, ok bool) {
+	if !e.Compactor.BlockStats {
+		return 0, nil, false
+	}
+	return e.FileStore.WindowStats(key, start, end, every, e.Cache.Values(key))
+}
+
 // IteratorCost produces the cost of an iterator.
 func (e *Engine) IteratorCost(measurement string, opt query.IteratorOptions) (query.IteratorCost, error) {
 	// Determine if this measurement exists. If it does not, then no shards are
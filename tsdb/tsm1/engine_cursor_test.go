@@ -2,12 +2,17 @@ package tsm1_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
 
 func TestEngine_CursorIterator_Stats(t *testing.T) {
@@ -115,3 +120,142 @@ func TestEngine_CursorIterator_Stats(t *testing.T) {
 		t.Fatalf("expected %v, got %v", exp, got)
 	}
 }
+
+func TestEngine_CursorIterator_WindowStats(t *testing.T) {
+	config := tsm1.NewConfig()
+	config.BlockStats = true
+	e, err := NewEngine(config, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	tags := models.Tags{{Key: []byte("a"), Value: []byte("b")}}
+	write := func(values map[int64]int64) {
+		var points []models.Point
+		for ts, v := range values {
+			points = append(points, models.MustNewPoint("cpu", tags, models.Fields{"value": v}, time.Unix(0, ts)))
+		}
+		if err := e.index.CreateSeriesListIfNotExists(tsdb.NewSeriesCollection(points)); err != nil {
+			t.Fatal(err)
+		}
+		if err := e.WritePoints(points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Snapshot each block to its own file. Only the blocks of the windows
+	// starting at 0 and 100 are entirely within a window; the last block
+	// spans the windows starting at 200 and 300.
+	write(map[int64]int64{10: 4, 20: 1})
+	e.MustWriteSnapshot()
+	write(map[int64]int64{110: 3, 150: 9})
+	e.MustWriteSnapshot()
+	write(map[int64]int64{290: 5, 310: 2})
+	e.MustWriteSnapshot()
+	write(map[int64]int64{420: 6})
+
+	ctx := context.Background()
+	itr, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, windows, ok := itr.(cursors.WindowStatsCursorIterator).WindowStats(ctx, &tsdb.CursorRequest{
+		Name:      []byte("cpu"),
+		Tags:      tags,
+		Field:     "value",
+		Ascending: true,
+		StartTime: 0,
+		EndTime:   500,
+	}, 100)
+	if !ok {
+		t.Fatal("expected window stats")
+	} else if typ != models.Integer {
+		t.Fatalf("unexpected type: got %v, exp %v", typ, models.Integer)
+	}
+	exp := []cursors.WindowStats{
+		{Start: 0, End: 100, Count: 2, Min: 1, Max: 4, Sum: 5, MinTime: 20, MaxTime: 10},
+		{Start: 100, End: 200, Count: 2, Min: 3, Max: 9, Sum: 12, MinTime: 110, MaxTime: 150},
+		{Start: 200, End: 500, Decode: true},
+	}
+	if !cmp.Equal(windows, exp) {
+		t.Fatalf("unexpected windows: -got/+exp\n%s", cmp.Diff(windows, exp))
+	}
+
+	for _, tt := range []struct {
+		agg         datatypes.Aggregate_AggregateType
+		start, end  int64
+		createEmpty bool
+		times       []int64
+		values      []int64
+	}{
+		{agg: datatypes.AggregateTypeCount, end: 500, times: []int64{0, 100, 200, 300, 400}, values: []int64{2, 2, 1, 1, 1}},
+		{agg: datatypes.AggregateTypeCount, start: 5, end: 600, createEmpty: true, times: []int64{5, 100, 200, 300, 400, 500}, values: []int64{2, 2, 1, 1, 1, 0}},
+		{agg: datatypes.AggregateTypeSum, end: 500, times: []int64{0, 100, 200, 300, 400}, values: []int64{5, 12, 5, 2, 6}},
+		{agg: datatypes.AggregateTypeMin, end: 500, times: []int64{20, 110, 290, 310, 420}, values: []int64{1, 3, 5, 2, 6}},
+		{agg: datatypes.AggregateTypeMax, end: 500, times: []int64{10, 150, 290, 310, 420}, values: []int64{4, 9, 5, 2, 6}},
+	} {
+		t.Run(fmt.Sprintf("%s/%d-%d", tt.agg, tt.start, tt.end), func(t *testing.T) {
+			itr, err := e.CreateCursorIterator(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rs := reads.NewWindowAggregateResultSet(ctx, &datatypes.ReadWindowAggregateRequest{
+				Range:       datatypes.TimestampRange{Start: tt.start, End: tt.end},
+				WindowEvery: 100,
+				Aggregate:   &datatypes.Aggregate{Type: tt.agg},
+				CreateEmpty: tt.createEmpty,
+			}, &seriesCursor{rows: []reads.SeriesRow{{
+				Name:       []byte("cpu"),
+				SeriesTags: tags,
+				Tags:       tags,
+				Field:      "value",
+				Query:      cursors.CursorIterators{itr},
+			}}})
+			defer rs.Close()
+
+			if !rs.Next() {
+				t.Fatal("expected series")
+			}
+			cur := rs.Cursor().(cursors.IntegerArrayCursor)
+			var times, values []int64
+			for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+				times = append(times, a.Timestamps...)
+				values = append(values, a.Values...)
+			}
+			if err := cur.Err(); err != nil {
+				t.Fatal(err)
+			}
+			cur.Close()
+
+			if !cmp.Equal(times, tt.times) || !cmp.Equal(values, tt.values) {
+				t.Fatalf("unexpected result: got %v %v, exp %v %v", times, values, tt.times, tt.values)
+			}
+
+			// Only the values of the windows starting at 200, 300 and 400 are decoded.
+			if got, exp := rs.Stats().ScannedValues, 3; got != exp {
+				t.Fatalf("unexpected scanned values: got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
+
+// seriesCursor is a reads.SeriesCursor producing a fixed list of rows.
+type seriesCursor struct {
+	rows []reads.SeriesRow
+}
+
+func (c *seriesCursor) Close()     {}
+func (c *seriesCursor) Err() error { return nil }
+
+func (c *seriesCursor) Next() *reads.SeriesRow {
+	if len(c.rows) == 0 {
+		return nil
+	}
+	row := &c.rows[0]
+	c.rows = c.rows[1:]
+	return row
+}
@@ -131,6 +131,44 @@ func TestEngine_SnapshotsDisabled(t *testing.T) {
 	}
 }
 
+func TestEngine_ReadStats(t *testing.T) {
+	config := tsm1.NewConfig()
+	config.BlockStats = true
+	e, err := NewEngine(config, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	key := []byte("cpu,host=a#!~#value")
+	if err := e.WriteValues(map[string][]tsm1.Value{
+		string(key): {tsm1.NewValue(10, int64(2)), tsm1.NewValue(20, int64(5))},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values in the cache can not be answered from the index.
+	if _, _, ok := e.ReadStats(key, 0, 100); ok {
+		t.Fatal("expected cached values to require decoding")
+	}
+
+	e.MustWriteSnapshot()
+
+	typ, stats, ok := e.ReadStats(key, 0, 100)
+	if !ok {
+		t.Fatal("expected stats to be answered from the index")
+	}
+	if typ != tsm1.BlockInteger {
+		t.Fatalf("unexpected block type: got %v, exp %v", typ, tsm1.BlockInteger)
+	}
+	if min, max, sum := stats.IntegerStats(); stats.Count != 2 || min != 2 || max != 5 || sum != 7 {
+		t.Fatalf("unexpected stats: count=%v min=%v max=%v sum=%v", stats.Count, min, max, sum)
+	}
+}
+
 func TestEngine_ShouldCompactCache(t *testing.T) {
 	nowTime := time.Now()
 
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/limiter"
 	"github.com/influxdata/influxdb/pkg/metrics"
@@ -202,11 +203,14 @@ type FileStore struct {
 	currentGeneration     int        // internally maintained generation
 	currentGenerationFunc func() int // external generation
 	dir                   string
+	coldDir               string // directory of the cold tier, if any.
 
 	files           []TSMFile
 	tsmMMAPWillNeed bool          // If true then the kernel will be advised MMAP_WILLNEED for TSM files.
 	openLimiter     limiter.Fixed // limit the number of concurrent opening TSM files.
 
+	keyring *encryption.Keyring // keys used to decrypt TSM files, if any.
+
 	logger *zap.Logger // Logger to be used for important messages
 
 	tracker *fileTracker
@@ -223,6 +227,8 @@ type FileStore struct {
 type FileStat struct {
 	Path             string
 	HasTombstone     bool
+	KeyID            string // ID of the key the file is encrypted with, if any.
+	StaleKey         bool   // The file is not encrypted with the active key.
 	Size             uint32
 	LastModified     int64
 	MinTime, MaxTime int64
@@ -272,6 +278,13 @@ func (f *FileStore) WithObserver(obs FileStoreObserver) {
 	f.obs = obs
 }
 
+// WithKeyring sets the keys used to decrypt encrypted TSM files. Files not encrypted
+// with the active key are reported as stale so that compactions rewrite them. It
+// must be called before the file store is opened.
+func (f *FileStore) WithKeyring(k *encryption.Keyring) {
+	f.keyring = k
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -384,6 +397,16 @@ func (t *fileTracker) ClearDiskSizes() {
 	}
 }
 
+// SetTiers sets the number of bytes and files in each storage tier.
+func (t *fileTracker) SetTiers(bytes, files map[string]uint64) {
+	labels := t.Labels()
+	for _, tier := range []string{TierHot, TierCold} {
+		labels["tier"] = tier
+		t.metrics.TierDiskSize.With(labels).Set(float64(bytes[tier]))
+		t.metrics.TierFiles.With(labels).Set(float64(files[tier]))
+	}
+}
+
 func formatLevel(level uint64) string {
 	if level >= 4 {
 		return "4+"
@@ -630,6 +653,12 @@ func (f *FileStore) Open(ctx context.Context) error {
 		return err
 	}
 
+	if f.coldDir != "" {
+		if files, err = f.globColdFiles(files); err != nil {
+			return err
+		}
+	}
+
 	// struct to hold the result of opening each reader in a goroutine
 	type res struct {
 		r   *TSMReader
@@ -663,12 +692,21 @@ func (f *FileStore) Open(ctx context.Context) error {
 			start := time.Now()
 			df, err := NewTSMReader(file,
 				WithMadviseWillNeed(f.tsmMMAPWillNeed),
-				WithTSMReaderLogger(f.logger))
+				WithTSMReaderLogger(f.logger),
+				WithTSMReaderKeyring(f.keyring))
 			f.logger.Info("Opened file",
 				zap.String("path", file.Name()),
 				zap.Int("id", idx),
 				zap.Duration("duration", time.Since(start)))
 
+			// A file encrypted with a key that is not available is not corrupt,
+			// and must be kept until the key is provided.
+			if encryption.IsKeyNotFound(err) {
+				file.Close()
+				readerC <- &res{err: fmt.Errorf("cannot read encrypted tsm file %s: %w", file.Name(), err)}
+				return
+			}
+
 			// If we are unable to read a TSM file then log the error, rename
 			// the file, and continue loading the shard without it.
 			if err != nil {
@@ -726,6 +764,7 @@ func (f *FileStore) Open(ctx context.Context) error {
 	sort.Sort(tsmReaders(f.files))
 	f.tracker.SetBytes(sizes)
 	f.tracker.SetFileCount(counts)
+	f.trackTiers()
 	return nil
 }
 
@@ -739,6 +778,7 @@ func (f *FileStore) Close() error {
 	f.lastFileStats = nil
 	f.files = nil
 	f.tracker.ClearFileCounts()
+	f.tracker.SetTiers(nil, nil)
 
 	// Let other methods access this closed object while we do the actual closing.
 	f.mu.Unlock()
@@ -781,6 +821,255 @@ func (f *FileStore) Read(key []byte, t int64) ([]Value, error) {
 	return nil, nil
 }
 
+// ReadStats returns the block type and the combined statistics of the values for key
+// within the time range [min, max]. The statistics are answered from the index of the
+// files without decoding any blocks. It returns false if they cannot be determined
+// from the index alone; that is, if a block within the time range was written
+// without statistics, is only partially within the time range, overlaps another
+// block or has some of its values deleted.
+func (f *FileStore) ReadStats(key []byte, min, max int64) (typ byte, stats BlockStats, ok bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var (
+		entries []IndexEntry
+		blocks  []IndexEntry
+		trbuf   []TimeRange
+		err     error
+	)
+
+	for _, fd := range f.files {
+		if !fd.OverlapsTimeRange(min, max) || !fd.Contains(key) {
+			continue
+		}
+
+		trbuf = fd.TombstoneRange(key, trbuf[:0])
+		entries, err = fd.ReadEntries(key, entries)
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+
+		ft, err := fd.Type(key)
+		if err != nil {
+			return 0, BlockStats{}, false
+		} else if len(blocks) > 0 && ft != typ {
+			return 0, BlockStats{}, false
+		}
+		typ = ft
+
+	LOOP:
+		for _, ie := range entries {
+			if !ie.OverlapsTimeRange(min, max) {
+				continue
+			}
+
+			for _, t := range trbuf {
+				// Skip any blocks only containing values that are tombstoned.
+				if t.Min <= ie.MinTime && t.Max >= ie.MaxTime {
+					continue LOOP
+				}
+				if t.Min <= ie.MaxTime && t.Max >= ie.MinTime {
+					return 0, BlockStats{}, false
+				}
+			}
+
+			if ie.MinTime < min || ie.MaxTime > max || !ie.HasStats() {
+				return 0, BlockStats{}, false
+			}
+			blocks = append(blocks, ie)
+		}
+	}
+
+	// Overlapping blocks may contain values for the same timestamps, which
+	// are only deduplicated when the blocks are decoded.
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].MinTime < blocks[j].MinTime })
+	for i := range blocks {
+		if i > 0 && blocks[i].MinTime <= blocks[i-1].MaxTime {
+			return 0, BlockStats{}, false
+		}
+		stats.Merge(typ, blocks[i].Stats)
+	}
+	return typ, stats, true
+}
+
+// WindowStats are the combined statistics of the values of a key within the
+// windows spanning the time range [Start, End). Unless Decode is set, the range
+// is a single window whose statistics were answered from the index. Otherwise,
+// the statistics of the windows are unknown and their values must be decoded.
+type WindowStats struct {
+	Start, End int64
+	Stats      BlockStats
+	Decode     bool
+}
+
+// windowBlock is a block or cached value contributing to the statistics of a window.
+type windowBlock struct {
+	min, max int64
+	stats    BlockStats
+	decode   bool
+}
+
+// WindowStats returns the block type and the statistics of the values for key
+// within each window of the time range [start, end). Windows are every
+// nanoseconds wide and aligned to the Unix epoch; the first and last windows
+// are truncated to the time range. Windows without values are omitted.
+//
+// The statistics of a window are answered from the index of the files when all
+// of its blocks are entirely within it and could be combined by ReadStats.
+// Adjacent windows for which that is not possible, or that contain any of the
+// cached values, are combined into ranges of windows to be decoded. It returns
+// false if no window could be answered from the index.
+func (f *FileStore) WindowStats(key []byte, start, end, every int64, cached Values) (typ byte, windows []WindowStats, ok bool) {
+	if every <= 0 || start >= end {
+		return 0, nil, false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var (
+		entries []IndexEntry
+		blocks  []windowBlock
+		trbuf   []TimeRange
+		err     error
+	)
+
+	for _, fd := range f.files {
+		if !fd.OverlapsTimeRange(start, end-1) || !fd.Contains(key) {
+			continue
+		}
+
+		trbuf = fd.TombstoneRange(key, trbuf[:0])
+		entries, err = fd.ReadEntries(key, entries)
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+
+		ft, err := fd.Type(key)
+		if err != nil {
+			return 0, nil, false
+		} else if len(blocks) > 0 && ft != typ {
+			return 0, nil, false
+		}
+		typ = ft
+
+	LOOP:
+		for _, ie := range entries {
+			if !ie.OverlapsTimeRange(start, end-1) {
+				continue
+			}
+
+			b := windowBlock{min: ie.MinTime, max: ie.MaxTime, stats: ie.Stats}
+			for _, t := range trbuf {
+				// Skip any blocks only containing values that are tombstoned.
+				if t.Min <= ie.MinTime && t.Max >= ie.MaxTime {
+					continue LOOP
+				}
+				if t.Min <= ie.MaxTime && t.Max >= ie.MinTime {
+					b.decode = true
+				}
+			}
+
+			if ie.MinTime < start || ie.MaxTime >= end || !ie.HasStats() {
+				b.decode = true
+			}
+			b.min, b.max = clampTime(b.min, start, end-1), clampTime(b.max, start, end-1)
+			blocks = append(blocks, b)
+		}
+	}
+
+	for i := 0; i < len(cached); i++ {
+		t := cached[i].UnixNano()
+		if t < start || t >= end {
+			continue
+		}
+		// Skip the other cached values of the window, which is decoded anyway.
+		ws := windowStart(t, every)
+		for i+1 < len(cached) && windowStart(cached[i+1].UnixNano(), every) == ws && cached[i+1].UnixNano() < end {
+			i++
+		}
+		blocks = append(blocks, windowBlock{min: t, max: t, decode: true})
+	}
+
+	// Overlapping blocks may contain values for the same timestamps, which
+	// are only deduplicated when the blocks are decoded.
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].min < blocks[j].min })
+
+	var maxTime int64
+	for i, b := range blocks {
+		ws, we := windowStart(b.min, every), windowStart(b.max, every)
+		if ws != we {
+			// The block spans several windows.
+			b.decode = true
+		}
+		if we += every; we > end || we < ws {
+			we = end
+		}
+		if ws < start {
+			ws = start
+		}
+
+		if n := len(windows); n > 0 && ws < windows[n-1].End {
+			w := &windows[n-1]
+			if b.decode || w.Decode || b.min <= maxTime {
+				w.Stats, w.Decode = BlockStats{}, true
+				if we > w.End {
+					w.End = we
+				}
+			} else {
+				w.Stats.Merge(typ, b.stats)
+			}
+		} else {
+			w := WindowStats{Start: ws, End: we, Decode: b.decode}
+			if !b.decode {
+				w.Stats = b.stats
+			}
+			windows = append(windows, w)
+		}
+
+		if i == 0 || b.max > maxTime {
+			maxTime = b.max
+		}
+	}
+
+	// Combine adjacent ranges of windows to decode, as the windows between
+	// them have no values.
+	var answered bool
+	merged := windows[:0]
+	for _, w := range windows {
+		if n := len(merged); n > 0 && w.Decode && merged[n-1].Decode {
+			merged[n-1].End = w.End
+			continue
+		}
+		answered = answered || !w.Decode
+		merged = append(merged, w)
+	}
+	if !answered {
+		return 0, nil, false
+	}
+	return typ, merged, true
+}
+
+// windowStart returns the start of the window of width every, aligned to the
+// Unix epoch, containing t.
+func windowStart(t, every int64) int64 {
+	r := t % every
+	if r < 0 {
+		r += every
+	}
+	return t - r
+}
+
+// clampTime returns t limited to the time range [min, max].
+func clampTime(t, min, max int64) int64 {
+	if t < min {
+		return min
+	} else if t > max {
+		return max
+	}
+	return t
+}
+
 func (f *FileStore) Cost(key []byte, min, max int64) query.IteratorCost {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
@@ -834,7 +1123,9 @@ func (f *FileStore) Stats() []FileStat {
 	}
 
 	for _, fd := range f.files {
-		f.lastFileStats = append(f.lastFileStats, fd.Stats())
+		stat := fd.Stats()
+		stat.StaleKey = f.keyring != nil && stat.KeyID != f.keyring.ActiveID()
+		f.lastFileStats = append(f.lastFileStats, stat)
 	}
 	return f.lastFileStats
 }
@@ -904,7 +1195,8 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 
 		tsm, err := NewTSMReader(fd,
 			WithMadviseWillNeed(f.tsmMMAPWillNeed),
-			WithTSMReaderLogger(f.logger))
+			WithTSMReaderLogger(f.logger),
+			WithTSMReaderKeyring(f.keyring))
 		if err != nil {
 			return err
 		}
@@ -1044,6 +1336,7 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 	}
 	f.tracker.SetBytes(sizes)
 	f.tracker.SetFileCount(counts)
+	f.trackTiers()
 
 	return nil
 }
@@ -1242,7 +1535,13 @@ func (f *FileStore) CreateSnapshot(ctx context.Context) (string, error) {
 	}
 	for _, tsmf := range files {
 		newpath := filepath.Join(tmpPath, filepath.Base(tsmf.Path()))
-		if err := os.Link(tsmf.Path(), newpath); err != nil {
+		if f.Tier(tsmf.Path()) == TierCold {
+			// Files in the cold tier are usually on another device and cannot be
+			// hard linked into the snapshot.
+			if err := fs.CopyFile(tsmf.Path(), newpath); err != nil {
+				return "", fmt.Errorf("error copying cold tsm file: %q", err)
+			}
+		} else if err := os.Link(tsmf.Path(), newpath); err != nil {
 			return "", fmt.Errorf("error creating tsm hard link: %q", err)
 		}
 		for _, tf := range tsmf.TombstoneFiles() {
@@ -1365,7 +1664,7 @@ func (a descLocations) Len() int      { return len(a) }
 func (a descLocations) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a descLocations) Less(i, j int) bool {
 	if a[i].entry.OverlapsTimeRange(a[j].entry.MinTime, a[j].entry.MaxTime) {
-		return a[i].r.Path() < a[j].r.Path()
+		return filepath.Base(a[i].r.Path()) < filepath.Base(a[j].r.Path())
 	}
 	return a[i].entry.MaxTime < a[j].entry.MaxTime
 }
@@ -1377,7 +1676,7 @@ func (a ascLocations) Len() int      { return len(a) }
 func (a ascLocations) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 func (a ascLocations) Less(i, j int) bool {
 	if a[i].entry.OverlapsTimeRange(a[j].entry.MinTime, a[j].entry.MaxTime) {
-		return a[i].r.Path() < a[j].r.Path()
+		return filepath.Base(a[i].r.Path()) < filepath.Base(a[j].r.Path())
 	}
 	return a[i].entry.MinTime < a[j].entry.MinTime
 }
@@ -1603,6 +1902,10 @@ func (p *purger) purge() {
 
 type tsmReaders []TSMFile
 
-func (a tsmReaders) Len() int           { return len(a) }
-func (a tsmReaders) Less(i, j int) bool { return a[i].Path() < a[j].Path() }
-func (a tsmReaders) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a tsmReaders) Len() int { return len(a) }
+func (a tsmReaders) Less(i, j int) bool {
+	// Files are ordered by name rather than path so that files in the cold tier
+	// keep their position amongst the generations.
+	return filepath.Base(a[i].Path()) < filepath.Base(a[j].Path())
+}
+func (a tsmReaders) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
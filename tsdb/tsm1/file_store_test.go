@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
@@ -2347,6 +2348,176 @@ func TestKeyCursor_TombstoneRange_PartialBoolean(t *testing.T) {
 	}
 }
 
+func TestFileStore_ReadStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := []keyValues{
+		{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 4.0)}},
+		{"cpu", []tsm1.Value{tsm1.NewValue(2, -2.0), tsm1.NewValue(3, 3.0)}},
+		{"mem", []tsm1.Value{tsm1.NewValue(0, int64(7))}},
+	}
+	files, err := newFilesWithBlockStats(dir, true, data...)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+
+	fs := tsm1.NewFileStore(dir)
+	fs.Replace(nil, files)
+	defer fs.Close()
+
+	typ, stats, ok := fs.ReadStats([]byte("cpu"), 0, 10)
+	if !ok {
+		t.Fatal("expected stats to be answered from the index")
+	}
+	if typ != tsm1.BlockFloat64 {
+		t.Fatalf("unexpected block type: got %v, exp %v", typ, tsm1.BlockFloat64)
+	}
+	if got, exp := stats.Count, uint32(4); got != exp {
+		t.Fatalf("unexpected count: got %v, exp %v", got, exp)
+	}
+	if min, max, sum := stats.FloatStats(); min != -2 || max != 4 || sum != 6 {
+		t.Fatalf("unexpected stats: got min=%v max=%v sum=%v", min, max, sum)
+	}
+
+	// A time range only partially covering a block can not be answered.
+	if _, _, ok := fs.ReadStats([]byte("cpu"), 0, 2); ok {
+		t.Fatal("expected partially covered block to require decoding")
+	}
+
+	// Missing keys have no values.
+	if _, stats, ok := fs.ReadStats([]byte("disk"), 0, 10); !ok || stats.Count != 0 {
+		t.Fatalf("unexpected stats for missing key: %v %v", stats, ok)
+	}
+
+	// A block with all values deleted is skipped, while a partial delete
+	// requires decoding.
+	if err := fs.DeleteRange([][]byte{[]byte("cpu")}, 0, 1); err != nil {
+		t.Fatalf("unexpected error delete range: %v", err)
+	}
+	if _, stats, ok := fs.ReadStats([]byte("cpu"), 0, 10); !ok || stats.Count != 2 {
+		t.Fatalf("unexpected stats after delete: %v %v", stats, ok)
+	}
+	if err := fs.DeleteRange([][]byte{[]byte("cpu")}, 3, 3); err != nil {
+		t.Fatalf("unexpected error delete range: %v", err)
+	}
+	if _, _, ok := fs.ReadStats([]byte("cpu"), 0, 10); ok {
+		t.Fatal("expected partially deleted block to require decoding")
+	}
+}
+
+func TestFileStore_ReadStats_Unanswerable(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// Overlapping blocks may duplicate values.
+	files, err := newFilesWithBlockStats(dir, true,
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(2, 2.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(2, 3.0)}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+
+	fs := tsm1.NewFileStore(dir)
+	fs.Replace(nil, files)
+	defer fs.Close()
+
+	if _, _, ok := fs.ReadStats([]byte("cpu"), 0, 10); ok {
+		t.Fatal("expected overlapping blocks to require decoding")
+	}
+
+	// Files written without block statistics.
+	dir2 := MustTempDir()
+	defer os.RemoveAll(dir2)
+	files, err = newFiles(dir2, keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0)}})
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+
+	fs2 := tsm1.NewFileStore(dir2)
+	fs2.Replace(nil, files)
+	defer fs2.Close()
+
+	if _, _, ok := fs2.ReadStats([]byte("cpu"), 0, 10); ok {
+		t.Fatal("expected file without block statistics to require decoding")
+	}
+}
+
+func TestFileStore_WindowStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	files, err := newFilesWithBlockStats(dir, true,
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 4.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(12, 2.0), tsm1.NewValue(15, 3.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(14, 5.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(25, -1.0), tsm1.NewValue(27, 6.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(41, 1.0), tsm1.NewValue(45, 2.0)}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+
+	fs := tsm1.NewFileStore(dir)
+	fs.Replace(nil, files)
+	defer fs.Close()
+
+	stats := func(count uint32, min, max, sum float64, minAt, maxAt int64) tsm1.BlockStats {
+		return tsm1.BlockStats{
+			Count: count,
+			Min:   math.Float64bits(min),
+			Max:   math.Float64bits(max),
+			Sum:   math.Float64bits(sum),
+			MinAt: minAt,
+			MaxAt: maxAt,
+		}
+	}
+
+	// The window starting at 10 has overlapping blocks and the window
+	// starting at 30 has cached values.
+	cached := tsm1.Values{tsm1.NewValue(33, 1.0)}
+	typ, windows, ok := fs.WindowStats([]byte("cpu"), 0, 50, 10, cached)
+	if !ok {
+		t.Fatal("expected window stats")
+	} else if typ != tsm1.BlockFloat64 {
+		t.Fatalf("unexpected block type: got %v, exp %v", typ, tsm1.BlockFloat64)
+	}
+	exp := []tsm1.WindowStats{
+		{Start: 0, End: 10, Stats: stats(2, 1, 4, 5, 0, 1)},
+		{Start: 10, End: 20, Decode: true},
+		{Start: 20, End: 30, Stats: stats(2, -1, 6, 5, 25, 27)},
+		{Start: 30, End: 40, Decode: true},
+		{Start: 40, End: 50, Stats: stats(2, 1, 2, 3, 41, 45)},
+	}
+	if !reflect.DeepEqual(windows, exp) {
+		t.Fatalf("unexpected windows: got %v, exp %v", windows, exp)
+	}
+
+	// Blocks only partially within the time range are decoded, and adjacent
+	// ranges to decode are combined.
+	if err := fs.DeleteRange([][]byte{[]byte("cpu")}, 45, 45); err != nil {
+		t.Fatalf("unexpected error delete range: %v", err)
+	}
+	_, windows, ok = fs.WindowStats([]byte("cpu"), 1, 50, 10, cached)
+	if !ok {
+		t.Fatal("expected window stats")
+	}
+	exp = []tsm1.WindowStats{
+		{Start: 1, End: 20, Decode: true},
+		{Start: 20, End: 30, Stats: stats(2, -1, 6, 5, 25, 27)},
+		{Start: 30, End: 50, Decode: true},
+	}
+	if !reflect.DeepEqual(windows, exp) {
+		t.Fatalf("unexpected windows: got %v, exp %v", windows, exp)
+	}
+
+	// Missing keys have no windows to answer.
+	if _, _, ok := fs.WindowStats([]byte("mem"), 0, 50, 10, nil); ok {
+		t.Fatal("expected no window stats for missing key")
+	}
+}
+
 func TestFileStore_Open(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -2554,6 +2725,68 @@ func TestFileStore_Open_Deleted(t *testing.T) {
 	}
 }
 
+func TestFileStore_Open_Encrypted(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	f := MustTempFile(dir)
+	w, err := tsm1.NewTSMWriter(f, tsm1.WithEncryption(mustNewKeyring(1)))
+	if err != nil {
+		fatal(t, "creating writer", err)
+	}
+	if err := w.Write([]byte("cpu"), []tsm1.Value{tsm1.NewValue(0, 1.0)}); err != nil {
+		fatal(t, "writing values", err)
+	} else if err := w.WriteIndex(); err != nil {
+		fatal(t, "writing index", err)
+	} else if err := w.Close(); err != nil {
+		fatal(t, "closing writer", err)
+	}
+
+	path := filepath.Join(dir, tsm1.DefaultFormatFileName(1, 1)+".tsm")
+	if err := fs.RenameFile(f.Name(), path); err != nil {
+		fatal(t, "renaming file", err)
+	}
+
+	// A file that cannot be decrypted must not be mistaken for a corrupt one.
+	fs := tsm1.NewFileStore(dir)
+	if err := fs.Open(context.Background()); !encryption.IsKeyNotFound(err) {
+		t.Fatalf("unexpected error opening file store: %v", err)
+	}
+	fs.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("encrypted file moved: %v", err)
+	}
+
+	for _, tt := range []struct {
+		keyring *encryption.Keyring
+		stale   bool
+	}{
+		{keyring: mustNewKeyring(1), stale: false},
+		{keyring: mustNewKeyring(2, 1), stale: true},
+	} {
+		fs := tsm1.NewFileStore(dir)
+		fs.WithKeyring(tt.keyring)
+		if err := fs.Open(context.Background()); err != nil {
+			fatal(t, "opening file store", err)
+		}
+
+		if values, err := fs.Read([]byte("cpu"), 0); err != nil {
+			fatal(t, "reading values", err)
+		} else if got, exp := len(values), 1; got != exp {
+			t.Fatalf("value count mismatch: got %v, exp %v", got, exp)
+		}
+
+		stats := fs.Stats()
+		if got, exp := stats[0].KeyID, mustNewKeyring(1).ActiveID(); got != exp {
+			t.Fatalf("key id mismatch: got %v, exp %v", got, exp)
+		} else if got, exp := stats[0].StaleKey, tt.stale; got != exp {
+			t.Fatalf("stale key mismatch: got %v, exp %v", got, exp)
+		}
+		fs.Close()
+	}
+}
+
 func TestFileStore_Delete(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -2893,12 +3126,16 @@ func newFileDir(dir string, values ...keyValues) ([]string, error) {
 }
 
 func newFiles(dir string, values ...keyValues) ([]string, error) {
+	return newFilesWithBlockStats(dir, false, values...)
+}
+
+func newFilesWithBlockStats(dir string, blockStats bool, values ...keyValues) ([]string, error) {
 	var files []string
 
 	id := 1
 	for _, v := range values {
 		f := MustTempFile(dir)
-		w, err := tsm1.NewTSMWriter(f)
+		w, err := tsm1.NewTSMWriter(f, tsm1.WithBlockStats(blockStats))
 		if err != nil {
 			return nil, err
 		}
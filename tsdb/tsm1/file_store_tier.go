@@ -0,0 +1,303 @@
+package tsm1
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+	"go.uber.org/zap"
+)
+
+// Storage tiers of TSM files.
+const (
+	// TierHot is the tier of the files in the directory of the FileStore.
+	TierHot = "hot"
+
+	// TierCold is the tier of the files in the cold directory of the FileStore.
+	TierCold = "cold"
+)
+
+// ErrNoColdTier is returned when moving a file to the cold tier of a FileStore
+// without a cold directory.
+var ErrNoColdTier = errors.New("no cold tier directory configured")
+
+// WithColdDir sets the directory holding the cold tier of the file store. It must
+// be called before the file store is opened.
+func (f *FileStore) WithColdDir(dir string) {
+	if dir != "" {
+		dir = filepath.Clean(dir)
+	}
+	f.coldDir = dir
+}
+
+// ColdDir returns the directory holding the cold tier, if any.
+func (f *FileStore) ColdDir() string { return f.coldDir }
+
+// Tier returns the storage tier of the TSM file at path.
+func (f *FileStore) Tier(path string) string {
+	if f.coldDir != "" && filepath.Dir(path) == f.coldDir {
+		return TierCold
+	}
+	return TierHot
+}
+
+// globColdFiles returns the TSM files in the cold tier along with files, the TSM
+// files in the hot tier. A file found in both tiers was left behind in the hot tier
+// by an interrupted move and is removed from it.
+func (f *FileStore) globColdFiles(files []string) ([]string, error) {
+	cold, err := filepath.Glob(filepath.Join(f.coldDir, fmt.Sprintf("*.%s", TSMFileExtension)))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(cold))
+	for _, fn := range cold {
+		names[filepath.Base(fn)] = struct{}{}
+	}
+
+	hot := files[:0]
+	for _, fn := range files {
+		if _, ok := names[filepath.Base(fn)]; !ok {
+			hot = append(hot, fn)
+			continue
+		}
+
+		f.logger.Info("Removing tsm file already moved to cold tier", zap.String("path", fn))
+		if err := f.obs.FileUnlinking(fn); err != nil {
+			return nil, err
+		} else if err := os.Remove(fn); err != nil {
+			return nil, err
+		} else if err := os.Remove(StatsFilename(fn)); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return append(hot, cold...), nil
+}
+
+// trackTiers updates the tier statistics of the file store. It assumes the lock
+// is held.
+func (f *FileStore) trackTiers() {
+	bytes := make(map[string]uint64, 2)
+	files := make(map[string]uint64, 2)
+	for _, fd := range f.files {
+		tier := f.Tier(fd.Path())
+		bytes[tier] += uint64(fd.Size())
+		files[tier]++
+	}
+	f.tracker.SetTiers(bytes, files)
+}
+
+// PlanColdTier returns the paths of the TSM files in the hot tier that should be
+// moved to the cold tier. Only the files of fully compacted generations without
+// tombstones are moved; a generation is moved once its newest data is older than
+// age, or when the hot tier holds more than maxHotSize bytes, starting with the
+// generations holding the oldest data. A zero age or maxHotSize disables the
+// respective policy.
+func (f *FileStore) PlanColdTier(age time.Duration, maxHotSize uint64, now time.Time) []string {
+	if f.coldDir == "" || (age <= 0 && maxHotSize == 0) {
+		return nil
+	}
+
+	type generation struct {
+		files   []string
+		maxTime int64
+		size    uint64
+		skip    bool
+	}
+
+	var hotSize uint64
+	generations := make(map[int]*generation)
+	for _, stat := range f.Stats() {
+		if f.Tier(stat.Path) == TierCold {
+			continue
+		}
+		hotSize += uint64(stat.Size)
+
+		gen, seq, err := f.parseFileName(stat.Path)
+		if err != nil {
+			continue
+		}
+
+		g := generations[gen]
+		if g == nil {
+			g = &generation{maxTime: stat.MaxTime}
+			generations[gen] = g
+		}
+
+		// A generation with any file that is not fully compacted, or has values
+		// deleted, will be rewritten by a compaction and is not moved.
+		if seq < 4 || stat.HasTombstone {
+			g.skip = true
+		}
+
+		g.files = append(g.files, stat.Path)
+		g.size += uint64(stat.Size)
+		if stat.MaxTime > g.maxTime {
+			g.maxTime = stat.MaxTime
+		}
+	}
+
+	candidates := make([]*generation, 0, len(generations))
+	for _, g := range generations {
+		if !g.skip {
+			candidates = append(candidates, g)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].maxTime < candidates[j].maxTime })
+
+	var paths []string
+	for _, g := range candidates {
+		cold := age > 0 && now.Sub(time.Unix(0, g.maxTime)) > age
+		if !cold && maxHotSize > 0 && hotSize > maxHotSize {
+			cold = true
+		}
+		if !cold {
+			continue
+		}
+
+		paths = append(paths, g.files...)
+		hotSize -= g.size
+	}
+	return paths
+}
+
+// MoveToColdTier moves the TSM file at path, and its statistics file, to the cold
+// tier. The file remains readable throughout and is replaced in the file store by
+// its copy once the copy is complete.
+//
+// MoveToColdTier must not be called while the file may be compacted or have
+// values deleted.
+func (f *FileStore) MoveToColdTier(path string) error {
+	if f.coldDir == "" {
+		return ErrNoColdTier
+	}
+
+	f.mu.RLock()
+	var old TSMFile
+	for _, fd := range f.files {
+		if fd.Path() == path {
+			old = fd
+			break
+		}
+	}
+	f.mu.RUnlock()
+
+	if old == nil {
+		return fmt.Errorf("tsm file not found: %s", path)
+	} else if f.Tier(path) == TierCold {
+		return nil
+	} else if old.HasTombstones() {
+		return fmt.Errorf("tsm file has tombstones: %s", path)
+	}
+
+	newPath := filepath.Join(f.coldDir, filepath.Base(path))
+	tmpPath := fmt.Sprintf("%s.%s", newPath, TmpTSMFileExtension)
+
+	// Remove anything left behind by a previous attempt that failed.
+	for _, fn := range []string{tmpPath, StatsFilename(newPath)} {
+		if err := os.Remove(fn); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := fs.CopyFile(path, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	// Copy the associated stats file, if available.
+	statsFile := StatsFilename(path)
+	if _, err := os.Stat(statsFile); err == nil {
+		if err := f.obs.FileFinishing(StatsFilename(newPath)); err != nil {
+			return err
+		} else if err := fs.CopyFile(statsFile, StatsFilename(newPath)); err != nil {
+			return err
+		}
+	}
+
+	// give the observer a chance to process the file first.
+	if err := f.obs.FileFinishing(newPath); err != nil {
+		return err
+	} else if err := fs.RenameFile(tmpPath, newPath); err != nil {
+		return err
+	} else if err := fs.SyncDir(f.coldDir); err != nil {
+		return err
+	}
+
+	fd, err := os.Open(newPath)
+	if err != nil {
+		return err
+	}
+
+	tsm, err := NewTSMReader(fd,
+		WithMadviseWillNeed(f.tsmMMAPWillNeed),
+		WithTSMReaderLogger(f.logger),
+		WithTSMReaderKeyring(f.keyring))
+	if err != nil {
+		return err
+	}
+	tsm.WithObserver(f.obs)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	idx := -1
+	for i, fd := range f.files {
+		if fd == old {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		// The file was replaced while it was being copied, so the copy is stale.
+		if err := tsm.Close(); err != nil {
+			return err
+		} else if err := tsm.Remove(); err != nil {
+			return err
+		}
+		return fmt.Errorf("tsm file replaced during move: %s", path)
+	}
+
+	if err := f.obs.FileUnlinking(path); err != nil {
+		return err
+	}
+	if _, err := os.Stat(statsFile); err == nil {
+		if err := f.obs.FileUnlinking(statsFile); err != nil {
+			return err
+		}
+	}
+
+	f.files[idx] = tsm
+
+	// As with a replace, a file in use by queries is moved out of the way and
+	// removed once they complete.
+	if old.InUse() {
+		if err := old.Rename(fmt.Sprintf("%s.%s", path, TmpTSMFileExtension)); err != nil {
+			return err
+		} else if err := os.Remove(statsFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		f.purger.add([]TSMFile{old})
+	} else {
+		if err := old.Close(); err != nil {
+			return err
+		} else if err := old.Remove(); err != nil {
+			return err
+		}
+	}
+
+	if err := fs.SyncDir(f.dir); err != nil {
+		return err
+	}
+
+	// The path of the file has changed, so lastModified must change for the stats
+	// of the file store to be recalculated by the compaction planner.
+	f.lastModified = f.lastModified.Add(1)
+	f.lastFileStats = nil
+	f.trackTiers()
+	return nil
+}
@@ -0,0 +1,211 @@
+package tsm1_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// newFullyCompactedFileDir creates a TSM file for each of values, named as a
+// fully compacted generation.
+func newFullyCompactedFileDir(dir string, values ...keyValues) ([]string, error) {
+	files, err := newFileDir(dir, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, file := range files {
+		gen, _, err := tsm1.DefaultParseFileName(file)
+		if err != nil {
+			return nil, err
+		}
+
+		newName := filepath.Join(dir, tsm1.DefaultFormatFileName(gen, 4)+".tsm")
+		if err := fs.RenameFile(file, newName); err != nil {
+			return nil, err
+		}
+		files[i] = newName
+	}
+	return files, nil
+}
+
+func TestFileStore_MoveToColdTier(t *testing.T) {
+	dir, coldDir := MustTempDir(), MustTempDir()
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(coldDir)
+
+	data := []keyValues{
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(1, 2.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(2, 3.0)}},
+	}
+
+	files, err := newFullyCompactedFileDir(dir, data...)
+	if err != nil {
+		fatal(t, "creating test files", err)
+	}
+
+	fs := tsm1.NewFileStore(dir)
+	fs.WithColdDir(coldDir)
+	if err := fs.Open(context.Background()); err != nil {
+		fatal(t, "opening file store", err)
+	}
+	defer fs.Close()
+
+	paths := fs.PlanColdTier(time.Hour, 0, time.Now())
+	if !reflect.DeepEqual(paths, files) {
+		t.Fatalf("unexpected plan: got %v, exp %v", paths, files)
+	}
+
+	// Should record references to the existing TSM files, which must remain
+	// readable while they are moved.
+	cur := fs.KeyCursor(context.Background(), []byte("cpu"), 0, true)
+	defer cur.Close()
+
+	for _, path := range paths {
+		if err := fs.MoveToColdTier(path); err != nil {
+			t.Fatalf("move to cold tier: %v", err)
+		}
+	}
+
+	for i, file := range fs.Files() {
+		if got, exp := file.Path(), filepath.Join(coldDir, filepath.Base(files[i])); got != exp {
+			t.Fatalf("unexpected path: got %v, exp %v", got, exp)
+		} else if got, exp := fs.Tier(file.Path()), tsm1.TierCold; got != exp {
+			t.Fatalf("unexpected tier: got %v, exp %v", got, exp)
+		}
+	}
+
+	if paths := fs.PlanColdTier(time.Hour, 0, time.Now()); len(paths) != 0 {
+		t.Fatalf("unexpected plan after move: %v", paths)
+	}
+
+	buf := make([]tsm1.FloatValue, 10)
+	values, err := cur.ReadFloatBlock(&buf)
+	if err != nil {
+		t.Fatalf("read block: %v", err)
+	} else if got, exp := len(values), 1; got != exp {
+		t.Fatalf("value length mismatch: got %v, exp %v", got, exp)
+	}
+
+	// New cursors read from the cold tier.
+	c := fs.KeyCursor(context.Background(), []byte("cpu"), 0, true)
+	defer c.Close()
+	for i, d := range data {
+		values, err := c.ReadFloatBlock(&buf)
+		if err != nil {
+			t.Fatalf("read block %d: %v", i, err)
+		} else if got, exp := values[0].Value(), d.values[0].Value(); got != exp {
+			t.Fatalf("value %d mismatch: got %v, exp %v", i, got, exp)
+		}
+		c.Next()
+	}
+}
+
+func TestFileStore_PlanColdTier(t *testing.T) {
+	dir, coldDir := MustTempDir(), MustTempDir()
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(coldDir)
+
+	now := time.Unix(0, 0).Add(10 * time.Hour)
+	data := []keyValues{
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(now.Add(-8*time.Hour).UnixNano(), 1.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(now.Add(-4*time.Hour).UnixNano(), 2.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(now.Add(-2*time.Hour).UnixNano(), 3.0)}},
+	}
+
+	files, err := newFullyCompactedFileDir(dir, data...)
+	if err != nil {
+		fatal(t, "creating test files", err)
+	}
+
+	// A generation that is not fully compacted is never moved.
+	level1, err := newFileDir(dir, keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 4.0)}})
+	if err != nil {
+		fatal(t, "creating test files", err)
+	}
+	if err := fs.RenameFile(level1[0], filepath.Join(dir, tsm1.DefaultFormatFileName(4, 1)+".tsm")); err != nil {
+		fatal(t, "renaming test file", err)
+	}
+
+	store := tsm1.NewFileStore(dir)
+	store.WithColdDir(coldDir)
+	if err := store.Open(context.Background()); err != nil {
+		fatal(t, "opening file store", err)
+	}
+	defer store.Close()
+
+	var size uint64
+	for _, stat := range store.Stats() {
+		size += uint64(stat.Size)
+	}
+
+	tests := []struct {
+		name       string
+		age        time.Duration
+		maxHotSize uint64
+		exp        []string
+	}{
+		{name: "disabled"},
+		{name: "age", age: 3 * time.Hour, exp: files[:2]},
+		{name: "max hot size", maxHotSize: size - 1, exp: files[:1]},
+		{name: "age and max hot size", age: 6 * time.Hour, maxHotSize: 1, exp: files[:3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.PlanColdTier(tt.age, tt.maxHotSize, now); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("unexpected plan: got %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestFileStore_Open_ColdTierInterruptedMove(t *testing.T) {
+	dir, coldDir := MustTempDir(), MustTempDir()
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(coldDir)
+
+	data := []keyValues{
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0)}},
+		keyValues{"cpu", []tsm1.Value{tsm1.NewValue(1, 2.0)}},
+	}
+
+	files, err := newFullyCompactedFileDir(dir, data...)
+	if err != nil {
+		fatal(t, "creating test files", err)
+	}
+
+	// The move of the first file completed, but it was not removed from the hot tier.
+	coldPath := filepath.Join(coldDir, filepath.Base(files[0]))
+	if err := fs.CopyFile(files[0], coldPath); err != nil {
+		fatal(t, "copying test file", err)
+	}
+
+	store := tsm1.NewFileStore(dir)
+	store.WithColdDir(coldDir)
+	if err := store.Open(context.Background()); err != nil {
+		fatal(t, "opening file store", err)
+	}
+	defer store.Close()
+
+	if got, exp := store.Count(), 2; got != exp {
+		t.Fatalf("file count mismatch: got %v, exp %v", got, exp)
+	}
+
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Fatalf("expected hot copy to be removed: %v", err)
+	}
+
+	if got, exp := store.Files()[0].Path(), coldPath; got != exp {
+		t.Fatalf("unexpected path: got %v, exp %v", got, exp)
+	} else if got, exp := store.Files()[1].Path(), files[1]; got != exp {
+		t.Fatalf("unexpected path: got %v, exp %v", got, exp)
+	}
+}
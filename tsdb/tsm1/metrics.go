@@ -131,8 +131,10 @@ func (m *compactionMetrics) PrometheusCollectors() []prometheus.Collector {
 
 // fileMetrics are a set of metrics concerned with tracking data about compactions.
 type fileMetrics struct {
-	DiskSize *prometheus.GaugeVec
-	Files    *prometheus.GaugeVec
+	DiskSize     *prometheus.GaugeVec
+	Files        *prometheus.GaugeVec
+	TierDiskSize *prometheus.GaugeVec
+	TierFiles    *prometheus.GaugeVec
 }
 
 // newFileMetrics initialises the prometheus metrics for tracking files on disk.
@@ -141,6 +143,9 @@ func newFileMetrics(labels prometheus.Labels) *fileMetrics {
 	for k := range labels {
 		names = append(names, k)
 	}
+	tierNames := append(append([]string(nil), names...), "tier")
+	sort.Strings(tierNames)
+
 	names = append(names, "level")
 	sort.Strings(names)
 
@@ -157,6 +162,18 @@ func newFileMetrics(labels prometheus.Labels) *fileMetrics {
 			Name:      "total",
 			Help:      "Number of files.",
 		}, names),
+		TierDiskSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: fileStoreSubsystem,
+			Name:      "tier_disk_bytes",
+			Help:      "Number of bytes TSM files using on disk in each storage tier.",
+		}, tierNames),
+		TierFiles: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: fileStoreSubsystem,
+			Name:      "tier_total",
+			Help:      "Number of files in each storage tier.",
+		}, tierNames),
 	}
 }
 
@@ -165,6 +182,8 @@ func (m *fileMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		m.DiskSize,
 		m.Files,
+		m.TierDiskSize,
+		m.TierFiles,
 	}
 }
 
@@ -55,6 +55,37 @@ func TestMetrics_Filestore(t *testing.T) {
 	}
 }
 
+func TestMetrics_FilestoreTiers(t *testing.T) {
+	metrics := newFileMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
+	tracker := newFileTracker(metrics, prometheus.Labels{"engine_id": "1", "node_id": "0"})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	tracker.SetTiers(map[string]uint64{TierHot: 300, TierCold: 700}, map[string]uint64{TierHot: 2, TierCold: 1})
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := namespace + "_" + fileStoreSubsystem + "_"
+	for _, tt := range []struct {
+		name, tier string
+		exp        float64
+	}{
+		{"tier_disk_bytes", TierHot, 300},
+		{"tier_disk_bytes", TierCold, 700},
+		{"tier_total", TierHot, 2},
+		{"tier_total", TierCold, 1},
+	} {
+		m := promtest.MustFindMetric(t, mfs, base+tt.name, prometheus.Labels{"engine_id": "1", "node_id": "0", "tier": tt.tier})
+		if got := m.GetGauge().GetValue(); got != tt.exp {
+			t.Errorf("[%s] got %v, expected %v", m, got, tt.exp)
+		}
+	}
+}
+
 func TestMetrics_Cache(t *testing.T) {
 	// metrics to be shared by multiple file stores.
 	metrics := newCacheMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
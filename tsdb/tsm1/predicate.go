@@ -115,10 +115,11 @@ type predicateMatcher struct {
 //
 // It is not safe to modify p.pred on the returned clone.
 func (p *predicateMatcher) Clone() influxdb.Predicate {
+	state := p.state.Clone()
 	return &predicateMatcher{
 		pred:  p.pred,
-		state: p.state.Clone(),
-		root:  p.root.Clone(),
+		state: state,
+		root:  p.root.Clone(state),
 	}
 }
 
@@ -385,10 +386,10 @@ func newPredicateCache(state *predicateState) predicateCache {
 	}
 }
 
-// Clone returns a deep copy of p.
-func (p *predicateCache) Clone() *predicateCache {
+// Clone returns a copy of p that caches responses for state.
+func (p *predicateCache) Clone(state *predicateState) *predicateCache {
 	return &predicateCache{
-		state: p.state.Clone(),
+		state: state,
 		gen:   p.gen,
 		resp:  p.resp,
 	}
@@ -415,8 +416,8 @@ type predicateNode interface {
 	// a response.
 	Update() predicateResponse
 
-	// Clone returns a deep copy of the node.
-	Clone() predicateNode
+	// Clone returns a deep copy of the node that reads tag values from state.
+	Clone(state *predicateState) predicateNode
 }
 
 // predicateNodeAnd combines two predicate nodes with an And.
@@ -426,11 +427,11 @@ type predicateNodeAnd struct {
 }
 
 // Clone returns a deep copy of p.
-func (p *predicateNodeAnd) Clone() predicateNode {
+func (p *predicateNodeAnd) Clone(state *predicateState) predicateNode {
 	return &predicateNodeAnd{
-		predicateCache: *p.predicateCache.Clone(),
-		left:           p.left.Clone(),
-		right:          p.right.Clone(),
+		predicateCache: *p.predicateCache.Clone(state),
+		left:           p.left.Clone(state),
+		right:          p.right.Clone(state),
 	}
 }
 
@@ -467,11 +468,11 @@ type predicateNodeOr struct {
 }
 
 // Clone returns a deep copy of p.
-func (p *predicateNodeOr) Clone() predicateNode {
-	return &predicateNodeAnd{
-		predicateCache: *p.predicateCache.Clone(),
-		left:           p.left.Clone(),
-		right:          p.right.Clone(),
+func (p *predicateNodeOr) Clone(state *predicateState) predicateNode {
+	return &predicateNodeOr{
+		predicateCache: *p.predicateCache.Clone(state),
+		left:           p.left.Clone(state),
+		right:          p.right.Clone(state),
 	}
 }
 
@@ -514,20 +515,25 @@ type predicateNodeComparison struct {
 }
 
 // Clone returns a deep copy of p.
-func (p *predicateNodeComparison) Clone() predicateNode {
-	q := &predicateNodeComparison{
-		predicateCache: *p.predicateCache.Clone(),
+func (p *predicateNodeComparison) Clone(state *predicateState) predicateNode {
+	return &predicateNodeComparison{
+		predicateCache: *p.predicateCache.Clone(state),
 		comp:           p.comp,
-		// skip rightReg as it shouldn't be mutated
-		leftLiteral:  make([]byte, len(p.leftLiteral)),
-		rightLiteral: make([]byte, len(p.rightLiteral)),
+		// rightReg is shared as it is safe for concurrent use.
+		rightReg:     p.rightReg,
+		leftLiteral:  cloneBytes(p.leftLiteral),
+		rightLiteral: cloneBytes(p.rightLiteral),
 		leftIndex:    p.leftIndex,
 		rightIndex:   p.rightIndex,
 	}
+}
 
-	copy(q.leftLiteral, p.leftLiteral)
-	copy(q.rightLiteral, p.rightLiteral)
-	return q
+// cloneBytes returns a copy of b, preserving nil, which marks an unset literal.
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
 }
 
 // Update checks if both sides of the comparison are determined, and if so, evaluates
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/storage/wal"
 )
 
 func TestPredicate_Matches(t *testing.T) {
@@ -218,10 +219,61 @@ func TestPredicate_Matches(t *testing.T) {
 			if got, exp := pred.Matches([]byte(test.Key)), test.Matches; got != exp {
 				t.Fatal("match failure:", "got", got, "!=", "exp", exp)
 			}
+
+			// Deletes clone the predicate for each file, so clones must match the same keys.
+			if got, exp := pred.Clone().Matches([]byte(test.Key)), test.Matches; got != exp {
+				t.Fatal("clone match failure:", "got", got, "!=", "exp", exp)
+			}
 		})
 	}
 }
 
+func TestPredicate_DeleteBucketRangeWALEntry(t *testing.T) {
+	protoPred := predicate(
+		andNode(
+			orNode(
+				comparisonNode(datatypes.ComparisonEqual, tagNode("host"), stringNode("a")),
+				comparisonNode(datatypes.ComparisonRegex, tagNode("host"), regexNode(`^web-\d+$`))),
+			comparisonNode(datatypes.ComparisonNotRegex, tagNode("region"), regexNode("^eu-"))))
+
+	pred1, err := NewProtobufPredicate(protoPred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	predData, err := pred1.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := &wal.DeleteBucketRangeWALEntry{OrgID: 1, BucketID: 2, Min: 1, Max: 2, Predicate: predData}
+	b, err := entry.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded wal.DeleteBucketRangeWALEntry
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	pred2, err := UnmarshalPredicate(decoded.Predicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, exp := range map[string]bool{
+		"bucketorg,host=a,region=us-west":      true,
+		"bucketorg,host=web-12,region=us-east": true,
+		"bucketorg,host=web-12,region=eu-west": false,
+		"bucketorg,host=web-x,region=us-east":  false,
+		"bucketorg,host=b,region=us-west":      false,
+	} {
+		if got := pred2.Matches([]byte(key)); got != exp {
+			t.Errorf("%s: got %v, expected %v", key, got, exp)
+		}
+	}
+}
+
 func TestPredicate_Unmarshal(t *testing.T) {
 	protoPred := predicate(
 		orNode(
@@ -2,11 +2,14 @@ package tsm1
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"sync/atomic"
 
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +24,7 @@ type TSMReader struct {
 
 	logger          *zap.Logger
 	madviseWillNeed bool // Hint to the kernel with MADV_WILLNEED.
+	keyring         *encryption.Keyring
 	mu              sync.RWMutex
 
 	// accessor provides access and decoding of blocks for the reader.
@@ -35,6 +39,12 @@ type TSMReader struct {
 	// size is the size of the file on disk.
 	size int64
 
+	// version is the TSM file format version.
+	version byte
+
+	// keyID is the ID of the key the file is encrypted with, if any.
+	keyID string
+
 	// lastModified is the last time this file was modified on disk
 	lastModified int64
 
@@ -57,7 +67,17 @@ var WithTSMReaderLogger = func(logger *zap.Logger) tsmReaderOption {
 	}
 }
 
-// NewTSMReader returns a new TSMReader from the given file.
+// WithTSMReaderKeyring is an option for specifying the keys used to decrypt
+// encrypted files.
+var WithTSMReaderKeyring = func(keyring *encryption.Keyring) tsmReaderOption {
+	return func(r *TSMReader) {
+		r.keyring = keyring
+	}
+}
+
+// NewTSMReader returns a new TSMReader from the given file. Blocks of encrypted
+// files are decrypted as they are read, and a KeyNotFoundError is returned if
+// their key is not available.
 func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	t := &TSMReader{
 		logger: zap.NewNop(),
@@ -72,11 +92,13 @@ func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	}
 	t.size = stat.Size()
 	t.lastModified = stat.ModTime().UnixNano()
-	t.accessor = &mmapAccessor{
+	accessor := &mmapAccessor{
 		logger:       t.logger,
 		f:            f,
 		mmapWillNeed: t.madviseWillNeed,
+		keyring:      t.keyring,
 	}
+	t.accessor = accessor
 
 	index, err := t.accessor.init()
 	if err != nil {
@@ -84,6 +106,8 @@ func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	}
 
 	t.index = index
+	t.version = index.version
+	t.keyID = accessor.keyID
 	t.tombstoner = NewTombstoner(t.Path(), index.MaybeContainsKey)
 
 	if err := t.applyTombstones(); err != nil {
@@ -201,6 +225,7 @@ func (t *TSMReader) Type(key []byte) (byte, error) {
 }
 
 // MeasurementStats returns the on-disk measurement stats for this file, if available.
+// The stats files of encrypted files are encrypted as well.
 func (t *TSMReader) MeasurementStats() (MeasurementStats, error) {
 	f, err := os.Open(StatsFilename(t.Path()))
 	if os.IsNotExist(err) {
@@ -210,8 +235,24 @@ func (t *TSMReader) MeasurementStats() (MeasurementStats, error) {
 	}
 	defer f.Close()
 
+	var r io.Reader = bufio.NewReader(f)
+	if keyID, err := encryption.FileKeyID(f); err != nil {
+		return nil, err
+	} else if keyID != "" {
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := encryption.ReadFile(f, fi.Size(), t.keyring)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+		r = bytes.NewReader(data)
+	}
+
 	stats := make(MeasurementStats)
-	if _, err := stats.ReadFrom(bufio.NewReader(f)); err != nil {
+	if _, err := stats.ReadFrom(r); err != nil {
 		return nil, err
 	}
 	return stats, err
@@ -395,6 +436,12 @@ func (t *TSMReader) KeyCount() int {
 	return t.index.KeyCount()
 }
 
+// HasBlockStats returns true if the file records the statistics of the values
+// in each block in its index.
+func (t *TSMReader) HasBlockStats() bool {
+	return t.version >= VersionBlockStats
+}
+
 // ReadEntries reads the index entries for key into entries.
 func (t *TSMReader) ReadEntries(key []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	return t.index.ReadEntries(key, entries)
@@ -413,6 +460,10 @@ func (t *TSMReader) Size() uint32 {
 	return uint32(size)
 }
 
+// KeyID returns the ID of the key the file is encrypted with, or an empty string
+// if the file is not encrypted.
+func (t *TSMReader) KeyID() string { return t.keyID }
+
 // LastModified returns the last time the underlying file was modified.
 func (t *TSMReader) LastModified() int64 {
 	t.mu.RLock()
@@ -463,6 +514,7 @@ func (t *TSMReader) Stats() FileStat {
 		MinKey:       minKey,
 		MaxKey:       maxKey,
 		HasTombstone: t.tombstoner.HasTombstones(),
+		KeyID:        t.keyID,
 	}
 }
 
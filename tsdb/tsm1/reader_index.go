@@ -151,6 +151,10 @@ type indirectIndex struct {
 	// prefixTombstones contains the tombestoned keys with a subset of the values deleted that
 	// all share the same prefix.
 	prefixTombstones *prefixTree
+
+	// version is the version of the TSM file containing the index, which determines the
+	// size of the index entries.
+	version byte
 }
 
 // NewIndirectIndex returns a new indirect index.
@@ -158,6 +162,7 @@ func NewIndirectIndex() *indirectIndex {
 	return &indirectIndex{
 		tombstones:       make(map[uint32][]TimeRange),
 		prefixTombstones: newPrefixTree(),
+		version:          Version,
 	}
 }
 
@@ -177,7 +182,7 @@ func (d *indirectIndex) ReadEntries(key []byte, entries []IndexEntry) ([]IndexEn
 		return nil, nil
 	}
 
-	entries, err := readEntries(d.b.access(iter.EntryOffset(&d.b), 0), entries)
+	entries, err := readEntries(d.b.access(iter.EntryOffset(&d.b), 0), d.entrySize(), entries)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +350,7 @@ func (d *indirectIndex) DeleteRange(keys [][]byte, minTime, maxTime int64) bool
 		}
 
 		entryOffset := iter.EntryOffset(&d.b)
-		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), d.entrySize(), entries)
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -410,7 +415,7 @@ func (d *indirectIndex) DeleteRange(keys [][]byte, minTime, maxTime int64) bool
 		// rare and only during concurrent deletes to the same key. We could make
 		// a copy of the entries before getting here, but that penalizes the common
 		// no-concurrent case.
-		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), d.entrySize(), entries)
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -504,7 +509,7 @@ func (d *indirectIndex) DeletePrefix(prefix []byte, minTime, maxTime int64,
 		}
 
 		entryOffset := iter.EntryOffset(&d.b)
-		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(entryOffset, 0), d.entrySize(), entries)
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -591,7 +596,7 @@ func (d *indirectIndex) DeletePrefix(prefix []byte, minTime, maxTime int64,
 		// rare and only during concurrent deletes to the same key. We could make
 		// a copy of the entries before getting here, but that penalizes the common
 		// no-concurrent case.
-		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), entries)
+		entries, err = readEntriesTimes(d.b.access(p.EntryOffset, 0), d.entrySize(), entries)
 		if err != nil {
 			// If we have an error reading the entries for a key, we should just pretend
 			// the whole key is deleted. Maybe a better idea is to report this up somehow
@@ -761,6 +766,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 	}
 
 	var minTime, maxTime int64 = math.MaxInt64, math.MinInt64
+	entrySize := d.entrySize()
 
 	// To create our "indirect" index, we need to find the location of all the keys in
 	// the raw byte slice.  The keys are listed once each (in sorted order).  Following
@@ -811,7 +817,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			minTime = minT
 		}
 
-		i += (count - 1) * indexEntrySize
+		i += (count - 1) * entrySize
 
 		// Find the max time for the block
 		if i+16 >= iMax {
@@ -822,7 +828,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			maxTime = maxT
 		}
 
-		i += indexEntrySize
+		i += entrySize
 	}
 
 	ro.Done()
@@ -854,12 +860,17 @@ func (d *indirectIndex) Close() error {
 	return nil
 }
 
+// entrySize returns the size in bytes of each index entry.
+func (d *indirectIndex) entrySize() uint32 {
+	return indexEntrySizeFor(d.version)
+}
+
 func readKey(b []byte) (key []byte) {
 	size := binary.BigEndian.Uint16(b[:2])
 	return b[2 : 2+size]
 }
 
-func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
+func readEntries(b []byte, entrySize uint32, entries []IndexEntry) ([]IndexEntry, error) {
 	if len(b) < indexTypeSize+indexCountSize {
 		return entries[:0], errors.New("readEntries: data too short for headers")
 	}
@@ -876,7 +887,13 @@ func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 		if err := entries[i].UnmarshalBinary(b); err != nil {
 			return entries[:0], err
 		}
-		b = b[indexEntrySize:]
+		entries[i].Stats = BlockStats{}
+		if entrySize > indexEntrySize {
+			if err := entries[i].Stats.UnmarshalBinary(b[indexEntrySize:]); err != nil {
+				return entries[:0], err
+			}
+		}
+		b = b[entrySize:]
 	}
 
 	return entries, nil
@@ -884,7 +901,7 @@ func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 
 // readEntriesTimes is a helper function to read entries at the provided buffer but
 // only reading in the min and max times.
-func readEntriesTimes(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
+func readEntriesTimes(b []byte, entrySize uint32, entries []IndexEntry) ([]IndexEntry, error) {
 	if len(b) < indexTypeSize+indexCountSize {
 		return entries[:0], errors.New("readEntries: data too short for headers")
 	}
@@ -898,12 +915,12 @@ func readEntriesTimes(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	b = b[indexTypeSize+indexCountSize:]
 
 	for i := range entries {
-		if len(b) < indexEntrySize {
+		if len(b) < int(entrySize) {
 			return entries[:0], errors.New("readEntries: stream too short for entry")
 		}
 		entries[i].MinTime = int64(binary.BigEndian.Uint64(b[0:8]))
 		entries[i].MaxTime = int64(binary.BigEndian.Uint64(b[8:16]))
-		b = b[indexEntrySize:]
+		b = b[entrySize:]
 	}
 
 	return entries, nil
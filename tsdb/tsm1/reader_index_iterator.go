@@ -135,7 +135,7 @@ func (t *TSMIndexIterator) Type() byte {
 func (t *TSMIndexIterator) Entries() []IndexEntry {
 	if len(t.entries) == 0 {
 		buf := t.b.access(t.eoffset, 0)
-		t.entries, t.err = readEntries(buf, t.entries)
+		t.entries, t.err = readEntries(buf, t.d.entrySize(), t.entries)
 	}
 	if t.err != nil {
 		return nil
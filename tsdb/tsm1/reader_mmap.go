@@ -3,16 +3,19 @@ package tsm1
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"sync/atomic"
 
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 	"go.uber.org/zap"
 )
 
 // mmapAccess is mmap based block accessor.  It access blocks through an
-// MMAP file interface.
+// MMAP file interface.  Blocks of encrypted files are decrypted as they are
+// read instead, and only their index is held in memory.
 type mmapAccessor struct {
 	accessCount uint64 // Counter incremented everytime the mmapAccessor is accessed
 	freeCount   uint64 // Counter to determine whether the accessor can free its resources
@@ -20,8 +23,12 @@ type mmapAccessor struct {
 	logger       *zap.Logger
 	mmapWillNeed bool // If true then mmap advise value MADV_WILLNEED will be provided the kernel for b.
 
+	keyring *encryption.Keyring // Keys used to decrypt encrypted files.
+	keyID   string              // ID of the key the file is encrypted with, if any.
+
 	mu    sync.RWMutex
 	b     []byte
+	enc   *encryption.FileReader // Decrypts blocks of encrypted files, which are not mapped into b.
 	f     *os.File
 	_path string // If the underlying file is renamed then this gets updated
 
@@ -35,27 +42,55 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 	// Set the path explicitly.
 	m._path = m.f.Name()
 
-	if err := verifyVersion(m.f); err != nil {
+	keyID, err := encryption.FileKeyID(m.f)
+	if err != nil {
 		return nil, err
 	}
 
-	var err error
+	var version byte
+	var index []byte
+	if keyID == "" {
+		if version, err = verifyVersion(m.f); err != nil {
+			return nil, err
+		} else if err := m.mmap(); err != nil {
+			return nil, err
+		} else if index, err = m.mappedIndex(); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := m.decrypt(keyID); err != nil {
+			return nil, err
+		} else if version, err = verifyVersion(io.NewSectionReader(m.enc, 0, m.enc.Size())); err != nil {
+			return nil, err
+		} else if index, err = m.decryptIndex(); err != nil {
+			return nil, err
+		}
+	}
 
-	if _, err := m.f.Seek(0, 0); err != nil {
+	m.index = NewIndirectIndex()
+	m.index.version = version
+	if err := m.index.UnmarshalBinary(index); err != nil {
 		return nil, err
 	}
+	m.index.logger = m.logger
+
+	// Allow resources to be freed immediately if requested
+	m.incAccess()
+	atomic.StoreUint64(&m.freeCount, 1)
 
+	return m.index, nil
+}
+
+// mmap maps the file into b.
+func (m *mmapAccessor) mmap() error {
 	stat, err := m.f.Stat()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	m.b, err = mmap(m.f, 0, int(stat.Size()))
 	if err != nil {
-		return nil, err
-	}
-	if len(m.b) < 8 {
-		return nil, fmt.Errorf("mmapAccessor: byte slice too small for indirectIndex")
+		return err
 	}
 
 	// Hint to the kernel that we will be reading the file.  It would be better to hint
@@ -63,27 +98,85 @@ func (m *mmapAccessor) init() (*indirectIndex, error) {
 	// implemented as yet.
 	if m.mmapWillNeed {
 		if err := madviseWillNeed(m.b); err != nil {
-			return nil, err
+			return err
 		}
 	}
+	return nil
+}
+
+// mappedIndex returns the index of the file mapped into b.
+func (m *mmapAccessor) mappedIndex() ([]byte, error) {
+	if len(m.b) < 8 {
+		return nil, fmt.Errorf("mmapAccessor: byte slice too small for indirectIndex")
+	}
 
 	indexOfsPos := len(m.b) - 8
 	indexStart := binary.BigEndian.Uint64(m.b[indexOfsPos : indexOfsPos+8])
 	if indexStart >= uint64(indexOfsPos) {
 		return nil, fmt.Errorf("mmapAccessor: invalid indexStart")
 	}
+	return m.b[indexStart:indexOfsPos], nil
+}
 
-	m.index = NewIndirectIndex()
-	if err := m.index.UnmarshalBinary(m.b[indexStart:indexOfsPos]); err != nil {
+// decrypt prepares the file, encrypted with the key identified by keyID, to be
+// read through enc.
+func (m *mmapAccessor) decrypt(keyID string) error {
+	stat, err := m.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if m.enc, err = encryption.NewFileReader(m.f, stat.Size(), m.keyring); err != nil {
+		return err
+	}
+	m.keyID = keyID
+	return nil
+}
+
+// decryptIndex returns the index of the encrypted file, decrypted into memory.
+func (m *mmapAccessor) decryptIndex() ([]byte, error) {
+	size := m.enc.Size()
+	if size < 8 {
+		return nil, fmt.Errorf("mmapAccessor: byte slice too small for indirectIndex")
+	}
+
+	var buf [8]byte
+	if _, err := m.enc.ReadAt(buf[:], size-8); err != nil {
 		return nil, err
 	}
-	m.index.logger = m.logger
 
-	// Allow resources to be freed immediately if requested
-	m.incAccess()
-	atomic.StoreUint64(&m.freeCount, 1)
+	indexOfsPos := uint64(size - 8)
+	indexStart := binary.BigEndian.Uint64(buf[:])
+	if indexStart >= indexOfsPos {
+		return nil, fmt.Errorf("mmapAccessor: invalid indexStart")
+	}
 
-	return m.index, nil
+	index := make([]byte, indexOfsPos-indexStart)
+	if _, err := m.enc.ReadAt(index, int64(indexStart)); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// block returns the data of the block of entry, including its checksum. Blocks
+// of encrypted files are decrypted into buf, if it is large enough. The caller
+// must hold the read lock.
+func (m *mmapAccessor) block(entry *IndexEntry, buf []byte) ([]byte, error) {
+	if m.enc != nil {
+		if cap(buf) < int(entry.Size) {
+			buf = make([]byte, entry.Size)
+		}
+		buf = buf[:entry.Size]
+		if _, err := m.enc.ReadAt(buf, entry.Offset); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	if int64(len(m.b)) < entry.Offset+int64(entry.Size) {
+		return nil, ErrTSMClosed
+	}
+	return m.b[entry.Offset : entry.Offset+int64(entry.Size)], nil
 }
 
 func (m *mmapAccessor) free() error {
@@ -110,6 +203,11 @@ func (m *mmapAccessor) free() error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Drop the decrypted blocks cached for encrypted files.
+	if m.enc != nil {
+		m.enc.Free()
+		return nil
+	}
 	return madviseDontNeed(m.b)
 }
 
@@ -145,12 +243,12 @@ func (m *mmapAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, er
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if int64(len(m.b)) < entry.Offset+int64(entry.Size) {
-		return nil, ErrTSMClosed
+	b, err := m.block(entry, nil)
+	if err != nil {
+		return nil, err
 	}
 	//TODO: Validate checksum
-	var err error
-	values, err = DecodeBlock(m.b[entry.Offset+4:entry.Offset+int64(entry.Size)], values)
+	values, err = DecodeBlock(b[4:], values)
 	if err != nil {
 		return nil, err
 	}
@@ -162,15 +260,14 @@ func (m *mmapAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, e
 	m.incAccess()
 
 	m.mu.RLock()
-	if int64(len(m.b)) < entry.Offset+int64(entry.Size) {
-		m.mu.RUnlock()
-		return 0, nil, ErrTSMClosed
+	data, err := m.block(entry, b)
+	m.mu.RUnlock()
+	if err != nil {
+		return 0, nil, err
 	}
 
 	// return the bytes after the 4 byte checksum
-	crc, block := binary.BigEndian.Uint32(m.b[entry.Offset:entry.Offset+4]), m.b[entry.Offset+4:entry.Offset+int64(entry.Size)]
-	m.mu.RUnlock()
-
+	crc, block := binary.BigEndian.Uint32(data[:4]), data[4:]
 	return crc, block, nil
 }
 
@@ -190,6 +287,7 @@ func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 
 	var temp []Value
 	var values []Value
+	var buf []byte
 	for _, block := range blocks {
 		var skip bool
 		for _, t := range tombstones {
@@ -203,10 +301,14 @@ func (m *mmapAccessor) readAll(key []byte) ([]Value, error) {
 		if skip {
 			continue
 		}
+		if buf, err = m.block(&block, buf); err != nil {
+			return nil, err
+		}
+
 		//TODO: Validate checksum
 		temp = temp[:0]
 		// The +4 is the 4 byte checksum length
-		temp, err = DecodeBlock(m.b[block.Offset+4:block.Offset+int64(block.Size)], temp)
+		temp, err = DecodeBlock(buf[4:], temp)
 		if err != nil {
 			return nil, err
 		}
@@ -232,12 +334,16 @@ func (m *mmapAccessor) close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.enc != nil {
+		m.enc = nil
+		return m.f.Close()
+	}
+
 	if m.b == nil {
 		return nil
 	}
 
-	err := munmap(m.b)
-	if err != nil {
+	if err := munmap(m.b); err != nil {
 		return err
 	}
 
@@ -37,6 +37,7 @@ type Report struct {
 type ReportSummary struct {
 	Min, Max      int64
 	Total         uint64            //The exact or estimated unique set of series keys across all files.
+	Values        uint64            // The count of values across the files recording block statistics.
 	Organizations map[string]uint64 // The exact or estimated unique set of series keys segmented by org.
 	Buckets       map[string]uint64 // The exact or estimated unique set of series keys segmented by bucket.
 
@@ -98,7 +99,7 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 	start := time.Now()
 
 	tw := tabwriter.NewWriter(r.Stdout, 8, 2, 1, ' ', 0)
-	fmt.Fprintln(tw, strings.Join([]string{"File", "Series", "New" + estTitle, "Min Time", "Max Time", "Load Time"}, "\t"))
+	fmt.Fprintln(tw, strings.Join([]string{"File", "Series", "New" + estTitle, "Values", "Min Time", "Max Time", "Load Time"}, "\t"))
 
 	minTime, maxTime := int64(math.MaxInt64), int64(math.MinInt64)
 
@@ -106,7 +107,8 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 	if err != nil {
 		panic(err) // Only error would be a bad pattern; not runtime related.
 	}
-	var processedFiles int
+	var processedFiles, statsFiles int
+	var totalValues uint64
 
 	var tagBuf models.Tags // Buffer that can be re-used when parsing keys.
 	for _, path := range files {
@@ -133,6 +135,11 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 		currentTotalCount := totalSeries.Count()
 
 		seriesCount := reader.KeyCount()
+
+		// The count of values is answered from the index for files recording block
+		// statistics, otherwise it is not reported to avoid decoding every block.
+		hasStats := reader.HasBlockStats()
+		var valueCount uint64
 		itr := reader.Iterator(nil)
 		if itr == nil {
 			return nil, errors.New("invalid TSM file, no index iterator")
@@ -154,6 +161,12 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 
 			totalSeries.Add(key) // Update total cardinality.
 
+			if hasStats {
+				for _, e := range itr.Entries() {
+					valueCount += uint64(e.Stats.Count)
+				}
+			}
+
 			// Update org cardinality
 			orgCount := orgCardinalities[org.String()]
 			if orgCount == nil {
@@ -220,10 +233,18 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 			return nil, fmt.Errorf("error: %s: %v. Exiting", path, err)
 		}
 
+		values := "-"
+		if hasStats {
+			values = strconv.FormatUint(valueCount, 10)
+			totalValues += valueCount
+			statsFiles++
+		}
+
 		fmt.Fprintln(tw, strings.Join([]string{
 			filepath.Base(file.Name()),
 			strconv.FormatInt(int64(seriesCount), 10),
 			strconv.FormatInt(int64(totalSeries.Count()-currentTotalCount), 10),
+			values,
 			time.Unix(0, minT).UTC().Format(time.RFC3339Nano),
 			time.Unix(0, maxT).UTC().Format(time.RFC3339Nano),
 			loadTime.String(),
@@ -243,12 +264,16 @@ func (r *Report) Run(print bool) (*ReportSummary, error) {
 	summary.Min = minTime
 	summary.Max = maxTime
 	summary.Total = totalSeries.Count()
+	summary.Values = totalValues
 
 	println()
 
 	println("Summary:")
 	fmt.Printf("  Files: %d (%d skipped)\n", processedFiles, len(files)-processedFiles)
 	fmt.Printf("  Series Cardinality%s: %d\n", estTitle, totalSeries.Count())
+	if statsFiles > 0 {
+		fmt.Printf("  Values: %d (%d files with block statistics)\n", totalValues, statsFiles)
+	}
 	fmt.Printf("  Time Range: %s - %s\n",
 		time.Unix(0, minTime).UTC().Format(time.RFC3339Nano),
 		time.Unix(0, maxTime).UTC().Format(time.RFC3339Nano),
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
@@ -17,6 +18,13 @@ type VerifyTSM struct {
 	Paths    []string
 	OrgID    influxdb.ID
 	BucketID influxdb.ID
+
+	// Keyring holds the keys used to decrypt encrypted files.
+	Keyring *encryption.Keyring
+
+	// MissingKeyFiles are the encrypted files that could not be verified as
+	// their key is not in Keyring.
+	MissingKeyFiles []string
 }
 
 func (v *VerifyTSM) Run() error {
@@ -25,6 +33,13 @@ func (v *VerifyTSM) Run() error {
 			fmt.Fprintf(v.Stdout, "Error processing file %q: %v", path, err)
 		}
 	}
+
+	if len(v.MissingKeyFiles) > 0 {
+		fmt.Fprintf(v.Stdout, "Files not verified, missing encryption key:\n")
+		for _, path := range v.MissingKeyFiles {
+			fmt.Fprintf(v.Stdout, "  %s\n", path)
+		}
+	}
 	return nil
 }
 
@@ -36,12 +51,21 @@ func (v *VerifyTSM) processFile(path string) error {
 		return fmt.Errorf("OpenFile: %v", err)
 	}
 
-	reader, err := NewTSMReader(file)
-	if err != nil {
+	reader, err := NewTSMReader(file, WithTSMReaderKeyring(v.Keyring))
+	if encryption.IsKeyNotFound(err) {
+		file.Close()
+		fmt.Fprintf(v.Stdout, "%s: not verified: %v\n", path, err)
+		v.MissingKeyFiles = append(v.MissingKeyFiles, path)
+		return nil
+	} else if err != nil {
 		return fmt.Errorf("failed to create TSM reader for %q: %v", path, err)
 	}
 	defer reader.Close()
 
+	if id := reader.KeyID(); id != "" {
+		fmt.Fprintf(v.Stdout, "encrypted with key %s\n", id)
+	}
+
 	var start []byte
 	if v.OrgID.Valid() {
 		if v.BucketID.Valid() {
@@ -52,6 +52,20 @@ file, we can use the size to determine how much to read in a given IO.
 │ 2 bytes │ N bytes │1 byte│2 bytes│ 8 bytes │ 8 bytes │8 bytes │4 bytes │   │
 └─────────┴─────────┴──────┴───────┴─────────┴─────────┴────────┴────────┴───┘
 
+Files written with version 2 of the format extend each index entry with
+statistics of the values stored in the block: the count of values and, for
+numeric blocks, the minimum, maximum and sum of the values.  The min, max and
+sum are stored as the raw 8 byte representation of the block's value type and
+are zero for boolean and string blocks.  This allows aggregates to be answered
+from the index without decoding the blocks.
+
+┌───────────────────────────────────────────────────────────────────────────────┐
+│                             Extended Index Entry                              │
+├─────────┬─────────┬─────────┬─────────┬─────────┬─────────┬─────────┬─────────┤
+│Min Time │Max Time │ Offset  │  Size   │  Count  │   Min   │   Max   │   Sum   │
+│ 8 bytes │ 8 bytes │ 8 bytes │ 4 bytes │ 4 bytes │ 8 bytes │ 8 bytes │ 8 bytes │
+└─────────┴─────────┴─────────┴─────────┴─────────┴─────────┴─────────┴─────────┘
+
 The last section is the footer that stores the offset of the start of the index.
 
 ┌─────────┐
@@ -75,6 +89,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/pkg/fs"
 )
 
@@ -86,9 +101,16 @@ const (
 	// Version indicates the version of the TSM file format.
 	Version byte = 1
 
+	// VersionBlockStats indicates the version of the TSM file format that extends
+	// each index entry with statistics of the values stored in the block.
+	VersionBlockStats byte = 2
+
 	// Size in bytes of an index entry
 	indexEntrySize = 28
 
+	// Size in bytes of the block statistics extending an index entry
+	blockStatsSize = 44
+
 	// Size in bytes used to store the count of index entries for a key
 	indexCountSize = 2
 
@@ -161,6 +183,9 @@ type IndexWriter interface {
 	// Add records a new block entry for a key in the index.
 	Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32)
 
+	// AddEntry records a new block entry, including its statistics, for a key in the index.
+	AddEntry(key []byte, blockType byte, entry IndexEntry)
+
 	// Entries returns all index entries for a key.
 	Entries(key []byte) []IndexEntry
 
@@ -191,6 +216,10 @@ type IndexEntry struct {
 
 	// The size in bytes of the block in the file.
 	Size uint32
+
+	// Stats are the statistics of the values in the block. They are only
+	// recorded in files written with VersionBlockStats.
+	Stats BlockStats
 }
 
 // UnmarshalBinary decodes an IndexEntry from a byte slice.
@@ -235,21 +264,43 @@ func (e *IndexEntry) OverlapsTimeRange(min, max int64) bool {
 	return e.MinTime <= max && e.MaxTime >= min
 }
 
+// HasStats returns true if the statistics of the values in the block were recorded.
+func (e *IndexEntry) HasStats() bool {
+	return e.Stats.Count > 0
+}
+
 // String returns a string representation of the entry.
 func (e *IndexEntry) String() string {
 	return fmt.Sprintf("min=%s max=%s ofs=%d siz=%d",
 		time.Unix(0, e.MinTime).UTC(), time.Unix(0, e.MaxTime).UTC(), e.Offset, e.Size)
 }
 
+// indexEntrySizeFor returns the size in bytes of an index entry in a TSM file
+// of the given version.
+func indexEntrySizeFor(version byte) uint32 {
+	if version >= VersionBlockStats {
+		return indexEntrySize + blockStatsSize
+	}
+	return indexEntrySize
+}
+
 // NewIndexWriter returns a new IndexWriter.
 func NewIndexWriter() IndexWriter {
+	return newIndexWriter(Version)
+}
+
+func newIndexWriter(version byte) *directIndex {
 	buf := bytes.NewBuffer(make([]byte, 0, 1024*1024))
-	return &directIndex{buf: buf, w: bufio.NewWriter(buf)}
+	return &directIndex{buf: buf, w: bufio.NewWriter(buf), version: version}
 }
 
 // NewIndexWriter returns a new IndexWriter.
 func NewDiskIndexWriter(f *os.File) IndexWriter {
-	return &directIndex{fd: f, w: bufio.NewWriterSize(f, 1024*1024)}
+	return newDiskIndexWriter(f, Version)
+}
+
+func newDiskIndexWriter(f *os.File, version byte) *directIndex {
+	return &directIndex{fd: f, w: bufio.NewWriterSize(f, 1024*1024), version: version}
 }
 
 type syncer interface {
@@ -272,6 +323,10 @@ type directIndex struct {
 
 	w *bufio.Writer
 
+	// version is the TSM file version the index is written for, which determines
+	// whether block statistics are included in the index entries.
+	version byte
+
 	key          []byte
 	indexEntries *indexEntries
 }
@@ -279,6 +334,9 @@ type directIndex struct {
 type indexEntries struct {
 	Type    byte
 	entries []IndexEntry
+
+	// stats indicates the block statistics are encoded with each entry.
+	stats bool
 }
 
 func (a *indexEntries) Len() int      { return len(a.entries) }
@@ -287,23 +345,39 @@ func (a *indexEntries) Less(i, j int) bool {
 	return a.entries[i].MinTime < a.entries[j].MinTime
 }
 
+func (a *indexEntries) entrySize() int {
+	if a.stats {
+		return indexEntrySize + blockStatsSize
+	}
+	return indexEntrySize
+}
+
+func (a *indexEntries) appendEntry(b []byte, entry *IndexEntry) {
+	entry.AppendTo(b)
+	if a.stats {
+		entry.Stats.AppendTo(b[indexEntrySize:])
+	}
+}
+
 func (a *indexEntries) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, len(a.entries)*indexEntrySize)
+	size := a.entrySize()
+	buf := make([]byte, len(a.entries)*size)
 
-	for i, entry := range a.entries {
-		entry.AppendTo(buf[indexEntrySize*i:])
+	for i := range a.entries {
+		a.appendEntry(buf[size*i:], &a.entries[i])
 	}
 
 	return buf, nil
 }
 
 func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
-	var buf [indexEntrySize]byte
+	var buf [indexEntrySize + blockStatsSize]byte
 	var n int
 
-	for _, entry := range a.entries {
-		entry.AppendTo(buf[:])
-		n, err = w.Write(buf[:])
+	size := a.entrySize()
+	for i := range a.entries {
+		a.appendEntry(buf[:size], &a.entries[i])
+		n, err = w.Write(buf[:size])
 		total += int64(n)
 		if err != nil {
 			return total, err
@@ -314,6 +388,17 @@ func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
 }
 
 func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32) {
+	d.AddEntry(key, blockType, IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  offset,
+		Size:    size,
+	})
+}
+
+func (d *directIndex) AddEntry(key []byte, blockType byte, entry IndexEntry) {
+	entrySize := indexEntrySizeFor(d.version)
+
 	// Is this the first block being added?
 	if len(d.key) == 0 {
 		// size of the key stored in the index
@@ -323,18 +408,13 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 
 		d.key = key
 		if d.indexEntries == nil {
-			d.indexEntries = &indexEntries{}
+			d.indexEntries = &indexEntries{stats: d.version >= VersionBlockStats}
 		}
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += entrySize
 		d.keyCount++
 		return
 	}
@@ -343,15 +423,10 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 	cmp := bytes.Compare(d.key, key)
 	if cmp == 0 {
 		// The last block is still this key
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += entrySize
 
 	} else if cmp < 0 {
 		d.flush(d.w)
@@ -365,15 +440,10 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 
 		d.key = key
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += entrySize
 		d.keyCount++
 	} else {
 		// Keys can't be added out of order.
@@ -589,45 +659,83 @@ type tsmWriter struct {
 	index   IndexWriter
 	n       int64
 
+	// version is the TSM file version written.
+	version byte
+
+	// enc encrypts the file and keyring its stats file, if set.
+	enc     *encryption.FileWriter
+	keyring *encryption.Keyring
+
 	// The bytes written count of when we last fsync'd
 	lastSync int64
 
 	stats MeasurementStats
 }
 
-// NewTSMWriter returns a new TSMWriter writing to w.
-func NewTSMWriter(w io.Writer) (TSMWriter, error) {
-	index := NewIndexWriter()
-	return &tsmWriter{
+type tsmWriterOption func(*tsmWriter)
+
+// WithBlockStats is an option for specifying whether the statistics of the values
+// in each block are recorded in the index. Enabling it writes VersionBlockStats files.
+var WithBlockStats = func(enabled bool) tsmWriterOption {
+	return func(t *tsmWriter) {
+		if enabled {
+			t.version = VersionBlockStats
+		} else {
+			t.version = Version
+		}
+	}
+}
+
+// WithEncryption is an option for encrypting the file with the active key of
+// keyring. A nil keyring writes the file unencrypted.
+var WithEncryption = func(keyring *encryption.Keyring) tsmWriterOption {
+	return func(t *tsmWriter) {
+		if keyring != nil {
+			t.enc = encryption.NewFileWriter(t.wrapped, keyring)
+			t.keyring = keyring
+			t.w.Reset(t.enc)
+		}
+	}
+}
+
+func newTSMWriter(w io.Writer, options []tsmWriterOption) *tsmWriter {
+	t := &tsmWriter{
 		wrapped: w,
 		w:       bufio.NewWriterSize(w, 1024*1024),
-		index:   index,
+		version: Version,
 		stats:   NewMeasurementStats(),
-	}, nil
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// NewTSMWriter returns a new TSMWriter writing to w.
+func NewTSMWriter(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
+	t := newTSMWriter(w, options)
+	t.index = newIndexWriter(t.version)
+	return t, nil
 }
 
 // NewTSMWriterWithDiskBuffer returns a new TSMWriter writing to w and will use a disk
 // based buffer for the TSM index if possible.
-func NewTSMWriterWithDiskBuffer(w io.Writer) (TSMWriter, error) {
-	var index IndexWriter
+func NewTSMWriterWithDiskBuffer(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
+	t := newTSMWriter(w, options)
+
 	// Make sure is a File so we can write the temp index alongside it.
 	if fw, ok := w.(syncer); ok {
 		f, err := os.OpenFile(strings.TrimSuffix(fw.Name(), ".tsm.tmp")+".idx.tmp", os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
 		if err != nil {
 			return nil, err
 		}
-		index = NewDiskIndexWriter(f)
+		t.index = newDiskIndexWriter(f, t.version)
 	} else {
 		// w is not a file, just use an inmem index
-		index = NewIndexWriter()
+		t.index = newIndexWriter(t.version)
 	}
 
-	return &tsmWriter{
-		wrapped: w,
-		w:       bufio.NewWriterSize(w, 1024*1024),
-		index:   index,
-		stats:   NewMeasurementStats(),
-	}, nil
+	return t, nil
 }
 
 // MeasurementStats returns the measurement statistics generated by the writer.
@@ -636,7 +744,7 @@ func (t *tsmWriter) MeasurementStats() MeasurementStats { return t.stats }
 func (t *tsmWriter) writeHeader() error {
 	var buf [5]byte
 	binary.BigEndian.PutUint32(buf[0:4], MagicNumber)
-	buf[4] = Version
+	buf[4] = t.version
 
 	n, err := t.w.Write(buf[:])
 	if err != nil {
@@ -688,8 +796,18 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	}
 	n += len(checksum)
 
+	entry := IndexEntry{
+		MinTime: values[0].UnixNano(),
+		MaxTime: values[len(values)-1].UnixNano(),
+		Offset:  t.n,
+		Size:    uint32(n),
+	}
+	if t.version >= VersionBlockStats {
+		entry.Stats = newBlockStats(values)
+	}
+
 	// Record this block in index
-	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	t.index.AddEntry(key, blockType, entry)
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -744,8 +862,20 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	}
 	n += len(checksum)
 
+	entry := IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  t.n,
+		Size:    uint32(n),
+	}
+	if t.version >= VersionBlockStats {
+		if entry.Stats, err = DecodeBlockStats(block); err != nil {
+			return err
+		}
+	}
+
 	// Record this block in index
-	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	t.index.AddEntry(key, blockType, entry)
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -833,16 +963,42 @@ func (t *tsmWriter) writeStatsFile() error {
 	}
 	defer f.Close()
 
-	if _, err := t.stats.WriteTo(f); err != nil {
+	// The stats file holds measurement names, so it is encrypted along with
+	// the file.
+	var w io.Writer = f
+	var enc *encryption.FileWriter
+	if t.keyring != nil {
+		enc = encryption.NewFileWriter(f, t.keyring)
+		w = enc
+	}
+
+	if _, err := t.stats.WriteTo(w); err != nil {
 		return err
-	} else if err := f.Sync(); err != nil {
+	} else if enc != nil {
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Sync(); err != nil {
 		return err
 	}
 	return f.Close()
 }
 
 func (t *tsmWriter) Close() error {
-	if err := t.Flush(); err != nil {
+	if err := t.w.Flush(); err != nil {
+		return err
+	}
+
+	// Write the last chunk of an encrypted file.
+	if t.enc != nil {
+		if err := t.enc.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := t.sync(); err != nil {
 		return err
 	}
 
@@ -893,27 +1049,27 @@ func (t *tsmWriter) Size() uint32 {
 }
 
 // verifyVersion verifies that the reader's bytes are a TSM byte
-// stream of the correct version (1)
-func verifyVersion(r io.ReadSeeker) error {
+// stream of a supported version (1 or 2) and returns the version.
+func verifyVersion(r io.ReadSeeker) (byte, error) {
 	_, err := r.Seek(0, 0)
 	if err != nil {
-		return fmt.Errorf("init: failed to seek: %v", err)
+		return 0, fmt.Errorf("init: failed to seek: %v", err)
 	}
 	var b [4]byte
 	_, err = io.ReadFull(r, b[:])
 	if err != nil {
-		return fmt.Errorf("init: error reading magic number of file: %v", err)
+		return 0, fmt.Errorf("init: error reading magic number of file: %v", err)
 	}
 	if binary.BigEndian.Uint32(b[:]) != MagicNumber {
-		return fmt.Errorf("can only read from tsm file")
+		return 0, fmt.Errorf("can only read from tsm file")
 	}
 	_, err = io.ReadFull(r, b[:1])
 	if err != nil {
-		return fmt.Errorf("init: error reading version: %v", err)
+		return 0, fmt.Errorf("init: error reading version: %v", err)
 	}
-	if b[0] != Version {
-		return fmt.Errorf("init: file is version %b. expected %b", b[0], Version)
+	if b[0] != Version && b[0] != VersionBlockStats {
+		return 0, fmt.Errorf("init: file is version %b. expected %b or %b", b[0], Version, VersionBlockStats)
 	}
 
-	return nil
+	return b[0], nil
 }
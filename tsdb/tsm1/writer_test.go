@@ -4,12 +4,15 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/binary"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/pkg/encryption"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
 
@@ -115,6 +118,119 @@ func TestTSMWriter_Write_Single(t *testing.T) {
 	}
 }
 
+func TestTSMWriter_Write_Encrypted(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	keyring := mustNewKeyring(1)
+	w, err := tsm1.NewTSMWriter(f, tsm1.WithEncryption(keyring))
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+
+	// Write enough values to span several chunks of the encrypted file.
+	var values []tsm1.Value
+	for i := 0; i < 100000; i++ {
+		values = append(values, tsm1.NewValue(int64(i), float64(i)))
+	}
+	for i := 0; i < len(values); i += tsm1.MaxPointsPerBlock {
+		if err := w.Write([]byte("cpu,host=A#!~#value"), values[i:i+tsm1.MaxPointsPerBlock]); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	} else if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	} else if binary.BigEndian.Uint32(b[0:4]) == tsm1.MagicNumber || bytes.Contains(b, []byte("cpu,host=A")) {
+		t.Fatalf("unexpected plaintext in encrypted file")
+	}
+
+	// The file cannot be read without its key.
+	for _, k := range []*encryption.Keyring{nil, mustNewKeyring(2)} {
+		fd, err := os.Open(f.Name())
+		if err != nil {
+			t.Fatalf("unexpected error open file: %v", err)
+		}
+		if _, err := tsm1.NewTSMReader(fd, tsm1.WithTSMReaderKeyring(k)); !encryption.IsKeyNotFound(err) {
+			t.Fatalf("unexpected error creating reader: %v", err)
+		}
+		fd.Close()
+	}
+
+	fd, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+
+	r, err := tsm1.NewTSMReader(fd, tsm1.WithTSMReaderKeyring(mustNewKeyring(2, 1)))
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r.Close()
+
+	if got, exp := r.KeyID(), keyring.ActiveID(); got != exp {
+		t.Fatalf("key id mismatch: got %v, exp %v", got, exp)
+	}
+
+	// The stats file is encrypted along with the file.
+	if b, err := ioutil.ReadFile(tsm1.StatsFilename(f.Name())); err != nil {
+		t.Fatalf("unexpected error reading stats file: %v", err)
+	} else if bytes.Contains(b, []byte("cpu")) {
+		t.Fatalf("unexpected plaintext in encrypted stats file")
+	}
+	if stats, err := r.MeasurementStats(); err != nil {
+		t.Fatalf("unexpected error reading stats: %v", err)
+	} else if stats["cpu"] == 0 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+
+	readValues, err := r.ReadAll([]byte("cpu,host=A#!~#value"))
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	} else if got, exp := len(readValues), len(values); got != exp {
+		t.Fatalf("read values length mismatch: got %v, exp %v", got, exp)
+	}
+	for i, v := range values {
+		if v.Value() != readValues[i].Value() {
+			t.Fatalf("read value mismatch(%d): got %v, exp %v", i, readValues[i].Value(), v.Value())
+		}
+	}
+
+	// Blocks are decrypted as they are read, so their checksums match.
+	var blocks int
+	iter := r.BlockIterator()
+	for iter.Next() {
+		_, _, _, _, checksum, buf, err := iter.Read()
+		if err != nil {
+			t.Fatalf("unexpected error reading block: %v", err)
+		} else if got := crc32.ChecksumIEEE(buf); got != checksum {
+			t.Fatalf("block checksum mismatch: got %v, exp %v", got, checksum)
+		}
+		blocks++
+	}
+	if got, exp := blocks, len(values)/tsm1.MaxPointsPerBlock; got != exp {
+		t.Fatalf("block count mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Freeing the accessor drops the decrypted chunks, which are read again.
+	if err := r.Free(); err != nil {
+		t.Fatalf("unexpected error freeing: %v", err)
+	} else if err := r.Free(); err != nil {
+		t.Fatalf("unexpected error freeing: %v", err)
+	} else if readValues, err = r.ReadAll([]byte("cpu,host=A#!~#value")); err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	} else if got, exp := len(readValues), len(values); got != exp {
+		t.Fatalf("read values length mismatch: got %v, exp %v", got, exp)
+	}
+}
+
 func TestTSMWriter_Write_Multiple(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -645,3 +761,141 @@ func TestTSMWriter_Sync(t *testing.T) {
 		t.Fatal("failed to sync")
 	}
 }
+
+func TestTSMWriter_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := []struct {
+		key    string
+		values []tsm1.Value
+		exp    tsm1.BlockStats
+	}{
+		{
+			key:    "cpu",
+			values: []tsm1.Value{tsm1.NewValue(0, 1.5), tsm1.NewValue(1, -2.0), tsm1.NewValue(2, 4.0)},
+			exp:    tsm1.BlockStats{Count: 3, Min: math.Float64bits(-2), Max: math.Float64bits(4), Sum: math.Float64bits(3.5), MinAt: 1, MaxAt: 2},
+		},
+		{
+			key:    "disk",
+			values: []tsm1.Value{tsm1.NewValue(0, "a"), tsm1.NewValue(1, "b")},
+			exp:    tsm1.BlockStats{Count: 2},
+		},
+		{
+			key:    "mem",
+			values: []tsm1.Value{tsm1.NewValue(0, int64(-3)), tsm1.NewValue(1, int64(5))},
+			exp:    tsm1.BlockStats{Count: 2, Min: uint64(1<<64 - 3), Max: 5, Sum: 2, MinAt: 0, MaxAt: 1},
+		},
+		{
+			key:    "net",
+			values: []tsm1.Value{tsm1.NewValue(0, uint64(8)), tsm1.NewValue(1, uint64(2))},
+			exp:    tsm1.BlockStats{Count: 2, Min: 2, Max: 8, Sum: 10, MinAt: 1, MaxAt: 0},
+		},
+	}
+
+	// write writes data to a new file, either using Write or by copying the
+	// blocks of r using WriteBlock, and returns a reader for the file.
+	write := func(blockStats bool, r *tsm1.TSMReader) *tsm1.TSMReader {
+		f := MustTempFile(dir)
+		w, err := tsm1.NewTSMWriter(f, tsm1.WithBlockStats(blockStats))
+		if err != nil {
+			t.Fatalf("unexpected error creating writer: %v", err)
+		}
+
+		if r == nil {
+			for _, d := range data {
+				if err := w.Write([]byte(d.key), d.values); err != nil {
+					t.Fatalf("unexpected error writing: %v", err)
+				}
+			}
+		} else {
+			iter := r.BlockIterator()
+			for iter.Next() {
+				key, minTime, maxTime, _, _, b, err := iter.Read()
+				if err != nil {
+					t.Fatalf("unexpected error reading block: %v", err)
+				}
+				if err := w.WriteBlock(key, minTime, maxTime, b); err != nil {
+					t.Fatalf("unexpected error writing block: %v", err)
+				}
+			}
+		}
+
+		if err := w.WriteIndex(); err != nil {
+			t.Fatalf("unexpected error writing index: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error closing: %v", err)
+		}
+
+		fd, err := os.Open(f.Name())
+		if err != nil {
+			t.Fatalf("unexpected error open file: %v", err)
+		}
+		nr, err := tsm1.NewTSMReader(fd)
+		if err != nil {
+			t.Fatalf("unexpected error created reader: %v", err)
+		}
+		return nr
+	}
+
+	check := func(r *tsm1.TSMReader) {
+		if !r.HasBlockStats() {
+			t.Fatal("expected file to record block statistics")
+		}
+		for _, d := range data {
+			entries, err := r.ReadEntries([]byte(d.key), nil)
+			if err != nil {
+				t.Fatalf("unexpected error reading entries: %v", err)
+			}
+			if got, exp := len(entries), 1; got != exp {
+				t.Fatalf("entries length mismatch: got %v, exp %v", got, exp)
+			}
+			if !cmp.Equal(entries[0].Stats, d.exp) {
+				t.Fatalf("unexpected stats for %s: -got/+exp\n%s", d.key, cmp.Diff(entries[0].Stats, d.exp))
+			}
+
+			values, err := r.ReadAll([]byte(d.key))
+			if err != nil {
+				t.Fatalf("unexpected error reading: %v", err)
+			}
+			if got, exp := len(values), len(d.values); got != exp {
+				t.Fatalf("read values length mismatch: got %v, exp %v", got, exp)
+			}
+		}
+	}
+
+	plain := write(false, nil)
+	defer plain.Close()
+	if plain.HasBlockStats() {
+		t.Fatal("expected file without block statistics")
+	}
+	entries, err := plain.ReadEntries([]byte("cpu"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error reading entries: %v", err)
+	}
+	if entries[0].HasStats() {
+		t.Fatalf("unexpected stats: %v", entries[0].Stats)
+	}
+
+	written := write(true, nil)
+	defer written.Close()
+	check(written)
+
+	copied := write(true, plain)
+	defer copied.Close()
+	check(copied)
+}
+
+func mustNewKeyring(keys ...byte) *encryption.Keyring {
+	var b [][]byte
+	for _, k := range keys {
+		b = append(b, bytes.Repeat([]byte{k}, encryption.KeySize))
+	}
+
+	k, err := encryption.NewKeyring(b...)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
@@ -23,6 +23,19 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+
+	// UsageWriteRequestsRemaining is the name of the metrics for tracking the number of
+	// write requests an organization may still make in the current second.
+	UsageWriteRequestsRemaining UsageMetric = "usage_write_requests_remaining"
+	// UsageWriteBytesRemaining is the name of the metrics for tracking the number of
+	// bytes an organization may still write in the current second.
+	UsageWriteBytesRemaining UsageMetric = "usage_write_bytes_remaining"
+	// UsageConcurrentQueriesRemaining is the name of the metrics for tracking the number
+	// of additional queries an organization may run concurrently.
+	UsageConcurrentQueriesRemaining UsageMetric = "usage_concurrent_queries_remaining"
+	// UsageSeriesRemaining is the name of the metrics for tracking the number of series
+	// an organization may still create.
+	UsageSeriesRemaining UsageMetric = "usage_series_remaining"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.